@@ -36,6 +36,26 @@ func TestCellar(t *testing.T) {
 	}
 }
 
+func TestUnionClient(t *testing.T) {
+	defer os.RemoveAll("./union/client")
+	if err := goagen("./union", "client", "--notool", "-d", "github.com/goadesign/goa/_integration_tests/union/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./union"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestMultipartClient(t *testing.T) {
+	defer os.RemoveAll("./upload/client")
+	if err := goagen("./upload", "client", "--notool", "-d", "github.com/goadesign/goa/_integration_tests/upload/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gobuild("./upload"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func goagen(dir, command string, args ...string) error {
 	pkg, err := build.Import("github.com/goadesign/goa/goagen", "", 0)
 	if err != nil {
@@ -64,3 +84,13 @@ func gobuild(dir string) error {
 	}
 	return nil
 }
+
+func gotest(dir string) error {
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s\n%s", err.Error(), out)
+	}
+	return nil
+}
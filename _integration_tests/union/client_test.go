@@ -0,0 +1,47 @@
+package union_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/goadesign/goa/_integration_tests/union/client"
+)
+
+func response(body string) *http.Response {
+	return &http.Response{
+		Header: http.Header{"Content-Type": {"application/vnd.booking+json"}},
+		Body:   ioutil.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestDecodeUnion(t *testing.T) {
+	c := client.New(nil)
+	b, err := c.DecodeBooking(response(`{"id":1,"notification":{"type":"EmailNotification","address":"a@b.c"},` +
+		`"history":[{"type":"SMSNotification","phone":"555"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	email, ok := b.Notification.(*client.EmailNotification)
+	if !ok {
+		t.Fatalf("expected an email notification, got %#v", b.Notification)
+	}
+	if email.Address != "a@b.c" {
+		t.Errorf("invalid address %#v", email.Address)
+	}
+	if len(b.History) != 1 {
+		t.Fatalf("expected one notification in history, got %d", len(b.History))
+	}
+	if sms, ok := b.History[0].(*client.SMSNotification); !ok || sms.Phone != "555" {
+		t.Errorf("expected a SMS notification, got %#v", b.History[0])
+	}
+}
+
+func TestDecodeUnionInvalidDiscriminator(t *testing.T) {
+	c := client.New(nil)
+	_, err := c.DecodeBooking(response(`{"id":1,"notification":{"type":"Pigeon"}}`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
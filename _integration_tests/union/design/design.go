@@ -0,0 +1,63 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("union", func() {
+	Title("Union types integration test")
+	Host("localhost:8080")
+})
+
+var Notification = Type("Notification", func() {
+	Description("Notification is either an email or a SMS notification")
+	OneOf(EmailNotification, SMSNotification)
+	Discriminator("type")
+})
+
+var EmailNotification = Type("EmailNotification", func() {
+	Attribute("type", String)
+	Attribute("address", String)
+	Required("type", "address")
+})
+
+var SMSNotification = Type("SMSNotification", func() {
+	Attribute("type", String)
+	Attribute("phone", String)
+	Required("type", "phone")
+})
+
+var BookingMedia = MediaType("application/vnd.booking+json", func() {
+	Description("A booking")
+	Attributes(func() {
+		Attribute("id", Integer)
+		Attribute("notification", Notification)
+		Attribute("history", ArrayOf(Notification))
+		Required("id", "notification")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("notification")
+		Attribute("history")
+	})
+})
+
+var _ = Resource("booking", func() {
+	BasePath("/bookings")
+	Action("show", func() {
+		Routing(GET("/:id"))
+		Params(func() {
+			Param("id", Integer)
+		})
+		Response(OK, BookingMedia)
+	})
+	Action("create", func() {
+		Routing(POST(""))
+		Payload(func() {
+			Attribute("notification", Notification)
+			Required("notification")
+		})
+		Response(Created, BookingMedia)
+	})
+})
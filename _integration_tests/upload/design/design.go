@@ -0,0 +1,28 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("upload", func() {
+	Title("Multipart uploads integration test")
+	Host("localhost:8080")
+})
+
+var UploadPayload = Type("UploadPayload", func() {
+	Attribute("document", File, "Required file")
+	Attribute("attachment", File, "Optional file")
+	Attribute("title", String)
+	Required("document", "title")
+})
+
+var _ = Resource("document", func() {
+	BasePath("/documents")
+	Action("upload", func() {
+		Routing(POST(""))
+		MultipartForm()
+		Payload(UploadPayload)
+		Response(NoContent)
+	})
+})
@@ -0,0 +1,47 @@
+package goa
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy describes the cache policy of HTTP responses, that is the value of their
+// Cache-Control and Vary headers. See the Cache DSL.
+type CachePolicy struct {
+	// MaxAge is the duration the response may be cached for, rounded down to the second.
+	MaxAge time.Duration
+	// Private is true if the response may only be cached by the client and not by shared
+	// caches.
+	Private bool
+	// NoStore is true if the response may not be cached at all.
+	NoStore bool
+	// Vary lists the request headers that select the cached representation.
+	Vary []string
+}
+
+// CacheControl returns the value of the Cache-Control header defined by the policy.
+func (p *CachePolicy) CacheControl() string {
+	if p.NoStore {
+		return "no-store"
+	}
+	directives := []string{"public"}
+	if p.Private {
+		directives[0] = "private"
+	}
+	if secs := int64(p.MaxAge / time.Second); secs > 0 {
+		directives = append(directives, "max-age="+strconv.FormatInt(secs, 10))
+	} else {
+		directives = append(directives, "no-cache")
+	}
+	return strings.Join(directives, ", ")
+}
+
+// Apply sets the Cache-Control header and adds the Vary headers defined by the policy to header.
+func (p *CachePolicy) Apply(header http.Header) {
+	header.Set("Cache-Control", p.CacheControl())
+	for _, v := range p.Vary {
+		header.Add("Vary", v)
+	}
+}
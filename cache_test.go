@@ -0,0 +1,77 @@
+package goa_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CachePolicy", func() {
+	It("computes the Cache-Control header", func() {
+		Ω((&goa.CachePolicy{MaxAge: time.Minute}).CacheControl()).Should(Equal("public, max-age=60"))
+		Ω((&goa.CachePolicy{MaxAge: time.Hour, Private: true}).CacheControl()).Should(Equal("private, max-age=3600"))
+		Ω((&goa.CachePolicy{}).CacheControl()).Should(Equal("public, no-cache"))
+		Ω((&goa.CachePolicy{NoStore: true, MaxAge: time.Minute}).CacheControl()).Should(Equal("no-store"))
+	})
+
+	It("adds the Vary headers", func() {
+		header := http.Header{"Vary": {"Origin"}}
+		(&goa.CachePolicy{MaxAge: time.Minute, Vary: []string{"Accept"}}).Apply(header)
+		Ω(header.Get("Cache-Control")).Should(Equal("public, max-age=60"))
+		Ω(header["Vary"]).Should(Equal([]string{"Origin", "Accept"}))
+	})
+})
+
+var _ = Describe("FileHandler", func() {
+	var dir string
+	var cache []*goa.CachePolicy
+	var rw *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "goa")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0644)).Should(Succeed())
+		cache = nil
+		rw = httptest.NewRecorder()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	JustBeforeEach(func() {
+		ctrl := goa.New("test").NewController("test")
+		req, _ := http.NewRequest("GET", "/index.html", nil)
+		ctx := goa.NewContext(context.Background(), rw, req, nil)
+		err := ctrl.FileHandler("/index.html", filepath.Join(dir, "index.html"), cache...)(ctx, rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("serves the file", func() {
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Body.String()).Should(Equal("<html></html>"))
+		Ω(rw.Header().Get("Cache-Control")).Should(BeEmpty())
+	})
+
+	Context("with a cache policy", func() {
+		BeforeEach(func() {
+			cache = []*goa.CachePolicy{{MaxAge: 24 * time.Hour, Vary: []string{"Accept-Encoding"}}}
+		})
+
+		It("sets the cache headers", func() {
+			Ω(rw.Code).Should(Equal(200))
+			Ω(rw.Header().Get("Cache-Control")).Should(Equal("public, max-age=86400"))
+			Ω(rw.Header().Get("Vary")).Should(Equal("Accept-Encoding"))
+		})
+	})
+})
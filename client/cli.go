@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -73,6 +74,36 @@ func HandleResponse(c *Client, resp *http.Response, pretty bool) {
 	os.Exit(exitStatus)
 }
 
+// HandleStreamResponse prints the messages of a streamed response to STDOUT as they arrive.
+// Responses with a non 2xx status code are handled by HandleResponse.
+func HandleStreamResponse(c *Client, resp *http.Response, pretty bool) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		HandleResponse(c, resp, pretty)
+		return
+	}
+	stream := NewStreamReader(resp)
+	defer stream.Close()
+	for {
+		msg, err := stream.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read stream: %s", err)
+			os.Exit(-1)
+		}
+		if pretty {
+			var jmsg interface{}
+			if err := json.Unmarshal(msg, &jmsg); err == nil {
+				if b, err := json.MarshalIndent(jmsg, "", "    "); err == nil {
+					msg = b
+				}
+			}
+		}
+		fmt.Println(string(msg))
+	}
+}
+
 // WSWrite sends STDIN lines to a websocket server.
 func WSWrite(ws *websocket.Conn) {
 	scanner := bufio.NewScanner(os.Stdin)
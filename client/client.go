@@ -33,6 +33,12 @@ type (
 		UserAgent string
 		// Dump indicates whether to dump request response.
 		Dump bool
+		// Retries is the number of times requests made to idempotent actions are retried
+		// after a network error or a 429 or 5xx response, see DoIdempotent.
+		Retries int
+		// RetryBackoff is the time waited before the first retry, the wait doubles after
+		// each attempt.
+		RetryBackoff time.Duration
 	}
 )
 
@@ -83,6 +89,13 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, err
 		return nil, err
 	}
 	goa.LogInfo(ctx, "completed", "id", id, "status", resp.StatusCode, "time", time.Since(startedAt).String())
+	if resp.Header.Get("Deprecation") != "" {
+		keyvals := []interface{}{"id", id, req.Method, req.URL.String()}
+		if sunset := resp.Header.Get("Sunset"); sunset != "" {
+			keyvals = append(keyvals, "sunset", sunset)
+		}
+		goa.LogInfo(ctx, "warning: deprecated endpoint", keyvals...)
+	}
 	if c.Dump {
 		c.dumpResponse(ctx, resp)
 	}
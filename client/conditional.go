@@ -0,0 +1,41 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/goadesign/goa"
+)
+
+// Condition sets a conditional header on requests made to actions whose responses carry an entity
+// tag. The generated client methods of these actions accept conditions as optional arguments.
+type Condition func(*http.Request)
+
+// IfMatch makes the request conditional on the current entity tag of the resource matching one of
+// etags. The service responds with 412 Precondition Failed otherwise.
+func IfMatch(etags ...string) Condition {
+	return condition("If-Match", etags)
+}
+
+// IfNoneMatch makes the request conditional on the current entity tag of the resource not
+// matching any of etags. The service responds with 304 Not Modified to GET and HEAD requests and
+// with 412 Precondition Failed to the other requests otherwise.
+func IfNoneMatch(etags ...string) Condition {
+	return condition("If-None-Match", etags)
+}
+
+// condition returns a Condition that sets the given header to the list of quoted etags.
+func condition(header string, etags []string) Condition {
+	quoted := make([]string, len(etags))
+	for i, etag := range etags {
+		if etag == "*" {
+			quoted[i] = etag
+			continue
+		}
+		quoted[i] = goa.QuoteETag(etag)
+	}
+	value := strings.Join(quoted, ", ")
+	return func(req *http.Request) {
+		req.Header.Set(header, value)
+	}
+}
@@ -0,0 +1,30 @@
+package client_test
+
+import (
+	"net/http"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Condition", func() {
+	var req *http.Request
+
+	BeforeEach(func() {
+		req, _ = http.NewRequest("GET", "http://goa.design/bottles/1", nil)
+	})
+
+	It("sets the If-Match header", func() {
+		client.IfMatch(`"v1"`, "v2")(req)
+		Expect(req.Header.Get("If-Match")).To(Equal(`"v1", "v2"`))
+	})
+
+	It("sets the If-None-Match header", func() {
+		client.IfNoneMatch(`W/"v1"`)(req)
+		Expect(req.Header.Get("If-None-Match")).To(Equal(`W/"v1"`))
+		client.IfNoneMatch("*")(req)
+		Expect(req.Header.Get("If-None-Match")).To(Equal("*"))
+	})
+})
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+// DoIdempotent makes a request to an action marked as idempotent in the design. It sets the
+// Idempotency-Key header to a random key unless the request already has one then makes the
+// request with Do, retrying up to c.Retries times with the same key after a network error or a
+// 429 or 5xx response. The service uses the key to replay the response to requests it already
+// processed. Requests whose body cannot be read again (see http.Request GetBody) are not
+// retried.
+func (c *Client) DoIdempotent(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if req.Header.Get(goa.IdempotencyKeyHeader) == "" {
+		req.Header.Set(goa.IdempotencyKeyHeader, idempotencyKey())
+	}
+	backoff := c.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.Do(ctx, req)
+		if attempt >= c.Retries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, gerr := req.GetBody()
+			if gerr != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		goa.LogInfo(ctx, "retrying", "attempt", attempt+1, req.Method, req.URL.String())
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// shouldRetry returns true if the request that produced resp and err may be retried.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// idempotencyKey returns a random idempotency key.
+func idempotencyKey() string {
+	b := make([]byte, 16)
+	io.ReadFull(rand.Reader, b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,73 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/goadesign/goa/client"
+	"golang.org/x/net/context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DoIdempotent", func() {
+	var statuses []int
+	var keys, bodies []string
+	var c *client.Client
+	var req *http.Request
+
+	BeforeEach(func() {
+		statuses = nil
+		keys = nil
+		bodies = nil
+		c = client.New(doer(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get("Idempotency-Key"))
+			b, _ := ioutil.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+			if len(statuses) == 0 {
+				return nil, errors.New("connection reset")
+			}
+			status := statuses[0]
+			statuses = statuses[1:]
+			return &http.Response{StatusCode: status, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil
+		}))
+		c.Retries = 2
+		req, _ = http.NewRequest("POST", "http://goa.design/bottles", bytes.NewBufferString(`{"name":"x"}`))
+	})
+
+	It("retries with the same key and body", func() {
+		statuses = []int{503, 201}
+		resp, err := c.DoIdempotent(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(201))
+		Expect(keys).To(HaveLen(2))
+		Expect(keys[0]).ToNot(BeEmpty())
+		Expect(keys[1]).To(Equal(keys[0]))
+		Expect(bodies).To(Equal([]string{`{"name":"x"}`, `{"name":"x"}`}))
+	})
+
+	It("gives up after the configured number of retries", func() {
+		_, err := c.DoIdempotent(context.Background(), req)
+		Expect(err).To(HaveOccurred())
+		Expect(keys).To(HaveLen(3))
+	})
+
+	It("does not retry client errors and keeps the caller key", func() {
+		statuses = []int{400}
+		req.Header.Set("Idempotency-Key", "abc")
+		resp, err := c.DoIdempotent(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(400))
+		Expect(keys).To(Equal([]string{"abc"}))
+	})
+})
+
+// doer is a client.Doer implemented with a function.
+type doer func(context.Context, *http.Request) (*http.Response, error)
+
+func (d doer) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return d(ctx, req)
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NextPageRequest returns the request that retrieves the page of results following the one
+// contained in resp. The page URL is read from the RFC 5988 "next" link of the response Link
+// header and resolved against the URL of req. The new request copies the method and headers of
+// req so that it is signed the same way. NextPageRequest returns nil if resp has no next link.
+func NextPageRequest(req *http.Request, resp *http.Response) (*http.Request, error) {
+	next := ParseLinks(resp.Header.Get("Link"))["next"]
+	if next == "" {
+		return nil, nil
+	}
+	u, err := req.URL.Parse(next)
+	if err != nil {
+		return nil, err
+	}
+	nreq, err := http.NewRequest(req.Method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.Header {
+		nreq.Header[k] = append([]string(nil), v...)
+	}
+	return nreq, nil
+}
+
+// ParseLinks parses the value of a RFC 5988 Link header and returns the link URLs indexed by
+// relation type.
+func ParseLinks(header string) map[string]string {
+	links := make(map[string]string)
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		target = target[1 : len(target)-1]
+		for _, param := range parts[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 || strings.ToLower(kv[0]) != "rel" {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(kv[1], `"`)) {
+				links[strings.ToLower(rel)] = target
+			}
+		}
+	}
+	return links
+}
@@ -0,0 +1,37 @@
+package client_test
+
+import (
+	"net/http"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NextPageRequest", func() {
+	var req *http.Request
+	var resp *http.Response
+
+	BeforeEach(func() {
+		req, _ = http.NewRequest("GET", "http://goa.design/bottles?limit=10", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		resp = &http.Response{Header: http.Header{}}
+	})
+
+	It("follows the next link", func() {
+		resp.Header.Set("Link", `</bottles?cursor=abc&limit=10>; rel="next", </bottles?limit=10>; rel="first"`)
+		next, err := client.NextPageRequest(req, resp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next).ToNot(BeNil())
+		Expect(next.URL.String()).To(Equal("http://goa.design/bottles?cursor=abc&limit=10"))
+		Expect(next.Header.Get("Authorization")).To(Equal("Bearer token"))
+	})
+
+	It("returns nil on the last page", func() {
+		resp.Header.Set("Link", `</bottles?limit=10>; rel="first"`)
+		next, err := client.NextPageRequest(req, resp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next).To(BeNil())
+	})
+})
@@ -25,7 +25,10 @@ type (
 		// SignQuery indicates whether to set the API key in the URL query with key KeyName
 		// or whether to use a header with name KeyName.
 		SignQuery bool
-		// KeyName is the name of the HTTP header or query string that contains the API key.
+		// SignCookie indicates whether to set the API key in a cookie with name KeyName.
+		SignCookie bool
+		// KeyName is the name of the HTTP header, query string or cookie that contains the
+		// API key.
 		KeyName string
 		// KeyValue stores the actual key.
 		KeyValue string
@@ -89,13 +92,17 @@ func (s *BasicSigner) Sign(req *http.Request) error {
 	return nil
 }
 
-// Sign adds the API key header to the request.
+// Sign adds the API key header, query string value or cookie to the request.
 func (s *APIKeySigner) Sign(req *http.Request) error {
 	if s.KeyName == "" {
 		s.KeyName = "Authorization"
 	}
 	if s.Format == "" {
-		s.Format = "Bearer %s"
+		if s.SignCookie {
+			s.Format = "%s"
+		} else {
+			s.Format = "Bearer %s"
+		}
 	}
 	name := s.KeyName
 	format := s.Format
@@ -104,6 +111,8 @@ func (s *APIKeySigner) Sign(req *http.Request) error {
 		query := req.URL.Query()
 		query.Set(name, val)
 		req.URL.RawQuery = query.Encode()
+	} else if s.SignCookie {
+		req.AddCookie(&http.Cookie{Name: name, Value: val})
 	} else {
 		req.Header.Set(name, val)
 	}
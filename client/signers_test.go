@@ -0,0 +1,52 @@
+package client_test
+
+import (
+	"net/http"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("APIKeySigner", func() {
+	var signer *client.APIKeySigner
+	var req *http.Request
+
+	BeforeEach(func() {
+		signer = &client.APIKeySigner{KeyName: "key", KeyValue: "secret"}
+		req, _ = http.NewRequest("GET", "http://goa.design/bottles", nil)
+	})
+
+	JustBeforeEach(func() {
+		Expect(signer.Sign(req)).To(Succeed())
+	})
+
+	It("signs the header", func() {
+		Expect(req.Header.Get("key")).To(Equal("Bearer secret"))
+	})
+
+	Context("with SignQuery", func() {
+		BeforeEach(func() {
+			signer.SignQuery = true
+			signer.Format = "%s"
+		})
+
+		It("signs the query string", func() {
+			Expect(req.URL.Query().Get("key")).To(Equal("secret"))
+		})
+	})
+
+	Context("with SignCookie", func() {
+		BeforeEach(func() {
+			signer.SignCookie = true
+		})
+
+		It("signs the cookie", func() {
+			c, err := req.Cookie("key")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(c.Value).To(Equal("secret"))
+			Expect(req.Header.Get("key")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/goadesign/goa"
+)
+
+// MaxStreamLineSize is the maximum length in bytes of the lines read by StreamReader. Reading a
+// longer line fails with bufio.ErrTooLong.
+var MaxStreamLineSize = 1 << 20 // 1 MB
+
+// StreamReader reads the messages of a streamed response written by goa.ResponseStream. The
+// framing (Server-Sent Events or newline delimited JSON) is determined from the response
+// Content-Type header.
+type StreamReader struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	sse     bool
+	// Event contains the name of the last event read from a Server-Sent Events stream if any.
+	Event string
+}
+
+// NewStreamReader creates a reader for the messages contained in the body of resp.
+func NewStreamReader(resp *http.Response) *StreamReader {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, MaxStreamLineSize)
+	return &StreamReader{
+		body:    resp.Body,
+		scanner: scanner,
+		sse:     mediaType == goa.StreamSSE,
+	}
+}
+
+// Next reads the raw content of the next message. It returns io.EOF once the stream ends.
+func (r *StreamReader) Next() ([]byte, error) {
+	if r.sse {
+		return r.nextEvent()
+	}
+	for r.scanner.Scan() {
+		if line := bytes.TrimSpace(r.scanner.Bytes()); len(line) > 0 {
+			return append([]byte(nil), line...), nil
+		}
+	}
+	return nil, r.err()
+}
+
+// Decode reads the next message and unmarshals it into v. It returns io.EOF once the stream
+// ends.
+func (r *StreamReader) Decode(v interface{}) error {
+	msg, err := r.Next()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(msg, v)
+}
+
+// Close closes the underlying response body.
+func (r *StreamReader) Close() error {
+	return r.body.Close()
+}
+
+// nextEvent reads the data of the next Server-Sent Event. Events with no data are skipped.
+func (r *StreamReader) nextEvent() ([]byte, error) {
+	var data [][]byte
+	r.Event = ""
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			if len(data) > 0 {
+				return bytes.Join(data, []byte("\n")), nil
+			}
+			continue
+		}
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], bytes.TrimPrefix(line[i+1:], []byte(" "))
+		}
+		switch string(field) {
+		case "data":
+			data = append(data, append([]byte(nil), value...))
+		case "event":
+			r.Event = string(value)
+		}
+	}
+	if len(data) > 0 {
+		return bytes.Join(data, []byte("\n")), nil
+	}
+	return nil, r.err()
+}
+
+// err returns the scanner error or io.EOF if the end of the body was reached.
+func (r *StreamReader) err() error {
+	if err := r.scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
@@ -0,0 +1,96 @@
+package client_test
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StreamReader", func() {
+	var contentType, body string
+	var stream *client.StreamReader
+
+	JustBeforeEach(func() {
+		resp := &http.Response{
+			Header: http.Header{"Content-Type": []string{contentType}},
+			Body:   ioutil.NopCloser(strings.NewReader(body)),
+		}
+		stream = client.NewStreamReader(resp)
+	})
+
+	Context("with server-sent events", func() {
+		BeforeEach(func() {
+			contentType = "text/event-stream; charset=utf-8"
+			body = ": comment\n\ndata: {\"id\":1}\n\nevent: update\ndata: {\"id\":\ndata: 2}\n\n"
+		})
+
+		It("decodes each event", func() {
+			var v struct{ ID int }
+			Expect(stream.Decode(&v)).To(Succeed())
+			Expect(v.ID).To(Equal(1))
+			Expect(stream.Event).To(BeEmpty())
+			Expect(stream.Decode(&v)).To(Succeed())
+			Expect(v.ID).To(Equal(2))
+			Expect(stream.Event).To(Equal("update"))
+			Expect(stream.Decode(&v)).To(Equal(io.EOF))
+		})
+	})
+
+	Context("with newline delimited JSON", func() {
+		BeforeEach(func() {
+			contentType = "application/x-ndjson"
+			body = "{\"id\":1}\n\n{\"id\":2}\n"
+		})
+
+		It("decodes each line", func() {
+			msg, err := stream.Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(msg)).To(Equal(`{"id":1}`))
+			msg, err = stream.Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(msg)).To(Equal(`{"id":2}`))
+			_, err = stream.Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+	Context("with lines longer than the default scanner buffer", func() {
+		var name string
+
+		BeforeEach(func() {
+			contentType = "text/event-stream"
+			name = strings.Repeat("a", 100*1024)
+			body = "data: {\"name\":\"" + name + "\"}\n\n"
+		})
+
+		It("decodes the event", func() {
+			var v struct{ Name string }
+			Expect(stream.Decode(&v)).To(Succeed())
+			Expect(v.Name).To(Equal(name))
+		})
+
+		Context("exceeding MaxStreamLineSize", func() {
+			var max int
+
+			BeforeEach(func() {
+				max = client.MaxStreamLineSize
+				client.MaxStreamLineSize = 64 * 1024
+			})
+
+			AfterEach(func() {
+				client.MaxStreamLineSize = max
+			})
+
+			It("returns an error", func() {
+				_, err := stream.Next()
+				Expect(err).To(Equal(bufio.ErrTooLong))
+			})
+		})
+	})
+})
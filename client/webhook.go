@@ -0,0 +1,145 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+type (
+	// WebhookSender delivers callbacks to the URLs registered by subscribers. Each request is
+	// signed with the sender secret, see goa.SignWebhook. Deliveries that fail because of a
+	// network error, a 5xx status or a 429 status are retried with an exponential backoff.
+	WebhookSender struct {
+		// Client is used to make the requests, it logs each delivery attempt.
+		*Client
+		// Secret is the key used to compute the request signatures.
+		Secret []byte
+		// MaxAttempts is the maximum number of delivery attempts, 1 disables retries.
+		MaxAttempts int
+		// Backoff is the delay before the first retry, it doubles after each attempt.
+		Backoff time.Duration
+		// MaxBackoff caps the delay between two attempts, 0 means no limit.
+		MaxBackoff time.Duration
+	}
+
+	// WebhookError is the error returned when the subscriber responds with a non 2xx status
+	// to the last delivery attempt.
+	WebhookError struct {
+		// Event is the name of the callback being delivered.
+		Event string
+		// URL is the subscriber URL.
+		URL string
+		// Status is the HTTP status code of the last response.
+		Status int
+		// Attempts is the number of delivery attempts.
+		Attempts int
+	}
+)
+
+// NewWebhookSender creates a webhook sender that signs requests with secret and makes up to 3
+// delivery attempts starting with a 1 second backoff. If c is nil the sender uses
+// http.DefaultClient.
+func NewWebhookSender(c Doer, secret []byte) *WebhookSender {
+	return &WebhookSender{
+		Client:      New(c),
+		Secret:      secret,
+		MaxAttempts: 3,
+		Backoff:     time.Second,
+	}
+}
+
+// SendJSON encodes payload using JSON and delivers it to url, see Send.
+func (s *WebhookSender) SendJSON(ctx context.Context, url, event string, payload interface{}) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+	return s.Send(ctx, url, event, body)
+}
+
+// Send delivers the callback event with the given body to url. The request sets the webhook
+// event, delivery, timestamp and signature headers. Send retries failed deliveries until the
+// maximum number of attempts is reached or ctx is done.
+func (s *WebhookSender) Send(ctx context.Context, url, event string, body []byte) error {
+	var (
+		id       = shortID()
+		backoff  = s.Backoff
+		attempts = s.MaxAttempts
+		err      error
+	)
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		var status int
+		status, err = s.deliver(ctx, url, event, id, body)
+		if err == nil {
+			if status < 300 {
+				goa.LogInfo(ctx, "webhook delivered", "event", event, "delivery", id, "attempt", attempt, "status", status)
+				return nil
+			}
+			err = &WebhookError{Event: event, URL: url, Status: status, Attempts: attempt}
+			if status < 500 && status != http.StatusTooManyRequests {
+				break
+			}
+		}
+		if attempt >= attempts {
+			break
+		}
+		goa.LogInfo(ctx, "webhook delivery failed, retrying", "event", event, "delivery", id,
+			"attempt", attempt, "err", err, "backoff", backoff.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if s.MaxBackoff > 0 && backoff > s.MaxBackoff {
+			backoff = s.MaxBackoff
+		}
+	}
+	goa.LogError(ctx, "webhook delivery failed", "event", event, "delivery", id, "err", err)
+	return err
+}
+
+// deliver makes a single delivery attempt and returns the response status code.
+func (s *WebhookSender) deliver(ctx context.Context, url, event, id string, body []byte) (int, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := time.Now().Unix()
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set(goa.WebhookEventHeader, event)
+	req.Header.Set(goa.WebhookDeliveryHeader, id)
+	req.Header.Set(goa.WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(goa.WebhookSignatureHeader, goa.SignWebhook(s.Secret, ts, body))
+	resp, err := s.Do(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Error returns the error message.
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("webhook %s delivery to %s failed after %d attempt(s) with status %d",
+		e.Event, e.URL, e.Attempts, e.Status)
+}
@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookSender", func() {
+	var secret = []byte("secret")
+	var statuses []int
+	var requests []*http.Request
+	var bodies [][]byte
+	var server *httptest.Server
+	var sender *client.WebhookSender
+	var sendErr error
+
+	BeforeEach(func() {
+		statuses = []int{http.StatusOK}
+		requests = nil
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			b, _ := ioutil.ReadAll(req.Body)
+			requests = append(requests, req)
+			bodies = append(bodies, b)
+			status := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+			rw.WriteHeader(status)
+		}))
+		sender = client.NewWebhookSender(nil, secret)
+		sender.Backoff = time.Millisecond
+	})
+
+	JustBeforeEach(func() {
+		sendErr = sender.SendJSON(context.Background(), server.URL, "order_shipped", map[string]int{"order_id": 1})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends signed requests", func() {
+		Ω(sendErr).ShouldNot(HaveOccurred())
+		Ω(requests).Should(HaveLen(1))
+		req := requests[0]
+		Ω(req.Method).Should(Equal("POST"))
+		Ω(req.Header.Get("Content-Type")).Should(Equal("application/json"))
+		Ω(req.Header.Get(goa.WebhookEventHeader)).Should(Equal("order_shipped"))
+		Ω(req.Header.Get(goa.WebhookDeliveryHeader)).ShouldNot(BeEmpty())
+		Ω(string(bodies[0])).Should(Equal(`{"order_id":1}`))
+		ts, err := strconv.ParseInt(req.Header.Get(goa.WebhookTimestampHeader), 10, 64)
+		Ω(err).ShouldNot(HaveOccurred())
+		sig := req.Header.Get(goa.WebhookSignatureHeader)
+		Ω(goa.VerifyWebhookSignature(secret, ts, bodies[0], sig)).Should(BeTrue())
+	})
+
+	Context("with a server error", func() {
+		BeforeEach(func() {
+			statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent}
+		})
+
+		It("retries with the same delivery identifier", func() {
+			Ω(sendErr).ShouldNot(HaveOccurred())
+			Ω(requests).Should(HaveLen(3))
+			id := requests[0].Header.Get(goa.WebhookDeliveryHeader)
+			Ω(requests[1].Header.Get(goa.WebhookDeliveryHeader)).Should(Equal(id))
+			Ω(requests[2].Header.Get(goa.WebhookDeliveryHeader)).Should(Equal(id))
+		})
+
+		Context("persisting after the last attempt", func() {
+			BeforeEach(func() {
+				statuses = []int{http.StatusInternalServerError}
+			})
+
+			It("returns a webhook error", func() {
+				Ω(requests).Should(HaveLen(3))
+				Ω(sendErr).Should(HaveOccurred())
+				werr, ok := sendErr.(*client.WebhookError)
+				Ω(ok).Should(BeTrue())
+				Ω(werr.Status).Should(Equal(http.StatusInternalServerError))
+				Ω(werr.Attempts).Should(Equal(3))
+			})
+		})
+	})
+
+	Context("with a client error", func() {
+		BeforeEach(func() {
+			statuses = []int{http.StatusBadRequest}
+		})
+
+		It("does not retry", func() {
+			Ω(requests).Should(HaveLen(1))
+			Ω(sendErr).Should(HaveOccurred())
+		})
+	})
+})
@@ -45,6 +45,9 @@ type (
 		Status int
 		// Length is the response body length.
 		Length int
+		// ContentType is the content type negotiated for the response body if any, see
+		// NegotiatedHandler.
+		ContentType string
 	}
 
 	// key is the type used to store internal values in the context.
@@ -0,0 +1,37 @@
+package goa
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+// DeprecatedHandler wraps the handler of a deprecated endpoint. The returned handler sets the
+// Deprecation response header and the Sunset header defined in RFC 8594 if sunset is not empty,
+// logs the request then calls h. sunset must be formatted as a HTTP date.
+func DeprecatedHandler(h Handler, reason, sunset string) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.Header().Set("Deprecation", "true")
+		if sunset != "" {
+			rw.Header().Set("Sunset", sunset)
+		}
+		keyvals := []interface{}{"path", req.URL.Path}
+		if reason != "" {
+			keyvals = append(keyvals, "reason", reason)
+		}
+		if sunset != "" {
+			keyvals = append(keyvals, "sunset", sunset)
+		}
+		LogInfo(ctx, "deprecated endpoint", keyvals...)
+		return h(ctx, rw, req)
+	}
+}
+
+// LogDeprecatedAttribute logs the use of a deprecated param, header or payload attribute.
+func LogDeprecatedAttribute(ctx context.Context, name, reason string) {
+	keyvals := []interface{}{"attribute", name}
+	if reason != "" {
+		keyvals = append(keyvals, "reason", reason)
+	}
+	LogInfo(ctx, "deprecated attribute", keyvals...)
+}
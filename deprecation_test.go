@@ -0,0 +1,50 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeprecatedHandler", func() {
+	var sunset string
+	var called bool
+	var rw *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		sunset = ""
+		called = false
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return nil
+		}
+		req, _ := http.NewRequest("GET", "/legacy", nil)
+		err := goa.DeprecatedHandler(h, "use /bar instead", sunset)(context.Background(), rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("sets the Deprecation header and calls the handler", func() {
+		Ω(called).Should(BeTrue())
+		Ω(rw.Header().Get("Deprecation")).Should(Equal("true"))
+		Ω(rw.Header()).ShouldNot(HaveKey("Sunset"))
+	})
+
+	Context("with a sunset date", func() {
+		BeforeEach(func() {
+			sunset = "Sat, 30 Jun 2018 00:00:00 GMT"
+		})
+
+		It("sets the Sunset header", func() {
+			Ω(rw.Header().Get("Sunset")).Should(Equal(sunset))
+		})
+	})
+})
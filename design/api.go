@@ -63,6 +63,17 @@ const (
 	HTTPVersionNotSupported = "HTTPVersionNotSupported"
 )
 
+// List of supported pagination styles, see Paginated.
+const (
+	// CursorPagination identifies actions that accept an opaque "cursor" query string param
+	// indicating where the page of results starts and a "limit" param for its size.
+	CursorPagination = "cursor"
+
+	// OffsetPagination identifies actions that accept a "page" query string param indicating
+	// the page number (starting at 1) and a "limit" param for its size.
+	OffsetPagination = "offset"
+)
+
 var (
 	// Design being built by DSL.
 	Design *APIDefinition
@@ -150,6 +161,33 @@ var (
 		Views:      map[string]*ViewDefinition{"default": errorMediaView},
 	}
 
+	// ProblemMediaIdentifier is the media type identifier used for error responses when the
+	// API renders errors as RFC 7807 problem details, see the ProblemDetails DSL.
+	ProblemMediaIdentifier = "application/problem+json"
+
+	// ProblemMedia is the built-in media type for RFC 7807 problem details error responses.
+	// It replaces ErrorMedia in designs that use the ProblemDetails DSL.
+	ProblemMedia = &MediaTypeDefinition{
+		UserTypeDefinition: &UserTypeDefinition{
+			AttributeDefinition: &AttributeDefinition{
+				Type:        problemMediaType,
+				Description: "RFC 7807 problem details error response media type",
+				Example: map[string]interface{}{
+					"type":     "about:blank",
+					"title":    "Bad Request",
+					"status":   400,
+					"detail":   "Value of ID must be an integer",
+					"instance": "/bottles/foo",
+					"id":       "3F1FKVRR",
+					"code":     "invalid_value",
+				},
+			},
+			TypeName: "problem",
+		},
+		Identifier: ProblemMediaIdentifier,
+		Views:      map[string]*ViewDefinition{"default": problemMediaView},
+	}
+
 	errorMediaType = Object{
 		"id": &AttributeDefinition{
 			Type:        String,
@@ -185,6 +223,49 @@ var (
 		AttributeDefinition: &AttributeDefinition{Type: errorMediaType},
 		Name:                "default",
 	}
+
+	problemMediaType = Object{
+		"type": &AttributeDefinition{
+			Type:        String,
+			Description: "a URI reference that identifies the problem type.",
+			Example:     "about:blank",
+		},
+		"title": &AttributeDefinition{
+			Type:        String,
+			Description: "a short, human-readable summary of the problem type.",
+			Example:     "Bad Request",
+		},
+		"status": &AttributeDefinition{
+			Type:        Integer,
+			Description: "the HTTP status code applicable to this problem.",
+			Example:     400,
+		},
+		"detail": &AttributeDefinition{
+			Type:        String,
+			Description: "a human-readable explanation specific to this occurrence of the problem.",
+			Example:     "Value of ID must be an integer",
+		},
+		"instance": &AttributeDefinition{
+			Type:        String,
+			Description: "a URI reference that identifies this occurrence of the problem.",
+			Example:     "/bottles/foo",
+		},
+		"id": &AttributeDefinition{
+			Type:        String,
+			Description: "a unique identifier for this particular occurrence of the problem.",
+			Example:     "3F1FKVRR",
+		},
+		"code": &AttributeDefinition{
+			Type:        String,
+			Description: "an application-specific error code, expressed as a string value.",
+			Example:     "invalid_value",
+		},
+	}
+
+	problemMediaView = &ViewDefinition{
+		AttributeDefinition: &AttributeDefinition{Type: problemMediaType},
+		Name:                "default",
+	}
 )
 
 func init() {
@@ -200,6 +281,7 @@ func init() {
 		{MIMETypes: GobContentTypes, PackagePath: goa, Function: "NewGobDecoder"},
 	}
 	errorMediaView.Parent = ErrorMedia
+	problemMediaView.Parent = ProblemMedia
 }
 
 // CanonicalIdentifier returns the media type identifier sans suffix
@@ -216,6 +298,13 @@ func CanonicalIdentifier(identifier string) string {
 	return mime.FormatMediaType(id, params)
 }
 
+// isErrorMediaIdentifier returns true if identifier identifies one of the built-in error media
+// types ErrorMedia or ProblemMedia.
+func isErrorMediaIdentifier(identifier string) bool {
+	id := CanonicalIdentifier(identifier)
+	return id == CanonicalIdentifier(ErrorMediaIdentifier) || id == CanonicalIdentifier(ProblemMediaIdentifier)
+}
+
 // HasKnownEncoder returns true if the encoder for the given MIME type is known by goa.
 // MIME types with unknown encoders must be associated with a package path explicitly in the DSL.
 func HasKnownEncoder(mimeType string) bool {
@@ -104,6 +104,7 @@ func Action(name string, dsl func()) {
 				Name:     name,
 				Metadata: make(dslengine.MetadataDefinition),
 			}
+			dslengine.RecordLocation(action)
 		}
 		if !dslengine.Execute(dsl, action) {
 			return
@@ -237,7 +238,10 @@ func PATCH(path string, dsl ...func()) *design.RouteDefinition {
 //	})
 //
 // Headers can be used inside Action to define the action request headers, Response to define the
-// response headers or Resource to define common request headers to all the resource actions.
+// response headers or Resource to define common request headers to all the resource actions. The
+// generated contexts expose typed setters for the response headers that run the header validations,
+// the response methods fail if a required response header is not set. The generated client
+// decodes the response headers into typed structs.
 func Headers(params ...interface{}) {
 	if len(params) == 0 {
 		dslengine.ReportError("missing parameter")
@@ -293,6 +297,66 @@ func Headers(params ...interface{}) {
 	}
 }
 
+// Cookies implements the DSL for describing HTTP cookies. The DSL syntax is identical to the one
+// of Attribute, cookie attributes must be primitives. Here is an example defining a session
+// cookie:
+//
+//	Cookies(func() {
+//		Cookie("session", String, func() {
+//			MinLength(32)
+//		})
+//		Required("session")
+//	})
+//
+// Cookies can be used inside Action to define the action request cookies, Response to define the
+// cookies set by the response or Resource to define common request cookies to all the resource
+// actions. The generated contexts coerce and validate the request cookies and expose typed setters
+// for the response cookies. The attributes of the cookies set by responses can be given with
+// metadata:
+//
+//	Cookie("session", String, func() {
+//		Metadata("cookie:path", "/")
+//		Metadata("cookie:max-age", "3600")
+//		Metadata("cookie:http-only")
+//		Metadata("cookie:secure")
+//	})
+//
+// The "cookie:domain" metadata sets the cookie domain.
+func Cookies(dsl func()) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		cookies := newAttribute(def.Parent.MediaType)
+		if dslengine.Execute(dsl, cookies) {
+			def.Cookies = def.Cookies.Merge(cookies)
+		}
+
+	case *design.ResourceDefinition:
+		cookies := newAttribute(def.MediaType)
+		if dslengine.Execute(dsl, cookies) {
+			def.Cookies = def.Cookies.Merge(cookies)
+		}
+
+	case *design.ResponseDefinition:
+		var c *design.AttributeDefinition
+		switch actual := def.Parent.(type) {
+		case *design.ResourceDefinition:
+			c = newAttribute(actual.MediaType)
+		case *design.ActionDefinition:
+			c = newAttribute(actual.Parent.MediaType)
+		case nil: // API ResponseTemplate
+			c = &design.AttributeDefinition{}
+		default:
+			dslengine.ReportError("invalid use of Response or ResponseTemplate")
+		}
+		if dslengine.Execute(dsl, c) {
+			def.Cookies = def.Cookies.Merge(c)
+		}
+
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
 // Params describe the action parameters, either path parameters identified via wildcards or query
 // string parameters if there is no corresponding path parameter. Each parameter is described via
 // the Param function which uses the same DSL as the Attribute DSL. Here is an example:
@@ -389,63 +453,320 @@ func OptionalPayload(p interface{}, dsls ...func()) {
 	payload(true, p, dsls...)
 }
 
+// MultipartForm implements the action multipart form DSL. An action multipart form indicates that
+// the HTTP request body should be encoded using multipart form data as described in
+// https://www.w3.org/TR/html401/interact/forms.html#h-17.13.4.2. The payload attributes must all
+// be primitives, file uploads are described using the File type. Example:
+//
+//	Action("upload", func() {
+//		Routing(POST("/upload"))
+//		MultipartForm()
+//		Payload(func() {
+//			Member("title", String)
+//			Member("file", File)
+//			Required("file")
+//		})
+//	})
+//
+func MultipartForm() {
+	if a, ok := actionDefinition(); ok {
+		a.PayloadMultipart = true
+	}
+}
+
+// Paginated indicates that the action returns its results one page at a time. The argument
+// specifies the pagination style: CursorPagination adds the "cursor" and "limit" query string
+// params to the action while OffsetPagination adds the "page" and "limit" params. Params with
+// the same names already defined by the action are left untouched and params defined later
+// override the ones added by Paginated. Example:
+//
+//	Action("list", func() {
+//		Routing(GET(""))
+//		Paginated(CursorPagination)
+//		Response(OK, func() {
+//			Media(CollectionOf(BottleMedia))
+//		})
+//	})
+//
+// goagen generates a SetPageLinks method on the action context that sets the RFC 5988 Link
+// header of the response and a client iterator that follows the "next" links. The links are
+// built from the Href function of the parent resource if any.
+func Paginated(style string) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if style != design.CursorPagination && style != design.OffsetPagination {
+		dslengine.ReportError("invalid pagination style %#v, must be %#v or %#v",
+			style, design.CursorPagination, design.OffsetPagination)
+		return
+	}
+	a.Pagination = style
+	if a.Params == nil {
+		a.Params = &design.AttributeDefinition{Type: design.Object{}}
+	}
+	params := a.Params.Type.ToObject()
+	addParam := func(name string, att *design.AttributeDefinition) {
+		if _, ok := params[name]; !ok {
+			params[name] = att
+		}
+	}
+	one := 1.0
+	if style == design.CursorPagination {
+		addParam("cursor", &design.AttributeDefinition{
+			Type:        design.String,
+			Description: "Opaque cursor indicating the start of the page of results, omit to retrieve the first page",
+		})
+	} else {
+		addParam("page", &design.AttributeDefinition{
+			Type:         design.Integer,
+			Description:  "Page number starting at 1",
+			DefaultValue: 1,
+			Validation:   &dslengine.ValidationDefinition{Minimum: &one},
+		})
+	}
+	addParam("limit", &design.AttributeDefinition{
+		Type:         design.Integer,
+		Description:  "Maximum number of results in the page",
+		DefaultValue: 20,
+		Validation:   &dslengine.ValidationDefinition{Minimum: &one},
+	})
+}
+
+// Idempotent indicates that the action accepts an "Idempotency-Key" request header that makes it
+// safe for clients to retry requests. Idempotent adds the header to the action unless it is
+// already defined. Example:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Idempotent()
+//		Payload(BottlePayload)
+//		Response(Created)
+//	})
+//
+// goagen mounts the idempotency middleware given to the service UseIdempotency method (see
+// middleware.Idempotency) on the action handler so that the first response is replayed to
+// duplicate requests, and the generated client sets a random key on requests it retries.
+func Idempotent() {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	a.Idempotent = true
+	if a.Headers == nil {
+		a.Headers = &design.AttributeDefinition{Type: design.Object{}}
+	}
+	headers := a.Headers.Type.ToObject()
+	if _, ok := headers["Idempotency-Key"]; !ok {
+		headers["Idempotency-Key"] = &design.AttributeDefinition{
+			Type:        design.String,
+			Description: "Unique key used by the service to detect retries of the same request",
+		}
+	}
+}
+
 func payload(isOptional bool, p interface{}, dsls ...func()) {
 	if len(dsls) > 1 {
 		dslengine.ReportError("too many arguments given to Payload")
 		return
 	}
-	if a, ok := actionDefinition(); ok {
-		var att *design.AttributeDefinition
-		var dsl func()
-		switch actual := p.(type) {
-		case func():
-			dsl = actual
-			att = newAttribute(a.Parent.MediaType)
-			att.Type = design.Object{}
-		case *design.AttributeDefinition:
-			att = design.DupAtt(actual)
-		case *design.UserTypeDefinition:
-			if len(dsls) == 0 {
-				a.Payload = actual
-				a.PayloadOptional = isOptional
-				return
-			}
-			att = design.DupAtt(actual.Definition())
-		case *design.MediaTypeDefinition:
-			att = design.DupAtt(actual.AttributeDefinition)
-		case string:
-			ut, ok := design.Design.Types[actual]
-			if !ok {
-				dslengine.ReportError("unknown payload type %s", actual)
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		rn := camelize(def.Parent.Name)
+		an := camelize(def.Name)
+		typeName := fmt.Sprintf("%s%sPayload", an, rn)
+		if ut, ok := payloadType(p, def.Parent.MediaType, typeName, dsls...); ok {
+			def.Payload = ut
+			def.PayloadOptional = isOptional
+		}
+	case *design.CallbackDefinition:
+		if isOptional {
+			dslengine.ReportError("callback payloads cannot be optional, use Payload instead")
+			return
+		}
+		typeName := fmt.Sprintf("%sCallbackPayload", camelize(def.Name))
+		if ut, ok := payloadType(p, "", typeName, dsls...); ok {
+			def.Payload = ut
+		}
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// payloadType builds the payload type described by the Payload DSL arguments. mt is the identifier
+// of the media type used to inherit attribute definitions from, typeName the name given to the
+// payload type when it is not a user type used as is.
+func payloadType(p interface{}, mt, typeName string, dsls ...func()) (*design.UserTypeDefinition, bool) {
+	var att *design.AttributeDefinition
+	var dsl func()
+	switch actual := p.(type) {
+	case func():
+		dsl = actual
+		att = newAttribute(mt)
+		att.Type = design.Object{}
+	case *design.AttributeDefinition:
+		att = design.DupAtt(actual)
+	case *design.UserTypeDefinition:
+		if len(dsls) == 0 && !hasReadOnly(actual.AttributeDefinition) {
+			return actual, true
+		}
+		if actual.IsUnion() {
+			dslengine.ReportError("union payload type %s cannot be refined with a DSL", actual.TypeName)
+			return nil, false
+		}
+		att = design.DupAtt(actual.Definition())
+	case *design.MediaTypeDefinition:
+		att = design.DupAtt(actual.AttributeDefinition)
+	case string:
+		ut, ok := design.Design.Types[actual]
+		if !ok {
+			dslengine.ReportError("unknown payload type %s", actual)
+		}
+		att = design.DupAtt(ut.AttributeDefinition)
+	case *design.Array:
+		att = &design.AttributeDefinition{Type: actual}
+	case *design.Hash:
+		att = &design.AttributeDefinition{Type: actual}
+	case design.Primitive:
+		att = &design.AttributeDefinition{Type: actual}
+	default:
+		dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+		return nil, false
+	}
+	if len(dsls) == 1 {
+		if dsl != nil {
+			dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
+		}
+		dsl = dsls[0]
+	}
+	if dsl != nil {
+		dslengine.Execute(dsl, att)
+	}
+	if hasReadOnly(att) {
+		att = omitReadOnly(att)
+	}
+	return &design.UserTypeDefinition{
+		AttributeDefinition: att,
+		TypeName:            typeName,
+	}, true
+}
+
+// hasReadOnly returns true if the given attribute has read-only child attributes, including the
+// attributes of nested objects, arrays, hashes, user types and media types.
+func hasReadOnly(att *design.AttributeDefinition) bool {
+	return hasReadOnlyChild(att, make(map[string]bool))
+}
+
+// hasReadOnlyChild implements hasReadOnly, seen records the user types already visited.
+func hasReadOnlyChild(att *design.AttributeDefinition, seen map[string]bool) bool {
+	switch actual := att.Type.(type) {
+	case design.Object:
+		for _, catt := range actual {
+			if catt.ReadOnly || hasReadOnlyChild(catt, seen) {
+				return true
 			}
-			att = design.DupAtt(ut.AttributeDefinition)
-		case *design.Array:
-			att = &design.AttributeDefinition{Type: actual}
-		case *design.Hash:
-			att = &design.AttributeDefinition{Type: actual}
-		case design.Primitive:
-			att = &design.AttributeDefinition{Type: actual}
-		default:
-			dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+		}
+	case *design.Array:
+		return hasReadOnlyChild(actual.ElemType, seen)
+	case *design.Hash:
+		return hasReadOnlyChild(actual.KeyType, seen) || hasReadOnlyChild(actual.ElemType, seen)
+	case *design.UserTypeDefinition:
+		if seen[actual.TypeName] {
+			return false
+		}
+		seen[actual.TypeName] = true
+		return hasReadOnlyChild(actual.AttributeDefinition, seen)
+	case *design.MediaTypeDefinition:
+		if seen[actual.TypeName] {
+			return false
+		}
+		seen[actual.TypeName] = true
+		return hasReadOnlyChild(actual.AttributeDefinition, seen)
+	}
+	return false
+}
+
+// omitReadOnly returns a copy of the given attribute that does not have read-only child
+// attributes at any depth. Nested user and media types that have read-only attributes are
+// replaced with anonymous objects so that the types themselves are left untouched.
+func omitReadOnly(att *design.AttributeDefinition) *design.AttributeDefinition {
+	return omitReadOnlyChild(att, make(map[string]bool))
+}
+
+// omitReadOnlyChild implements omitReadOnly, inlined records the user types being replaced.
+func omitReadOnlyChild(att *design.AttributeDefinition, inlined map[string]bool) *design.AttributeDefinition {
+	if !hasReadOnly(att) {
+		return att
+	}
+	dup := design.DupAtt(att)
+	inline := func(ut *design.UserTypeDefinition) {
+		if inlined[ut.TypeName] {
+			dslengine.ReportError("recursive type %s has read-only attributes and cannot be used in a payload", ut.TypeName)
 			return
 		}
-		if len(dsls) == 1 {
-			if dsl != nil {
-				dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
+		inlined[ut.TypeName] = true
+		def := omitReadOnlyChild(ut.AttributeDefinition, inlined)
+		delete(inlined, ut.TypeName)
+		dup.Type = def.Type
+		if def.Validation != nil {
+			if dup.Validation == nil {
+				dup.Validation = def.Validation.Dup()
+			} else {
+				dup.Validation.Merge(def.Validation)
 			}
-			dsl = dsls[0]
 		}
-		if dsl != nil {
-			dslengine.Execute(dsl, att)
+	}
+	switch actual := att.Type.(type) {
+	case design.Object:
+		obj := make(design.Object)
+		for n, catt := range actual {
+			if !catt.ReadOnly {
+				obj[n] = omitReadOnlyChild(catt, inlined)
+			}
+		}
+		dup.Type = obj
+		if dup.Validation != nil {
+			var required []string
+			for _, n := range dup.Validation.Required {
+				if _, ok := obj[n]; ok {
+					required = append(required, n)
+				}
+			}
+			dup.Validation.Required = required
+		}
+	case *design.Array:
+		dup.Type = &design.Array{ElemType: omitReadOnlyElem(actual.ElemType, inlined)}
+	case *design.Hash:
+		dup.Type = &design.Hash{
+			KeyType:  omitReadOnlyElem(actual.KeyType, inlined),
+			ElemType: omitReadOnlyElem(actual.ElemType, inlined),
+		}
+	case *design.UserTypeDefinition:
+		inline(actual)
+	case *design.MediaTypeDefinition:
+		inline(actual.UserTypeDefinition)
+	}
+	return dup
+}
+
+// omitReadOnlyElem omits the read-only attributes of array elements and hash keys and values.
+// Generated code does not support anonymous objects in arrays and hashes so user types that have
+// read-only attributes cannot be replaced there and are rejected instead.
+func omitReadOnlyElem(att *design.AttributeDefinition, inlined map[string]bool) *design.AttributeDefinition {
+	if hasReadOnly(att) {
+		var name string
+		switch actual := att.Type.(type) {
+		case *design.UserTypeDefinition:
+			name = actual.TypeName
+		case *design.MediaTypeDefinition:
+			name = actual.TypeName
 		}
-		rn := camelize(a.Parent.Name)
-		an := camelize(a.Name)
-		a.Payload = &design.UserTypeDefinition{
-			AttributeDefinition: att,
-			TypeName:            fmt.Sprintf("%s%sPayload", an, rn),
+		if name != "" {
+			dslengine.ReportError("type %s has read-only attributes and cannot be used in the arrays and hashes of a payload", name)
+			return att
 		}
-		a.PayloadOptional = isOptional
 	}
+	return omitReadOnlyChild(att, inlined)
 }
 
 // newAttribute creates a new attribute definition using the media type with the given identifier
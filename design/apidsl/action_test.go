@@ -149,6 +149,46 @@ var _ = Describe("Action", func() {
 		})
 	})
 
+	Context("with cookies", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Cookies(func() {
+					Cookie("session", String)
+					Cookie("theme", String, func() {
+						Enum("dark", "light")
+					})
+					Required("session")
+				})
+			}
+		})
+
+		It("produces a valid action with the given cookies", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.Validate()).ShouldNot(HaveOccurred())
+			Ω(action.Cookies).ShouldNot(BeNil())
+			Ω(action.Cookies.Type.(Object)).Should(HaveLen(2))
+			Ω(action.Cookies.Type.(Object)).Should(HaveKey("theme"))
+			Ω(action.Cookies.IsRequired("session")).Should(BeTrue())
+		})
+
+		Context("with an array cookie", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Cookies(func() {
+						Cookie("ids", ArrayOf(Integer))
+					})
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(action.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Context("using a response with a media type modifier", func() {
 		const mtID = "application/vnd.app.foo+json"
 
@@ -265,6 +305,124 @@ var _ = Describe("Payload", func() {
 		})
 	})
 
+	Context("with a type that has read-only attributes", func() {
+		var account *UserTypeDefinition
+
+		BeforeEach(func() {
+			dslengine.Reset()
+			account = Type("Account", func() {
+				Attribute("id", Integer, func() {
+					ReadOnly()
+				})
+				Attribute("name")
+				Required("id", "name")
+			})
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					Payload(account)
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("omits the read-only attributes from the payload", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			payload := Design.Resources["foo"].Actions["bar"].Payload
+			Ω(payload).ShouldNot(Equal(account))
+			Ω(payload.TypeName).Should(Equal("BarFooPayload"))
+			Ω(payload.Type.ToObject()).Should(HaveKey("name"))
+			Ω(payload.Type.ToObject()).ShouldNot(HaveKey("id"))
+			Ω(payload.Validation.Required).Should(Equal([]string{"name"}))
+			Ω(account.Type.ToObject()).Should(HaveKey("id"))
+			Ω(account.Validation.Required).Should(Equal([]string{"id", "name"}))
+		})
+	})
+
+	Context("with a type that has nested read-only attributes", func() {
+		var address, order *UserTypeDefinition
+
+		BeforeEach(func() {
+			dslengine.Reset()
+			address = Type("Address", func() {
+				Attribute("id", Integer, func() {
+					ReadOnly()
+				})
+				Attribute("street")
+				Required("id", "street")
+			})
+			order = Type("Order", func() {
+				Attribute("shipping", address)
+				Attribute("billing", func() {
+					Attribute("verified", Boolean, func() {
+						ReadOnly()
+					})
+					Attribute("street")
+					Required("verified", "street")
+				})
+				Required("shipping")
+			})
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					Payload(order)
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("omits the nested read-only attributes from the payload", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			payload := Design.Resources["foo"].Actions["bar"].Payload
+			Ω(payload).ShouldNot(Equal(order))
+			obj := payload.Type.ToObject()
+			Ω(payload.Validation.Required).Should(Equal([]string{"shipping"}))
+
+			shipping := obj["shipping"]
+			Ω(shipping.Type).ShouldNot(Equal(address))
+			Ω(shipping.Type.ToObject()).Should(HaveKey("street"))
+			Ω(shipping.Type.ToObject()).ShouldNot(HaveKey("id"))
+			Ω(shipping.Validation.Required).Should(Equal([]string{"street"}))
+
+			billing := obj["billing"]
+			Ω(billing.Type.ToObject()).Should(HaveKey("street"))
+			Ω(billing.Type.ToObject()).ShouldNot(HaveKey("verified"))
+			Ω(billing.Validation.Required).Should(Equal([]string{"street"}))
+
+			Ω(address.Type.ToObject()).Should(HaveKey("id"))
+			Ω(address.Validation.Required).Should(Equal([]string{"id", "street"}))
+			Ω(order.Type.ToObject()["shipping"].Type).Should(Equal(address))
+			Ω(order.Type.ToObject()["billing"].Type.ToObject()).Should(HaveKey("verified"))
+		})
+
+		Context("used in an array", func() {
+			BeforeEach(func() {
+				Type("Shipment", func() {
+					Attribute("addresses", ArrayOf(address))
+				})
+				Resource("shipments", func() {
+					Action("ship", func() {
+						Routing(POST("/ship"))
+						Payload("Shipment")
+					})
+				})
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("type Address has read-only attributes"))
+			})
+		})
+	})
+
 	Context("with an array", func() {
 		BeforeEach(func() {
 			dslengine.Reset()
@@ -325,4 +483,299 @@ var _ = Describe("Payload", func() {
 		})
 	})
 
+	Context("with a multipart form", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					MultipartForm()
+					Payload(func() {
+						Member("title")
+						Member("file", File)
+						Required("file")
+					})
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("sets the multipart flag", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["foo"].Actions["bar"].PayloadMultipart).Should(BeTrue())
+			Ω(Design.Resources["foo"].Actions["bar"].Payload.ToObject()["file"].Type).Should(Equal(File))
+		})
+	})
+
+	Context("with a file attribute and no multipart form", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Member("file", File)
+					})
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("MultipartForm"))
+		})
+	})
+
+	Context("with a multipart form and a non primitive attribute", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					MultipartForm()
+					Payload(func() {
+						Member("tags", ArrayOf(String))
+					})
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must be a primitive or a file"))
+		})
+	})
+})
+
+var _ = Describe("Paginated", func() {
+	var style string
+	var params func()
+	var action *ActionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		style = CursorPagination
+		params = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("bar", func() {
+				Routing(GET(""))
+				Paginated(style)
+				if params != nil {
+					Params(params)
+				}
+			})
+		})
+		dslengine.Run()
+		action = Design.Resources["foo"].Actions["bar"]
+	})
+
+	It("adds the cursor pagination query params", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(action.Pagination).Should(Equal(CursorPagination))
+		qp := action.QueryParams.Type.ToObject()
+		Ω(qp).Should(HaveLen(2))
+		Ω(qp["cursor"].Type).Should(Equal(String))
+		Ω(qp["limit"].Type).Should(Equal(Integer))
+		Ω(qp["limit"].DefaultValue).Should(Equal(20))
+	})
+
+	Context("using offset pagination", func() {
+		BeforeEach(func() {
+			style = OffsetPagination
+		})
+
+		It("adds the page and limit query params", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			qp := action.QueryParams.Type.ToObject()
+			Ω(qp).Should(HaveLen(2))
+			Ω(qp["page"].Type).Should(Equal(Integer))
+			Ω(qp["page"].DefaultValue).Should(Equal(1))
+			Ω(qp["limit"].Type).Should(Equal(Integer))
+		})
+	})
+
+	Context("with params overriding the pagination params", func() {
+		BeforeEach(func() {
+			params = func() {
+				Param("limit", Integer, func() {
+					Default(10)
+					Maximum(50)
+				})
+			}
+		})
+
+		It("uses the overridden params", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			limit := action.QueryParams.Type.ToObject()["limit"]
+			Ω(limit.DefaultValue).Should(Equal(10))
+			Ω(*limit.Validation.Maximum).Should(Equal(50.0))
+		})
+	})
+
+	Context("with params changing the type of a pagination param", func() {
+		BeforeEach(func() {
+			params = func() {
+				Param("cursor", Integer)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("pagination param cursor must be of type string"))
+		})
+	})
+
+	Context("with an unknown pagination style", func() {
+		BeforeEach(func() {
+			style = "foo"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Idempotent", func() {
+	var verb string
+	var headers func()
+	var action *ActionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		verb = "POST"
+		headers = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("bar", func() {
+				if verb == "POST" {
+					Routing(POST(""))
+				} else {
+					Routing(GET(""))
+				}
+				if headers != nil {
+					Headers(headers)
+				}
+				Idempotent()
+			})
+		})
+		dslengine.Run()
+		action = Design.Resources["foo"].Actions["bar"]
+	})
+
+	It("adds the Idempotency-Key header", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(action.Idempotent).Should(BeTrue())
+		h := action.Headers.Type.ToObject()
+		Ω(h).Should(HaveKey("Idempotency-Key"))
+		Ω(h["Idempotency-Key"].Type).Should(Equal(String))
+		Ω(action.Headers.IsRequired("Idempotency-Key")).Should(BeFalse())
+	})
+
+	Context("with the key header already defined", func() {
+		BeforeEach(func() {
+			headers = func() {
+				Header("Idempotency-Key", String, func() {
+					MinLength(16)
+				})
+				Required("Idempotency-Key")
+			}
+		})
+
+		It("keeps the existing header", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.Headers.IsRequired("Idempotency-Key")).Should(BeTrue())
+			Ω(*action.Headers.Type.ToObject()["Idempotency-Key"].Validation.MinLength).Should(Equal(16))
+		})
+	})
+
+	Context("with a key header that is not a string", func() {
+		BeforeEach(func() {
+			headers = func() {
+				Header("Idempotency-Key", Integer)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("Idempotency-Key header must be of type String"))
+		})
+	})
+
+	Context("on an action with only safe routes", func() {
+		BeforeEach(func() {
+			verb = "GET"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("unsafe HTTP method"))
+		})
+	})
+})
+
+var _ = Describe("Produces and Consumes", func() {
+	var produces string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		produces = "application/xml"
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			Consumes("application/json", "application/xml")
+			Produces("application/json", "application/xml")
+		})
+		Resource("foo", func() {
+			Consumes("application/json")
+			Action("bar", func() {
+				Routing(POST(""))
+				Produces(produces)
+			})
+			Action("baz", func() {
+				Routing(GET(""))
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the effective MIME types of the actions", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		res := Design.Resources["foo"]
+		Ω(res.Actions["bar"].EffectiveProduces()).Should(Equal([]string{"application/xml"}))
+		Ω(res.Actions["bar"].EffectiveConsumes()).Should(Equal([]string{"application/json"}))
+		Ω(res.Actions["baz"].EffectiveProduces()).Should(BeNil())
+		Ω(res.Actions["baz"].EffectiveConsumes()).Should(Equal([]string{"application/json"}))
+	})
+
+	Context("with a MIME type not listed by the API", func() {
+		BeforeEach(func() {
+			produces = "application/gob"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`MIME type "application/gob" is not listed by the API`))
+		})
+	})
 })
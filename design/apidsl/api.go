@@ -85,6 +85,7 @@ func API(name string, dsl func()) *design.APIDefinition {
 	}
 	design.Design.Name = name
 	design.Design.DSLFunc = dsl
+	dslengine.RecordLocation(design.Design)
 	return design.Design
 }
 
@@ -117,6 +118,10 @@ func Description(d string) {
 		def.Description = d
 	case *design.SecuritySchemeDefinition:
 		def.Description = d
+	case *design.CallbackDefinition:
+		def.Description = d
+	case *design.ErrorDefinition:
+		def.Description = d
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -208,10 +213,16 @@ func Expose(vals ...string) {
 	}
 }
 
-// MaxAge sets the cache expiry for preflight request responses. Used in Origin DSL.
+// MaxAge sets the cache expiry in seconds for preflight request responses when used in Origin DSL
+// and for the responses the cache policy applies to when used in Cache DSL.
 func MaxAge(val uint) {
-	if cors, ok := corsDefinition(); ok {
-		cors.MaxAge = val
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.CORSDefinition:
+		def.MaxAge = val
+	case *design.CacheDefinition:
+		def.MaxAge = val
+	default:
+		dslengine.IncompatibleDSL()
 	}
 }
 
@@ -329,7 +340,8 @@ func Email(email string) {
 	}
 }
 
-// URL sets the contact or license URL.
+// URL sets the contact, license or docs URL. In Callback URL sets the runtime expression used
+// in the OpenAPI specification to compute the subscriber URL.
 func URL(url string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.ContactDefinition:
@@ -338,6 +350,8 @@ func URL(url string) {
 		def.URL = url
 	case *design.DocsDefinition:
 		def.URL = url
+	case *design.CallbackDefinition:
+		def.URL = url
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -347,11 +361,30 @@ func URL(url string) {
 // Consumes may also specify the path of the decoding package.
 // The package must expose a DecoderFactory method that returns an object which implements
 // goa.DecoderFactory.
+//
+// Consumes may also appear in Resource or Action to restrict the MIME types accepted by the
+// actions to a subset of the API MIME types. Requests whose Content-Type is not listed are
+// rejected with a 415 Unsupported Media Type response:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Consumes("application/json")
+//	})
 func Consumes(args ...interface{}) {
-	if a, ok := apiDefinition(); ok {
-		if def := buildEncodingDefinition(false, args...); def != nil {
-			a.Consumes = append(a.Consumes, def)
-		}
+	var consumes *[]*design.EncodingDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		consumes = &def.Consumes
+	case *design.ResourceDefinition:
+		consumes = &def.Consumes
+	case *design.ActionDefinition:
+		consumes = &def.Consumes
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if def := buildEncodingDefinition(false, args...); def != nil {
+		*consumes = append(*consumes, def)
 	}
 }
 
@@ -359,11 +392,26 @@ func Consumes(args ...interface{}) {
 // Produces may also specify the path of the encoding package.
 // The package must expose a EncoderFactory method that returns an object which implements
 // goa.EncoderFactory.
+//
+// Produces may also appear in Resource or Action to restrict the MIME types of the action
+// responses to a subset of the API MIME types. The response Content-Type is negotiated from the
+// request Accept header and requests that accept none of the listed types are rejected with a 406
+// Not Acceptable response.
 func Produces(args ...interface{}) {
-	if a, ok := apiDefinition(); ok {
-		if def := buildEncodingDefinition(true, args...); def != nil {
-			a.Produces = append(a.Produces, def)
-		}
+	var produces *[]*design.EncodingDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		produces = &def.Produces
+	case *design.ResourceDefinition:
+		produces = &def.Produces
+	case *design.ActionDefinition:
+		produces = &def.Produces
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if def := buildEncodingDefinition(true, args...); def != nil {
+		*produces = append(*produces, def)
 	}
 }
 
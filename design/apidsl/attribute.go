@@ -126,6 +126,7 @@ func Attribute(name string, args ...interface{}) {
 			}
 		}
 		baseAttr.Reference = parent.Reference
+		dslengine.RecordLocation(baseAttr)
 		if dsl != nil {
 			dslengine.Execute(dsl, baseAttr)
 		}
@@ -253,6 +254,24 @@ func Header(name string, args ...interface{}) {
 	Attribute(name, args...)
 }
 
+// Cookie is an alias of Attribute for the most part.
+//
+// Within an APIKeySecurity or JWTSecurity definition, Cookie
+// defines that an implementation must check the given cookie to get
+// the API Key.  In this case, no `args` parameter is necessary.
+func Cookie(name string, args ...interface{}) {
+	if _, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+		if len(args) != 0 {
+			dslengine.ReportError("do not specify args")
+			return
+		}
+		inCookie(name)
+		return
+	}
+
+	Attribute(name, args...)
+}
+
 // Member is an alias of Attribute.
 func Member(name string, args ...interface{}) {
 	Attribute(name, args...)
@@ -317,6 +336,98 @@ func NoExample() {
 	}
 }
 
+// Examples adds a named example to an attribute, a type or a response. Examples may be called
+// multiple times to describe different use cases, for example in a payload:
+//
+//	Payload(AccountPayload, func() {
+//		Examples("minimal", map[string]interface{}{"name": "alice"})
+//		Examples("full", map[string]interface{}{"name": "alice", "email": "alice@goa.design"})
+//	})
+//
+// or in a response:
+//
+//	Response(OK, AccountMedia, func() {
+//		Examples("created", map[string]interface{}{"id": 1, "name": "alice"})
+//	})
+//
+// The generated swagger specification lists the named examples in the "x-examples" extension of
+// schemas and parameters and in the "examples" field of responses. The generated CLI prints the
+// payload examples in the command help and the generated test helpers return them decoded.
+func Examples(name string, value interface{}) {
+	var (
+		examples *[]*design.ExampleDefinition
+		att      *design.AttributeDefinition
+	)
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		att = def
+	case *design.UserTypeDefinition:
+		att = def.AttributeDefinition
+	case *design.MediaTypeDefinition:
+		att = def.AttributeDefinition
+	case *design.ResponseDefinition:
+		examples = &def.Examples
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if att != nil {
+		if att.Type != nil && !att.Type.IsCompatible(value) {
+			dslengine.ReportError("example %#v value %#v is incompatible with attribute of type %s",
+				name, value, att.Type.Name())
+			return
+		}
+		examples = &att.Examples
+	}
+	for _, e := range *examples {
+		if e.Name == name {
+			dslengine.ReportError("example %#v is defined twice", name)
+			return
+		}
+	}
+	*examples = append(*examples, &design.ExampleDefinition{Name: name, Value: value})
+}
+
+// ReadOnly marks the attribute as read-only: its value is set by the service. Read-only attributes
+// may be used in types shared by payloads and media types, they are removed from request payloads
+// at any depth so that values sent by clients are ignored:
+//
+//	var Account = Type("Account", func() {
+//		Attribute("id", Integer, func() {
+//			ReadOnly()
+//		})
+//		Attribute("name", String)
+//		Required("id", "name")
+//	})
+//
+// An action using Account as payload uses a payload type generated from Account without the id
+// attribute. Nested types that have read-only attributes are replaced with anonymous objects in
+// the payload type, such types cannot be used as payload array elements or hash keys and values.
+// The generated JSON schema and swagger specification set the "readOnly" property.
+func ReadOnly() {
+	if a, ok := attributeDefinition(); ok {
+		a.ReadOnly = true
+	}
+}
+
+// WriteOnly marks the attribute as write-only: its value is sent by clients in request payloads
+// but never rendered in responses. Write-only attributes are removed from the media type views:
+//
+//	var Account = Type("Account", func() {
+//		Attribute("name", String)
+//		Attribute("password", String, func() {
+//			WriteOnly()
+//		})
+//	})
+//
+// The generated JSON schema and OpenAPI specification set the "writeOnly" property. Swagger 2.0
+// does not support it so the generated swagger specification omits it.
+func WriteOnly() {
+	if a, ok := attributeDefinition(); ok {
+		a.WriteOnly = true
+	}
+}
+
 // Enum adds a "enum" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor76.
 func Enum(val ...interface{}) {
@@ -458,6 +569,7 @@ func Minimum(val interface{}) {
 				a.Validation = &dslengine.ValidationDefinition{}
 			}
 			a.Validation.Minimum = &f
+			a.Validation.ExclusiveMinimum = false
 		}
 	}
 }
@@ -488,6 +600,59 @@ func Maximum(val interface{}) {
 				a.Validation = &dslengine.ValidationDefinition{}
 			}
 			a.Validation.Maximum = &f
+			a.Validation.ExclusiveMaximum = false
+		}
+	}
+}
+
+// ExclusiveMinimum adds a "minimum" validation with the "exclusiveMinimum" flag set to the
+// attribute, the attribute value must be strictly greater than val.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor21.
+func ExclusiveMinimum(val interface{}) {
+	if a, ok := attributeDefinition(); ok {
+		Minimum(val)
+		if a.Validation != nil && a.Validation.Minimum != nil {
+			a.Validation.ExclusiveMinimum = true
+		}
+	}
+}
+
+// ExclusiveMaximum adds a "maximum" validation with the "exclusiveMaximum" flag set to the
+// attribute, the attribute value must be strictly less than val.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor17.
+func ExclusiveMaximum(val interface{}) {
+	if a, ok := attributeDefinition(); ok {
+		Maximum(val)
+		if a.Validation != nil && a.Validation.Maximum != nil {
+			a.Validation.ExclusiveMaximum = true
+		}
+	}
+}
+
+// MultipleOf adds a "multipleOf" validation to the attribute, the attribute value must be a
+// multiple of val. val must be strictly greater than 0.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor14.
+func MultipleOf(val interface{}) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
+			incompatibleAttributeType("multiple of", a.Type.Name(), "an integer or a number")
+		} else {
+			var f float64
+			switch v := val.(type) {
+			case float32, float64, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+				f = reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0.0))).Float()
+			default:
+				dslengine.ReportError("invalid number value %#v", v)
+				return
+			}
+			if f <= 0 {
+				dslengine.ReportError("multiple of value must be strictly greater than 0, got %v", f)
+				return
+			}
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MultipleOf = &f
 		}
 	}
 }
@@ -522,18 +687,97 @@ func MaxLength(val int) {
 	}
 }
 
+// UniqueItems adds a "uniqueItems" validation to the attribute, the elements of the array must all
+// be different.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor49.
+func UniqueItems() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.ArrayKind {
+			incompatibleAttributeType("unique items", a.Type.Name(), "an array")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.UniqueItems = true
+		}
+	}
+}
+
+// MinProperties adds a "minProperties" validation to the attribute, the hash must contain at least
+// val keys.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor57.
+func MinProperties(val int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.HashKind {
+			incompatibleAttributeType("minimum properties", a.Type.Name(), "a hash")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MinProperties = &val
+		}
+	}
+}
+
+// MaxProperties adds a "maxProperties" validation to the attribute, the hash must contain at most
+// val keys.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor54.
+func MaxProperties(val int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.HashKind {
+			incompatibleAttributeType("maximum properties", a.Type.Name(), "a hash")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MaxProperties = &val
+		}
+	}
+}
+
+// ValidateWith adds a custom validation to the attribute, type or media type. The generated
+// Validate methods call the function with the given name defined in the package with the given
+// import path and report the error it returns as an invalid request error. The function accepts
+// the Go value of the attribute if it is a primitive type or a interface{} holding the generated
+// data structure otherwise:
+//
+//	Attribute("iban", String, func() {
+//		ValidateWith("github.com/acme/validators", "IBAN") // func IBAN(v string) error
+//	})
+//
+//	var Booking = Type("Booking", func() {
+//		ValidateWith("github.com/acme/validators", "Booking") // func Booking(v interface{}) error
+//		Attribute("start", DateTime)
+//		Attribute("end", DateTime)
+//	})
+//
+// The name of the package must match the last element of its import path.
+func ValidateWith(pkgPath, name string) {
+	if a, ok := attributeDefinition(); ok {
+		if pkgPath == "" {
+			dslengine.ReportError("missing package path for validation function %#v", name)
+			return
+		}
+		if !validFunctionName.MatchString(name) {
+			dslengine.ReportError("invalid validation function name %#v, must be an exported Go identifier", name)
+			return
+		}
+		if a.Validation == nil {
+			a.Validation = &dslengine.ValidationDefinition{}
+		}
+		a.Validation.Functions = append(a.Validation.Functions,
+			&dslengine.ValidationFunction{PackagePath: pkgPath, Name: name})
+	}
+}
+
+// validFunctionName matches the names of exported Go functions.
+var validFunctionName = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
 // Required adds a "required" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor61.
 func Required(names ...string) {
-	var at *design.AttributeDefinition
-
-	switch def := dslengine.CurrentDefinition().(type) {
-	case *design.AttributeDefinition:
-		at = def
-	case *design.MediaTypeDefinition:
-		at = def.AttributeDefinition
-	default:
-		dslengine.IncompatibleDSL()
+	at, ok := requiredContainer()
+	if !ok {
 		return
 	}
 
@@ -547,6 +791,73 @@ func Required(names ...string) {
 	}
 }
 
+// RequiredIf adds a conditional "required" validation to the attribute: the attribute with the
+// given name is required when the attribute dependsOn is set. If values are given then name is only
+// required when dependsOn is set to one of them:
+//
+//	Attribute("payment", func() {
+//		Attribute("payment_method", String, func() {
+//			Enum("card", "cash")
+//		})
+//		Attribute("card_number", String)
+//		RequiredIf("card_number", "payment_method", "card")
+//	})
+//
+// The generated JSON schema uses the "dependentRequired" keyword when no value is given.
+func RequiredIf(name, dependsOn string, values ...interface{}) {
+	if at, ok := requiredContainer(); ok {
+		if at.Type != nil && at.Type.Kind() != design.ObjectKind {
+			incompatibleAttributeType("required if", at.Type.Name(), "an object")
+			return
+		}
+		if at.Validation == nil {
+			at.Validation = &dslengine.ValidationDefinition{}
+		}
+		at.Validation.RequiredIf = append(at.Validation.RequiredIf,
+			&dslengine.ConditionalRequirement{Name: name, DependsOn: dependsOn, Values: values})
+	}
+}
+
+// AtLeastOneOf adds a validation to the attribute that requires at least one of the attributes
+// with the given names to be set:
+//
+//	Attribute("contact", func() {
+//		Attribute("email", String)
+//		Attribute("phone", String)
+//		AtLeastOneOf("email", "phone")
+//	})
+//
+// The generated JSON schema uses the "anyOf" keyword.
+func AtLeastOneOf(names ...string) {
+	if at, ok := requiredContainer(); ok {
+		if at.Type != nil && at.Type.Kind() != design.ObjectKind {
+			incompatibleAttributeType("at least one of", at.Type.Name(), "an object")
+			return
+		}
+		if len(names) < 2 {
+			dslengine.ReportError("at least one of validation requires two or more attribute names")
+			return
+		}
+		if at.Validation == nil {
+			at.Validation = &dslengine.ValidationDefinition{}
+		}
+		at.Validation.AtLeastOneOf = append(at.Validation.AtLeastOneOf, names)
+	}
+}
+
+// requiredContainer returns the attribute definition of the current attribute or media type.
+func requiredContainer() (*design.AttributeDefinition, bool) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		return def, true
+	case *design.MediaTypeDefinition:
+		return def.AttributeDefinition, true
+	default:
+		dslengine.IncompatibleDSL()
+		return nil, false
+	}
+}
+
 // incompatibleAttributeType reports an error for validations defined on
 // incompatible attributes (e.g. max value on string).
 func incompatibleAttributeType(validation, actual, expected string) {
@@ -253,6 +253,219 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 
+	Context("with a name, type number and a DSL defining exclusive bounds and multiple of validations", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = Number
+			dsl = func() {
+				ExclusiveMinimum(0)
+				Maximum(10)
+				MultipleOf(0.5)
+			}
+		})
+
+		It("produces an attribute with the validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			val := parent.Type.(Object)[name].Validation
+			Ω(val).ShouldNot(BeNil())
+			Ω(*val.Minimum).Should(Equal(0.0))
+			Ω(val.ExclusiveMinimum).Should(BeTrue())
+			Ω(*val.Maximum).Should(Equal(10.0))
+			Ω(val.ExclusiveMaximum).Should(BeFalse())
+			Ω(*val.MultipleOf).Should(Equal(0.5))
+		})
+
+		Context("with a multiple of value that is not positive", func() {
+			BeforeEach(func() {
+				dsl = func() { MultipleOf(0) }
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a name, type array and a DSL defining a unique items validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = ArrayOf(String)
+			dsl = func() { UniqueItems() }
+		})
+
+		It("produces an attribute with the validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			val := parent.Type.(Object)[name].Validation
+			Ω(val).ShouldNot(BeNil())
+			Ω(val.UniqueItems).Should(BeTrue())
+		})
+	})
+
+	Context("with a name, type hash and a DSL defining properties count validations", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = HashOf(String, String)
+			dsl = func() {
+				MinProperties(1)
+				MaxProperties(3)
+			}
+		})
+
+		It("produces an attribute with the validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			val := parent.Type.(Object)[name].Validation
+			Ω(val).ShouldNot(BeNil())
+			Ω(*val.MinProperties).Should(Equal(1))
+			Ω(*val.MaxProperties).Should(Equal(3))
+		})
+
+		Context("on a string attribute", func() {
+			BeforeEach(func() {
+				dataType = String
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a name and a DSL defining a validation function", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() { ValidateWith("github.com/acme/validators", "IBAN") }
+		})
+
+		It("produces an attribute with the validation function", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			val := parent.Type.(Object)[name].Validation
+			Ω(val).ShouldNot(BeNil())
+			Ω(val.Functions).Should(HaveLen(1))
+			Ω(val.Functions[0].PackagePath).Should(Equal("github.com/acme/validators"))
+			Ω(val.Functions[0].Name).Should(Equal("IBAN"))
+			Ω(val.Functions[0].Package()).Should(Equal("validators"))
+		})
+
+		Context("with an unexported function name", func() {
+			BeforeEach(func() {
+				dsl = func() { ValidateWith("github.com/acme/validators", "iban") }
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a name and a DSL marking the attribute read-only", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() { ReadOnly() }
+		})
+
+		It("produces a read-only attribute", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			att := parent.Type.(Object)[name]
+			Ω(att.ReadOnly).Should(BeTrue())
+			Ω(att.WriteOnly).Should(BeFalse())
+		})
+
+		Context("and write-only", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					ReadOnly()
+					WriteOnly()
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a name and a DSL defining named examples", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = Integer
+			dsl = func() {
+				Examples("small", 1)
+				Examples("large", 1000)
+			}
+		})
+
+		It("records the examples in order", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			att := parent.Type.(Object)[name]
+			Ω(att.Examples).Should(HaveLen(2))
+			Ω(att.Examples[0].Name).Should(Equal("small"))
+			Ω(att.Examples[0].Value).Should(Equal(1))
+			Ω(att.Examples[1].Name).Should(Equal("large"))
+		})
+
+		Context("with an incompatible value", func() {
+			BeforeEach(func() {
+				dsl = func() { Examples("bad", "one") }
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a duplicate name", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Examples("small", 1)
+					Examples("small", 2)
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a name and a DSL defining conditional requirements", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Attribute("method")
+				Attribute("card_number")
+				Attribute("email")
+				Attribute("phone")
+				RequiredIf("card_number", "method", "card")
+				AtLeastOneOf("email", "phone")
+			}
+		})
+
+		It("produces an attribute with the validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			val := parent.Type.(Object)[name].Validation
+			Ω(val).ShouldNot(BeNil())
+			Ω(val.RequiredIf).Should(HaveLen(1))
+			Ω(val.RequiredIf[0].Name).Should(Equal("card_number"))
+			Ω(val.RequiredIf[0].DependsOn).Should(Equal("method"))
+			Ω(val.RequiredIf[0].Values).Should(Equal([]interface{}{"card"}))
+			Ω(val.AtLeastOneOf).Should(Equal([][]string{{"email", "phone"}}))
+		})
+
+		Context("referring to an unknown attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute("email")
+					AtLeastOneOf("email", "phone")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Context("with a name, type integer, a description and a DSL defining an enum validation", func() {
 		BeforeEach(func() {
 			name = "foo"
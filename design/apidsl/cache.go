@@ -0,0 +1,73 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Cache defines the cache policy of HTTP responses, that is the value of their Cache-Control and
+// Vary headers. Cache may appear in Action, Response or Files. A policy defined in Action applies
+// to the action responses with a 2xx status that do not define their own. Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		Cache(func() {
+//			MaxAge(60)        // Cache-Control: private, max-age=60
+//			Private()
+//			Vary("Accept")    // Vary: Accept
+//		})
+//		Response(OK, BottleMedia)
+//		Response(NotFound, func() {
+//			Cache(func() {
+//				NoStore() // Cache-Control: no-store
+//			})
+//		})
+//	})
+//
+//	Files("/public/*filepath", "/www/data", func() {
+//		Cache(func() {
+//			MaxAge(86400)
+//		})
+//	})
+//
+// Responses that define no max age may be cached but must be revalidated (no-cache). goagen sets
+// the headers in the generated response helpers and documents them in the Swagger specification.
+func Cache(dsl func()) {
+	cache := new(design.CacheDefinition)
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		def.Cache = cache
+	case *design.ResponseDefinition:
+		def.Cache = cache
+	case *design.FileServerDefinition:
+		def.Cache = cache
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	cache.Parent = dslengine.CurrentDefinition()
+	dslengine.Execute(dsl, cache)
+}
+
+// Private indicates that the responses may only be cached by the client and not by shared caches
+// such as proxies. Used in Cache DSL.
+func Private() {
+	if cache, ok := cacheDefinition(); ok {
+		cache.Private = true
+	}
+}
+
+// NoStore indicates that the responses may not be cached at all. Used in Cache DSL.
+func NoStore() {
+	if cache, ok := cacheDefinition(); ok {
+		cache.NoStore = true
+	}
+}
+
+// Vary lists the request headers that select the cached representation, for example the Accept
+// header for actions whose responses depend on content negotiation. Used in Cache DSL.
+func Vary(headers ...string) {
+	if cache, ok := cacheDefinition(); ok {
+		cache.Vary = append(cache.Vary, headers...)
+	}
+}
@@ -0,0 +1,82 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	var noStore bool
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		noStore = false
+	})
+
+	JustBeforeEach(func() {
+		Resource("bottle", func() {
+			Response(NotFound)
+			Files("/public/*filepath", "/www/data", func() {
+				Cache(func() {
+					MaxAge(86400)
+				})
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Cache(func() {
+					MaxAge(60)
+					Private()
+					Vary("Accept", "Accept-Language")
+					if noStore {
+						NoStore()
+					}
+				})
+				Response(OK, "text/plain")
+				Response(Created, func() {
+					Cache(func() {
+						NoStore()
+					})
+				})
+				Response(BadRequest)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the effective cache policies of the responses", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		show := Design.Resources["bottle"].Actions["show"]
+
+		c := show.Responses["OK"].EffectiveCache()
+		Ω(c).ShouldNot(BeNil())
+		Ω(c.CacheControl()).Should(Equal("private, max-age=60"))
+		Ω(c.Vary).Should(Equal([]string{"Accept", "Accept-Language"}))
+
+		c = show.Responses["Created"].EffectiveCache()
+		Ω(c).ShouldNot(BeNil())
+		Ω(c.CacheControl()).Should(Equal("no-store"))
+
+		Ω(show.Responses["BadRequest"].EffectiveCache()).Should(BeNil())
+		Ω(show.Responses["NotFound"].EffectiveCache()).Should(BeNil())
+	})
+
+	It("sets the cache policy of the file servers", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		fs := Design.Resources["bottle"].FileServers[0]
+		Ω(fs.Cache).ShouldNot(BeNil())
+		Ω(fs.Cache.CacheControl()).Should(Equal("public, max-age=86400"))
+	})
+
+	Context("with both NoStore and MaxAge", func() {
+		BeforeEach(func() {
+			noStore = true
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
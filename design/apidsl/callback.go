@@ -0,0 +1,62 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Callback describes a request sent by the API to a URL registered by a subscriber, also known
+// as webhook. Callback may appear in API to describe events not tied to a specific action or in
+// Action to describe requests sent as a result of calling the action. The DSL may use
+// Description, Payload, URL and Metadata:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Payload(OrderPayload)
+//		Callback("order_shipped", func() {
+//			Description("Sent when the order ships")
+//			URL("{$request.body#/callback_url}")
+//			Payload(func() {
+//				Member("order_id", Integer)
+//				Member("shipped_at", DateTime)
+//				Required("order_id", "shipped_at")
+//			})
+//		})
+//	})
+//
+// URL is the runtime expression used in the OpenAPI specification to compute the subscriber URL,
+// it defaults to "{$request.body#/callback_url}".
+//
+// goagen generates a CallbackSender type in the app package that exposes one method per callback,
+// e.g. SendOrderShipped. The methods validate the payload, sign the request and deliver it with
+// retries using client.WebhookSender. Receivers check the signatures with the
+// middleware.VerifyWebhook HTTP middleware.
+func Callback(name string, dsl func()) {
+	var callbacks *map[string]*design.CallbackDefinition
+	var parent dslengine.Definition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		callbacks, parent = &def.Callbacks, def
+	case *design.ActionDefinition:
+		callbacks, parent = &def.Callbacks, def
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if _, ok := (*callbacks)[name]; ok {
+		dslengine.ReportError("callback %#v is defined twice", name)
+		return
+	}
+	cb := &design.CallbackDefinition{
+		Name:     name,
+		Parent:   parent,
+		Metadata: make(dslengine.MetadataDefinition),
+	}
+	if dsl != nil && !dslengine.Execute(dsl, cb) {
+		return
+	}
+	if *callbacks == nil {
+		*callbacks = make(map[string]*design.CallbackDefinition)
+	}
+	(*callbacks)[name] = cb
+}
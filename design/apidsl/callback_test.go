@@ -0,0 +1,104 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Callback", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			Callback("maintenance", func() {
+				Description("Sent before maintenance windows")
+				Payload(String)
+			})
+		})
+		Resource("order", func() {
+			Action("create", func() {
+				Routing(POST(""))
+				Callback("order_shipped", dsl)
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with an inline payload", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Description("Sent when the order ships")
+				URL("{$request.body#/hook}")
+				Metadata("foo", "bar")
+				Payload(func() {
+					Member("order_id", Integer)
+					Required("order_id")
+				})
+			}
+		})
+
+		It("defines the action callback", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			action := Design.Resources["order"].Actions["create"]
+			Ω(action.Callbacks).Should(HaveKey("order_shipped"))
+			cb := action.Callbacks["order_shipped"]
+			Ω(cb.Name).Should(Equal("order_shipped"))
+			Ω(cb.Parent).Should(Equal(action))
+			Ω(cb.Description).Should(Equal("Sent when the order ships"))
+			Ω(cb.URL).Should(Equal("{$request.body#/hook}"))
+			Ω(cb.Metadata).Should(HaveKeyWithValue("foo", []string{"bar"}))
+			Ω(cb.Payload).ShouldNot(BeNil())
+			Ω(cb.Payload.TypeName).Should(Equal("OrderShippedCallbackPayload"))
+			Ω(cb.Payload.Type.ToObject()).Should(HaveKey("order_id"))
+			Ω(cb.Payload.Validation.Required).Should(Equal([]string{"order_id"}))
+		})
+
+		It("defines the API callback", func() {
+			Ω(Design.Callbacks).Should(HaveKey("maintenance"))
+			cb := Design.Callbacks["maintenance"]
+			Ω(cb.Parent).Should(Equal(Design))
+			Ω(cb.Payload.Type).Should(Equal(String))
+		})
+
+		It("iterates through all the callbacks", func() {
+			var names []string
+			Design.IterateCallbacks(func(c *CallbackDefinition) error {
+				names = append(names, c.Name)
+				return nil
+			})
+			Ω(names).Should(Equal([]string{"maintenance", "order_shipped"}))
+		})
+	})
+
+	Context("with an optional payload", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				OptionalPayload(String)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a name conflicting with an API callback", func() {
+		JustBeforeEach(func() {
+			Design.Callbacks["order_shipped"] = &CallbackDefinition{Name: "order_shipped", Parent: Design}
+		})
+
+		It("fails validation", func() {
+			err := Design.Validate()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("callback names must be unique"))
+		})
+	})
+})
@@ -112,6 +112,16 @@ func corsDefinition() (*design.CORSDefinition, bool) {
 	return cors, ok
 }
 
+// cacheDefinition returns true and current context if it is a CacheDefinition,
+// nil and false otherwise.
+func cacheDefinition() (*design.CacheDefinition, bool) {
+	c, ok := dslengine.CurrentDefinition().(*design.CacheDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return c, ok
+}
+
 // actionDefinition returns true and current context if it is an ActionDefinition,
 // nil and false otherwise.
 func actionDefinition() (*design.ActionDefinition, bool) {
@@ -0,0 +1,81 @@
+package apidsl
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Deprecated marks an action, a route or an attribute as deprecated. The argument explains why
+// and what clients should use instead. Deprecated can be used in Action, in the DSL given to the
+// route functions (GET, POST etc.) and in Attribute, Member, Param or Header:
+//
+//	Action("show", func() {
+//		Deprecated("use the get action instead")
+//		Routing(GET("/:id"))
+//	})
+//
+//	Routing(GET("/legacy/:id", func() {
+//		Deprecated("use /:id instead")
+//	}))
+//
+//	Param("sort", String, func() {
+//		Deprecated("results are always sorted by name")
+//	})
+//
+// The generated controllers set the Deprecation response header and log the use of deprecated
+// actions, routes and attributes. The generated swagger marks deprecated operations and uses the
+// x-deprecated extension for deprecated attributes and params, the generated JSON schema sets the
+// deprecated keyword of deprecated attributes.
+func Deprecated(reason string) {
+	if d := deprecation(); d != nil {
+		d.Reason = reason
+	}
+}
+
+// Sunset sets the date after which a deprecated action, route or attribute may stop working. The
+// date is given using the "2006-01-02" layout or as a RFC 3339 timestamp. Sunset implies
+// Deprecated and can be used in the same places:
+//
+//	Action("show", func() {
+//		Deprecated("use the get action instead")
+//		Sunset("2018-06-30")
+//		Routing(GET("/:id"))
+//	})
+//
+// The generated controllers set the Sunset response header defined in RFC 8594.
+func Sunset(date string) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		if t, err = time.Parse(time.RFC3339, date); err != nil {
+			dslengine.ReportError("invalid sunset date %#v, must use the 2006-01-02 layout or RFC 3339", date)
+			return
+		}
+	}
+	if d := deprecation(); d != nil {
+		d.Sunset = t
+	}
+}
+
+// deprecation returns the deprecation definition of the current action, route or attribute,
+// creating it if needed. It reports an error and returns nil if the current definition cannot be
+// deprecated.
+func deprecation() *design.DeprecationDefinition {
+	var d **design.DeprecationDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		d = &def.Deprecation
+	case *design.RouteDefinition:
+		d = &def.Deprecation
+	case *design.AttributeDefinition:
+		d = &def.Deprecation
+	default:
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	if *d == nil {
+		*d = &design.DeprecationDefinition{}
+	}
+	return *d
+}
@@ -0,0 +1,82 @@
+package apidsl_test
+
+import (
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deprecated", func() {
+	var action *ActionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		Resource("foo", func() {
+			Action("bar", func() {
+				Deprecated("use baz instead")
+				Sunset("2018-06-30")
+				Routing(
+					GET("/bar"),
+					GET("/legacy", func() {
+						Deprecated("use /bar instead")
+					}),
+				)
+				Params(func() {
+					Param("sort", String, func() {
+						Deprecated("results are always sorted")
+					})
+				})
+			})
+			Action("baz", func() {
+				Routing(GET("/baz"))
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Run()
+		action = Design.Resources["foo"].Actions["bar"]
+	})
+
+	It("sets the action deprecation", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(action.Deprecation).ShouldNot(BeNil())
+		Ω(action.Deprecation.Reason).Should(Equal("use baz instead"))
+		Ω(action.Deprecation.Sunset).Should(Equal(time.Date(2018, 6, 30, 0, 0, 0, 0, time.UTC)))
+		Ω(action.Deprecation.SunsetHeader()).Should(Equal("Sat, 30 Jun 2018 00:00:00 GMT"))
+	})
+
+	It("sets the route deprecation", func() {
+		Ω(action.Routes[0].Deprecation).Should(BeNil())
+		Ω(action.Routes[0].EffectiveDeprecation()).Should(Equal(action.Deprecation))
+		Ω(action.Routes[1].EffectiveDeprecation().Reason).Should(Equal("use /bar instead"))
+		Ω(Design.Resources["foo"].Actions["baz"].Routes[0].EffectiveDeprecation()).Should(BeNil())
+	})
+
+	It("sets the attribute deprecation", func() {
+		sort := action.Params.Type.ToObject()["sort"]
+		Ω(sort.Deprecation).ShouldNot(BeNil())
+		Ω(sort.Deprecation.Reason).Should(Equal("results are always sorted"))
+	})
+
+	Context("with an invalid sunset date", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			Resource("foo", func() {
+				Action("bar", func() {
+					Sunset("tomorrow")
+					Routing(GET("/bar"))
+				})
+			})
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid sunset date"))
+		})
+	})
+})
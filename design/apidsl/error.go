@@ -0,0 +1,88 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Error describes an error that may be returned by an action. Error may appear in API to describe
+// errors that may be returned by all the actions or in Action. The first argument is the name of
+// the error which is also the error code sent in responses. The optional arguments are the media
+// type that describes the response body given as a pointer to its definition or via its
+// identifier (ErrorMedia by default), the HTTP status code of the responses (400 by default) and a
+// DSL that may set a Description:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Payload(OrderPayload)
+//		Error("out_of_stock", OutOfStockMedia, http.StatusConflict, func() {
+//			Description("The requested quantity is not available")
+//		})
+//		Error("order_limit_reached", http.StatusForbidden) // Uses ErrorMedia
+//	})
+//
+// goagen generates a constructor in the app package for each error, e.g. ErrOutOfStock, and a
+// response helper on the action contexts, e.g. OutOfStockError. The client package exposes a
+// DecodeError method that decodes error responses into typed errors using the error code.
+func Error(name string, args ...interface{}) {
+	var errs *map[string]*design.ErrorDefinition
+	var parent dslengine.Definition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		errs, parent = &def.Errors, def
+	case *design.ActionDefinition:
+		errs, parent = &def.Errors, def
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if _, ok := (*errs)[name]; ok {
+		dslengine.ReportError("error %#v is defined twice", name)
+		return
+	}
+	e := &design.ErrorDefinition{
+		Name:      name,
+		Status:    400,
+		MediaType: design.ErrorMediaIdentifier,
+		Parent:    parent,
+	}
+	var dsl func()
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case *design.MediaTypeDefinition:
+			if a != nil {
+				e.MediaType = a.Identifier
+			}
+		case string:
+			e.MediaType = a
+		case int:
+			e.Status = a
+		case func():
+			dsl = a
+		default:
+			dslengine.ReportError("invalid Error argument %#v, must be a media type, a status or a DSL", arg)
+			return
+		}
+	}
+	if dsl != nil && !dslengine.Execute(dsl, e) {
+		return
+	}
+	if *errs == nil {
+		*errs = make(map[string]*design.ErrorDefinition)
+	}
+	(*errs)[name] = e
+}
+
+// ProblemDetails causes the API to render errors as RFC 7807 problem details using the
+// application/problem+json content type. ProblemDetails must appear in API. The responses and
+// errors that use ErrorMedia use ProblemMedia instead, the generated service renders the errors
+// returned by the actions as problem details and the generated client decodes them:
+//
+//	API("cellar", func() {
+//		ProblemDetails()
+//	})
+func ProblemDetails() {
+	if a, ok := apiDefinition(); ok {
+		a.ProblemDetails = true
+	}
+}
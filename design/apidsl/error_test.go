@@ -0,0 +1,172 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	var args []interface{}
+	var outOfStock *MediaTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		args = nil
+		outOfStock = MediaType("application/vnd.out-of-stock", func() {
+			Attributes(func() {
+				Attribute("available", Integer)
+			})
+			View("default", func() {
+				Attribute("available")
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			Error("maintenance", 503)
+		})
+		Resource("order", func() {
+			Action("create", func() {
+				Routing(POST(""))
+				Error("out_of_stock", args...)
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with no argument", func() {
+		It("uses the error media type and a 400 status", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			action := Design.Resources["order"].Actions["create"]
+			Ω(action.Errors).Should(HaveKey("out_of_stock"))
+			e := action.Errors["out_of_stock"]
+			Ω(e.Name).Should(Equal("out_of_stock"))
+			Ω(e.Parent).Should(Equal(action))
+			Ω(e.Status).Should(Equal(400))
+			Ω(e.MediaType).Should(Equal(ErrorMediaIdentifier))
+			Ω(e.IsError()).Should(BeTrue())
+		})
+
+		It("registers the error media type", func() {
+			Ω(Design.MediaTypeWithIdentifier(ErrorMediaIdentifier)).ShouldNot(BeNil())
+		})
+	})
+
+	Context("with a media type, a status and a DSL", func() {
+		BeforeEach(func() {
+			args = []interface{}{outOfStock, 409, func() {
+				Description("Not enough items")
+			}}
+		})
+
+		It("defines the error", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			e := Design.Resources["order"].Actions["create"].Errors["out_of_stock"]
+			Ω(e.Status).Should(Equal(409))
+			Ω(e.MediaType).Should(Equal("application/vnd.out-of-stock"))
+			Ω(e.Description).Should(Equal("Not enough items"))
+			Ω(e.IsError()).Should(BeFalse())
+		})
+
+		It("iterates through the action and API errors", func() {
+			var names []string
+			Design.Resources["order"].Actions["create"].IterateErrors(func(e *ErrorDefinition) error {
+				names = append(names, e.Name)
+				return nil
+			})
+			Ω(names).Should(Equal([]string{"maintenance", "out_of_stock"}))
+		})
+	})
+
+	Context("with an invalid status", func() {
+		BeforeEach(func() {
+			args = []interface{}{200}
+		})
+
+		It("fails validation", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must be a 4xx or 5xx status"))
+		})
+	})
+
+	Context("with an undefined media type", func() {
+		BeforeEach(func() {
+			args = []interface{}{"application/vnd.unknown"}
+		})
+
+		It("fails validation", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("is not defined in the design"))
+		})
+	})
+
+	Context("with an invalid argument", func() {
+		BeforeEach(func() {
+			args = []interface{}{true}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a name conflicting with an API error", func() {
+		BeforeEach(func() {
+			args = []interface{}{503}
+		})
+
+		JustBeforeEach(func() {
+			Design.Errors["out_of_stock"] = &ErrorDefinition{
+				Name:      "out_of_stock",
+				Status:    409,
+				MediaType: ErrorMediaIdentifier,
+				Parent:    Design,
+			}
+		})
+
+		It("fails validation", func() {
+			err := Design.Validate()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("error status and media type must match"))
+		})
+	})
+})
+
+var _ = Describe("ProblemDetails", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", func() {
+			ProblemDetails()
+			Error("maintenance", 503)
+		})
+		Resource("order", func() {
+			Response(Unauthorized, ErrorMedia)
+			Action("create", func() {
+				Routing(POST(""))
+				Response(BadRequest, ErrorMedia)
+				Error("out_of_stock", 409)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("uses the problem details media type for errors", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.ProblemDetails).Should(BeTrue())
+		Ω(Design.ErrorMedia()).Should(Equal(ProblemMedia))
+		action := Design.Resources["order"].Actions["create"]
+		Ω(action.Responses["BadRequest"].MediaType).Should(Equal(ProblemMediaIdentifier))
+		Ω(action.Responses["BadRequest"].Type).Should(Equal(ProblemMedia))
+		Ω(action.Responses["Unauthorized"].MediaType).Should(Equal(ProblemMediaIdentifier))
+		Ω(action.Errors["out_of_stock"].IsProblem()).Should(BeTrue())
+		Ω(Design.Errors["maintenance"].IsProblem()).Should(BeTrue())
+		mt := Design.MediaTypeWithIdentifier(ProblemMediaIdentifier)
+		Ω(mt).Should(Equal(ProblemMedia))
+		Ω(mt.IsError()).Should(BeTrue())
+		Ω(Design.MediaTypeWithIdentifier(ErrorMediaIdentifier)).Should(BeNil())
+	})
+})
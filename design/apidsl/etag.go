@@ -0,0 +1,34 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ETag indicates that responses carry an entity tag so that clients may make conditional requests.
+// ETag may appear in MediaType, in which case it applies to all the actions that respond with the
+// media type, or in Action:
+//
+//	var BottleMedia = MediaType("application/vnd.bottle", func() {
+//		ETag()
+//		Attributes(func() {
+//			Attribute("id", Integer)
+//		})
+//		View("default", func() {
+//			Attribute("id")
+//		})
+//	})
+//
+// goagen generates SetETag and CheckPreconditions helpers on the contexts of the actions, mounts
+// the middleware given to the service UseETag method on their handlers and adds If-Match and
+// If-None-Match conditions to the client methods.
+func ETag() {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.MediaTypeDefinition:
+		def.ETag = true
+	case *design.ActionDefinition:
+		def.ETag = true
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,50 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ETag", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+		bottle := MediaType("application/vnd.bottle", func() {
+			ETag()
+			Attributes(func() {
+				Attribute("id", Integer)
+			})
+			View("default", func() {
+				Attribute("id")
+			})
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK, bottle)
+			})
+			Action("update", func() {
+				Routing(PUT("/:id"))
+				ETag()
+				Response(NoContent)
+			})
+			Action("delete", func() {
+				Routing(DELETE("/:id"))
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("marks the media types and actions", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.MediaTypeWithIdentifier("application/vnd.bottle").ETag).Should(BeTrue())
+		actions := Design.Resources["bottle"].Actions
+		Ω(actions["show"].ETag).Should(BeFalse())
+		Ω(actions["show"].HasETag()).Should(BeTrue())
+		Ω(actions["update"].HasETag()).Should(BeTrue())
+		Ω(actions["delete"].HasETag()).Should(BeFalse())
+	})
+})
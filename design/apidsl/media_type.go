@@ -109,6 +109,7 @@ func MediaType(identifier string, apidsl func()) *design.MediaTypeDefinition {
 	}
 	// Now save the type in the API media types map
 	mt := design.NewMediaTypeDefinition(typeName, identifier, apidsl)
+	dslengine.RecordLocation(mt)
 	design.Design.MediaTypes[canonicalID] = mt
 	return mt
 }
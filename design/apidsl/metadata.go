@@ -66,6 +66,12 @@ import (
 //
 //        Metadata("swagger:extension:x-api", `{"foo":"bar"}`)
 //
+// `lint:ignore`: disables the listed goagen lint rules for the definition and its children. All
+// rules are disabled if no value is given.
+// Applicable to all definitions that support metadata.
+//
+//        Metadata("lint:ignore", "error-response", "description")
+//
 // The special key names listed above may be used as follows:
 //
 //        var Account = Type("Account", func() {
@@ -104,6 +110,8 @@ func Metadata(name string, value ...string) {
 		def.Metadata = appendMetadata(def.Metadata, name, value...)
 	case *design.RouteDefinition:
 		def.Metadata = appendMetadata(def.Metadata, name, value...)
+	case *design.CallbackDefinition:
+		def.Metadata = appendMetadata(def.Metadata, name, value...)
 	case *design.SecurityDefinition:
 		def.Scheme.Metadata = appendMetadata(def.Scheme.Metadata, name, value...)
 	default:
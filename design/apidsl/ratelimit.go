@@ -0,0 +1,49 @@
+package apidsl
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// RateLimit limits the number of requests a client may make in the given period of time.
+// RateLimit may appear in API, Resource or Action. A rate limit defined in API or Resource is
+// shared by all the actions that do not define their own: requests made to any of these actions
+// count against the same limit. Example:
+//
+//	Resource("bottle", func() {
+//		RateLimit(1000, time.Hour)
+//		Action("create", func() {
+//			Routing(POST(""))
+//			RateLimit(10, time.Minute)
+//		})
+//	})
+//
+// goagen mounts the rate limiter given to the service UseRateLimiter method on the handlers of
+// the rate limited actions and documents the 429 responses in the Swagger specification. See the
+// middleware/ratelimit package for the limiter implementations.
+func RateLimit(requests int, per time.Duration) {
+	var rl **design.RateLimitDefinition
+	var parent dslengine.Definition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		rl, parent = &def.RateLimit, def
+	case *design.ResourceDefinition:
+		rl, parent = &def.RateLimit, def
+	case *design.ActionDefinition:
+		rl, parent = &def.RateLimit, def
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if requests <= 0 {
+		dslengine.ReportError("invalid number of requests %d, must be strictly positive", requests)
+		return
+	}
+	if per <= 0 {
+		dslengine.ReportError("invalid period %s, must be strictly positive", per)
+		return
+	}
+	*rl = &design.RateLimitDefinition{Requests: requests, Period: per, Parent: parent}
+}
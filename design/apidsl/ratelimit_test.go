@@ -0,0 +1,87 @@
+package apidsl_test
+
+import (
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimit", func() {
+	var requests int
+	var per time.Duration
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		requests = 10
+		per = time.Minute
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			RateLimit(10000, time.Hour)
+		})
+		Resource("bottle", func() {
+			RateLimit(1000, time.Hour)
+			Action("create", func() {
+				Routing(POST(""))
+				RateLimit(requests, per)
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+		})
+		Resource("account", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the effective rate limits of the actions", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		bottle := Design.Resources["bottle"]
+
+		rl := bottle.Actions["create"].EffectiveRateLimit()
+		Ω(rl).ShouldNot(BeNil())
+		Ω(rl.Requests).Should(Equal(10))
+		Ω(rl.Period).Should(Equal(time.Minute))
+		Ω(rl.Scope()).Should(Equal("bottle#create"))
+
+		rl = bottle.Actions["show"].EffectiveRateLimit()
+		Ω(rl).ShouldNot(BeNil())
+		Ω(rl.Requests).Should(Equal(1000))
+		Ω(rl.Scope()).Should(Equal("bottle"))
+
+		rl = Design.Resources["account"].Actions["show"].EffectiveRateLimit()
+		Ω(rl).ShouldNot(BeNil())
+		Ω(rl.Requests).Should(Equal(10000))
+		Ω(rl.Scope()).Should(BeEmpty())
+	})
+
+	Context("with an invalid number of requests", func() {
+		BeforeEach(func() {
+			requests = 0
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid number of requests 0"))
+		})
+	})
+
+	Context("with an invalid period", func() {
+		BeforeEach(func() {
+			per = 0
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid period 0s"))
+		})
+	})
+})
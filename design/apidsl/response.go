@@ -118,6 +118,55 @@ func Status(status int) {
 	}
 }
 
+// Stream indicates that the response body consists of a stream of instances of the given media
+// type rather than a single instance. The media type must be defined in the design, it may be
+// given as a pointer to its definition or via its identifier. The optional second argument
+// specifies how messages are framed in the response body: "text/event-stream" (the default)
+// sends each message as a Server-Sent Event while "application/x-ndjson" sends each message as
+// a JSON document terminated by a newline. An action may define at most one streamed response.
+//
+//	Action("watch", func() {
+//		Routing(GET("/events"))
+//		Response(OK, func() {
+//			Stream(EventMedia)                         // Server-Sent Events
+//		})
+//	})
+//
+//	Response(OK, func() {
+//		Stream(EventMedia, "application/x-ndjson") // newline delimited JSON
+//	})
+//
+// goagen generates a Stream method on the action context that starts the response and returns
+// a sender for the media type, a client decoder that iterates over the messages and a CLI
+// command that prints the messages as they arrive.
+//
+// Stream can be used inside Response or ResponseTemplate.
+func Stream(val interface{}, contentType ...string) {
+	r, ok := responseDefinition()
+	if !ok {
+		return
+	}
+	switch m := val.(type) {
+	case *design.MediaTypeDefinition:
+		if m != nil {
+			r.MediaType = m.Identifier
+		}
+	case string:
+		r.MediaType = m
+	default:
+		dslengine.ReportError("media type must be a string or a pointer to MediaTypeDefinition, got %#v", val)
+		return
+	}
+	switch len(contentType) {
+	case 0:
+		r.Stream = "text/event-stream"
+	case 1:
+		r.Stream = contentType[0]
+	default:
+		dslengine.ReportError("too many arguments given to Stream")
+	}
+}
+
 func executeResponseDSL(name string, paramsAndDSL ...interface{}) *design.ResponseDefinition {
 	var params []string
 	var dsl func()
@@ -177,6 +177,120 @@ var _ = Describe("Response", func() {
 		})
 	})
 
+	Context("with a status and cookies", func() {
+		const status = 200
+
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Status(status)
+				Cookies(func() {
+					Cookie("session", func() {
+						Metadata("cookie:max-age", "3600")
+					})
+				})
+			}
+		})
+
+		It("sets the cookies", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Cookies).ShouldNot(BeNil())
+			Ω(res.Cookies.Type.(Object)).Should(HaveKey("session"))
+			Ω(res.Dup().Cookies.Type.(Object)).Should(HaveKey("session"))
+		})
+	})
+
+	Context("with named examples", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Status(200)
+				Examples("empty", map[string]interface{}{})
+				Examples("full", map[string]interface{}{"name": "alice"})
+			}
+		})
+
+		It("sets the examples", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Examples).Should(HaveLen(2))
+			Ω(res.Examples[1].Name).Should(Equal("full"))
+			Ω(res.Dup().Examples).Should(HaveLen(2))
+		})
+	})
+
+	Context("with a stream", func() {
+		const status = 200
+		const identifier = "application/vnd.goa.event"
+
+		BeforeEach(func() {
+			name = "foo"
+			mt := MediaType(identifier, func() {
+				Attributes(func() {
+					Attribute("id", Integer)
+				})
+				View("default", func() {
+					Attribute("id")
+				})
+			})
+			dsl = func() {
+				Status(status)
+				Stream(mt)
+			}
+		})
+
+		It("sets the media type and defaults to server-sent events", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.MediaType).Should(Equal(identifier))
+			Ω(res.Stream).Should(Equal("text/event-stream"))
+		})
+
+		Context("using newline delimited JSON", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Status(status)
+					Stream(identifier, "application/x-ndjson")
+				}
+			})
+
+			It("sets the stream content type", func() {
+				Ω(res).ShouldNot(BeNil())
+				Ω(res.Validate()).ShouldNot(HaveOccurred())
+				Ω(res.Stream).Should(Equal("application/x-ndjson"))
+			})
+		})
+
+		Context("using an unsupported content type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Status(status)
+					Stream(identifier, "application/json")
+				}
+			})
+
+			It("produces an invalid response definition", func() {
+				Ω(res).ShouldNot(BeNil())
+				Ω(res.Validate()).Should(HaveOccurred())
+			})
+		})
+
+		Context("using an unknown media type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Status(status)
+					Stream("application/vnd.unknown")
+				}
+			})
+
+			It("produces an invalid response definition", func() {
+				Ω(res).ShouldNot(BeNil())
+				Ω(res.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Context("not from the goa default definitions", func() {
 		BeforeEach(func() {
 			name = "foo"
@@ -113,6 +113,7 @@ func BasicAuthSecurity(name string, dsl ...func()) *design.SecuritySchemeDefinit
 		def.DSLFunc = dsl[0]
 	}
 
+	dslengine.RecordLocation(def)
 	design.Design.SecuritySchemes = append(design.Design.SecuritySchemes, def)
 
 	return def
@@ -137,6 +138,12 @@ func securitySchemeRedefined(name string) bool {
 //          Header("Authorization")
 //    })
 //
+// The key may also be read from the query string with Query or from a cookie with Cookie:
+//
+//     APIKeySecurity("session", func() {
+//          Cookie("session_id")
+//    })
+//
 func APIKeySecurity(name string, dsl ...func()) *design.SecuritySchemeDefinition {
 	switch dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition, *dslengine.TopLevelDefinition:
@@ -159,6 +166,7 @@ func APIKeySecurity(name string, dsl ...func()) *design.SecuritySchemeDefinition
 		def.DSLFunc = dsl[0]
 	}
 
+	dslengine.RecordLocation(def)
 	design.Design.SecuritySchemes = append(design.Design.SecuritySchemes, def)
 
 	return def
@@ -209,6 +217,7 @@ func OAuth2Security(name string, dsl ...func()) *design.SecuritySchemeDefinition
 		def.DSLFunc = dsl[0]
 	}
 
+	dslengine.RecordLocation(def)
 	design.Design.SecuritySchemes = append(design.Design.SecuritySchemes, def)
 
 	return def
@@ -251,6 +260,7 @@ func JWTSecurity(name string, dsl ...func()) *design.SecuritySchemeDefinition {
 		def.DSLFunc = dsl[0]
 	}
 
+	dslengine.RecordLocation(def)
 	design.Design.SecuritySchemes = append(design.Design.SecuritySchemes, def)
 
 	return def
@@ -289,7 +299,7 @@ func inHeader(headerName string) {
 	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
 		if current.Kind == design.APIKeySecurityKind || current.Kind == design.JWTSecurityKind {
 			if current.In != "" {
-				dslengine.ReportError("'In' previously defined through Header or Query")
+				dslengine.ReportError("'In' previously defined through Header, Query or Cookie")
 				return
 			}
 			current.In = "header"
@@ -306,7 +316,7 @@ func Query(parameterName string) {
 	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
 		if current.Kind == design.APIKeySecurityKind || current.Kind == design.JWTSecurityKind {
 			if current.In != "" {
-				dslengine.ReportError("'In' previously defined through Header or Query")
+				dslengine.ReportError("'In' previously defined through Header, Query or Cookie")
 				return
 			}
 			current.In = "query"
@@ -317,6 +327,22 @@ func Query(parameterName string) {
 	dslengine.IncompatibleDSL()
 }
 
+// inCookie is called by `Cookie()`, see documentation there.
+func inCookie(cookieName string) {
+	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+		if current.Kind == design.APIKeySecurityKind || current.Kind == design.JWTSecurityKind {
+			if current.In != "" {
+				dslengine.ReportError("'In' previously defined through Header, Query or Cookie")
+				return
+			}
+			current.In = "cookie"
+			current.Name = cookieName
+			return
+		}
+	}
+	dslengine.IncompatibleDSL()
+}
+
 // AccessCodeFlow defines an "access code" OAuth2 flow.  Use within an OAuth2Security definition.
 func AccessCodeFlow(authorizationURL, tokenURL string) {
 	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
@@ -175,6 +175,33 @@ var _ = Describe("Security", func() {
 
 	})
 
+	Context("with cookie based security", func() {
+		It("should read the key from the cookie", func() {
+			API("", func() {
+				APIKeySecurity("session", func() {
+					Cookie("session_id")
+				})
+			})
+			dslengine.Run()
+
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.SecuritySchemes).Should(HaveLen(1))
+			Ω(Design.SecuritySchemes[0].In).Should(Equal("cookie"))
+			Ω(Design.SecuritySchemes[0].Name).Should(Equal("session_id"))
+		})
+
+		It("should fail because of duplicate In declaration", func() {
+			API("", func() {
+				JWTSecurity("jwt", func() {
+					Header("Authorization")
+					Cookie("jwt")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with resources and actions", func() {
 		It("should fallback properly to lower-level security", func() {
 			API("", func() {
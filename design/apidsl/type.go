@@ -55,6 +55,7 @@ func Type(name string, dsl func()) *design.UserTypeDefinition {
 	} else {
 		t.Type = make(design.Object)
 	}
+	dslengine.RecordLocation(t)
 	design.Design.Types[name] = t
 	return t
 }
@@ -133,3 +134,84 @@ func HashOf(k, v design.DataType) *design.Hash {
 	vat := design.AttributeDefinition{Type: v}
 	return &design.Hash{KeyType: &kat, ElemType: &vat}
 }
+
+// OneOf defines a union type: values of the type may be any one of the given user types. OneOf
+// must appear in a Type definition and accepts user types or user type names. The union must also
+// define the name of the attribute used to identify the actual type of a value with Discriminator.
+// Each union type must define the discriminator as a required string attribute whose value is the
+// name of the type. Example:
+//
+//	var Notification = Type("Notification", func() {
+//		Description("Notification is either an email or a SMS notification")
+//		OneOf(EmailNotification, "SMSNotification")
+//		Discriminator("type")
+//	})
+//
+//	var EmailNotification = Type("EmailNotification", func() {
+//		Attribute("type", String, "Always EmailNotification")
+//		Attribute("address", String, func() {
+//			Format("email")
+//		})
+//		Required("type", "address")
+//	})
+//
+// See http://json-schema.org/latest/json-schema-validation.html#anchor88.
+func OneOf(types ...interface{}) {
+	a, ok := attributeDefinition()
+	if !ok {
+		return
+	}
+	if !isTypeDefinition(a) {
+		dslengine.ReportError("OneOf must be used in a Type definition")
+		return
+	}
+	union, ok := a.Type.(*design.Union)
+	if !ok {
+		if o, ok := a.Type.(design.Object); a.Type != nil && (!ok || len(o) > 0) {
+			dslengine.ReportError("OneOf cannot be used on a type that defines attributes")
+			return
+		}
+		union = &design.Union{}
+	}
+	for i, t := range types {
+		var ut *design.UserTypeDefinition
+		switch actual := t.(type) {
+		case *design.UserTypeDefinition:
+			ut = actual
+		case string:
+			ut = design.Design.Types[actual]
+		}
+		if ut == nil {
+			dslengine.ReportError("invalid OneOf argument at index %d: not a user type and not a known user type name", i)
+			continue
+		}
+		union.Types = append(union.Types, ut)
+	}
+	a.Type = union
+}
+
+// Discriminator sets the name of the attribute that identifies the actual type of a union type
+// value, see OneOf.
+func Discriminator(name string) {
+	a, ok := attributeDefinition()
+	if !ok {
+		return
+	}
+	union, ok := a.Type.(*design.Union)
+	if !ok {
+		dslengine.ReportError("Discriminator must follow OneOf")
+		return
+	}
+	union.Discriminator = name
+}
+
+// isTypeDefinition returns true if the given attribute is the attribute backing a user type
+// definition.
+func isTypeDefinition(a *design.AttributeDefinition) bool {
+	for _, ut := range design.Design.Types {
+		if ut.AttributeDefinition == a {
+			return true
+		}
+	}
+	return false
+}
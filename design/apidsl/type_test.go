@@ -226,3 +226,93 @@ var _ = Describe("ArrayOf", func() {
 		})
 	})
 })
+
+var _ = Describe("OneOf", func() {
+	var ut *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		Type("email", func() {
+			Attribute("kind")
+			Attribute("address")
+			Required("kind", "address")
+		})
+		Type("sms", func() {
+			Attribute("kind")
+			Attribute("phone")
+			Required("kind")
+		})
+	})
+
+	Context("with a discriminator", func() {
+		BeforeEach(func() {
+			ut = Type("notification", func() {
+				OneOf("email", "sms")
+				Discriminator("kind")
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("produces a union type", func() {
+			Ω(ut.Type).Should(BeAssignableToTypeOf(&Union{}))
+			Ω(ut.IsUnion()).Should(BeTrue())
+			u := ut.ToUnion()
+			Ω(u.Kind()).Should(Equal(UnionKind))
+			Ω(u.Discriminator).Should(Equal("kind"))
+			Ω(u.Types).Should(HaveLen(2))
+			Ω(u.Types[0]).Should(Equal(Design.Types["email"]))
+			Ω(u.Types[1]).Should(Equal(Design.Types["sms"]))
+			Ω(u.Values()).Should(Equal([]interface{}{"email", "sms"}))
+		})
+	})
+
+	Context("with no discriminator", func() {
+		BeforeEach(func() {
+			ut = Type("notification", func() {
+				OneOf("email", "sms")
+			})
+		})
+
+		It("produces an invalid type definition", func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a type that does not require the discriminator", func() {
+		BeforeEach(func() {
+			Type("push", func() {
+				Attribute("kind")
+			})
+			ut = Type("notification", func() {
+				OneOf("email", "push")
+				Discriminator("kind")
+			})
+		})
+
+		It("produces an invalid type definition", func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("used in an attribute", func() {
+		BeforeEach(func() {
+			Type("holder", func() {
+				Attribute("notification", func() {
+					OneOf("email", "sms")
+					Discriminator("kind")
+				})
+			})
+		})
+
+		It("reports an error", func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/dimfeld/httppath"
 	"github.com/goadesign/goa/dslengine"
@@ -73,6 +74,17 @@ type (
 		Security *SecurityDefinition
 		// NoExamples indicates whether to bypass automatic example generation.
 		NoExamples bool
+		// Callbacks lists the callbacks sent by the API that are not tied to a specific
+		// action indexed by name.
+		Callbacks map[string]*CallbackDefinition
+		// Errors lists the errors that may be returned by all the API actions indexed by
+		// name.
+		Errors map[string]*ErrorDefinition
+		// RateLimit is the rate limit shared by all the API actions if any.
+		RateLimit *RateLimitDefinition
+		// ProblemDetails is true if the API renders errors as RFC 7807 problem details, in
+		// which case the responses and errors that use ErrorMedia use ProblemMedia instead.
+		ProblemDetails bool
 
 		// rand is the random generator used to generate examples.
 		rand *RandomGenerator
@@ -134,6 +146,8 @@ type (
 		Responses map[string]*ResponseDefinition
 		// Request headers that apply to all actions.
 		Headers *AttributeDefinition
+		// Request cookies that apply to all actions.
+		Cookies *AttributeDefinition
 		// Origins defines the CORS policies that apply to this resource.
 		Origins map[string]*CORSDefinition
 		// DSLFunc contains the DSL used to create this definition if any.
@@ -143,6 +157,14 @@ type (
 		// Security defines security requirements for the Resource,
 		// for actions that don't define one themselves.
 		Security *SecurityDefinition
+		// RateLimit is the rate limit shared by all the resource actions if any.
+		RateLimit *RateLimitDefinition
+		// Consumes lists the mime types of the request bodies accepted by the resource
+		// actions, a subset of the API mime types.
+		Consumes []*EncodingDefinition
+		// Produces lists the mime types of the response bodies generated by the resource
+		// actions, a subset of the API mime types.
+		Produces []*EncodingDefinition
 	}
 
 	// CORSDefinition contains the definition for a specific origin CORS policy.
@@ -195,14 +217,23 @@ type (
 		MediaType string
 		// Response view name if MediaType is MediaTypeDefinition
 		ViewName string
+		// Stream is the content type of the response stream, either "text/event-stream" or
+		// "application/x-ndjson". Stream is empty if the response body is not streamed.
+		Stream string
 		// Response header definitions
 		Headers *AttributeDefinition
+		// Response cookie definitions
+		Cookies *AttributeDefinition
 		// Parent action or resource
 		Parent dslengine.Definition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Standard is true if the response definition comes from the goa default responses
 		Standard bool
+		// Examples lists the named examples of the response body.
+		Examples []*ExampleDefinition
+		// Cache is the cache policy of the response if any.
+		Cache *CacheDefinition
 	}
 
 	// ResponseTemplateDefinition defines a response template.
@@ -244,12 +275,121 @@ type (
 		Payload *UserTypeDefinition
 		// PayloadOptional is true if the request payload is optional, false otherwise.
 		PayloadOptional bool
+		// PayloadMultipart is true if the request payload is encoded using multipart form
+		// data, false otherwise.
+		PayloadMultipart bool
+		// Pagination is the pagination style of the action, either CursorPagination or
+		// OffsetPagination. Pagination is empty if the action is not paginated.
+		Pagination string
+		// Idempotent is true if the action accepts an Idempotency-Key request header that
+		// makes it safe for clients to retry requests.
+		Idempotent bool
+		// ETag is true if the action responses carry an entity tag and the action supports
+		// conditional requests.
+		ETag bool
 		// Request headers that need to be made available to action
 		Headers *AttributeDefinition
+		// Request cookies that need to be made available to action
+		Cookies *AttributeDefinition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Deprecation is set if the action is deprecated.
+		Deprecation *DeprecationDefinition
+		// Callbacks lists the callbacks sent as a result of calling the action indexed by
+		// name.
+		Callbacks map[string]*CallbackDefinition
+		// Errors lists the errors that may be returned by the action indexed by name.
+		Errors map[string]*ErrorDefinition
+		// RateLimit is the rate limit of the action if any.
+		RateLimit *RateLimitDefinition
+		// Cache is the cache policy of the action successful responses if any.
+		Cache *CacheDefinition
+		// Consumes lists the mime types of the request bodies accepted by the action, a
+		// subset of the API mime types.
+		Consumes []*EncodingDefinition
+		// Produces lists the mime types of the response bodies generated by the action, a
+		// subset of the API mime types.
+		Produces []*EncodingDefinition
+	}
+
+	// ErrorDefinition describes an error that may be returned by an action.
+	ErrorDefinition struct {
+		// Name of the error, e.g. "out_of_stock". The name is the error code used in
+		// responses.
+		Name string
+		// Description of the error
+		Description string
+		// Status is the HTTP status code of the error responses.
+		Status int
+		// MediaType is the identifier of the media type that describes the error response
+		// bodies.
+		MediaType string
+		// Parent is the API or action definition that defines the error.
+		Parent dslengine.Definition
+	}
+
+	// CallbackDefinition describes a request sent by the API to the URL registered by a
+	// subscriber, also known as webhook.
+	CallbackDefinition struct {
+		// Name of the callback, e.g. "order_shipped"
+		Name string
+		// Description of the callback
+		Description string
+		// Parent is the API or action definition that defines the callback.
+		Parent dslengine.Definition
+		// URL is the runtime expression that computes the subscriber URL in the OpenAPI
+		// specification, e.g. "{$request.body#/callback_url}".
+		URL string
+		// Payload describes the request body if any.
+		Payload *UserTypeDefinition
+		// Metadata is a list of key/value pairs
+		Metadata dslengine.MetadataDefinition
+	}
+
+	// DeprecationDefinition describes the deprecation of an action, route or attribute.
+	DeprecationDefinition struct {
+		// Reason explains why the definition is deprecated and what to use instead.
+		Reason string
+		// Sunset is the date after which the definition may stop working, zero if not
+		// known.
+		Sunset time.Time
+	}
+
+	// RateLimitDefinition describes the maximum number of requests a client may make in a given
+	// period of time.
+	RateLimitDefinition struct {
+		// Requests is the maximum number of requests allowed in Period.
+		Requests int
+		// Period is the duration of the rate limit window.
+		Period time.Duration
+		// Parent is the API, resource or action definition that defines the rate limit.
+		Parent dslengine.Definition
+	}
+
+	// CacheDefinition describes the cache policy of HTTP responses: the value of their
+	// Cache-Control and Vary headers.
+	CacheDefinition struct {
+		// MaxAge is the number of seconds the response may be cached.
+		MaxAge uint
+		// Private is true if the response may only be cached by the client and not by
+		// shared caches.
+		Private bool
+		// NoStore is true if the response may not be cached at all.
+		NoStore bool
+		// Vary lists the request headers that select the cached representation.
+		Vary []string
+		// Parent is the action, response or file server definition that defines the policy.
+		Parent dslengine.Definition
+	}
+
+	// ExampleDefinition describes a named example of an attribute or response.
+	ExampleDefinition struct {
+		// Name identifies the example, e.g. "minimal" or "full".
+		Name string
+		// Value is the example value.
+		Value interface{}
 	}
 
 	// FileServerDefinition defines an endpoint that servers static assets.
@@ -268,6 +408,8 @@ type (
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the file server.
 		Security *SecurityDefinition
+		// Cache is the cache policy of the served files if any.
+		Cache *CacheDefinition
 	}
 
 	// LinkDefinition defines a media type link, it specifies a URL to a related resource.
@@ -306,6 +448,8 @@ type (
 		Parent *ActionDefinition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
+		// Deprecation is set if the route is deprecated.
+		Deprecation *DeprecationDefinition
 	}
 
 	// AttributeDefinition defines a JSON object member with optional description, default
@@ -325,6 +469,8 @@ type (
 		DefaultValue interface{}
 		// Optional member example value
 		Example interface{}
+		// Examples lists the named examples of the attribute.
+		Examples []*ExampleDefinition
 		// Optional view used to render Attribute (only applies to media type attributes).
 		View string
 		// NonZeroAttributes lists the names of the child attributes that cannot have a
@@ -332,6 +478,14 @@ type (
 		NonZeroAttributes map[string]bool
 		// DSLFunc contains the initialization DSL. This is used for user types.
 		DSLFunc func()
+		// Deprecation is set if the attribute is deprecated.
+		Deprecation *DeprecationDefinition
+		// ReadOnly is true if the attribute value is set by the service and is ignored when
+		// sent by clients in request payloads.
+		ReadOnly bool
+		// WriteOnly is true if the attribute value is only sent by clients in request
+		// payloads and is never rendered in responses.
+		WriteOnly bool
 	}
 
 	// ContainerDefinition defines a generic container definition that contains attributes.
@@ -361,6 +515,12 @@ type (
 
 	// ResponseIterator is the type of functions given to IterateResponses.
 	ResponseIterator func(r *ResponseDefinition) error
+
+	// CallbackIterator is the type of functions given to IterateCallbacks.
+	CallbackIterator func(c *CallbackDefinition) error
+
+	// ErrorIterator is the type of functions given to IterateErrors.
+	ErrorIterator func(e *ErrorDefinition) error
 )
 
 // NewAPIDefinition returns a new design with built-in response templates.
@@ -570,6 +730,43 @@ func (a *APIDefinition) IterateResponses(it ResponseIterator) error {
 	return nil
 }
 
+// IterateCallbacks calls the given iterator passing in each callback sorted in alphabetical order.
+// The API level callbacks come first followed by the callbacks of each action. Iteration stops if
+// an iterator returns an error and in this case IterateCallbacks returns that error.
+func (a *APIDefinition) IterateCallbacks(it CallbackIterator) error {
+	if err := iterateCallbacks(a.Callbacks, it); err != nil {
+		return err
+	}
+	return a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(action *ActionDefinition) error {
+			return action.IterateCallbacks(it)
+		})
+	})
+}
+
+// IterateErrors calls the given iterator passing in each error sorted in alphabetical order. The
+// API level errors come first followed by the errors of each action. Errors defined by multiple
+// actions are only iterated once. Iteration stops if an iterator returns an error and in this case
+// IterateErrors returns that error.
+func (a *APIDefinition) IterateErrors(it ErrorIterator) error {
+	seen := make(map[string]bool)
+	iter := func(e *ErrorDefinition) error {
+		if seen[e.Name] {
+			return nil
+		}
+		seen[e.Name] = true
+		return it(e)
+	}
+	if err := iterateErrors(a.Errors, iter); err != nil {
+		return err
+	}
+	return a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(action *ActionDefinition) error {
+			return iterateErrors(action.Errors, iter)
+		})
+	})
+}
+
 // RandomGenerator is seeded after the API name. It's used to generate examples.
 func (a *APIDefinition) RandomGenerator() *RandomGenerator {
 	if a.rand == nil {
@@ -625,6 +822,9 @@ func (a *APIDefinition) Finalize() {
 	if len(a.Produces) == 0 {
 		a.Produces = DefaultEncoders
 	}
+	if a.ProblemDetails {
+		a.useProblemMedia()
+	}
 	found := false
 	a.IterateResources(func(r *ResourceDefinition) error {
 		if found {
@@ -635,11 +835,7 @@ func (a *APIDefinition) Finalize() {
 				return nil
 			}
 			for _, resp := range action.Responses {
-				if resp.MediaType == ErrorMediaIdentifier {
-					if a.MediaTypes == nil {
-						a.MediaTypes = make(map[string]*MediaTypeDefinition)
-					}
-					a.MediaTypes[CanonicalIdentifier(ErrorMediaIdentifier)] = ErrorMedia
+				if isErrorMediaIdentifier(resp.MediaType) {
 					found = true
 					break
 				}
@@ -647,6 +843,60 @@ func (a *APIDefinition) Finalize() {
 			return nil
 		})
 	})
+	a.IterateErrors(func(e *ErrorDefinition) error {
+		if isErrorMediaIdentifier(e.MediaType) {
+			found = true
+		}
+		return nil
+	})
+	if found {
+		if a.MediaTypes == nil {
+			a.MediaTypes = make(map[string]*MediaTypeDefinition)
+		}
+		mt := a.ErrorMedia()
+		a.MediaTypes[CanonicalIdentifier(mt.Identifier)] = mt
+	}
+}
+
+// ErrorMedia returns the built-in media type used by the API error responses: ProblemMedia if the
+// API renders errors as problem details, ErrorMedia otherwise.
+func (a *APIDefinition) ErrorMedia() *MediaTypeDefinition {
+	if a.ProblemDetails {
+		return ProblemMedia
+	}
+	return ErrorMedia
+}
+
+// useProblemMedia replaces ErrorMedia with ProblemMedia in the responses and errors defined by
+// the API, its resources and actions.
+func (a *APIDefinition) useProblemMedia() {
+	responses := func(resps map[string]*ResponseDefinition) {
+		for _, r := range resps {
+			if CanonicalIdentifier(r.MediaType) == CanonicalIdentifier(ErrorMediaIdentifier) {
+				r.MediaType = ProblemMediaIdentifier
+			}
+			if r.Type == ErrorMedia {
+				r.Type = ProblemMedia
+			}
+		}
+	}
+	errors := func(errs map[string]*ErrorDefinition) {
+		for _, e := range errs {
+			if CanonicalIdentifier(e.MediaType) == CanonicalIdentifier(ErrorMediaIdentifier) {
+				e.MediaType = ProblemMediaIdentifier
+			}
+		}
+	}
+	responses(a.Responses)
+	errors(a.Errors)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		responses(r.Responses)
+		return r.IterateActions(func(action *ActionDefinition) error {
+			responses(action.Responses)
+			errors(action.Errors)
+			return nil
+		})
+	})
 }
 
 // NewResourceDefinition creates a resource definition but does not
@@ -967,7 +1217,8 @@ func (a *AttributeDefinition) IsNonZero(attName string) bool {
 }
 
 // IsPrimitivePointer returns true if the field generated for the given attribute should be a
-// pointer to a primitive type. The target attribute must be an object.
+// pointer to a primitive type. The target attribute must be an object. File attributes are always
+// pointers.
 func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if !a.Type.IsObject() {
 		panic("checking pointer field on non-object") // bug
@@ -976,6 +1227,9 @@ func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if att == nil {
 		return false
 	}
+	if att.Type.Kind() == FileKind {
+		return true
+	}
 	if att.Type.IsPrimitive() {
 		return !a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName)
 	}
@@ -1181,6 +1435,11 @@ func (a *AttributeDefinition) inheritRecursive(parent *AttributeDefinition) {
 			if att.Example == nil {
 				att.Example = patt.Example
 			}
+			if att.Examples == nil {
+				att.Examples = patt.Examples
+			}
+			att.ReadOnly = att.ReadOnly || patt.ReadOnly
+			att.WriteOnly = att.WriteOnly || patt.WriteOnly
 		}
 	}
 }
@@ -1264,6 +1523,19 @@ func (r *ResponseDefinition) Finalize() {
 	r.MediaType = mt.Identifier
 }
 
+// EffectiveCache returns the cache policy of the response: the policy defined on the response
+// itself if any, the policy defined on the parent action if the response status is 2xx, nil
+// otherwise.
+func (r *ResponseDefinition) EffectiveCache() *CacheDefinition {
+	if r.Cache != nil {
+		return r.Cache
+	}
+	if a, ok := r.Parent.(*ActionDefinition); ok && r.Status >= 200 && r.Status < 300 {
+		return a.Cache
+	}
+	return nil
+}
+
 // Dup returns a copy of the response definition.
 func (r *ResponseDefinition) Dup() *ResponseDefinition {
 	res := ResponseDefinition{
@@ -1272,10 +1544,18 @@ func (r *ResponseDefinition) Dup() *ResponseDefinition {
 		Description: r.Description,
 		MediaType:   r.MediaType,
 		ViewName:    r.ViewName,
+		Stream:      r.Stream,
+		Cache:       r.Cache,
 	}
 	if r.Headers != nil {
 		res.Headers = DupAtt(r.Headers)
 	}
+	if r.Cookies != nil {
+		res.Cookies = DupAtt(r.Cookies)
+	}
+	if r.Examples != nil {
+		res.Examples = append([]*ExampleDefinition{}, r.Examples...)
+	}
 	return &res
 }
 
@@ -1293,9 +1573,13 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 	if r.Description == "" {
 		r.Description = other.Description
 	}
+	if r.Cache == nil {
+		r.Cache = other.Cache
+	}
 	if r.MediaType == "" {
 		r.MediaType = other.MediaType
 		r.ViewName = other.ViewName
+		r.Stream = other.Stream
 	}
 	if other.Headers != nil {
 		otherHeaders := other.Headers.Type.ToObject()
@@ -1311,6 +1595,23 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 			}
 		}
 	}
+	if other.Cookies != nil {
+		otherCookies := other.Cookies.Type.ToObject()
+		if len(otherCookies) > 0 {
+			if r.Cookies == nil {
+				r.Cookies = &AttributeDefinition{Type: Object{}}
+			}
+			cookies := r.Cookies.Type.ToObject()
+			for n, c := range otherCookies {
+				if _, ok := cookies[n]; !ok {
+					cookies[n] = c
+				}
+			}
+		}
+	}
+	if r.Examples == nil {
+		r.Examples = other.Examples
+	}
 }
 
 // Context returns the generic definition name used in error messages.
@@ -1432,6 +1733,33 @@ func (a *ActionDefinition) WebSocket() bool {
 	return true
 }
 
+// HasETag returns true if the action responses carry an entity tag, either because the action
+// uses the ETag DSL or because one of its responses uses a media type that does.
+func (a *ActionDefinition) HasETag() bool {
+	if a.ETag {
+		return true
+	}
+	for _, r := range a.Responses {
+		if r.MediaType == "" || Design == nil {
+			continue
+		}
+		if mt := Design.MediaTypeWithIdentifier(r.MediaType); mt != nil && mt.ETag {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamResponse returns the action response whose body is streamed if any, nil otherwise.
+func (a *ActionDefinition) StreamResponse() *ResponseDefinition {
+	for _, r := range a.Responses {
+		if r.Stream != "" {
+			return r
+		}
+	}
+	return nil
+}
+
 // Finalize inherits security scheme and action responses from parent and top level design.
 func (a *ActionDefinition) Finalize() {
 	// Inherit security scheme
@@ -1513,6 +1841,29 @@ func (a *ActionDefinition) IterateResponses(it ResponseIterator) error {
 	return nil
 }
 
+// IterateCallbacks calls the given iterator passing in each action callback sorted in alphabetical
+// order. Iteration stops if an iterator returns an error and in this case IterateCallbacks returns
+// that error.
+func (a *ActionDefinition) IterateCallbacks(it CallbackIterator) error {
+	return iterateCallbacks(a.Callbacks, it)
+}
+
+// IterateErrors calls the given iterator passing in each error that may be returned by the action
+// sorted in alphabetical order. This includes the errors defined at the API level. Iteration stops
+// if an iterator returns an error and in this case IterateErrors returns that error.
+func (a *ActionDefinition) IterateErrors(it ErrorIterator) error {
+	errs := make(map[string]*ErrorDefinition)
+	if Design != nil {
+		for n, e := range Design.Errors {
+			errs[n] = e
+		}
+	}
+	for n, e := range a.Errors {
+		errs[n] = e
+	}
+	return iterateErrors(errs, it)
+}
+
 // mergeResponses merges the parent resource and design responses.
 func (a *ActionDefinition) mergeResponses() {
 	for name, resp := range a.Parent.Responses {
@@ -1520,7 +1871,9 @@ func (a *ActionDefinition) mergeResponses() {
 			if a.Responses == nil {
 				a.Responses = make(map[string]*ResponseDefinition)
 			}
-			a.Responses[name] = resp.Dup()
+			dup := resp.Dup()
+			dup.Parent = a
+			a.Responses[name] = dup
 		}
 	}
 	for name, resp := range a.Responses {
@@ -1647,6 +2000,79 @@ func (b ByFilePath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
 func (b ByFilePath) Len() int           { return len(b) }
 func (b ByFilePath) Less(i, j int) bool { return b[i].FilePath < b[j].FilePath }
 
+// Context returns the generic definition name used in error messages.
+func (c *CallbackDefinition) Context() string {
+	var prefix, suffix string
+	if c.Name != "" {
+		suffix = fmt.Sprintf("callback %#v", c.Name)
+	} else {
+		suffix = "unnamed callback"
+	}
+	if c.Parent != nil {
+		prefix = c.Parent.Context() + " "
+	}
+	return prefix + suffix
+}
+
+// Context returns the generic definition name used in error messages.
+func (e *ErrorDefinition) Context() string {
+	var prefix, suffix string
+	if e.Name != "" {
+		suffix = fmt.Sprintf("error %#v", e.Name)
+	} else {
+		suffix = "unnamed error"
+	}
+	if e.Parent != nil {
+		prefix = e.Parent.Context() + " "
+	}
+	return prefix + suffix
+}
+
+// IsError returns true if the error response bodies are described by the built-in error media
+// type.
+func (e *ErrorDefinition) IsError() bool {
+	return isErrorMediaIdentifier(e.MediaType)
+}
+
+// IsProblem returns true if the error response bodies are RFC 7807 problem details.
+func (e *ErrorDefinition) IsProblem() bool {
+	return CanonicalIdentifier(e.MediaType) == CanonicalIdentifier(ProblemMediaIdentifier)
+}
+
+// iterateErrors calls the given iterator passing in each error sorted by name.
+func iterateErrors(errs map[string]*ErrorDefinition, it ErrorIterator) error {
+	names := make([]string, len(errs))
+	i := 0
+	for n := range errs {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := it(errs[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterateCallbacks calls the given iterator passing in each callback sorted by name.
+func iterateCallbacks(callbacks map[string]*CallbackDefinition, it CallbackIterator) error {
+	names := make([]string, len(callbacks))
+	i := 0
+	for n := range callbacks {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := it(callbacks[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Context returns the generic definition name used in error messages.
 func (l *LinkDefinition) Context() string {
 	var prefix, suffix string
@@ -1723,6 +2149,123 @@ func (r *RouteDefinition) IsAbsolute() bool {
 	return strings.HasPrefix(r.Path, "//")
 }
 
+// EffectiveDeprecation returns the deprecation of the route if any or the deprecation of its
+// parent action otherwise.
+func (r *RouteDefinition) EffectiveDeprecation() *DeprecationDefinition {
+	if r.Deprecation != nil || r.Parent == nil {
+		return r.Deprecation
+	}
+	return r.Parent.Deprecation
+}
+
+// EffectiveRateLimit returns the rate limit of the action if any, the rate limit of its parent
+// resource otherwise or the API rate limit if the resource does not define one either.
+func (a *ActionDefinition) EffectiveRateLimit() *RateLimitDefinition {
+	if a.RateLimit != nil {
+		return a.RateLimit
+	}
+	if a.Parent != nil && a.Parent.RateLimit != nil {
+		return a.Parent.RateLimit
+	}
+	if Design != nil {
+		return Design.RateLimit
+	}
+	return nil
+}
+
+// EffectiveConsumes returns the mime types of the request bodies accepted by the action: the
+// types listed in the action Consumes DSL if any, in the parent resource Consumes DSL otherwise.
+// It returns nil if neither define Consumes in which case the action accepts all the API mime
+// types.
+func (a *ActionDefinition) EffectiveConsumes() []string {
+	if len(a.Consumes) > 0 {
+		return mimeTypes(a.Consumes)
+	}
+	if a.Parent != nil {
+		return mimeTypes(a.Parent.Consumes)
+	}
+	return nil
+}
+
+// EffectiveProduces returns the mime types of the response bodies generated by the action: the
+// types listed in the action Produces DSL if any, in the parent resource Produces DSL otherwise.
+// It returns nil if neither define Produces in which case the action generates all the API mime
+// types.
+func (a *ActionDefinition) EffectiveProduces() []string {
+	if len(a.Produces) > 0 {
+		return mimeTypes(a.Produces)
+	}
+	if a.Parent != nil {
+		return mimeTypes(a.Parent.Produces)
+	}
+	return nil
+}
+
+// mimeTypes returns the mime types listed by the given encoding definitions.
+func mimeTypes(encs []*EncodingDefinition) []string {
+	var res []string
+	for _, enc := range encs {
+		res = append(res, enc.MIMETypes...)
+	}
+	return res
+}
+
+// Context returns the generic definition name used in error messages.
+func (r *RateLimitDefinition) Context() string {
+	if r.Parent != nil {
+		return "rate limit of " + r.Parent.Context()
+	}
+	return "rate limit"
+}
+
+// Scope returns the name that identifies the requests counted against the rate limit: the empty
+// string for the API rate limit, the resource name for resource rate limits and the resource
+// and action names separated with "#" for action rate limits.
+func (r *RateLimitDefinition) Scope() string {
+	switch p := r.Parent.(type) {
+	case *ResourceDefinition:
+		return p.Name
+	case *ActionDefinition:
+		return p.Parent.Name + "#" + p.Name
+	default:
+		return ""
+	}
+}
+
+// Context returns the generic definition name used in error messages.
+func (c *CacheDefinition) Context() string {
+	if c.Parent != nil {
+		return "cache policy of " + c.Parent.Context()
+	}
+	return "cache policy"
+}
+
+// CacheControl returns the value of the Cache-Control response header.
+func (c *CacheDefinition) CacheControl() string {
+	if c.NoStore {
+		return "no-store"
+	}
+	directives := []string{"public"}
+	if c.Private {
+		directives[0] = "private"
+	}
+	if c.MaxAge > 0 {
+		directives = append(directives, fmt.Sprintf("max-age=%d", c.MaxAge))
+	} else {
+		directives = append(directives, "no-cache")
+	}
+	return strings.Join(directives, ", ")
+}
+
+// SunsetHeader returns the value of the Sunset response header as defined by RFC 8594, the
+// empty string if no sunset date is known.
+func (d *DeprecationDefinition) SunsetHeader() string {
+	if d.Sunset.IsZero() {
+		return ""
+	}
+	return d.Sunset.UTC().Format(http.TimeFormat)
+}
+
 func iterateHeaders(headers *AttributeDefinition, isRequired func(name string) bool, it HeaderIterator) error {
 	if headers == nil || !headers.Type.IsObject() {
 		return nil
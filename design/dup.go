@@ -50,6 +50,10 @@ func (d *dupper) DupAttribute(att *AttributeDefinition) *AttributeDefinition {
 		View:              att.View,
 		DSLFunc:           att.DSLFunc,
 		Example:           att.Example,
+		Examples:          att.Examples,
+		Deprecation:       att.Deprecation,
+		ReadOnly:          att.ReadOnly,
+		WriteOnly:         att.WriteOnly,
 	}
 	return &dup
 }
@@ -72,6 +76,12 @@ func (d *dupper) DupType(t DataType) DataType {
 			KeyType:  d.DupAttribute(actual.KeyType),
 			ElemType: d.DupAttribute(actual.ElemType),
 		}
+	case *Union:
+		types := make([]*UserTypeDefinition, len(actual.Types))
+		for i, ut := range actual.Types {
+			types[i] = d.DupType(ut).(*UserTypeDefinition)
+		}
+		return &Union{Types: types, Discriminator: actual.Discriminator}
 	case *UserTypeDefinition:
 		if u, ok := d.dts[actual.TypeName]; ok {
 			return u
@@ -49,6 +49,11 @@ func (r *RandomGenerator) String() string {
 
 }
 
+// FileName produces a random file name.
+func (r *RandomGenerator) FileName() string {
+	return r.faker.Words(1, false)[0] + ".txt"
+}
+
 // DateTime produces a random date.
 func (r *RandomGenerator) DateTime() time.Time {
 	// Use a constant max value to make sure the same pseudo random
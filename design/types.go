@@ -58,6 +58,12 @@ type (
 		// ToHash returns the underlying hash map if any (i.e. if IsHash returns true),
 		// nil otherwise.
 		ToHash() *Hash
+		// IsUnion returns true if the underlying type is a union or a user type which is a
+		// union.
+		IsUnion() bool
+		// ToUnion returns the underlying union if any (i.e. if IsUnion returns true), nil
+		// otherwise.
+		ToUnion() *Union
 		// CanHaveDefault returns whether the data type can have a default value.
 		CanHaveDefault() bool
 		// IsCompatible checks whether val has a Go type that is
@@ -106,6 +112,16 @@ type (
 	// HashVal is the value of a hash used to specify the default value.
 	HashVal map[interface{}]interface{}
 
+	// Union is the type for a JSON object that may be one of several user types. The actual
+	// type of a value is identified by the value of the discriminator attribute which must be
+	// the name of one of the union types.
+	Union struct {
+		// Types lists the user types that make up the union.
+		Types []*UserTypeDefinition
+		// Discriminator is the name of the attribute used to identify the actual type.
+		Discriminator string
+	}
+
 	// UserTypeDefinition is the type for user defined types that are not media types
 	// (e.g. payload types).
 	UserTypeDefinition struct {
@@ -135,6 +151,8 @@ type (
 		Views map[string]*ViewDefinition
 		// Resource this media type is the canonical representation for if any
 		Resource *ResourceDefinition
+		// ETag is true if the responses that use the media type carry an entity tag.
+		ETag bool
 	}
 )
 
@@ -163,6 +181,10 @@ const (
 	UserTypeKind
 	// MediaTypeKind represents a media type.
 	MediaTypeKind
+	// UnionKind represents a union of user types.
+	UnionKind
+	// FileKind represents a file uploaded in a multipart form.
+	FileKind
 )
 
 const (
@@ -188,6 +210,10 @@ const (
 
 	// Any is the type for an arbitrary JSON value (interface{} in Go).
 	Any = Primitive(AnyKind)
+
+	// File is the type for a file uploaded in a multipart form (*multipart.FileHeader in Go).
+	// File may only be used to define the attributes of multipart form payloads.
+	File = Primitive(FileKind)
 )
 
 // DataType implementation
@@ -208,6 +234,8 @@ func (p Primitive) Name() string {
 		return "string"
 	case Any:
 		return "any"
+	case File:
+		return "file"
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -237,6 +265,12 @@ func (p Primitive) ToArray() *Array { return nil }
 // ToHash returns nil.
 func (p Primitive) ToHash() *Hash { return nil }
 
+// IsUnion returns false.
+func (p Primitive) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (p Primitive) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns whether the primitive can have a default value.
 func (p Primitive) CanHaveDefault() (ok bool) {
 	switch p {
@@ -248,7 +282,7 @@ func (p Primitive) CanHaveDefault() (ok bool) {
 
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any && p != File {
 		panic("unknown primitive type") // bug
 	}
 	if p == Any {
@@ -262,7 +296,7 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 	case float32, float64:
 		return p == Number
 	case string:
-		if p == String {
+		if p == String || p == File {
 			return true
 		}
 		if p == DateTime {
@@ -297,6 +331,9 @@ func (p Primitive) GenerateExample(r *RandomGenerator, seen []string) interface{
 	case Any:
 		// to not make it too complicated, pick one of the primitive types
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r, seen)
+	case File:
+		// file contents cannot be represented in examples, use a file name instead
+		return r.FileName()
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -336,6 +373,12 @@ func (a *Array) ToArray() *Array { return a }
 // ToHash returns nil.
 func (a *Array) ToHash() *Hash { return nil }
 
+// IsUnion returns false.
+func (a *Array) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (a *Array) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns true if the array type can have a default value.
 // The array type can have a default value only if the element type can
 // have a default value.
@@ -409,6 +452,12 @@ func (o Object) ToArray() *Array { return nil }
 // ToHash returns nil.
 func (o Object) ToHash() *Hash { return nil }
 
+// IsUnion returns false.
+func (o Object) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (o Object) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns false.
 func (o Object) CanHaveDefault() bool { return false }
 
@@ -475,6 +524,12 @@ func (h *Hash) ToArray() *Array { return nil }
 // ToHash returns the underlying hash map.
 func (h *Hash) ToHash() *Hash { return h }
 
+// IsUnion returns false.
+func (h *Hash) IsUnion() bool { return false }
+
+// ToUnion returns nil.
+func (h *Hash) ToUnion() *Union { return nil }
+
 // CanHaveDefault returns true if the hash type can have a default value.
 // The hash type can have a default value only if both the key type and
 // the element type can have a default value.
@@ -519,6 +574,85 @@ func (h *Hash) MakeMap(m map[interface{}]interface{}) interface{} {
 	return hash.Interface()
 }
 
+// Kind implements DataKind.
+func (u *Union) Kind() Kind { return UnionKind }
+
+// Name returns the type name.
+func (u *Union) Name() string { return "union" }
+
+// IsPrimitive returns false.
+func (u *Union) IsPrimitive() bool { return false }
+
+// HasAttributes returns true.
+func (u *Union) HasAttributes() bool { return true }
+
+// IsObject returns false.
+func (u *Union) IsObject() bool { return false }
+
+// IsArray returns false.
+func (u *Union) IsArray() bool { return false }
+
+// IsHash returns false.
+func (u *Union) IsHash() bool { return false }
+
+// ToObject returns nil.
+func (u *Union) ToObject() Object { return nil }
+
+// ToArray returns nil.
+func (u *Union) ToArray() *Array { return nil }
+
+// ToHash returns nil.
+func (u *Union) ToHash() *Hash { return nil }
+
+// IsUnion returns true.
+func (u *Union) IsUnion() bool { return true }
+
+// ToUnion returns the underlying union.
+func (u *Union) ToUnion() *Union { return u }
+
+// CanHaveDefault returns false.
+func (u *Union) CanHaveDefault() bool { return false }
+
+// IsCompatible returns true if val is compatible with one of the union types.
+func (u *Union) IsCompatible(val interface{}) bool {
+	for _, ut := range u.Types {
+		if ut.IsCompatible(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateExample returns a random value of one of the union types. The discriminator attribute
+// of the value is set to the name of the type.
+func (u *Union) GenerateExample(r *RandomGenerator, seen []string) interface{} {
+	if len(u.Types) == 0 {
+		return nil
+	}
+	ut := u.Types[r.Int()%len(u.Types)]
+	ex := ut.GenerateExample(r, seen)
+	m, ok := ex.(map[string]interface{})
+	if !ok || u.Discriminator == "" {
+		return ex
+	}
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	res[u.Discriminator] = ut.TypeName
+	return res
+}
+
+// Values returns the values of the discriminator attribute, that is the names of the union
+// types in the order they were defined.
+func (u *Union) Values() []interface{} {
+	vals := make([]interface{}, len(u.Types))
+	for i, ut := range u.Types {
+		vals[i] = ut.TypeName
+	}
+	return vals
+}
+
 // AttributeIterator is the type of the function given to IterateAttributes.
 type AttributeIterator func(string, *AttributeDefinition) error
 
@@ -569,6 +703,17 @@ func UserTypes(dt DataType) map[string]*UserTypeDefinition {
 			vtypes[n] = ut
 		}
 		return vtypes
+	case *Union:
+		types := make(map[string]*UserTypeDefinition)
+		for _, ut := range actual.Types {
+			for n, t := range UserTypes(ut) {
+				types[n] = t
+			}
+		}
+		if len(types) == 0 {
+			return nil
+		}
+		return types
 	case Object:
 		types := make(map[string]*UserTypeDefinition)
 		for _, att := range actual {
@@ -662,6 +807,12 @@ func (u *UserTypeDefinition) ToArray() *Array { return u.Type.ToArray() }
 // ToHash calls ToHash on the user type underlying data type.
 func (u *UserTypeDefinition) ToHash() *Hash { return u.Type.ToHash() }
 
+// IsUnion calls IsUnion on the user type underlying data type.
+func (u *UserTypeDefinition) IsUnion() bool { return u.Type != nil && u.Type.IsUnion() }
+
+// ToUnion calls ToUnion on the user type underlying data type.
+func (u *UserTypeDefinition) ToUnion() *Union { return u.Type.ToUnion() }
+
 // CanHaveDefault calls CanHaveDefault on the user type underlying data type.
 func (u *UserTypeDefinition) CanHaveDefault() bool { return u.Type.CanHaveDefault() }
 
@@ -698,12 +849,23 @@ func (m *MediaTypeDefinition) Kind() Kind { return MediaTypeKind }
 
 // IsError returns true if the media type is implemented via a goa struct.
 func (m *MediaTypeDefinition) IsError() bool {
+	id := m.errorIdentifier()
+	return id == ErrorMedia.Identifier || id == ProblemMedia.Identifier
+}
+
+// IsProblem returns true if the media type describes RFC 7807 problem details.
+func (m *MediaTypeDefinition) IsProblem() bool {
+	return m.errorIdentifier() == ProblemMedia.Identifier
+}
+
+// errorIdentifier returns the media type identifier sans view parameter.
+func (m *MediaTypeDefinition) errorIdentifier() string {
 	base, params, err := mime.ParseMediaType(m.Identifier)
 	if err != nil {
 		panic("invalid media type identifier " + m.Identifier) // bug
 	}
 	delete(params, "view")
-	return mime.FormatMediaType(base, params) == ErrorMedia.Identifier
+	return mime.FormatMediaType(base, params)
 }
 
 // ComputeViews returns the media type views recursing as necessary if the media type is a
@@ -773,6 +935,12 @@ func (m *MediaTypeDefinition) Project(view string) (*MediaTypeDefinition, *UserT
 	return m.projectSingle(view, canonical)
 }
 
+// isWriteOnly returns true if the media type attribute with the given name is write-only.
+func isWriteOnly(m *MediaTypeDefinition, name string) bool {
+	att := m.Type.ToObject()[name]
+	return att != nil && att.WriteOnly
+}
+
 func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTypeDefinition, links *UserTypeDefinition, err error) {
 	v, ok := m.Views[view]
 	if !ok {
@@ -786,7 +954,7 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 		names := m.Validation.Required
 		var required []string
 		for _, n := range names {
-			if _, ok := viewObj[n]; ok {
+			if _, ok := viewObj[n]; ok && !isWriteOnly(m, n) {
 				required = append(required, n)
 			}
 		}
@@ -823,6 +991,11 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 	mtObj := m.Type.ToObject()
 	_, hasAttNamedLinks := mtObj["links"]
 	for n := range viewObj {
+		if isWriteOnly(m, n) {
+			// Write-only attributes are never rendered
+			delete(projectedObj, n)
+			continue
+		}
 		if n == "links" && !hasAttNamedLinks {
 			linkObj := make(Object)
 			for n, link := range m.Links {
@@ -1004,6 +1177,12 @@ func walk(at *AttributeDefinition, walker func(*AttributeDefinition) error, seen
 			return err
 		}
 		return walk(actual.ElemType, walker, seen)
+	case *Union:
+		for _, ut := range actual.Types {
+			if err := walkUt(ut); err != nil {
+				return err
+			}
+		}
 	case Object:
 		for _, cat := range actual {
 			if err := walk(cat, walker, seen); err != nil {
@@ -162,6 +162,21 @@ var _ = Describe("Project", func() {
 			})
 		})
 
+		Context("with a write-only attribute", func() {
+			BeforeEach(func() {
+				view = "default"
+				mt.Type.ToObject()["att2"].WriteOnly = true
+				mt.Validation = &dslengine.ValidationDefinition{Required: []string{"att1", "att2"}}
+			})
+
+			It("does not render the attribute", func() {
+				Ω(prErr).ShouldNot(HaveOccurred())
+				Ω(projected.Type.ToObject()).Should(HaveKey("att1"))
+				Ω(projected.Type.ToObject()).ShouldNot(HaveKey("att2"))
+				Ω(projected.Validation.Required).Should(Equal([]string{"att1"}))
+			})
+		})
+
 		Context("using the tiny view", func() {
 			BeforeEach(func() {
 				view = "tiny"
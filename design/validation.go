@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/goadesign/goa/dslengine"
@@ -154,6 +155,19 @@ func (a *APIDefinition) Validate() error {
 		verr.Merge(r.Validate())
 		return nil
 	})
+	callbacks := make(map[string]*CallbackDefinition)
+	a.IterateCallbacks(func(c *CallbackDefinition) error {
+		verr.Merge(c.Validate())
+		if other, ok := callbacks[c.Name]; ok {
+			verr.Add(c, "callback name conflicts with %s, callback names must be unique", other.Context())
+		}
+		callbacks[c.Name] = c
+		return nil
+	})
+	a.validateErrors(verr)
+	if a.RateLimit != nil {
+		verr.Merge(a.RateLimit.Validate())
+	}
 	for _, dec := range a.Consumes {
 		verr.Merge(dec.Validate())
 	}
@@ -169,6 +183,31 @@ func (a *APIDefinition) Validate() error {
 	return err
 }
 
+// validateErrors checks that errors defined by different actions or by the API with the same name
+// use the same status and media type: they share the same generated code.
+func (a *APIDefinition) validateErrors(verr *dslengine.ValidationErrors) {
+	errs := make(map[string]*ErrorDefinition)
+	check := func(e *ErrorDefinition) error {
+		if other, ok := errs[e.Name]; ok {
+			if other.Status != e.Status || CanonicalIdentifier(other.MediaType) != CanonicalIdentifier(e.MediaType) {
+				verr.Add(e, "error status and media type must match the ones defined in %s", other.Context())
+			}
+			return nil
+		}
+		errs[e.Name] = e
+		return nil
+	}
+	iterateErrors(a.Errors, func(e *ErrorDefinition) error {
+		verr.Merge(e.Validate())
+		return check(e)
+	})
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(action *ActionDefinition) error {
+			return iterateErrors(action.Errors, check)
+		})
+	})
+}
+
 func (a *APIDefinition) validateContact(verr *dslengine.ValidationErrors) {
 	if a.Contact != nil && a.Contact.URL != "" {
 		if _, err := url.ParseRequestURI(a.Contact.URL); err != nil {
@@ -216,9 +255,15 @@ func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Params != nil {
 		verr.Merge(r.Params.Validate("resource parameters", r))
 	}
+	verr.Merge(validateCookies("resource cookies", r.Cookies, r))
 	for _, origin := range r.Origins {
 		verr.Merge(origin.Validate())
 	}
+	if r.RateLimit != nil {
+		verr.Merge(r.RateLimit.Validate())
+	}
+	verr.Merge(validateEncodingSubset(r, r.Consumes, Design.Consumes, DefaultDecoders))
+	verr.Merge(validateEncodingSubset(r, r.Produces, Design.Produces, DefaultEncoders))
 	return verr.AsError()
 }
 
@@ -320,23 +365,52 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	if len(a.Routes) == 0 {
 		verr.Add(a, "No route defined for action")
 	}
+	streams := 0
 	for i, r := range a.Responses {
 		for j, r2 := range a.Responses {
 			if i != j && r.Status == r2.Status {
 				verr.Add(r, "Multiple response definitions with status code %d", r.Status)
 			}
 		}
+		if r.Stream != "" {
+			streams++
+		}
 		verr.Merge(r.Validate())
 	}
+	if streams > 1 {
+		verr.Add(a, "Action defines %d streamed responses, an action may stream at most one response", streams)
+	}
+	if streams > 0 && a.WebSocket() {
+		verr.Add(a, "Websocket actions cannot define streamed responses")
+	}
 	verr.Merge(a.ValidateParams())
+	verr.Merge(validateCookies("action cookies", a.Cookies, a))
 	if a.Payload != nil {
 		verr.Merge(a.Payload.Validate("action payload", a))
 	}
+	verr.Merge(a.validateMultipartPayload())
+	verr.Merge(a.validatePagination())
+	verr.Merge(a.validateIdempotency())
+	for _, e := range a.Errors {
+		verr.Merge(e.Validate())
+	}
+	if a.RateLimit != nil {
+		verr.Merge(a.RateLimit.Validate())
+	}
+	if a.Cache != nil {
+		verr.Merge(a.Cache.Validate())
+	}
+	verr.Merge(validateEncodingSubset(a, a.Consumes, Design.Consumes, DefaultDecoders))
+	verr.Merge(validateEncodingSubset(a, a.Produces, Design.Produces, DefaultEncoders))
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
 	}
 	if a.Params != nil {
 		for n, p := range a.Params.Type.ToObject() {
+			if p.Type.Kind() == FileKind {
+				verr.Add(a, "Param %s cannot be a file, only multipart form payloads may define file attributes", n)
+				continue
+			}
 			if p.Type.IsPrimitive() {
 				continue
 			}
@@ -352,6 +426,103 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	return verr.AsError()
 }
 
+// validateIdempotency checks that idempotent actions have at least one route using an unsafe
+// HTTP method and that the Idempotency-Key header is a string.
+func (a *ActionDefinition) validateIdempotency() *dslengine.ValidationErrors {
+	if !a.Idempotent {
+		return nil
+	}
+	verr := new(dslengine.ValidationErrors)
+	unsafe := false
+	for _, r := range a.Routes {
+		switch r.Verb {
+		case "GET", "HEAD", "OPTIONS", "TRACE":
+		default:
+			unsafe = true
+		}
+	}
+	if !unsafe {
+		verr.Add(a, "idempotent action must define at least one route using an unsafe HTTP method such as POST or PATCH")
+	}
+	if a.Headers != nil {
+		if h, ok := a.Headers.Type.ToObject()["Idempotency-Key"]; ok && h.Type.Kind() != StringKind {
+			verr.Add(a, "Idempotency-Key header must be of type String")
+		}
+	}
+	return verr.AsError()
+}
+
+// validatePagination checks that the pagination style is known and that the pagination params
+// have the expected types.
+func (a *ActionDefinition) validatePagination() *dslengine.ValidationErrors {
+	if a.Pagination == "" {
+		return nil
+	}
+	verr := new(dslengine.ValidationErrors)
+	var names []string
+	switch a.Pagination {
+	case CursorPagination:
+		names = []string{"cursor", "limit"}
+	case OffsetPagination:
+		names = []string{"page", "limit"}
+	default:
+		verr.Add(a, "invalid pagination style %#v, must be %#v or %#v", a.Pagination, CursorPagination, OffsetPagination)
+		return verr.AsError()
+	}
+	var params Object
+	if a.Params != nil {
+		params = a.Params.Type.ToObject()
+	}
+	for _, n := range names {
+		expected := Integer
+		if n == "cursor" {
+			expected = String
+		}
+		p, ok := params[n]
+		if !ok {
+			verr.Add(a, "paginated action must define the %s param", n)
+			continue
+		}
+		if p.Type.Kind() != expected.Kind() {
+			verr.Add(a, "pagination param %s must be of type %s", n, expected.Name())
+		}
+		if n != "cursor" && a.Pagination == OffsetPagination && !a.Params.HasDefaultValue(n) && !a.Params.IsRequired(n) {
+			verr.Add(a, "pagination param %s must be required or have a default value", n)
+		}
+	}
+	return verr.AsError()
+}
+
+// validateMultipartPayload checks that multipart form payloads are objects whose attributes are
+// primitives or files and that file attributes are only used in multipart form payloads.
+func (a *ActionDefinition) validateMultipartPayload() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if !a.PayloadMultipart {
+		if a.Payload != nil {
+			for n, att := range a.Payload.ToObject() {
+				if att.Type.Kind() == FileKind {
+					verr.Add(a, "payload attribute %s is a file but the action does not use MultipartForm", n)
+				}
+			}
+		}
+		return verr.AsError()
+	}
+	if a.Payload == nil {
+		verr.Add(a, "MultipartForm requires a payload")
+		return verr.AsError()
+	}
+	if !a.Payload.IsObject() {
+		verr.Add(a, "multipart form payload must be an object")
+		return verr.AsError()
+	}
+	for n, att := range a.Payload.ToObject() {
+		if !att.Type.IsPrimitive() {
+			verr.Add(a, "multipart form payload attribute %s must be a primitive or a file", n)
+		}
+	}
+	return verr.AsError()
+}
+
 // Validate checks the file server is properly initialized.
 func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -373,6 +544,9 @@ func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	if len(matches) > 2 {
 		verr.Add(f, "invalid request path, may only contain one wildcard")
 	}
+	if f.Cache != nil {
+		verr.Merge(f.Cache.Validate())
+	}
 
 	return verr.AsError()
 }
@@ -445,6 +619,9 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 	if ctx != "" {
 		ctx += " - "
 	}
+	if a.ReadOnly && a.WriteOnly {
+		verr.Add(parent, "%sattribute cannot be both read-only and write-only", ctx)
+	}
 	// If both Default and Enum are given, make sure the Default value is one of Enum values.
 	// TODO: We only do the default value and enum check just for primitive types.
 	// Issue 388 (https://github.com/goadesign/goa/issues/388) will address this for other types.
@@ -474,8 +651,28 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 				verr.Add(parent, `%srequired field "%s" does not exist`, ctx, n)
 			}
 		}
+		if a.Validation != nil {
+			for _, r := range a.Validation.RequiredIf {
+				for _, n := range []string{r.Name, r.DependsOn} {
+					if _, ok := o[n]; !ok {
+						verr.Add(parent, `%sconditionally required field "%s" does not exist`, ctx, n)
+					}
+				}
+			}
+			for _, names := range a.Validation.AtLeastOneOf {
+				for _, n := range names {
+					if _, ok := o[n]; !ok {
+						verr.Add(parent, `%sat least one of field "%s" does not exist`, ctx, n)
+					}
+				}
+			}
+		}
 		for n, att := range o {
 			ctx = fmt.Sprintf("field %s", n)
+			if att.Type != nil && att.Type.Kind() == UnionKind {
+				verr.Add(parent, "%s: union types must be defined with Type", ctx)
+				continue
+			}
 			verr.Merge(att.Validate(ctx, parent))
 		}
 	} else {
@@ -495,9 +692,127 @@ func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Headers != nil {
 		verr.Merge(r.Headers.Validate("response headers", r))
 	}
+	verr.Merge(validateCookies("response cookies", r.Cookies, r))
 	if r.Status == 0 {
 		verr.Add(r, "response status not defined")
 	}
+	if r.Stream != "" {
+		if r.Stream != "text/event-stream" && r.Stream != "application/x-ndjson" {
+			verr.Add(r, "invalid stream content type %#v, must be \"text/event-stream\" or \"application/x-ndjson\"", r.Stream)
+		}
+		if Design.MediaTypeWithIdentifier(r.MediaType) == nil {
+			verr.Add(r, "streamed response must use a media type defined in the design, got %#v", r.MediaType)
+		}
+	}
+	if r.Cache != nil {
+		verr.Merge(r.Cache.Validate())
+	}
+	return verr.AsError()
+}
+
+// validateCookies checks that the cookie attributes are valid and hold primitive values.
+func validateCookies(ctx string, cookies *AttributeDefinition, parent dslengine.Definition) *dslengine.ValidationErrors {
+	if cookies == nil {
+		return nil
+	}
+	verr := new(dslengine.ValidationErrors)
+	verr.Merge(cookies.Validate(ctx, parent))
+	for n, c := range cookies.Type.ToObject() {
+		if !c.Type.IsPrimitive() || c.Type.Kind() == FileKind {
+			verr.Add(parent, "%s - cookie %s has an invalid type, cookies must be primitives", ctx, n)
+		}
+		if v, ok := c.Metadata["cookie:max-age"]; ok {
+			if len(v) == 0 {
+				verr.Add(parent, "%s - cookie %s max age is missing", ctx, n)
+			} else if _, err := strconv.Atoi(v[0]); err != nil {
+				verr.Add(parent, "%s - cookie %s max age must be an integer, got %#v", ctx, n, v[0])
+			}
+		}
+	}
+	return verr.AsError()
+}
+
+// Validate checks that the callback definition is consistent: it has a name and its payload if
+// any is valid.
+func (c *CallbackDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if c.Name == "" {
+		verr.Add(c, "callback name cannot be empty")
+	}
+	if c.Payload != nil {
+		verr.Merge(c.Payload.Validate("callback payload", c))
+	}
+	return verr.AsError()
+}
+
+// Validate checks that the error definition is consistent: it has a name, a 4xx or 5xx status and
+// its media type is defined in the design.
+func (e *ErrorDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if e.Name == "" {
+		verr.Add(e, "error name cannot be empty")
+	}
+	if e.Status < 400 || e.Status > 599 {
+		verr.Add(e, "invalid error status %d, must be a 4xx or 5xx status", e.Status)
+	}
+	if !e.IsError() && Design.MediaTypeWithIdentifier(e.MediaType) == nil {
+		verr.Add(e, "error media type %#v is not defined in the design", e.MediaType)
+	}
+	return verr.AsError()
+}
+
+// validateEncodingSubset checks that the mime types listed by the resource or action encoding
+// definitions encs are listed by the API encoding definitions apiEncs or by defaults if the API
+// does not define any.
+func validateEncodingSubset(def dslengine.Definition, encs, apiEncs, defaults []*EncodingDefinition) *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if len(encs) == 0 {
+		return nil
+	}
+	if len(apiEncs) == 0 {
+		apiEncs = defaults
+	}
+	known := make(map[string]bool)
+	for _, m := range mimeTypes(apiEncs) {
+		known[m] = true
+	}
+	for _, enc := range encs {
+		for _, m := range enc.MIMETypes {
+			if !known[m] {
+				verr.Add(def, "MIME type %#v is not listed by the API, Consumes and Produces in resources and actions must use MIME types defined in the API", m)
+			}
+		}
+		if enc.PackagePath != "" || enc.Function != "" {
+			verr.Add(def, "encoder package and function may only be defined in the API Consumes and Produces")
+		}
+	}
+	return verr.AsError()
+}
+
+// Validate checks that the rate limit allows at least one request in a non-empty period.
+func (r *RateLimitDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if r.Requests <= 0 {
+		verr.Add(r, "invalid number of requests %d, must be strictly positive", r.Requests)
+	}
+	if r.Period <= 0 {
+		verr.Add(r, "invalid period %s, must be strictly positive", r.Period)
+	}
+	return verr.AsError()
+}
+
+// Validate checks that the cache policy does not both forbid caching and define a max age and
+// that the Vary header names are not empty.
+func (c *CacheDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if c.NoStore && c.MaxAge > 0 {
+		verr.Add(c, "cache policy cannot define both NoStore and MaxAge")
+	}
+	for _, h := range c.Vary {
+		if h == "" {
+			verr.Add(c, "Vary header names cannot be empty")
+		}
+	}
 	return verr.AsError()
 }
 
@@ -518,6 +833,44 @@ func (u *UserTypeDefinition) Validate(ctx string, parent dslengine.Definition) *
 		verr.Add(parent, "%s - %s", ctx, "User type must have a name")
 	}
 	verr.Merge(u.AttributeDefinition.Validate(ctx, u))
+	if un, ok := u.Type.(*Union); ok {
+		verr.Merge(un.Validate(ctx, u))
+	}
+	return verr.AsError()
+}
+
+// Validate checks that the union definition is consistent: it defines a discriminator and all
+// the union types are objects that define the discriminator as a required string attribute.
+func (u *Union) Validate(ctx string, parent dslengine.Definition) *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if ctx != "" {
+		ctx += " - "
+	}
+	if len(u.Types) == 0 {
+		verr.Add(parent, "%sunion must define at least one type", ctx)
+	}
+	if u.Discriminator == "" {
+		verr.Add(parent, "%sunion must define a discriminator, use Discriminator to define it", ctx)
+		return verr.AsError()
+	}
+	for _, ut := range u.Types {
+		o := ut.ToObject()
+		if o == nil {
+			verr.Add(parent, "%sunion type %s must be an object", ctx, ut.TypeName)
+			continue
+		}
+		att, ok := o[u.Discriminator]
+		if !ok {
+			verr.Add(parent, "%sunion type %s does not define the discriminator attribute %#v", ctx, ut.TypeName, u.Discriminator)
+			continue
+		}
+		if att.Type.Kind() != StringKind {
+			verr.Add(parent, "%sdiscriminator attribute %#v of union type %s must be a string", ctx, u.Discriminator, ut.TypeName)
+		}
+		if !ut.IsRequired(u.Discriminator) {
+			verr.Add(parent, "%sdiscriminator attribute %#v of union type %s must be required", ctx, u.Discriminator, ut.TypeName)
+		}
+	}
 	return verr.AsError()
 }
 
@@ -95,6 +95,11 @@ The ErrorHandler middleware maps errors to HTTP responses. Errors that are insta
 struct are mapped using the struct fields while other types of errors return responses with status
 code 500 and the error message in the body.
 
+Services whose ProblemDetails field is true render errors as RFC 7807 problem details with the
+application/problem+json content type instead, see ProblemDetails and SendProblem. The
+ProblemDetails DSL sets the field in the generated code and uses the matching ProblemMedia media
+type in the generated documentation and client.
+
 Validation
 
 The goa design language documented in the dsl package makes it possible to attach validations to
@@ -108,7 +113,11 @@ Encoding
 The goa design language makes it possible to specify the encodings supported by the API both as
 input (Consumes) and output (Produces). goagen uses that information to registed the corresponding
 packages with the service encoders and decoders via their Register methods. The service exposes the
-DecodeRequest and EncodeResponse that implement a simple content type negotiation algorithm for
-picking the right encoder for the "Content-Type" (decoder) or "Accept" (encoder) request header.
+DecodeRequest and EncodeResponse that implement content type negotiation for picking the right
+encoder for the "Content-Type" (decoder) or "Accept" (encoder) request header. The Accept header
+is parsed as defined by RFC 7231 including quality values and wildcards, see NegotiateContentType.
+Requests with a Content-Type that no decoder supports are rejected with ErrUnsupportedMediaType.
+Resources and actions may restrict the encodings they support with Consumes and Produces, the
+generated code then rejects requests that accept none of the produced types with ErrNotAcceptable.
 */
 package goa
@@ -1,6 +1,9 @@
 package dslengine
 
-import "fmt"
+import (
+	"fmt"
+	"path"
+)
 
 type (
 
@@ -94,15 +97,60 @@ type (
 		// Maximum represents a maximum value validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor17.
 		Maximum *float64
+		// ExclusiveMinimum is true if the value must be strictly greater than Minimum as
+		// described at http://json-schema.org/latest/json-schema-validation.html#anchor21.
+		ExclusiveMinimum bool
+		// ExclusiveMaximum is true if the value must be strictly less than Maximum as
+		// described at http://json-schema.org/latest/json-schema-validation.html#anchor17.
+		ExclusiveMaximum bool
+		// MultipleOf represents a "multiple of" validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor14.
+		MultipleOf *float64
 		// MinLength represents an minimum length validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor29.
 		MinLength *int
 		// MaxLength represents an maximum length validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor26.
 		MaxLength *int
+		// UniqueItems represents a unique items validation on arrays as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor49.
+		UniqueItems bool
+		// MinProperties represents a minimum number of properties validation on hashes as
+		// described at http://json-schema.org/latest/json-schema-validation.html#anchor57.
+		MinProperties *int
+		// MaxProperties represents a maximum number of properties validation on hashes as
+		// described at http://json-schema.org/latest/json-schema-validation.html#anchor54.
+		MaxProperties *int
 		// Required list the required fields of object attributes as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor61.
 		Required []string
+		// RequiredIf lists the fields of object attributes that are only required when
+		// another field is set, similar to the "dependentRequired" JSON schema keyword.
+		RequiredIf []*ConditionalRequirement
+		// AtLeastOneOf lists sets of fields of object attributes, at least one field of each
+		// set must be set.
+		AtLeastOneOf [][]string
+		// Functions lists the custom Go functions used to validate the attribute.
+		Functions []*ValidationFunction
+	}
+
+	// ConditionalRequirement describes a field that is required when another field is set.
+	ConditionalRequirement struct {
+		// Name is the name of the required field.
+		Name string
+		// DependsOn is the name of the field that causes Name to be required.
+		DependsOn string
+		// Values lists the values of DependsOn that cause Name to be required, any value
+		// causes Name to be required if Values is empty.
+		Values []interface{}
+	}
+
+	// ValidationFunction identifies a custom Go function used to validate an attribute.
+	ValidationFunction struct {
+		// PackagePath is the import path of the package that defines the function.
+		PackagePath string
+		// Name is the name of the function.
+		Name string
 	}
 )
 
@@ -137,9 +185,14 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	}
 	if v.Minimum == nil || (other.Minimum != nil && *v.Minimum > *other.Minimum) {
 		v.Minimum = other.Minimum
+		v.ExclusiveMinimum = other.ExclusiveMinimum
 	}
 	if v.Maximum == nil || (other.Maximum != nil && *v.Maximum < *other.Maximum) {
 		v.Maximum = other.Maximum
+		v.ExclusiveMaximum = other.ExclusiveMaximum
+	}
+	if v.MultipleOf == nil {
+		v.MultipleOf = other.MultipleOf
 	}
 	if v.MinLength == nil || (other.MinLength != nil && *v.MinLength > *other.MinLength) {
 		v.MinLength = other.MinLength
@@ -147,7 +200,28 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.MaxLength == nil || (other.MaxLength != nil && *v.MaxLength < *other.MaxLength) {
 		v.MaxLength = other.MaxLength
 	}
+	v.UniqueItems = v.UniqueItems || other.UniqueItems
+	if v.MinProperties == nil || (other.MinProperties != nil && *v.MinProperties > *other.MinProperties) {
+		v.MinProperties = other.MinProperties
+	}
+	if v.MaxProperties == nil || (other.MaxProperties != nil && *v.MaxProperties < *other.MaxProperties) {
+		v.MaxProperties = other.MaxProperties
+	}
 	v.AddRequired(other.Required)
+	v.RequiredIf = append(v.RequiredIf, other.RequiredIf...)
+	v.AtLeastOneOf = append(v.AtLeastOneOf, other.AtLeastOneOf...)
+	for _, f := range other.Functions {
+		found := false
+		for _, ff := range v.Functions {
+			if f.PackagePath == ff.PackagePath && f.Name == ff.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.Functions = append(v.Functions, f)
+		}
+	}
 }
 
 // AddRequired merges the required fields from other into v
@@ -177,19 +251,40 @@ func (v *ValidationDefinition) HasRequiredOnly() bool {
 	if (v.Minimum != nil) || (v.Maximum != nil) || (v.MaxLength != nil) {
 		return false
 	}
+	if (v.MultipleOf != nil) || v.UniqueItems || (v.MinProperties != nil) || (v.MaxProperties != nil) {
+		return false
+	}
+	if len(v.RequiredIf) > 0 || len(v.AtLeastOneOf) > 0 || len(v.Functions) > 0 {
+		return false
+	}
 	return true
 }
 
 // Dup makes a shallow dup of the validation.
 func (v *ValidationDefinition) Dup() *ValidationDefinition {
 	return &ValidationDefinition{
-		Values:    v.Values,
-		Format:    v.Format,
-		Pattern:   v.Pattern,
-		Minimum:   v.Minimum,
-		Maximum:   v.Maximum,
-		MinLength: v.MinLength,
-		MaxLength: v.MaxLength,
-		Required:  v.Required,
+		Values:           v.Values,
+		Format:           v.Format,
+		Pattern:          v.Pattern,
+		Minimum:          v.Minimum,
+		Maximum:          v.Maximum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		MultipleOf:       v.MultipleOf,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+		UniqueItems:      v.UniqueItems,
+		MinProperties:    v.MinProperties,
+		MaxProperties:    v.MaxProperties,
+		Required:         v.Required,
+		RequiredIf:       v.RequiredIf,
+		AtLeastOneOf:     v.AtLeastOneOf,
+		Functions:        v.Functions,
 	}
 }
+
+// Package returns the name of the package that defines the function, that is the last element of
+// its import path.
+func (f *ValidationFunction) Package() string {
+	return path.Base(f.PackagePath)
+}
@@ -0,0 +1,35 @@
+package dslengine
+
+import "fmt"
+
+// Location is the position in the design source code where a definition was declared.
+type Location struct {
+	// File is the path to the design file relative to the working directory.
+	File string
+	// Line is the line number in File.
+	Line int
+}
+
+// locations maps definitions to the location of the DSL that declared them.
+var locations = make(map[Definition]*Location)
+
+// RecordLocation records the location of the user DSL code calling the DSL function that
+// invokes RecordLocation. Tools that report on the design (e.g. goagen lint) may then retrieve
+// the location with LocationOf.
+func RecordLocation(def Definition) {
+	if def == nil {
+		return
+	}
+	file, line := computeErrorLocation()
+	locations[def] = &Location{File: file, Line: line}
+}
+
+// LocationOf returns the location recorded for the given definition, nil if none.
+func LocationOf(def Definition) *Location {
+	return locations[def]
+}
+
+// String returns the "file:line" representation of the location.
+func (l *Location) String() string {
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
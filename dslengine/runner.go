@@ -1,6 +1,7 @@
 package dslengine
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,10 +10,18 @@ import (
 	"strings"
 )
 
+// SilentFailureExitCode is the exit status of generators that fail with ErrSilentFailure.
+const SilentFailureExitCode = 3
+
 var (
 	// Errors contains the DSL execution errors if any.
 	Errors MultiError
 
+	// ErrSilentFailure is the error returned by generators whose output already describes the
+	// failure, for example a linter that printed its report. FailOnError exits with status
+	// SilentFailureExitCode without printing anything.
+	ErrSilentFailure = errors.New("generator failed")
+
 	// Global DSL evaluation stack
 	ctxStack contextStack
 
@@ -72,6 +81,7 @@ func Reset() {
 		r.Reset()
 	}
 	Errors = nil
+	locations = make(map[Definition]*Location)
 }
 
 // Run runs the given root definitions. It iterates over the definition sets
@@ -179,8 +189,12 @@ func ReportError(fm string, vals ...interface{}) {
 }
 
 // FailOnError will exit with code 1 if `err != nil`. This function
-// will handle properly the MultiError this dslengine provides.
+// will handle properly the MultiError this dslengine provides as well as
+// ErrSilentFailure.
 func FailOnError(err error) {
+	if err == ErrSilentFailure {
+		os.Exit(SilentFailureExitCode)
+	}
 	if merr, ok := err.(MultiError); ok {
 		if len(merr) == 0 {
 			return
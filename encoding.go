@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"mime"
+	"strings"
 	"sync"
 	"time"
 )
@@ -102,7 +103,9 @@ func NewHTTPDecoder() *HTTPDecoder {
 	}
 }
 
-// Decode uses registered Decoders to unmarshal a body based on the contentType.
+// Decode uses registered Decoders to unmarshal a body based on the contentType. Content types
+// with a structured syntax suffix such as application/problem+json fall back to the decoder
+// registered for the suffix.
 func (decoder *HTTPDecoder) Decode(v interface{}, body io.Reader, contentType string) error {
 	now := time.Now()
 	defer MeasureSince([]string{"goa", "decode", contentType}, now)
@@ -116,11 +119,18 @@ func (decoder *HTTPDecoder) Decode(v interface{}, body io.Reader, contentType st
 		}
 	}
 	p = decoder.pools[contentType]
+	if p == nil {
+		// Use the decoder of the structured syntax suffix if any, e.g. application/json for
+		// application/problem+json.
+		if i := strings.LastIndex(contentType, "+"); i > 0 {
+			p = decoder.pools["application/"+contentType[i+1:]]
+		}
+	}
 	if p == nil {
 		p = decoder.pools["*/*"]
 	}
 	if p == nil {
-		return nil
+		return ErrUnsupportedMediaType(fmt.Sprintf("no decoder registered for %s", contentType), "content_type", contentType)
 	}
 
 	// the decoderPool will handle whether or not a pool is actually in use
@@ -186,19 +196,15 @@ func (p *decoderPool) Put(d Decoder) {
 	p.pool.Put(d)
 }
 
-// Encode uses the registered encoders and given content type to marshal and write the given value
-// using the given writer.
+// Encode uses the registered encoders and given Accept header value to marshal and write the
+// given value using the given writer. The encoder is selected using content negotiation, see
+// NegotiateContentType. The default encoder registered with "*/*" is used if the request accepts
+// any content type or none of the registered content types.
 func (encoder *HTTPEncoder) Encode(v interface{}, resp io.Writer, accept string) error {
 	now := time.Now()
-	if accept == "" {
-		accept = "*/*"
-	}
-	var contentType string
-	for _, t := range encoder.contentTypes {
-		if accept == "*/*" || accept == t {
-			contentType = accept
-			break
-		}
+	contentType := encoder.contentType(accept)
+	if contentType == "" {
+		contentType = "*/*"
 	}
 	defer MeasureSince([]string{"goa", "encode", contentType}, now)
 	p := encoder.pools[contentType]
@@ -231,11 +237,46 @@ func (encoder *HTTPEncoder) Register(f EncoderFunc, contentTypes ...string) {
 		encoder.pools[mediaType] = p
 	}
 
-	// Rebuild a unique index of registered content encoders to be used in EncodeResponse
-	encoder.contentTypes = make([]string, 0, len(encoder.pools))
-	for contentType := range encoder.pools {
-		encoder.contentTypes = append(encoder.contentTypes, contentType)
+	// Maintain a unique index of registered content encoders in order of registration to be
+	// used in content negotiation.
+	for _, contentType := range contentTypes {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			mediaType = contentType
+		}
+		found := false
+		for _, t := range encoder.contentTypes {
+			if t == mediaType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			encoder.contentTypes = append(encoder.contentTypes, mediaType)
+		}
+	}
+}
+
+// contentType returns the registered content type that best matches the given Accept header
+// value. It returns "*/*" if a default encoder is registered and the request accepts any content
+// type and the empty string if none of the registered content types is acceptable.
+func (encoder *HTTPEncoder) contentType(accept string) string {
+	offers := make([]string, 0, len(encoder.contentTypes))
+	for _, t := range encoder.contentTypes {
+		if t != "*/*" {
+			offers = append(offers, t)
+		}
+	}
+	ranges := parseAccept(accept)
+	_, hasDefault := encoder.pools["*/*"]
+	if hasDefault && len(ranges) == 0 {
+		return "*/*"
+	}
+	best, spec := negotiate(ranges, offers)
+	if hasDefault && best != "" && spec == 0 {
+		return "*/*"
 	}
+	return best
 }
 
 // newEncodePool checks to see if the EncoderFactory returns reusable encoders and if so, creates
@@ -37,6 +37,11 @@ import (
 	"strings"
 )
 
+// ErrorCodeHeader is the name of the response header that contains the code of errors created
+// with NewTypedError. The code of errors whose body is described by the goa error media type is
+// part of the body.
+const ErrorCodeHeader = "X-Goa-Error-Code"
+
 var (
 	// ErrorMediaIdentifier is the media type identifier used for error responses.
 	ErrorMediaIdentifier = "application/vnd.goa.error"
@@ -71,6 +76,22 @@ var (
 
 	// ErrInternal is the class of error used for uncaught errors.
 	ErrInternal = NewErrorClass("internal", 500)
+
+	// ErrInvalidResponse is the class of errors produced by the generated code when a response
+	// header fails to validate or is missing.
+	ErrInvalidResponse = NewErrorClass("invalid_response", 500)
+
+	// ErrPreconditionFailed is the error returned by CheckPreconditions when the If-Match or
+	// If-None-Match request headers do not match the current entity tag.
+	ErrPreconditionFailed = NewErrorClass("precondition_failed", 412)
+
+	// ErrNotAcceptable is the error returned to requests whose Accept header does not accept any
+	// of the content types produced by the action.
+	ErrNotAcceptable = NewErrorClass("not_acceptable", 406)
+
+	// ErrUnsupportedMediaType is the error returned to requests whose Content-Type header is not
+	// one of the content types consumed by the action.
+	ErrUnsupportedMediaType = NewErrorClass("unsupported_media_type", 415)
 )
 
 type (
@@ -109,6 +130,22 @@ type (
 		// Meta contains additional key/value pairs useful to clients.
 		Meta map[string]interface{} `json:"meta,omitempty" xml:"meta,omitempty" form:"meta,omitempty"`
 	}
+
+	// TypedError is an error whose response body is described by a media type other than the
+	// goa error media type. It implements ServiceError. goagen generates constructors that
+	// create TypedError values for the errors declared in the design using such media types.
+	TypedError struct {
+		// ID is the unique error instance identifier.
+		ID string
+		// Code identifies the class of errors.
+		Code string
+		// Status is the HTTP status code used by responses that cary the error.
+		Status int
+		// MediaType is the identifier of the media type that describes Body.
+		MediaType string
+		// Body is the response body.
+		Body interface{}
+	}
 )
 
 // NewErrorClass creates a new error class.
@@ -143,6 +180,12 @@ func NewErrorClass(code string, status int) ErrorClass {
 	}
 }
 
+// NewTypedError creates a new typed error with the given code, status and response body described
+// by the media type with identifier mediaType.
+func NewTypedError(code string, status int, mediaType string, body interface{}) *TypedError {
+	return &TypedError{ID: newErrorID(), Code: code, Status: status, MediaType: mediaType, Body: body}
+}
+
 // MissingPayloadError is the error produced when a request is missing a required payload.
 func MissingPayloadError() error {
 	return ErrInvalidRequest("missing required payload")
@@ -175,12 +218,49 @@ func MissingAttributeError(ctx, name string) error {
 	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx)
 }
 
+// MissingDependentAttributeError is the error produced when a request payload is missing a field
+// that is required because of the value of another field.
+func MissingDependentAttributeError(ctx, name, dependsOn string) error {
+	msg := fmt.Sprintf("attribute %#v of %s is missing and required when %#v is set", name, ctx, dependsOn)
+	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx, "depends_on", dependsOn)
+}
+
+// MissingOneOfAttributesError is the error produced when a request payload does not contain at
+// least one of the given fields.
+func MissingOneOfAttributesError(ctx string, names []string) error {
+	msg := fmt.Sprintf("%s must contain at least one of the attributes %s", ctx, strings.Join(names, ", "))
+	return ErrInvalidRequest(msg, "attributes", names, "parent", ctx)
+}
+
 // MissingHeaderError is the error produced when a request is missing a required header.
 func MissingHeaderError(name string) error {
 	msg := fmt.Sprintf("missing required HTTP header %#v", name)
 	return ErrInvalidRequest(msg, "name", name)
 }
 
+// MissingCookieError is the error produced when a request is missing a required cookie.
+func MissingCookieError(name string) error {
+	msg := fmt.Sprintf("missing required cookie %#v", name)
+	return ErrInvalidRequest(msg, "name", name)
+}
+
+// MissingResponseHeaderError is the error produced when a response is sent without a required
+// header.
+func MissingResponseHeaderError(name string) error {
+	msg := fmt.Sprintf("missing required response HTTP header %#v", name)
+	return ErrInvalidResponse(msg, "name", name)
+}
+
+// InvalidResponseHeaderError is the error produced when the value of a response header does not
+// satisfy the validations defined in the design.
+func InvalidResponseHeaderError(name string, err error) error {
+	msg := err.Error()
+	if e, ok := err.(*ErrorResponse); ok {
+		msg = e.Detail
+	}
+	return ErrInvalidResponse(msg, "name", name)
+}
+
 // InvalidEnumValueError is the error produced when the value of a parameter or payload field does
 // not match one the values defined in the design Enum validation.
 func InvalidEnumValueError(ctx string, val interface{}, allowed []interface{}) error {
@@ -228,6 +308,51 @@ func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool)
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
 }
 
+// InvalidExclusiveRangeError is the error produced when the value of a parameter or payload field
+// does not match the exclusive range validation defined in the design. value may be a int or a
+// float64.
+func InvalidExclusiveRangeError(ctx string, target interface{}, value interface{}, min bool) error {
+	comp := "greater than"
+	if !min {
+		comp = "less than"
+	}
+	msg := fmt.Sprintf("%s must be %s %v but got value %#v", ctx, comp, value, target)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "comp", comp, "expected", value)
+}
+
+// InvalidMultipleOfError is the error produced when the value of a parameter or payload field is
+// not a multiple of the value defined in the design. value may be a int or a float64.
+func InvalidMultipleOfError(ctx string, target interface{}, value interface{}) error {
+	msg := fmt.Sprintf("%s must be a multiple of %v but got value %#v", ctx, value, target)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "multiple", value)
+}
+
+// InvalidUniqueItemsError is the error produced when the elements of an array parameter or payload
+// field are not unique as required by the design.
+func InvalidUniqueItemsError(ctx string, target interface{}) error {
+	msg := fmt.Sprintf("elements of %s must be unique but got value %#v", ctx, target)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target)
+}
+
+// InvalidPropertiesCountError is the error produced when the number of keys of a hash parameter or
+// payload field does not match the min or max properties validation defined in the design.
+func InvalidPropertiesCountError(ctx string, target interface{}, count, value int, min bool) error {
+	comp := "greater than or equal to"
+	if !min {
+		comp = "less than or equal to"
+	}
+	msg := fmt.Sprintf("number of properties of %s must be %s %d but got value %#v (count=%d)", ctx, comp, value, target, count)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "count", count, "comp", comp, "expected", value)
+}
+
+// InvalidValueError is the error produced when a custom validation function defined in the design
+// rejects the value of a parameter or payload field. validationError is the error returned by the
+// function.
+func InvalidValueError(ctx string, target interface{}, validationError error) error {
+	msg := fmt.Sprintf("invalid value for %s, %s", ctx, validationError.Error())
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "error", validationError.Error())
+}
+
 // NoAuthMiddleware is the error produced when goa is unable to lookup a auth middleware for a
 // security scheme defined in the design.
 func NoAuthMiddleware(schemeName string) error {
@@ -250,6 +375,17 @@ func (e *ErrorResponse) ResponseStatus() int { return e.Status }
 // Token is the unique error occurrence identifier.
 func (e *ErrorResponse) Token() string { return e.ID }
 
+// Error returns the error occurrence details.
+func (e *TypedError) Error() string {
+	return fmt.Sprintf("[%s] %d %s: %v", e.ID, e.Status, e.Code, e.Body)
+}
+
+// ResponseStatus is the status used to build responses.
+func (e *TypedError) ResponseStatus() int { return e.Status }
+
+// Token is the unique error occurrence identifier.
+func (e *TypedError) Token() string { return e.ID }
+
 // MergeErrors updates an error by merging another into it. It first converts other into a
 // ServiceError if not already one - producing an internal error in that case. The merge algorithm
 // is:
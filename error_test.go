@@ -31,6 +31,25 @@ var _ = Describe("Error", func() {
 	})
 })
 
+var _ = Describe("NewTypedError", func() {
+	var body = map[string]interface{}{"available": 2}
+	var terr *TypedError
+
+	BeforeEach(func() {
+		terr = NewTypedError("out_of_stock", 409, "application/vnd.out-of-stock", body)
+	})
+
+	It("creates a service error", func() {
+		var serr ServiceError = terr
+		Ω(serr.ResponseStatus()).Should(Equal(409))
+		Ω(serr.Token()).ShouldNot(BeEmpty())
+		Ω(terr.Code).Should(Equal("out_of_stock"))
+		Ω(terr.MediaType).Should(Equal("application/vnd.out-of-stock"))
+		Ω(terr.Body).Should(Equal(body))
+		Ω(terr.Error()).Should(ContainSubstring("409 out_of_stock"))
+	})
+})
+
 var _ = Describe("InvalidParamTypeError", func() {
 	var valErr error
 	name := "param"
@@ -121,6 +140,56 @@ var _ = Describe("MissingHeaderError", func() {
 	})
 })
 
+var _ = Describe("MissingCookieError", func() {
+	var valErr error
+	name := "session"
+
+	JustBeforeEach(func() {
+		valErr = MissingCookieError(name)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(name))
+	})
+})
+
+var _ = Describe("MissingResponseHeaderError", func() {
+	var valErr error
+	name := "X-Rate-Limit"
+
+	JustBeforeEach(func() {
+		valErr = MissingResponseHeaderError(name)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(name))
+		Ω(err.Status).Should(Equal(500))
+	})
+})
+
+var _ = Describe("InvalidResponseHeaderError", func() {
+	var valErr error
+	name := "X-Mode"
+
+	JustBeforeEach(func() {
+		valErr = InvalidResponseHeaderError(name, InvalidEnumValueError(name, "bogus", []interface{}{"fast", "slow"}))
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(HavePrefix("value of X-Mode must be one of"))
+		Ω(err.Status).Should(Equal(500))
+	})
+})
+
 var _ = Describe("InvalidEnumValueError", func() {
 	var valErr error
 	ctx := "ctx"
@@ -277,6 +346,101 @@ var _ = Describe("InvalidLengthError", func() {
 	})
 })
 
+var _ = Describe("InvalidExclusiveRangeError", func() {
+	const ctx = "ctx"
+	const target = 42
+
+	It("creates a http error", func() {
+		valErr := InvalidExclusiveRangeError(ctx, target, 42, false)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("less than 42"))
+	})
+})
+
+var _ = Describe("InvalidMultipleOfError", func() {
+	const ctx = "ctx"
+	const target = 42.5
+
+	It("creates a http error", func() {
+		valErr := InvalidMultipleOfError(ctx, target, 0.2)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("multiple of 0.2"))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", target)))
+	})
+})
+
+var _ = Describe("InvalidUniqueItemsError", func() {
+	const ctx = "ctx"
+	target := []string{"a", "a"}
+
+	It("creates a http error", func() {
+		valErr := InvalidUniqueItemsError(ctx, target)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("unique"))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", target)))
+	})
+})
+
+var _ = Describe("InvalidPropertiesCountError", func() {
+	const ctx = "ctx"
+	target := map[string]int{"a": 1}
+
+	It("creates a http error", func() {
+		valErr := InvalidPropertiesCountError(ctx, target, 1, 2, true)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("greater than or equal to 2"))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", target)))
+	})
+})
+
+var _ = Describe("InvalidValueError", func() {
+	const ctx = "ctx"
+	const target = "target"
+
+	It("creates a http error", func() {
+		valErr := InvalidValueError(ctx, target, errors.New("invalid checksum"))
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("invalid checksum"))
+		Ω(err.Meta).Should(HaveKeyWithValue("value", target))
+	})
+})
+
+var _ = Describe("MissingDependentAttributeError", func() {
+	It("creates a http error", func() {
+		valErr := MissingDependentAttributeError("ctx", "card_number", "method")
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring("card_number"))
+		Ω(err.Detail).Should(ContainSubstring("method"))
+	})
+})
+
+var _ = Describe("MissingOneOfAttributesError", func() {
+	It("creates a http error", func() {
+		valErr := MissingOneOfAttributesError("ctx", []string{"email", "phone"})
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring("email, phone"))
+	})
+})
+
 var _ = Describe("Merge", func() {
 	var err, err2 error
 	var mErr *ErrorResponse
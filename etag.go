@@ -0,0 +1,80 @@
+package goa
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// ETagHandler wraps the handler of an action whose responses carry an entity tag. The returned
+// handler runs h through the middleware given to the service UseETag method if any or calls h
+// directly otherwise.
+func ETagHandler(service *Service, h Handler) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		if m := service.etag; m != nil {
+			return m(h)(ctx, rw, req)
+		}
+		return h(ctx, rw, req)
+	}
+}
+
+// QuoteETag returns etag formatted as an entity tag: etag is returned unchanged if it is already
+// quoted or weak, wrapped in double quotes otherwise.
+func QuoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// WeakETag computes a weak entity tag from the given response body.
+func WeakETag(body []byte) string {
+	sum := sha1.Sum(body)
+	return `W/"` + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`
+}
+
+// ETagMatch returns true if etag matches one of the entity tags listed in header, the value of an
+// If-Match or If-None-Match header. weak selects the weak comparison function defined in RFC 7232
+// which ignores the weak indicator, If-None-Match uses the weak comparison and If-Match the strong
+// one. The "*" value matches any non-empty etag.
+func ETagMatch(header, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	etag = QuoteETag(etag)
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	} else if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPreconditions evaluates the If-Match and If-None-Match headers of req against current, the
+// entity tag of the current representation of the target resource or the empty string if there
+// is none. It returns ErrPreconditionFailed if a condition is not met. Use it in the handlers of
+// actions that modify resources to implement optimistic concurrency control.
+func CheckPreconditions(req *http.Request, current string) error {
+	if h := req.Header.Get("If-Match"); h != "" && !ETagMatch(h, current, false) {
+		return ErrPreconditionFailed("If-Match precondition failed", "etag", current)
+	}
+	if h := req.Header.Get("If-None-Match"); h != "" && ETagMatch(h, current, true) {
+		return ErrPreconditionFailed("If-None-Match precondition failed", "etag", current)
+	}
+	return nil
+}
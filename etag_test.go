@@ -0,0 +1,93 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ETagHandler", func() {
+	var service *goa.Service
+	var called bool
+	var rw *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		called = false
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return nil
+		}
+		req, _ := http.NewRequest("GET", "/bottles/1", nil)
+		err := goa.ETagHandler(service, h)(context.Background(), rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("calls the handler", func() {
+		Ω(called).Should(BeTrue())
+	})
+
+	Context("with an etag middleware", func() {
+		BeforeEach(func() {
+			service.UseETag(func(h goa.Handler) goa.Handler {
+				return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					rw.Header().Set("ETag", `"v1"`)
+					return h(ctx, rw, req)
+				}
+			})
+		})
+
+		It("runs the handler through the middleware", func() {
+			Ω(called).Should(BeTrue())
+			Ω(rw.Header().Get("ETag")).Should(Equal(`"v1"`))
+		})
+	})
+})
+
+var _ = Describe("ETagMatch", func() {
+	It("compares entity tags", func() {
+		Ω(goa.ETagMatch(`"a", "b"`, "b", false)).Should(BeTrue())
+		Ω(goa.ETagMatch(`"a"`, `"b"`, false)).Should(BeFalse())
+		Ω(goa.ETagMatch(`*`, `"b"`, false)).Should(BeTrue())
+		Ω(goa.ETagMatch(`*`, "", false)).Should(BeFalse())
+		Ω(goa.ETagMatch(`W/"a"`, `"a"`, false)).Should(BeFalse())
+		Ω(goa.ETagMatch(`W/"a"`, `"a"`, true)).Should(BeTrue())
+		Ω(goa.ETagMatch(`"a"`, `W/"a"`, true)).Should(BeTrue())
+		Ω(goa.ETagMatch(`"a"`, `W/"a"`, false)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("CheckPreconditions", func() {
+	var req *http.Request
+
+	BeforeEach(func() {
+		req, _ = http.NewRequest("PUT", "/bottles/1", nil)
+	})
+
+	It("accepts requests with no conditions", func() {
+		Ω(goa.CheckPreconditions(req, `"v1"`)).Should(Succeed())
+	})
+
+	It("checks If-Match", func() {
+		req.Header.Set("If-Match", `"v1"`)
+		Ω(goa.CheckPreconditions(req, `"v1"`)).Should(Succeed())
+		err := goa.CheckPreconditions(req, `"v2"`)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(412))
+	})
+
+	It("checks If-None-Match", func() {
+		req.Header.Set("If-None-Match", "*")
+		Ω(goa.CheckPreconditions(req, "")).Should(Succeed())
+		Ω(goa.CheckPreconditions(req, `"v1"`)).Should(HaveOccurred())
+	})
+})
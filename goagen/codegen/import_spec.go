@@ -31,7 +31,7 @@ func (s *ImportSpec) Code() string {
 }
 
 // AttributeImports will construct a new ImportsSpec slice from an existing slice and add in imports specified in
-// struct:field:type Metadata tags.
+// struct:field:type Metadata tags and by the custom validation functions.
 func AttributeImports(att *design.AttributeDefinition, imports []*ImportSpec, seen []*design.AttributeDefinition) []*ImportSpec {
 
 	for _, a := range seen {
@@ -49,7 +49,17 @@ func AttributeImports(att *design.AttributeDefinition, imports []*ImportSpec, se
 		}
 	}
 
+	if att.Validation != nil {
+		for _, f := range att.Validation.Functions {
+			imports = appendImports(imports, []*ImportSpec{SimpleImport(f.PackagePath)})
+		}
+	}
+
 	switch t := att.Type.(type) {
+	case design.Primitive:
+		if t.Kind() == design.FileKind {
+			return appendImports(imports, []*ImportSpec{SimpleImport("mime/multipart")})
+		}
 	case *design.UserTypeDefinition:
 		return appendImports(imports, AttributeImports(t.AttributeDefinition, imports, seen))
 	case *design.MediaTypeDefinition:
@@ -92,6 +92,8 @@ func Publicizer(att *design.AttributeDefinition, sourceField, targetField string
 		} else {
 			publication = RunTemplate(objectPublicizeT, data)
 		}
+	case att.Type.IsUnion():
+		publication = RunTemplate(recursivePublicizeT, data)
 	case att.Type.IsArray():
 		// If the array element is primitive type, we can simply copy the elements over (i.e) []string
 		if att.Type.HasAttributes() {
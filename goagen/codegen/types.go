@@ -20,6 +20,10 @@ var (
 	// TempCount holds the value appended to variable names to make them unique.
 	TempCount int
 
+	// FileType is the name of the Go type used to represent File attributes. Generated fields
+	// holding files are always pointers to this type.
+	FileType = "multipart.FileHeader"
+
 	// Templates used by GoTypeTransform
 	transformT       *template.Template
 	transformArrayT  *template.Template
@@ -87,6 +91,8 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 		return fmt.Sprintf("map[%s]%s", keyDef, elemDef)
 	case design.Object:
 		return goTypeDefObject(actual, def, tabs, jsonTags, private)
+	case *design.Union:
+		return goTypeDefUnion(ds, tabs, private)
 	case *design.UserTypeDefinition:
 		return GoTypeName(actual, actual.AllRequired(), tabs, private)
 	case *design.MediaTypeDefinition:
@@ -141,6 +147,31 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 	return buffer.String()
 }
 
+// goTypeDefUnion returns the Go code that defines the type of a union. The public type is a sealed
+// interface implemented by the union types, see UnionMethod. The private type holds the raw
+// decoded value so that it can be converted to the actual type once the discriminator is known.
+func goTypeDefUnion(ds design.DataStructure, tabs int, private bool) string {
+	ut, ok := ds.(*design.UserTypeDefinition)
+	if private || !ok {
+		return "map[string]interface{}"
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString("interface {\n")
+	WriteTabs(&buffer, tabs+1)
+	buffer.WriteString(UnionMethod(ut) + "()\n")
+	WriteTabs(&buffer, tabs+1)
+	buffer.WriteString("Validate() error\n")
+	WriteTabs(&buffer, tabs)
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+// UnionMethod returns the name of the unexported method that seals the interface generated for
+// the given union user type.
+func UnionMethod(ut *design.UserTypeDefinition) string {
+	return "is" + Goify(ut.TypeName, true)
+}
+
 // attributeTags computes the struct field tags.
 func attributeTags(parent, att *design.AttributeDefinition, name string, private bool) string {
 	var elems []string
@@ -215,6 +246,8 @@ func GoTypeName(t design.DataType, required []string, tabs int, private bool) st
 			GoTypeRef(actual.KeyType.Type, actual.KeyType.AllRequired(), tabs+1, private),
 			GoTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs+1, private),
 		)
+	case *design.Union:
+		return "interface{}"
 	case *design.UserTypeDefinition:
 		return Goify(actual.TypeName, !private)
 	case *design.MediaTypeDefinition:
@@ -246,6 +279,8 @@ func GoNativeType(t design.DataType) string {
 			return "uuid.UUID"
 		case design.AnyKind:
 			return "interface{}"
+		case design.FileKind:
+			return FileType
 		default:
 			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 		}
@@ -255,6 +290,8 @@ func GoNativeType(t design.DataType) string {
 		return "map[string]interface{}"
 	case *design.Hash:
 		return fmt.Sprintf("map[%s]%s", GoNativeType(actual.KeyType.Type), GoNativeType(actual.ElemType.Type))
+	case *design.Union:
+		return "map[string]interface{}"
 	case *design.MediaTypeDefinition:
 		return GoNativeType(actual.Type)
 	case *design.UserTypeDefinition:
@@ -530,6 +567,8 @@ func GoTypeTransform(source, target *design.UserTypeDefinition, targetPkg, funcN
 			return "", fmt.Errorf("source is a hash but target type is %s", target.Type.Name())
 		}
 		impl, err = transformHash(source.ToHash(), target.ToHash(), targetPkg, "source", "target", 1)
+	case source.IsUnion():
+		return "", fmt.Errorf("cannot transform union type %s", source.TypeName)
 	default:
 		panic("cannot transform primitive types") // bug
 	}
@@ -430,6 +430,47 @@ var _ = Describe("code generation", func() {
 			})
 		})
 
+		Context("given a union user type", func() {
+			var ut *UserTypeDefinition
+			var private bool
+			var source string
+
+			BeforeEach(func() {
+				email := &UserTypeDefinition{
+					TypeName:            "EmailNotification",
+					AttributeDefinition: &AttributeDefinition{Type: Object{"type": &AttributeDefinition{Type: String}}},
+				}
+				union := &Union{Types: []*UserTypeDefinition{email}, Discriminator: "type"}
+				ut = &UserTypeDefinition{
+					TypeName:            "Notification",
+					AttributeDefinition: &AttributeDefinition{Type: union},
+				}
+				private = false
+			})
+
+			JustBeforeEach(func() {
+				source = codegen.GoTypeDef(ut, 0, true, private)
+			})
+
+			It("produces a sealed interface", func() {
+				Ω(source).Should(Equal("interface {\n\tisNotification()\n\tValidate() error\n}"))
+			})
+
+			Context("that is private", func() {
+				BeforeEach(func() {
+					private = true
+				})
+
+				It("produces a map", func() {
+					Ω(source).Should(Equal("map[string]interface{}"))
+				})
+			})
+
+			It("is referred to by name", func() {
+				Ω(codegen.GoTypeRef(ut, nil, 0, false)).Should(Equal("Notification"))
+			})
+		})
+
 	})
 })
 
@@ -18,6 +18,13 @@ var (
 	minMaxValT   *template.Template
 	lengthValT   *template.Template
 	requiredValT *template.Template
+
+	multipleOfValT  *template.Template
+	uniqueItemsValT *template.Template
+	propertiesValT  *template.Template
+	functionValT    *template.Template
+	requiredIfValT  *template.Template
+	oneOfValT       *template.Template
 )
 
 //  init instantiates the templates.
@@ -49,12 +56,31 @@ func init() {
 	if requiredValT, err = template.New("required").Funcs(fm).Parse(requiredValTmpl); err != nil {
 		panic(err)
 	}
+	if multipleOfValT, err = template.New("multipleOf").Funcs(fm).Parse(multipleOfValTmpl); err != nil {
+		panic(err)
+	}
+	if uniqueItemsValT, err = template.New("uniqueItems").Funcs(fm).Parse(uniqueItemsValTmpl); err != nil {
+		panic(err)
+	}
+	if propertiesValT, err = template.New("properties").Funcs(fm).Parse(propertiesValTmpl); err != nil {
+		panic(err)
+	}
+	if functionValT, err = template.New("function").Funcs(fm).Parse(functionValTmpl); err != nil {
+		panic(err)
+	}
+	if requiredIfValT, err = template.New("requiredIf").Funcs(fm).Parse(requiredIfValTmpl); err != nil {
+		panic(err)
+	}
+	if oneOfValT, err = template.New("oneOf").Funcs(fm).Parse(oneOfValTmpl); err != nil {
+		panic(err)
+	}
 }
 
 // Validator is the code generator for the 'Validate' type methods.
 type Validator struct {
 	arrayValT *template.Template
 	userValT  *template.Template
+	unionValT *template.Template
 	seen      map[string]*bytes.Buffer
 }
 
@@ -81,6 +107,10 @@ func NewValidator() *Validator {
 	if err != nil {
 		panic(err)
 	}
+	v.unionValT, err = template.New("union").Funcs(fm).Parse(unionValTmpl)
+	if err != nil {
+		panic(err)
+	}
 	return v
 }
 
@@ -140,14 +170,11 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 			first = false
 		}
 		val := v.Code(a.ElemType, true, false, false, "e", context+"[*]", depth+1, false)
-		if val != "" {
+		if val != "" || a.ElemType.Type.IsUnion() {
 			switch a.ElemType.Type.(type) {
 			case *design.UserTypeDefinition, *design.MediaTypeDefinition:
 				// For user and media types, call the Validate method
-				val = RunTemplate(v.userValT, map[string]interface{}{
-					"depth":  depth + 2,
-					"target": "e",
-				})
+				val = v.userValidation(a.ElemType, "e", context+"[*]", depth+2, private)
 				val = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), val, Tabs(depth+1))
 			}
 			data := map[string]interface{}{
@@ -181,27 +208,32 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 		// We need to check empirically whether there are validations to be
 		// generated, we can't just generate and check whether something was
 		// generated to avoid infinite recursions.
-		hasValidations := false
+		// Union values must always be validated so that the discriminator is checked.
+		hasValidations := catt.Type.IsUnion()
 		done := errors.New("done")
 		ds.Walk(func(a *design.AttributeDefinition) error {
 			if a.Validation != nil {
 				if private {
-					hasValidations = true
-					return done
+					// Validation functions only run on public data structures.
+					if !hasFunctionsOnly(a.Validation) {
+						hasValidations = true
+						return done
+					}
+					return nil
 				}
 				// For public data structures there is a case where
 				// there is validation but no actual validation
 				// code: if the validation is a required validation
 				// that applies to attributes that cannot be nil or
 				// empty string i.e. primitive types other than
-				// string.
+				// string and file.
 				if !a.Validation.HasRequiredOnly() {
 					hasValidations = true
 					return done
 				}
 				for _, name := range a.Validation.Required {
 					att := a.Type.ToObject()[name]
-					if att != nil && (!att.Type.IsPrimitive() || att.Type.Kind() == design.StringKind) {
+					if att != nil && (!att.Type.IsPrimitive() || att.Type.Kind() == design.StringKind || att.Type.Kind() == design.FileKind) {
 						hasValidations = true
 						return done
 					}
@@ -210,10 +242,10 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 			return nil
 		})
 		if hasValidations {
-			validation = RunTemplate(v.userValT, map[string]interface{}{
-				"depth":  depth,
-				"target": fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
-			})
+			validation = v.userValidation(catt,
+				fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
+				fmt.Sprintf("%s.%s", context, n),
+				depth, private)
 		}
 	} else {
 		dp := depth
@@ -232,7 +264,7 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 		).String()
 	}
 	if validation != "" {
-		if catt.Type.IsObject() {
+		if catt.Type.IsObject() || catt.Type.IsUnion() {
 			validation = fmt.Sprintf("%sif %s.%s != nil {\n%s\n%s}",
 				Tabs(depth), target, GoifyAtt(catt, n, true), validation, Tabs(depth))
 		}
@@ -240,6 +272,21 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 	return validation
 }
 
+// userValidation produces the Go code that validates the user type value held by target. The
+// private data structures of union types are decoded so that errors on the discriminator refer to
+// context.
+func (v *Validator) userValidation(att *design.AttributeDefinition, target, context string, depth int, private bool) string {
+	t := v.userValT
+	if private && att.Type.IsUnion() {
+		t = v.unionValT
+	}
+	return RunTemplate(t, map[string]interface{}{
+		"depth":   depth,
+		"target":  target,
+		"context": context,
+	})
+}
+
 // ValidationChecker produces Go code that runs the validation defined in the given attribute
 // definition against the content of the variable named target recursively.
 // context is used to keep track of recursion to produce helpful error messages in case of type
@@ -295,6 +342,7 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 	if min := validation.Minimum; min != nil {
 		data["min"] = *min
 		data["isMin"] = true
+		data["exclusive"] = validation.ExclusiveMinimum
 		delete(data, "max")
 		if val := RunTemplate(minMaxValT, data); val != "" {
 			res = append(res, val)
@@ -303,11 +351,18 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 	if max := validation.Maximum; max != nil {
 		data["max"] = *max
 		data["isMin"] = false
+		data["exclusive"] = validation.ExclusiveMaximum
 		delete(data, "min")
 		if val := RunTemplate(minMaxValT, data); val != "" {
 			res = append(res, val)
 		}
 	}
+	if multipleOf := validation.MultipleOf; multipleOf != nil {
+		data["multipleOf"] = *multipleOf
+		if val := RunTemplate(multipleOfValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if minLength := validation.MinLength; minLength != nil {
 		data["minLength"] = minLength
 		data["isMinLength"] = true
@@ -324,6 +379,25 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 			res = append(res, val)
 		}
 	}
+	if validation.UniqueItems {
+		if val := RunTemplate(uniqueItemsValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if minProperties := validation.MinProperties; minProperties != nil {
+		data["properties"] = *minProperties
+		data["isMinProperties"] = true
+		if val := RunTemplate(propertiesValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if maxProperties := validation.MaxProperties; maxProperties != nil {
+		data["properties"] = *maxProperties
+		data["isMinProperties"] = false
+		if val := RunTemplate(propertiesValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if required := validation.Required; len(required) > 0 {
 		var val string
 		for i, r := range required {
@@ -335,9 +409,102 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 		}
 		res = append(res, val)
 	}
+	if att, ok := data["attribute"].(*design.AttributeDefinition); ok && att.Type.IsObject() {
+		target, _ := data["target"].(string)
+		private, _ := data["private"].(bool)
+		for _, r := range validation.RequiredIf {
+			field, zero, _, ok := fieldPresence(att, r.Name, target, private)
+			if !ok {
+				continue
+			}
+			conds := []string{field + " == " + zero}
+			depField, depZero, value, ok := fieldPresence(att, r.DependsOn, target, private)
+			if ok {
+				conds = append(conds, depField+" != "+depZero)
+			}
+			if len(r.Values) > 0 {
+				conds = append(conds, "("+oneof(value, r.Values)+")")
+			}
+			data["requiredIf"] = r
+			data["condition"] = strings.Join(conds, " && ")
+			res = append(res, RunTemplate(requiredIfValT, data))
+		}
+		for _, names := range validation.AtLeastOneOf {
+			conds := make([]string, len(names))
+			for i, n := range names {
+				field, zero, _, ok := fieldPresence(att, n, target, private)
+				if !ok {
+					conds = nil
+					break
+				}
+				conds[i] = field + " == " + zero
+			}
+			if conds == nil {
+				continue
+			}
+			data["names"] = fmt.Sprintf("%#v", names)
+			data["condition"] = strings.Join(conds, " && ")
+			res = append(res, RunTemplate(oneOfValT, data))
+		}
+	}
+	if private, _ := data["private"].(bool); !private {
+		for _, f := range validation.Functions {
+			data["function"] = f
+			if val := RunTemplate(functionValT, data); val != "" {
+				res = append(res, val)
+			}
+		}
+	}
 	return
 }
 
+// fieldPresence returns the Go expression of the field with the given name of the object attribute,
+// the zero value the field has when it is not set and the expression that evaluates to the field
+// value. ok is false if the field cannot be missing, e.g. because it is a non-pointer field of a
+// primitive type.
+func fieldPresence(att *design.AttributeDefinition, name, target string, private bool) (field, zero, value string, ok bool) {
+	catt := att.Type.ToObject()[name]
+	if catt == nil {
+		return "", "", "", false
+	}
+	field = fmt.Sprintf("%s.%s", target, GoifyAtt(catt, name, true))
+	if !catt.Type.IsPrimitive() || catt.Type.Kind() == design.FileKind {
+		return field, "nil", field, true
+	}
+	if private || (!att.IsRequired(name) && !att.HasDefaultValue(name) && !att.IsNonZero(name)) {
+		return field, "nil", "*" + field, true
+	}
+	if catt.Type.Kind() == design.StringKind {
+		return field, `""`, field, true
+	}
+	return field, "", field, false
+}
+
+// HasValidationFunctions returns true if the given attribute or any of its children defines
+// custom validation functions. Validation functions only run on public data structures so that
+// callers must validate the public version of private data structures that have some.
+func HasValidationFunctions(att *design.AttributeDefinition) bool {
+	found := errors.New("found")
+	err := att.Walk(func(a *design.AttributeDefinition) error {
+		if a.Validation != nil && len(a.Validation.Functions) > 0 {
+			return found
+		}
+		return nil
+	})
+	return err == found
+}
+
+// hasFunctionsOnly returns true if the only rules defined by the given validation are custom
+// validation functions.
+func hasFunctionsOnly(validation *dslengine.ValidationDefinition) bool {
+	if len(validation.Functions) == 0 {
+		return false
+	}
+	v := validation.Dup()
+	v.Functions = nil
+	return v.HasRequiredOnly() && v.MinLength == nil && len(v.Required) == 0
+}
+
 // oneof produces code that compares target with each element of vals and ORs
 // the result, e.g. "target == 1 || target == 2".
 func oneof(target string, vals []interface{}) string {
@@ -382,6 +549,10 @@ const (
 
 	userValTmpl = `{{ tabs .depth }}if err2 := {{ .target }}.Validate(); err2 != nil {
 {{ tabs .depth }}	err = goa.MergeErrors(err, err2)
+{{ tabs .depth }}}`
+
+	unionValTmpl = `{{ tabs .depth }}if _, err2 := {{ .target }}.decode(` + "`" + `{{ .context }}` + "`" + `); err2 != nil {
+{{ tabs .depth }}	err = goa.MergeErrors(err, err2)
 {{ tabs .depth }}}`
 
 	enumValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
@@ -407,8 +578,8 @@ const (
 
 	minMaxValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
-{{ end }}{{ tabs .depth }}	if {{ .targetVal }} {{ if .isMin }}<{{ else }}>{{ end }} {{ if .isMin }}{{ .min }}{{ else }}{{ .max }}{{ end }} {
-{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
+{{ end }}{{ tabs .depth }}	if {{ .targetVal }} {{ if .isMin }}<{{ else }}>{{ end }}{{ if .exclusive }}={{ end }} {{ if .isMin }}{{ .min }}{{ else }}{{ .max }}{{ end }} {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.{{ if .exclusive }}InvalidExclusiveRangeError{{ else }}InvalidRangeError{{ end }}(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
@@ -420,10 +591,46 @@ const (
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
+	multipleOfValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs $depth }}if !goa.ValidateMultipleOf(float64({{ .targetVal }}), {{ .multipleOf }}) {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ .multipleOf }}))
+{{ if .isPointer }}{{ tabs $depth }}}
+{{ end }}{{ tabs .depth }}}`
+
+	uniqueItemsValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs $depth }}if !goa.ValidateUniqueItems({{ .target }}) {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `{{ .context }}` + "`" + `, {{ .target }}))
+{{ if .isPointer }}{{ tabs $depth }}}
+{{ end }}{{ tabs .depth }}}`
+
+	propertiesValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs $depth }}if len({{ .target }}) {{ if .isMinProperties }}<{{ else }}>{{ end }} {{ .properties }} {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidPropertiesCountError(` + "`" + `{{ .context }}` + "`" + `, {{ .target }}, len({{ .target }}), {{ .properties }}, {{ if .isMinProperties }}true{{ else }}false{{ end }}))
+{{ if .isPointer }}{{ tabs $depth }}}
+{{ end }}{{ tabs .depth }}}`
+
+	functionValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs $depth }}if err2 := {{ .function.Package }}.{{ .function.Name }}({{ .targetVal }}); err2 != nil {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidValueError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, err2))
+{{ if .isPointer }}{{ tabs $depth }}}
+{{ end }}{{ tabs .depth }}}`
+
+	requiredIfValTmpl = `{{ tabs .depth }}if {{ .condition }} {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.MissingDependentAttributeError(` + "`" + `{{ .context }}` + "`" + `, "{{ .requiredIf.Name }}", "{{ .requiredIf.DependsOn }}"))
+{{ tabs .depth }}}`
+
+	oneOfValTmpl = `{{ tabs .depth }}if {{ .condition }} {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.MissingOneOfAttributesError(` + "`" + `{{ .context }}` + "`" + `, {{ .names }}))
+{{ tabs .depth }}}`
+
 	requiredValTmpl = `{{ $att := index $.attribute.Type.ToObject .required }}{{/*
 */}}{{ if and (not $.private) (eq $att.Type.Kind 4) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == "" {
 {{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{  .required  }}"))
-{{ tabs $.depth }}}{{ else if or $.private (not $att.Type.IsPrimitive) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == nil {
+{{ tabs $.depth }}}{{ else if or $.private (not $att.Type.IsPrimitive) (eq $att.Type.Kind 14) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == nil {
 {{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{ .required }}"))
 {{ tabs $.depth }}}{{ end }}`
 )
@@ -71,6 +71,110 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of exclusive max value 10", func() {
+				BeforeEach(func() {
+					attType = design.Number
+					max := 10.0
+					validation = &dslengine.ValidationDefinition{
+						Maximum:          &max,
+						ExclusiveMaximum: true,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(exclusiveMaxValCode))
+				})
+			})
+
+			Context("of multiple of 0.5", func() {
+				BeforeEach(func() {
+					attType = design.Number
+					m := 0.5
+					validation = &dslengine.ValidationDefinition{
+						MultipleOf: &m,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(multipleOfValCode))
+				})
+			})
+
+			Context("of array unique items", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.String,
+						},
+					}
+					validation = &dslengine.ValidationDefinition{
+						UniqueItems: true,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(uniqueItemsValCode))
+				})
+			})
+
+			Context("of hash max properties 3", func() {
+				BeforeEach(func() {
+					attType = &design.Hash{
+						KeyType:  &design.AttributeDefinition{Type: design.String},
+						ElemType: &design.AttributeDefinition{Type: design.String},
+					}
+					max := 3
+					validation = &dslengine.ValidationDefinition{
+						MaxProperties: &max,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(maxPropertiesValCode))
+				})
+			})
+
+			Context("of validation function", func() {
+				BeforeEach(func() {
+					attType = design.String
+					validation = &dslengine.ValidationDefinition{
+						Functions: []*dslengine.ValidationFunction{
+							{PackagePath: "github.com/acme/validators", Name: "IBAN"},
+						},
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(functionValCode))
+				})
+
+				It("does not produce code for private data structures", func() {
+					code = codegen.NewValidator().Code(att, false, false, false, target, context, 1, true)
+					Ω(code).Should(BeEmpty())
+				})
+			})
+
+			Context("of conditional requirements", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"method":      &design.AttributeDefinition{Type: design.String},
+						"card_number": &design.AttributeDefinition{Type: design.String},
+						"email":       &design.AttributeDefinition{Type: design.String},
+						"phone":       &design.AttributeDefinition{Type: design.String},
+					}
+					validation = &dslengine.ValidationDefinition{
+						RequiredIf: []*dslengine.ConditionalRequirement{
+							{Name: "card_number", DependsOn: "method", Values: []interface{}{"card"}},
+						},
+						AtLeastOneOf: [][]string{{"email", "phone"}},
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(conditionalRequiredValCode))
+				})
+			})
+
 			Context("of array min length 1", func() {
 				BeforeEach(func() {
 					attType = &design.Array{
@@ -218,6 +322,28 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of union attribute", func() {
+				BeforeEach(func() {
+					union := &design.UserTypeDefinition{
+						TypeName: "Notification",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: &design.Union{Discriminator: "type"},
+						},
+					}
+					attType = design.Object{"notification": &design.AttributeDefinition{Type: union}}
+					validation = nil
+				})
+
+				It("calls Validate on public data structures", func() {
+					Ω(code).Should(ContainSubstring("if err2 := val.Notification.Validate(); err2 != nil {"))
+				})
+
+				It("decodes private data structures with the attribute context", func() {
+					code = codegen.NewValidator().Code(att, false, false, false, target, context, 1, true)
+					Ω(code).Should(ContainSubstring("if _, err2 := val.Notification.decode(`context.notification`); err2 != nil {"))
+				})
+			})
+
 		})
 	})
 })
@@ -241,6 +367,43 @@ const (
 		}
 	}`
 
+	exclusiveMaxValCode = `	if val != nil {
+		if *val >= 10 {
+			err = goa.MergeErrors(err, goa.InvalidExclusiveRangeError(` + "`" + `context` + "`" + `, *val, 10, false))
+		}
+	}`
+
+	multipleOfValCode = `	if val != nil {
+		if !goa.ValidateMultipleOf(float64(*val), 0.5) {
+			err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`" + `context` + "`" + `, *val, 0.5))
+		}
+	}`
+
+	uniqueItemsValCode = `	if val != nil {
+		if !goa.ValidateUniqueItems(val) {
+			err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `context` + "`" + `, val))
+		}
+	}`
+
+	maxPropertiesValCode = `	if val != nil {
+		if len(val) > 3 {
+			err = goa.MergeErrors(err, goa.InvalidPropertiesCountError(` + "`" + `context` + "`" + `, val, len(val), 3, false))
+		}
+	}`
+
+	functionValCode = `	if val != nil {
+		if err2 := validators.IBAN(*val); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidValueError(` + "`" + `context` + "`" + `, *val, err2))
+		}
+	}`
+
+	conditionalRequiredValCode = `	if val.CardNumber == nil && val.Method != nil && (*val.Method == "card") {
+		err = goa.MergeErrors(err, goa.MissingDependentAttributeError(` + "`" + `context` + "`" + `, "card_number", "method"))
+	}
+	if val.Email == nil && val.Phone == nil {
+		err = goa.MergeErrors(err, goa.MissingOneOfAttributesError(` + "`" + `context` + "`" + `, []string{"email", "phone"}))
+	}`
+
 	arrayMinLengthValCode = `	if val != nil {
 		if len(val) < 1 {
 			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `context` + "`" + `, val, len(val), 1, true))
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
@@ -101,6 +102,12 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateUserTypes(); err != nil {
 		return nil, err
 	}
+	if err := g.generateCallbacks(); err != nil {
+		return nil, err
+	}
+	if err := g.generateErrors(); err != nil {
+		return nil, err
+	}
 	if !g.NoTest {
 		if err := g.generateResourceTest(); err != nil {
 			return nil, err
@@ -130,6 +137,7 @@ func (g *Generator) generateContexts() error {
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("mime/multipart"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
@@ -139,6 +147,22 @@ func (g *Generator) generateContexts() error {
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 	}
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		atts := []*design.AttributeDefinition{r.Params, r.Headers, r.Cookies}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			atts = append(atts, a.Params, a.Headers, a.Cookies)
+			if a.Payload != nil {
+				atts = append(atts, a.Payload.AttributeDefinition)
+			}
+			return nil
+		})
+		for _, att := range atts {
+			if att != nil {
+				imports = codegen.AttributeImports(att, imports, nil)
+			}
+		}
+		return nil
+	})
 	g.genfiles = append(g.genfiles, ctxFile)
 	ctxWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
@@ -158,6 +182,20 @@ func (g *Generator) generateContexts() error {
 			if headers != nil && len(headers.Type.ToObject()) == 0 {
 				headers = nil // So that {{if .Headers}} returns false in templates
 			}
+			cookies := &design.AttributeDefinition{
+				Type: design.Object{},
+			}
+			if r.Cookies != nil {
+				cookies.Merge(r.Cookies)
+				cookies.Validation = r.Cookies.Validation
+			}
+			if a.Cookies != nil {
+				cookies.Merge(a.Cookies)
+				cookies.Validation = a.Cookies.Validation
+			}
+			if len(cookies.Type.ToObject()) == 0 {
+				cookies = nil // So that {{if .Cookies}} returns false in templates
+			}
 			params := a.AllParams()
 			if params != nil && len(params.Type.ToObject()) == 0 {
 				params = nil // So that {{if .Params}} returns false in templates
@@ -169,6 +207,18 @@ func (g *Generator) generateContexts() error {
 					non101[k] = v
 				}
 			}
+			var errs []*ErrorTemplateData
+			err := a.IterateErrors(func(e *design.ErrorDefinition) error {
+				data, err := NewErrorTemplateData(e)
+				if err != nil {
+					return err
+				}
+				errs = append(errs, data)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
 			ctxData := ContextTemplateData{
 				Name:         ctxName,
 				ResourceName: r.Name,
@@ -176,11 +226,17 @@ func (g *Generator) generateContexts() error {
 				Payload:      a.Payload,
 				Params:       params,
 				Headers:      headers,
+				Cookies:      cookies,
 				Routes:       a.Routes,
 				Responses:    non101,
 				API:          g.API,
 				DefaultPkg:   g.Target,
 				Security:     a.Security,
+				Pagination:   a.Pagination,
+				PageHref:     pageHref(a, params),
+				ETag:         a.HasETag(),
+				Negotiated:   len(a.EffectiveProduces()) > 0,
+				Errors:       errs,
 			}
 			return ctxWr.Execute(&ctxData)
 		})
@@ -207,6 +263,9 @@ func (g *Generator) generateControllers() error {
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/cors"),
 		codegen.SimpleImport("regexp"),
+		codegen.SimpleImport("strconv"),
+		codegen.SimpleImport("time"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
 	encoders, err := BuildEncoders(g.API.Produces, true)
 	if err != nil {
@@ -252,6 +311,7 @@ func (g *Generator) generateControllers() error {
 					RequestPath: rpath,
 					Metadata:    fs.Metadata,
 					Security:    fs.Security,
+					Cache:       fs.Cache,
 				})
 			}
 		}
@@ -264,14 +324,24 @@ func (g *Generator) generateControllers() error {
 		ierr := r.IterateActions(func(a *design.ActionDefinition) error {
 			context := fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
 			unmarshal := fmt.Sprintf("unmarshal%s%sPayload", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+			var consumes []string
+			if a.Payload != nil && !a.PayloadMultipart {
+				consumes = a.EffectiveConsumes()
+			}
 			action := map[string]interface{}{
-				"Name":            codegen.Goify(a.Name, true),
-				"Routes":          a.Routes,
-				"Context":         context,
-				"Unmarshal":       unmarshal,
-				"Payload":         a.Payload,
-				"PayloadOptional": a.PayloadOptional,
-				"Security":        a.Security,
+				"Name":             codegen.Goify(a.Name, true),
+				"Routes":           a.Routes,
+				"Context":          context,
+				"Unmarshal":        unmarshal,
+				"Payload":          a.Payload,
+				"PayloadOptional":  a.PayloadOptional,
+				"PayloadMultipart": a.PayloadMultipart,
+				"Idempotent":       a.Idempotent,
+				"ETag":             a.HasETag(),
+				"RateLimit":        a.EffectiveRateLimit(),
+				"Produces":         a.EffectiveProduces(),
+				"Consumes":         consumes,
+				"Security":         a.Security,
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
@@ -412,6 +482,7 @@ func (g *Generator) generateUserTypes() error {
 	}
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
@@ -431,3 +502,114 @@ func (g *Generator) generateUserTypes() error {
 	}
 	return utWr.FormatCode()
 }
+
+// generateErrors iterates through the errors declared in the design and generates their
+// constructors.
+func (g *Generator) generateErrors() error {
+	found := false
+	g.API.IterateErrors(func(e *design.ErrorDefinition) error {
+		found = true
+		return nil
+	})
+	if !found {
+		return nil
+	}
+	errFile := filepath.Join(g.OutDir, "errors.go")
+	errWr, err := NewErrorsWriter(errFile)
+	if err != nil {
+		panic(err) // bug
+	}
+	title := fmt.Sprintf("%s: Application Errors", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	errWr.WriteHeader(title, g.Target, imports)
+	g.genfiles = append(g.genfiles, errFile)
+	if err = errWr.Execute(g.API); err != nil {
+		return err
+	}
+	return errWr.FormatCode()
+}
+
+// generateCallbacks generates the callback sender and the callback payload types if the API
+// defines callbacks.
+func (g *Generator) generateCallbacks() error {
+	var payloads []*design.AttributeDefinition
+	found := false
+	g.API.IterateCallbacks(func(cb *design.CallbackDefinition) error {
+		found = true
+		if cb.Payload != nil {
+			payloads = append(payloads, cb.Payload.AttributeDefinition)
+		}
+		return nil
+	})
+	if !found {
+		return nil
+	}
+	cbFile := filepath.Join(g.OutDir, "callbacks.go")
+	cbWr, err := NewCallbacksWriter(cbFile)
+	if err != nil {
+		panic(err) // bug
+	}
+	title := fmt.Sprintf("%s: Application Callbacks", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/client"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+	}
+	for _, att := range payloads {
+		imports = codegen.AttributeImports(att, imports, nil)
+	}
+	cbWr.WriteHeader(title, g.Target, imports)
+	g.genfiles = append(g.genfiles, cbFile)
+	if err = cbWr.Execute(g.API); err != nil {
+		return err
+	}
+	return cbWr.FormatCode()
+}
+
+// pageHref returns the Go expression that computes the href of the collection returned by the
+// paginated action a given its params. The expression relies on the Href function of the parent
+// resource when there is one and builds the href from the action path otherwise.
+func pageHref(a *design.ActionDefinition, params *design.AttributeDefinition) string {
+	if a.Pagination == "" || len(a.Routes) == 0 {
+		return ""
+	}
+	fullPath := a.Routes[0].FullPath()
+	paramRef := func(name string) string {
+		att := params.Type.ToObject()[name]
+		ref := "ctx." + codegen.GoifyAtt(att, name, true)
+		if att.Type.IsPrimitive() && params.IsPrimitivePointer(name) {
+			ref = "*" + ref
+		}
+		return ref
+	}
+	if p := a.Parent.Parent(); p != nil && !a.Routes[0].IsAbsolute() {
+		if tmpl := p.URITemplate(); tmpl != "" && strings.HasPrefix(fullPath, tmpl) {
+			names := design.ExtractWildcards(tmpl)
+			args := make([]string, len(names))
+			for i, n := range names {
+				args[i] = paramRef(n)
+			}
+			href := fmt.Sprintf("%sHref(%s)", codegen.Goify(p.Name, true), strings.Join(args, ", "))
+			if suffix := strings.TrimPrefix(fullPath, tmpl); suffix != "" {
+				href += fmt.Sprintf(" + %q", suffix)
+			}
+			return href
+		}
+	}
+	names := design.ExtractWildcards(fullPath)
+	if len(names) == 0 {
+		return fmt.Sprintf("%q", fullPath)
+	}
+	args := make([]string, len(names))
+	for i, n := range names {
+		args[i] = paramRef(n)
+	}
+	tmpl := design.WildcardRegex.ReplaceAllLiteralString(fullPath, "/%v")
+	return fmt.Sprintf("fmt.Sprintf(%q, %s)", tmpl, strings.Join(args, ", "))
+}
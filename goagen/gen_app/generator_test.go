@@ -244,6 +244,54 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with problem details", func() {
+			BeforeEach(func() {
+				design.Design.ProblemDetails = true
+			})
+
+			It("configures the service to render errors as problem details", func() {
+				Ω(genErr).Should(BeNil())
+
+				controllersContent, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(controllersContent)).Should(ContainSubstring(controllersProblemDetailsCode))
+			})
+		})
+
+		Context("with a paginated child resource", func() {
+			BeforeEach(func() {
+				gadgets := &design.ResourceDefinition{
+					Name:       "Gadget",
+					BasePath:   "/gadgets",
+					ParentName: "Widget",
+				}
+				list := &design.ActionDefinition{
+					Name:   "list",
+					Parent: gadgets,
+					Params: &design.AttributeDefinition{
+						Type: design.Object{
+							"id":     {Type: design.String},
+							"cursor": {Type: design.String},
+							"limit":  {Type: design.Integer},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+					},
+					Pagination: design.CursorPagination,
+				}
+				list.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "", Parent: list}}
+				gadgets.Actions = map[string]*design.ActionDefinition{"list": list}
+				design.Design.Resources["Gadget"] = gadgets
+			})
+
+			It("builds the page links from the parent resource href", func() {
+				Ω(genErr).Should(BeNil())
+
+				contextsContent, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(contextsContent)).Should(ContainSubstring(`goa.CursorPageLinks(WidgetHref(ctx.ID)+"/gadgets", ctx.Request.URL.Query(), next)`))
+			})
+		})
+
 	})
 })
 
@@ -464,6 +512,14 @@ func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *h
 }
 `
 
+const controllersProblemDetailsCode = `
+	// Setup default encoder and decoder
+
+	// Render errors as RFC 7807 problem details
+	service.ProblemDetails = true
+}
+`
+
 const controllersOptionalPayloadCode = `
 // MountWidgetController "mounts" a Widget resource controller on the given service.
 func MountWidgetController(service *goa.Service, ctrl WidgetController) {
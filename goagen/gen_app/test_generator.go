@@ -1,6 +1,7 @@
 package genapp
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -55,6 +56,16 @@ func (t *TestMethod) Escape(s string) string {
 	return s
 }
 
+// PayloadExamples structure
+type PayloadExamples struct {
+	Name         string
+	ActionName   string
+	ResourceName string
+	Type         string
+	Pointer      string
+	Examples     map[string]string
+}
+
 // ObjectType structure
 type ObjectType struct {
 	Label       string
@@ -72,6 +83,7 @@ func (g *Generator) generateResourceTest() error {
 		"isSlice": isSlice,
 	}
 	testTmpl := template.Must(template.New("test").Funcs(funcs).Parse(testTmpl))
+	examplesTmpl := template.Must(template.New("examples").Parse(payloadExamplesTmpl))
 	outDir, err := makeTestDir(g, g.API.Name)
 	if err != nil {
 		return err
@@ -82,6 +94,7 @@ func (g *Generator) generateResourceTest() error {
 	}
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
 		codegen.SimpleImport("log"),
@@ -110,15 +123,23 @@ func (g *Generator) generateResourceTest() error {
 		}
 
 		var methods []*TestMethod
+		var examples []*PayloadExamples
 
 		if err := res.IterateActions(func(action *design.ActionDefinition) error {
+			if action.Payload != nil && len(action.Payload.Examples) > 0 {
+				ex, err := g.createPayloadExamples(res, action)
+				if err != nil {
+					return err
+				}
+				examples = append(examples, ex)
+			}
 			if err := action.IterateResponses(func(response *design.ResponseDefinition) error {
 				if response.Status == 101 { // SwitchingProtocols, Don't currently handle WebSocket endpoints
 					return nil
 				}
 				for routeIndex, route := range action.Routes {
 					mediaType := design.Design.MediaTypeWithIdentifier(response.MediaType)
-					if mediaType == nil {
+					if mediaType == nil || response.Stream != "" { // Streamed bodies are left to the test to decode
 						methods = append(methods, g.createTestMethod(res, action, response, route, routeIndex, nil, nil))
 					} else {
 						if err := mediaType.IterateViews(func(view *design.ViewDefinition) error {
@@ -142,6 +163,9 @@ func (g *Generator) generateResourceTest() error {
 		if err != nil {
 			panic(err)
 		}
+		if err := examplesTmpl.Execute(file, examples); err != nil {
+			return err
+		}
 		return file.FormatCode()
 	})
 }
@@ -207,7 +231,7 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 		payload = &ObjectType{}
 		payload.Name = "payload"
 		payload.Type = fmt.Sprintf("%s.%s", g.Target, codegen.Goify(action.Payload.TypeName, true))
-		if !action.Payload.IsPrimitive() && !action.Payload.IsArray() && !action.Payload.IsHash() {
+		if !action.Payload.IsPrimitive() && !action.Payload.IsArray() && !action.Payload.IsHash() && !action.Payload.IsUnion() {
 			payload.Pointer = "*"
 		}
 
@@ -227,7 +251,7 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 		Headers:           header,
 		Payload:           payload,
 		ReturnType:        returnType,
-		ReturnsErrorMedia: mediaType == design.ErrorMedia,
+		ReturnsErrorMedia: mediaType == design.ErrorMedia || mediaType == design.ProblemMedia,
 		ControllerName:    fmt.Sprintf("%s.%sController", g.Target, ctrlName),
 		ContextVarName:    fmt.Sprintf("%sCtx", varName),
 		ContextType:       fmt.Sprintf("%s.New%s%sContext", g.Target, actionName, ctrlName),
@@ -239,6 +263,31 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 }
 
 // pathParams returns the path params for the given action and route.
+// createPayloadExamples returns the data used to render the function that returns the named
+// examples of the action payload.
+func (g *Generator) createPayloadExamples(resource *design.ResourceDefinition, action *design.ActionDefinition) (*PayloadExamples, error) {
+	actionName := codegen.Goify(action.Name, true)
+	ctrlName := codegen.Goify(resource.Name, true)
+	ex := &PayloadExamples{
+		Name:         fmt.Sprintf("%s%sPayloadExamples", actionName, ctrlName),
+		ActionName:   action.Name,
+		ResourceName: resource.Name,
+		Type:         fmt.Sprintf("%s.%s", g.Target, codegen.Goify(action.Payload.TypeName, true)),
+		Examples:     make(map[string]string, len(action.Payload.Examples)),
+	}
+	if action.Payload.IsObject() {
+		ex.Pointer = "*"
+	}
+	for _, e := range action.Payload.Examples {
+		b, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize example %#v of %s payload: %s", e.Name, action.Context(), err)
+		}
+		ex.Examples[e.Name] = string(b)
+	}
+	return ex, nil
+}
+
 func pathParams(action *design.ActionDefinition, route *design.RouteDefinition) []*ObjectType {
 	return paramFromNames(action, route.Params())
 }
@@ -349,6 +398,25 @@ var convertParamTmpl = `{{ if eq .Type "string" }}		sliceVal := []string{ {{ if
 */}}{{ else if eq .Type "time.Time" }}		sliceVal := []string{ {{ if .Pointer }}(*{{ end }}{{ .Name }}{{ if .Pointer }}){{ end }}.Format(time.RFC3339)}{{/*
 */}}{{ else }}		sliceVal := []string{fmt.Sprintf("%v", {{ if .Pointer }}*{{ end }}{{ .Name }})}{{ end }}`
 
+var payloadExamplesTmpl = `{{ range . }}
+// {{ .Name }} returns the named examples of the {{ .ActionName }} action payload of the {{ .ResourceName }}
+// resource indexed by name. It fails the test if an example cannot be decoded.
+func {{ .Name }}(t goatest.TInterface) map[string]{{ .Pointer }}{{ .Type }} {
+	raw := map[string]string{
+{{ range $name, $example := .Examples }}		{{ printf "%q" $name }}: {{ printf "%q" $example }},
+{{ end }}	}
+	examples := make(map[string]{{ .Pointer }}{{ .Type }}, len(raw))
+	for name, js := range raw {
+		var payload {{ .Type }}
+		if err := json.Unmarshal([]byte(js), &payload); err != nil {
+			t.Fatalf("invalid %s example: %s", name, err)
+		}
+		examples[name] = {{ if .Pointer }}&{{ end }}payload
+	}
+	return examples
+}
+{{ end }}`
+
 var testTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}` + `
 {{ range $test := . }}
 // {{ $test.Name }} {{ $test.Comment }}
@@ -57,8 +57,11 @@ var _ = Describe("Generate", func() {
 			codegen.TempCount = 0
 
 			userType := &design.UserTypeDefinition{
-				AttributeDefinition: &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
-				TypeName:            "CustomName",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+					Examples: []*design.ExampleDefinition{{Name: "few", Value: []string{"a", "b"}}},
+				},
+				TypeName: "CustomName",
 			}
 
 			intAttr := &design.AttributeDefinition{
@@ -255,6 +258,15 @@ var _ = Describe("Generate", func() {
 
 			Ω(content).Should(ContainSubstring(", payload app.CustomName)"))
 		})
+		It("generates the payload examples helper", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring("func GetFooPayloadExamples(t goatest.TInterface) map[string]app.CustomName {"))
+			Ω(content).Should(ContainSubstring(`"few": "[\"a\",\"b\"]",`))
+			Ω(content).Should(ContainSubstring("examples[name] = payload\n"))
+		})
+
 		It("generates header with DO NOT MODIFY", func() {
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
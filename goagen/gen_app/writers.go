@@ -3,13 +3,16 @@ package genapp
 import (
 	"fmt"
 	"net/http"
+	"reflect"
 	"regexp"
 	"strings"
 	"text/template"
+	"time"
 
 	"sort"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 )
 
@@ -71,6 +74,49 @@ type (
 		Validator    *codegen.Validator
 	}
 
+	// CallbacksWriter generate code for the goa application callback sender.
+	// The sender exposes one method per callback defined in the DSL with "Callback".
+	CallbacksWriter struct {
+		*codegen.SourceFile
+		Validator *codegen.Validator
+	}
+
+	// CallbackTemplateData contains the information used by the template to render the sender
+	// method of a callback.
+	CallbackTemplateData struct {
+		Name        string // e.g. "order_shipped"
+		Description string
+		// PayloadRef is the Go type of the callback payload, empty if the callback has no
+		// payload.
+		PayloadRef string
+		// HasValidate is true if the payload type exposes a Validate method.
+		HasValidate bool
+		// Validation contains the code that validates payloads that are neither objects nor
+		// user types.
+		Validation string
+	}
+
+	// ErrorsWriter generate code for the goa application error constructors.
+	// The errors are defined in the DSL with "Error".
+	ErrorsWriter struct {
+		*codegen.SourceFile
+	}
+
+	// ErrorTemplateData contains the information used by the templates to render the
+	// constructor and the context response helpers of an error.
+	ErrorTemplateData struct {
+		Name        string // e.g. "out_of_stock"
+		Description string
+		Status      int
+		// ContentType is the value of the Content-Type header of the error responses.
+		ContentType string
+		// BodyRef is the Go type of the error response body, empty if the error uses the
+		// goa error media type.
+		BodyRef string
+		// Problem is true if the error responses are RFC 7807 problem details.
+		Problem bool
+	}
+
 	// ContextTemplateData contains all the information used by the template to render the context
 	// code for an action.
 	ContextTemplateData struct {
@@ -80,11 +126,17 @@ type (
 		Params       *design.AttributeDefinition
 		Payload      *design.UserTypeDefinition
 		Headers      *design.AttributeDefinition
+		Cookies      *design.AttributeDefinition
 		Routes       []*design.RouteDefinition
 		Responses    map[string]*design.ResponseDefinition
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		Pagination   string // e.g. "cursor"
+		PageHref     string // e.g. "AccountHref(ctx.AccountID) + \"/bottles\""
+		ETag         bool   // true if the responses carry an entity tag
+		Negotiated   bool   // true if the response content type is negotiated
+		Errors       []*ErrorTemplateData
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
@@ -166,6 +218,103 @@ func (c *ContextTemplateData) HasParamAndHeader(name string) bool {
 	return false
 }
 
+// HasCookieField returns true if the generated struct field name for the given cookie name
+// matches the generated struct field name of a param in c.Params or of a header in c.Headers.
+func (c *ContextTemplateData) HasCookieField(name string) bool {
+	if c.Cookies == nil {
+		return false
+	}
+
+	cookieAtt := c.Cookies.Type.ToObject()[name]
+	cookieName := codegen.GoifyAtt(cookieAtt, name, true)
+	for _, atts := range []*design.AttributeDefinition{c.Params, c.Headers} {
+		if atts == nil {
+			continue
+		}
+		for n, att := range atts.Type.ToObject() {
+			if codegen.GoifyAtt(att, n, true) == cookieName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResponseCookies returns the cookies set by the action responses. Cookies with the same name
+// defined by different responses are only returned once.
+func (c *ContextTemplateData) ResponseCookies() *design.AttributeDefinition {
+	cookies := design.Object{}
+	c.IterateResponses(func(resp *design.ResponseDefinition) error {
+		if resp.Cookies == nil {
+			return nil
+		}
+		for n, att := range resp.Cookies.Type.ToObject() {
+			if _, ok := cookies[n]; !ok {
+				cookies[n] = att
+			}
+		}
+		return nil
+	})
+	if len(cookies) == 0 {
+		return nil
+	}
+	return &design.AttributeDefinition{Type: cookies}
+}
+
+// ResponseHeaders returns the headers set by the action responses. Headers with the same name
+// defined by different responses are only returned once. It returns an error if they are defined
+// with different types or validations as a single setter could not validate them.
+func (c *ContextTemplateData) ResponseHeaders() (*design.AttributeDefinition, error) {
+	headers := design.Object{}
+	definedBy := make(map[string]string)
+	err := c.IterateResponses(func(resp *design.ResponseDefinition) error {
+		if resp.Headers == nil {
+			return nil
+		}
+		for n, att := range resp.Headers.Type.ToObject() {
+			other, ok := headers[n]
+			if !ok {
+				headers[n] = att
+				definedBy[n] = resp.Name
+				continue
+			}
+			if !sameHeader(att, other) {
+				return fmt.Errorf("action %#v of resource %#v: responses %#v and %#v define header %#v with different types or validations",
+					c.ActionName, c.ResourceName, definedBy[n], resp.Name, n)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	return &design.AttributeDefinition{Type: headers}, nil
+}
+
+// sameHeader returns true if the given header attributes have the same type and validations.
+func sameHeader(a, b *design.AttributeDefinition) bool {
+	if a == b {
+		return true
+	}
+	if a.Type.Name() != b.Type.Name() {
+		return false
+	}
+	if a.Type.IsArray() && a.Type.ToArray().ElemType.Type.Name() != b.Type.ToArray().ElemType.Type.Name() {
+		return false
+	}
+	va, vb := a.Validation, b.Validation
+	if va == nil {
+		va = &dslengine.ValidationDefinition{}
+	}
+	if vb == nil {
+		vb = &dslengine.ValidationDefinition{}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
 // MustValidate returns true if code that checks for the presence of the given param must be
 // generated.
 func (c *ContextTemplateData) MustValidate(name string) bool {
@@ -214,6 +363,7 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 		"arrayAttribute":     arrayAttribute,
 		"printVal":           codegen.PrintVal,
 		"canonicalHeaderKey": http.CanonicalHeaderKey,
+		"requiredHeaders":    requiredHeaders,
 	}
 	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
 		return err
@@ -236,6 +386,56 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			}
 		}
 	}
+	if data.Pagination != "" {
+		if err := w.ExecuteTemplate("pagination", ctxPaginationT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.ETag {
+		if err := w.ExecuteTemplate("etag", ctxETagT, nil, data); err != nil {
+			return err
+		}
+	}
+	if cookies := data.ResponseCookies(); cookies != nil {
+		fn := template.FuncMap{
+			"stringValue":   stringValue,
+			"cookieOptions": cookieOptions,
+		}
+		cookiesData := map[string]interface{}{
+			"Context": data,
+			"Cookies": cookies,
+		}
+		if err := w.ExecuteTemplate("cookies", ctxCookiesT, fn, cookiesData); err != nil {
+			return err
+		}
+	}
+	headers, err := data.ResponseHeaders()
+	if err != nil {
+		return err
+	}
+	if headers != nil {
+		fn := template.FuncMap{
+			"stringValue":    stringValue,
+			"arrayAttribute": arrayAttribute,
+			"validationCode": w.Validator.Code,
+		}
+		headersData := map[string]interface{}{
+			"Context": data,
+			"Headers": headers,
+		}
+		if err := w.ExecuteTemplate("headers", ctxHeadersT, fn, headersData); err != nil {
+			return err
+		}
+	}
+	for _, e := range data.Errors {
+		errData := map[string]interface{}{
+			"Context": data,
+			"Error":   e,
+		}
+		if err := w.ExecuteTemplate("error", ctxErrorT, nil, errData); err != nil {
+			return err
+		}
+	}
 	return data.IterateResponses(func(resp *design.ResponseDefinition) error {
 		respData := map[string]interface{}{
 			"Context":  data,
@@ -247,11 +447,24 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			if mt, ok = resp.Type.(*design.MediaTypeDefinition); !ok {
 				respData["Type"] = resp.Type
 				respData["ContentType"] = resp.MediaType
-				return w.ExecuteTemplate("response", ctxTRespT, nil, respData)
+				return w.ExecuteTemplate("response", ctxTRespT, fn, respData)
 			}
 		} else {
 			mt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
 		}
+		if mt != nil && resp.Stream != "" {
+			view := resp.ViewName
+			if view == "" {
+				view = "default"
+			}
+			projected, _, err := mt.Project(view)
+			if err != nil {
+				return err
+			}
+			respData["Projected"] = projected
+			respData["StreamName"] = strings.TrimSuffix(data.Name, "Context") + "Stream"
+			return w.ExecuteTemplate("stream", ctxStreamT, nil, respData)
+		}
 		if mt != nil {
 			var views []string
 			if resp.ViewName != "" {
@@ -286,7 +499,7 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			}
 			return nil
 		}
-		return w.ExecuteTemplate("response", ctxNoMTRespT, nil, respData)
+		return w.ExecuteTemplate("response", ctxNoMTRespT, fn, respData)
 	})
 }
 
@@ -326,7 +539,11 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 		if err := w.ExecuteTemplate("controller", ctrlT, nil, d); err != nil {
 			return err
 		}
-		if err := w.ExecuteTemplate("mount", mountT, nil, d); err != nil {
+		mountFn := template.FuncMap{
+			"durationCode":    durationCode,
+			"cachePolicyCode": cachePolicyCode,
+		}
+		if err := w.ExecuteTemplate("mount", mountT, mountFn, d); err != nil {
 			return err
 		}
 		if len(d.Origins) > 0 {
@@ -335,8 +552,10 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 			}
 		}
 		fn := template.FuncMap{
-			"finalizeCode":   w.Finalizer.Code,
-			"validationCode": w.Validator.Code,
+			"finalizeCode":           w.Finalizer.Code,
+			"validationCode":         w.Validator.Code,
+			"newCoerceData":          newCoerceData,
+			"hasValidationFunctions": codegen.HasValidationFunctions,
 		}
 		if err := w.ExecuteTemplate("unmarshal", unmarshalT, fn, d); err != nil {
 			return err
@@ -434,10 +653,104 @@ func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 	fn := template.FuncMap{
 		"finalizeCode":   w.Finalizer.Code,
 		"validationCode": w.Validator.Code,
+		"unionMethod":    codegen.UnionMethod,
+	}
+	if t.IsUnion() {
+		return w.ExecuteTemplate("union", unionTypeT, fn, t)
 	}
 	return w.ExecuteTemplate("types", userTypeT, fn, t)
 }
 
+// NewCallbacksWriter returns a callbacks code writer.
+// Callbacks are requests sent by the application to the URLs registered by subscribers.
+func NewCallbacksWriter(filename string) (*CallbacksWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &CallbacksWriter{SourceFile: file, Validator: codegen.NewValidator()}, nil
+}
+
+// Execute writes the code for the callback sender and callback payload types to the writer.
+func (w *CallbacksWriter) Execute(api *design.APIDefinition) error {
+	if err := w.ExecuteTemplate("sender", callbackSenderT, nil, api); err != nil {
+		return err
+	}
+	fn := template.FuncMap{"validationCode": w.Validator.Code}
+	return api.IterateCallbacks(func(cb *design.CallbackDefinition) error {
+		data := CallbackTemplateData{Name: cb.Name, Description: cb.Description}
+		if p := cb.Payload; p != nil {
+			_, isUserType := api.Types[p.TypeName]
+			if !isUserType && p.IsObject() {
+				if err := w.ExecuteTemplate("callbackPayload", callbackPayloadT, fn, cb); err != nil {
+					return err
+				}
+				isUserType = true
+			}
+			if isUserType {
+				data.PayloadRef = codegen.GoTypeRef(p, nil, 0, false)
+				data.HasValidate = w.Validator.Code(p.AttributeDefinition, false, false, false, "payload", "raw", 1, false) != ""
+			} else {
+				data.PayloadRef = codegen.GoTypeRef(p.Type, p.AllRequired(), 0, false)
+				data.Validation = w.Validator.Code(p.AttributeDefinition, false, true, false, "payload", "raw", 1, false)
+			}
+		}
+		return w.ExecuteTemplate("callback", callbackT, nil, &data)
+	})
+}
+
+// NewErrorsWriter returns an errors code writer.
+// Errors are the errors declared in the design that may be returned by the actions.
+func NewErrorsWriter(filename string) (*ErrorsWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &ErrorsWriter{SourceFile: file}, nil
+}
+
+// Execute writes the code for the error constructors to the writer.
+func (w *ErrorsWriter) Execute(api *design.APIDefinition) error {
+	return api.IterateErrors(func(e *design.ErrorDefinition) error {
+		data, err := NewErrorTemplateData(e)
+		if err != nil {
+			return err
+		}
+		return w.ExecuteTemplate("error", errorT, nil, data)
+	})
+}
+
+// NewErrorTemplateData computes the data used to render the code of the given error.
+func NewErrorTemplateData(e *design.ErrorDefinition) (*ErrorTemplateData, error) {
+	data := &ErrorTemplateData{
+		Name:        e.Name,
+		Description: e.Description,
+		Status:      e.Status,
+		ContentType: design.ErrorMediaIdentifier,
+	}
+	if e.IsProblem() {
+		data.ContentType = design.ProblemMediaIdentifier
+		data.Problem = true
+	}
+	if e.IsError() {
+		return data, nil
+	}
+	mt := design.Design.MediaTypeWithIdentifier(e.MediaType)
+	if mt == nil {
+		return nil, fmt.Errorf("unknown media type %#v for error %#v", e.MediaType, e.Name)
+	}
+	projected, _, err := mt.Project("default")
+	if err != nil {
+		return nil, err
+	}
+	data.BodyRef = codegen.GoTypeRef(projected, projected.AllRequired(), 0, false)
+	data.ContentType = mt.ContentType
+	if data.ContentType == "" {
+		data.ContentType = mt.Identifier
+	}
+	return data, nil
+}
+
 // newCoerceData is a helper function that creates a map that can be given to the "Coerce" template.
 func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, pkg string, depth int) map[string]interface{} {
 	return map[string]interface{}{
@@ -450,11 +763,114 @@ func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, p
 	}
 }
 
+// requiredHeaders returns the sorted names of the headers required by the given response.
+func requiredHeaders(resp *design.ResponseDefinition) []string {
+	if resp.Headers == nil {
+		return nil
+	}
+	var names []string
+	for n := range resp.Headers.Type.ToObject() {
+		if resp.Headers.IsRequired(n) {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stringValue returns the code that renders the value of the variable with the given name holding
+// the value of a cookie or header described by att as a string.
+func stringValue(att *design.AttributeDefinition, varName string) string {
+	switch att.Type.Kind() {
+	case design.BooleanKind:
+		return fmt.Sprintf("strconv.FormatBool(%s)", varName)
+	case design.IntegerKind:
+		return fmt.Sprintf("strconv.Itoa(%s)", varName)
+	case design.NumberKind:
+		return fmt.Sprintf("strconv.FormatFloat(%s, 'f', -1, 64)", varName)
+	case design.StringKind:
+		return varName
+	case design.DateTimeKind:
+		return fmt.Sprintf("%s.Format(time.RFC3339)", varName)
+	case design.UUIDKind:
+		return fmt.Sprintf("%s.String()", varName)
+	default:
+		return fmt.Sprintf("fmt.Sprint(%s)", varName)
+	}
+}
+
+// cookieOptions returns the http.Cookie struct fields initialized from the "cookie:path",
+// "cookie:domain", "cookie:max-age", "cookie:secure" and "cookie:http-only" metadata of att.
+func cookieOptions(att *design.AttributeDefinition) []string {
+	var opts []string
+	if v, ok := att.Metadata["cookie:path"]; ok && len(v) > 0 {
+		opts = append(opts, fmt.Sprintf("Path: %q", v[0]))
+	}
+	if v, ok := att.Metadata["cookie:domain"]; ok && len(v) > 0 {
+		opts = append(opts, fmt.Sprintf("Domain: %q", v[0]))
+	}
+	if v, ok := att.Metadata["cookie:max-age"]; ok && len(v) > 0 {
+		opts = append(opts, fmt.Sprintf("MaxAge: %s", v[0]))
+	}
+	if _, ok := att.Metadata["cookie:secure"]; ok {
+		opts = append(opts, "Secure: true")
+	}
+	if _, ok := att.Metadata["cookie:http-only"]; ok {
+		opts = append(opts, "HttpOnly: true")
+	}
+	return opts
+}
+
 // arrayAttribute returns the array element attribute definition.
 func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
 	return a.Type.(*design.Array).ElemType
 }
 
+// durationCode returns the Go code of an expression whose value is d, e.g. "10 * time.Minute".
+func durationCode(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+	for _, u := range units {
+		if d%u.unit != 0 {
+			continue
+		}
+		if d == u.unit {
+			return u.name
+		}
+		return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
+
+// cachePolicyCode returns the Go code of a goa.CachePolicy literal initialized from c.
+func cachePolicyCode(c *design.CacheDefinition) string {
+	var fields []string
+	if c.MaxAge > 0 {
+		fields = append(fields, "MaxAge: "+durationCode(time.Duration(c.MaxAge)*time.Second))
+	}
+	if c.Private {
+		fields = append(fields, "Private: true")
+	}
+	if c.NoStore {
+		fields = append(fields, "NoStore: true")
+	}
+	if len(c.Vary) > 0 {
+		vary := make([]string, len(c.Vary))
+		for i, v := range c.Vary {
+			vary[i] = fmt.Sprintf("%q", v)
+		}
+		fields = append(fields, fmt.Sprintf("Vary: []string{%s}", strings.Join(vary, ", ")))
+	}
+	return fmt.Sprintf("&goa.CachePolicy{%s}", strings.Join(fields, ", "))
+}
+
 const (
 	// ctxT generates the code for the context data type.
 	// template input: *ContextTemplateData
@@ -465,6 +881,8 @@ type {{ .Name }} struct {
 	*goa.RequestData
 {{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}{{ if not ($.HasParamAndHeader $name) }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Headers.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
+{{ end }}{{ end }}{{ end }}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}{{ if not ($.HasCookieField $name) }}{{/*
+*/}}	{{ goifyatt $att $name true }} {{ if $.Cookies.IsPrimitivePointer $name }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ if .Payload }}	Payload {{ gotyperef .Payload nil 0 false }}
@@ -567,9 +985,23 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 {{ template "Coerce" (newCoerceData $name $att ($.Headers.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Headers.IsNonZero $name) ($.Headers.IsRequired $name) ($.Headers.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
+{{ end }}{{ with $att.Deprecation }}		goa.LogDeprecatedAttribute(ctx, "{{ $name }}", {{ printf "%q" .Reason }})
 {{ end }}	}
 {{ end }}{{ end }}{{/* if .Headers }}{{/*
 
+*/}}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}	if cookie{{ goify $name true }}, err2 := r.Cookie("{{ $name }}"); err2 == nil {
+		raw{{ goify $name true }} := cookie{{ goify $name true }}.Value
+{{ template "Coerce" (newCoerceData $name $att ($.Cookies.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{/*
+*/}}{{ $validation := validationChecker $att ($.Cookies.IsNonZero $name) ($.Cookies.IsRequired $name) ($.Cookies.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
+*/}}{{ if $validation }}{{ $validation }}
+{{ end }}{{ with $att.Deprecation }}		goa.LogDeprecatedAttribute(ctx, "{{ $name }}", {{ printf "%q" .Reason }})
+{{ end }}	}{{ if $.Cookies.HasDefaultValue $name }} else {
+		{{ printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}
+	}{{ else if $.Cookies.IsRequired $name }} else {
+		err = goa.MergeErrors(err, goa.MissingCookieError("{{ $name }}"))
+	}{{ end }}
+{{ end }}{{ end }}{{/* if .Cookies }}{{/*
+
 */}}{{ if.Params }}{{ range $name, $att := .Params.Type.ToObject }}	param{{ goify $name true }} := req.Params["{{ $name }}"]
 {{ $mustValidate := $.MustValidate $name }}{{ if $mustValidate }}	if len(param{{ goify $name true }}) == 0 {
 		{{ if $.Params.HasDefaultValue $name }}{{printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}{{else}}{{/*
@@ -589,6 +1021,7 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 {{ template "Coerce" (newCoerceData $name $att ($.Params.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
+{{ end }}{{ with $att.Deprecation }}		goa.LogDeprecatedAttribute(ctx, "{{ $name }}", {{ printf "%q" .Reason }})
 {{ end }}	}
 {{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
 }
@@ -596,31 +1029,153 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 
 	// ctxMTRespT generates the response helpers for responses with media types.
 	// template input: map[string]interface{}
-	ctxMTRespT = `// {{ goify .RespName true }} sends a HTTP response with status code {{ .Response.Status }}.
+	ctxMTRespT = `{{ define "RequiredHeaders" }}` + requiredHeadersT + `{{ end }}{{ define "CacheHeaders" }}` + cacheHeadersT + `{{ end }}` + `// {{ goify .RespName true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}) error {
-	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-{{ if .Projected.Type.IsArray }}	if r == nil {
+{{ template "RequiredHeaders" .Response }}{{ template "CacheHeaders" .Response }}{{ if .Projected.IsProblem }}	return ctx.ResponseData.Service.SendProblem(ctx.Context, {{ .Response.Status }}, r)
+{{ else }}{{ if not .Context.Negotiated }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
+{{ end }}{{ if .Projected.Type.IsArray }}	if r == nil {
 		r = {{ gotyperef .Projected .Projected.AllRequired 0 false }}{}
 	}
 {{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ end }}}
+`
+
+	// cacheHeadersT generates the code that sets the cache headers of a response.
+	// template input: *design.ResponseDefinition
+	cacheHeadersT = `{{ with .EffectiveCache }}	ctx.ResponseData.Header().Set("Cache-Control", {{ printf "%q" .CacheControl }})
+{{ range .Vary }}	ctx.ResponseData.Header().Add("Vary", {{ printf "%q" . }})
+{{ end }}{{ end }}`
+
+	// ctxCookiesT generates the typed setters of the response cookies.
+	// template input: map[string]interface{}
+	ctxCookiesT = `{{ range $name, $att := .Cookies.Type.ToObject }}
+// Set{{ goify $name true }}Cookie sets the {{ $name }} cookie of the response.
+func (ctx *{{ $.Context.Name }}) Set{{ goify $name true }}Cookie(v {{ gotyperef $att.Type nil 0 false }}) {
+	http.SetCookie(ctx.ResponseData, &http.Cookie{
+		Name:  "{{ $name }}",
+		Value: {{ stringValue $att "v" }},
+{{ range cookieOptions $att }}		{{ . }},
+{{ end }}	})
+}
+{{ end }}`
+
+	// requiredHeadersT generates the code that checks that the headers required by a response
+	// are set.
+	// template input: *design.ResponseDefinition
+	requiredHeadersT = `{{ range requiredHeaders . }}	if ctx.ResponseData.Header().Get("{{ . }}") == "" {
+		return goa.MissingResponseHeaderError("{{ . }}")
+	}
+{{ end }}`
+
+	// ctxHeadersT generates the typed setters of the response headers.
+	// template input: map[string]interface{}
+	ctxHeadersT = `{{ range $name, $att := .Headers.Type.ToObject }}
+// Set{{ goify $name true }}Header sets the {{ $name }} header of the response. It returns an error if
+// the value does not satisfy the validations defined in the design.
+func (ctx *{{ $.Context.Name }}) Set{{ goify $name true }}Header(v {{ gotyperef $att.Type nil 0 false }}) (err error) {
+{{ $validation := validationCode $att true false false "v" $name 1 false }}{{ if $validation }}{{ $validation }}
+	if err != nil {
+		return goa.InvalidResponseHeaderError("{{ $name }}", err)
+	}
+{{ end }}{{ if $att.Type.IsArray }}	ctx.ResponseData.Header().Del("{{ $name }}")
+	for _, e := range v {
+		ctx.ResponseData.Header().Add("{{ $name }}", {{ stringValue (arrayAttribute $att) "e" }})
+	}
+{{ else }}	ctx.ResponseData.Header().Set("{{ $name }}", {{ stringValue $att "v" }})
+{{ end }}	return nil
+}
+{{ end }}`
+
+	// ctxPaginationT generates the helper that sets the Link header of paginated responses.
+	// template input: *ContextTemplateData
+	ctxPaginationT = `{{ if eq .Pagination "cursor" }}
+// SetPageLinks sets the RFC 5988 Link header of the response with a link to the page of results
+// starting at the given cursor. No link is set if next is empty.
+func (ctx *{{ .Name }}) SetPageLinks(next string) {
+	if links := goa.CursorPageLinks({{ .PageHref }}, ctx.Request.URL.Query(), next); links != "" {
+		ctx.ResponseData.Header().Set("Link", links)
+	}
+}
+{{ else }}{{ $params := .Params.Type.ToObject }}
+// SetPageLinks sets the RFC 5988 Link header of the response with links to the first, previous,
+// next and last pages of results given the total number of results.
+func (ctx *{{ .Name }}) SetPageLinks(total int) {
+	links := goa.OffsetPageLinks({{ .PageHref }}, ctx.Request.URL.Query(), ctx.{{ goifyatt (index $params "page") "page" true }}, ctx.{{ goifyatt (index $params "limit") "limit" true }}, total)
+	if links != "" {
+		ctx.ResponseData.Header().Set("Link", links)
+	}
+}
+{{ end }}`
+
+	// ctxETagT generates the helpers used by actions whose responses carry an entity tag.
+	// template input: *ContextTemplateData
+	ctxETagT = `
+// SetETag sets the ETag header of the response to the entity tag of the current representation,
+// etag is quoted if needed.
+func (ctx *{{ .Name }}) SetETag(etag string) {
+	ctx.ResponseData.Header().Set("ETag", goa.QuoteETag(etag))
+}
+
+// CheckPreconditions returns an error if the If-Match or If-None-Match request headers do not
+// match current, the entity tag of the current representation or the empty string if there is
+// none. Return the error to send a 412 Precondition Failed response.
+func (ctx *{{ .Name }}) CheckPreconditions(current string) error {
+	return goa.CheckPreconditions(ctx.Request, current)
+}
+`
+
+	// ctxStreamT generates the stream sender for streamed responses.
+	// template input: map[string]interface{}
+	ctxStreamT = `
+// {{ .StreamName }} sends the messages of the {{ .Context.ActionName }} action {{ .Response.Name }} response stream.
+type {{ .StreamName }} struct {
+	*goa.ResponseStream
+}
+
+// Stream starts the {{ .Response.Name }} response with status code {{ .Response.Status }} and returns the stream used to send
+// {{ if eq .Response.Stream "text/event-stream" }}Server-Sent Events{{ else }}newline delimited messages{{ end }}. Each message is flushed to the client as soon as it is sent.
+func (ctx *{{ .Context.Name }}) Stream() (*{{ .StreamName }}, error) {
+	s, err := goa.NewResponseStream(ctx.Context, {{ .Response.Status }}, "{{ .Response.Stream }}")
+	if err != nil {
+		return nil, err
+	}
+	return &{{ .StreamName }}{ResponseStream: s}, nil
+}
+
+// Send writes a message to the stream, it returns an error if the request context is done.
+func (s *{{ .StreamName }}) Send(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}) error {
+	return s.ResponseStream.Send(r)
 }
 `
 
 	// ctxTRespT generates the response helpers for responses with overridden types.
 	// template input: map[string]interface{}
-	ctxTRespT = `// {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
+	ctxTRespT = `{{ define "RequiredHeaders" }}` + requiredHeadersT + `{{ end }}{{ define "CacheHeaders" }}` + cacheHeadersT + `{{ end }}` + `// {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .Type nil 0 false }}) error {
-	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ template "RequiredHeaders" .Response }}{{ template "CacheHeaders" .Response }}{{ if not .Context.Negotiated }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
+{{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
+`
+
+	// ctxErrorT generates the response helpers for the errors declared in the design.
+	// template input: map[string]interface{}
+	ctxErrorT = `{{ $name := goify .Error.Name true }}
+// {{ $name }}Error sends a HTTP response with status code {{ .Error.Status }} describing the {{ .Error.Name }} error.
+func (ctx *{{ .Context.Name }}) {{ $name }}Error({{ if .Error.BodyRef }}r {{ .Error.BodyRef }}{{ else }}message interface{}, keyvals ...interface{}{{ end }}) error {
+{{ if .Error.Problem }}	return ctx.ResponseData.Service.SendProblem(ctx.Context, {{ .Error.Status }}, Err{{ $name }}(message, keyvals...))
+{{ else }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .Error.ContentType }}")
+{{ if .Error.BodyRef }}	ctx.ResponseData.Header().Set(goa.ErrorCodeHeader, {{ printf "%q" .Error.Name }})
+	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Error.Status }}, r)
+{{ else }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Error.Status }}, Err{{ $name }}(message, keyvals...))
+{{ end }}{{ end }}}
 `
 
 	// ctxNoMTRespT generates the response helpers for responses with no known media type.
 	// template input: *ContextTemplateData
-	ctxNoMTRespT = `
+	ctxNoMTRespT = `{{ define "RequiredHeaders" }}` + requiredHeadersT + `{{ end }}{{ define "CacheHeaders" }}` + cacheHeadersT + `{{ end }}` + `
 // {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.MediaType }}resp []byte{{ end }}) error {
-{{ if .Response.MediaType }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
+{{ template "RequiredHeaders" .Response }}{{ template "CacheHeaders" .Response }}{{ if .Response.MediaType }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
 {{ end }}	ctx.ResponseData.WriteHeader({{ .Response.Status }}){{ if .Response.MediaType }}
 	_, err := ctx.ResponseData.Write(resp)
 	return err{{ else }}
@@ -661,6 +1216,62 @@ func (payload {{ gotyperef .Payload .Payload.AllRequired 0 false }}) Validate()
 	return
 }{{ end }}
 `
+	// callbackSenderT generates the callback sender type.
+	// template input: *design.APIDefinition
+	callbackSenderT = `// CallbackSender sends the {{ .Name }} callbacks. It delivers the requests using a webhook
+// sender that signs them and retries failed deliveries.
+type CallbackSender struct {
+	*client.WebhookSender
+}
+
+// NewCallbackSender creates a callback sender that delivers the requests with sender.
+func NewCallbackSender(sender *client.WebhookSender) *CallbackSender {
+	return &CallbackSender{WebhookSender: sender}
+}
+`
+
+	// callbackPayloadT generates the callback payload type definition.
+	// template input: *design.CallbackDefinition
+	callbackPayloadT = `// {{ gotypename .Payload nil 0 false }} is the {{ .Name }} callback payload.
+type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
+
+{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}// Validate runs the validation rules defined in the design.
+func (payload {{ gotyperef .Payload .Payload.AllRequired 0 false }}) Validate() (err error) {
+{{ $validation }}
+	return
+}{{ end }}
+`
+
+	// callbackT generates the callback sender method.
+	// template input: *CallbackTemplateData
+	callbackT = `{{ $name := goify .Name true }}// Send{{ $name }} sends the {{ .Name }} callback to url.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func (s *CallbackSender) Send{{ $name }}(ctx context.Context, url string{{ if .PayloadRef }}, payload {{ .PayloadRef }}{{ end }}) error {
+{{ if .HasValidate }}	if err := payload.Validate(); err != nil {
+		return err
+	}
+{{ else if .Validation }}	var err error
+{{ .Validation }}
+	if err != nil {
+		return err
+	}
+{{ end }}{{ if .PayloadRef }}	return s.SendJSON(ctx, url, {{ printf "%q" .Name }}, payload)
+{{ else }}	return s.Send(ctx, url, {{ printf "%q" .Name }}, nil)
+{{ end }}}
+`
+
+	// errorT generates the constructor of an error.
+	// template input: *ErrorTemplateData
+	errorT = `{{ $name := goify .Name true }}{{ if .BodyRef }}// Err{{ $name }} creates {{ .Name }} errors with the given response body.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func Err{{ $name }}(body {{ .BodyRef }}) error {
+	return goa.NewTypedError({{ printf "%q" .Name }}, {{ .Status }}, {{ printf "%q" .ContentType }}, body)
+}
+{{ else }}// Err{{ $name }} creates {{ .Name }} errors.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+var Err{{ $name }} = goa.NewErrorClass({{ printf "%q" .Name }}, {{ .Status }})
+{{ end }}`
+
 	// ctrlT generates the controller interface for a given resource.
 	// template input: *ControllerTemplateData
 	ctrlT = `// {{ .Resource }}Controller is the controller interface for the {{ .Resource }} actions.
@@ -688,12 +1299,15 @@ func initService(service *goa.Service) {
 */}}	service.Encoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
 {{ end }}{{ end }}{{ range .Decoders }}{{ if .Default }}{{/*
 */}}	service.Decoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
-{{ end }}{{ end }}}
+{{ end }}{{ end }}{{ if .API.ProblemDetails }}
+	// Render errors as RFC 7807 problem details
+	service.ProblemDetails = true
+{{ end }}}
 `
 
 	// mountT generates the code for a resource "Mount" function.
 	// template input: *ControllerTemplateData
-	mountT = `
+	mountT = `{{ define "mimeTypes" }}` + mimeTypesT + `{{ end }}
 // Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given service.
 func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
 	initService(service)
@@ -719,12 +1333,18 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 {{ end }}		}
 {{ end }}		return ctrl.{{ .Name }}(rctx)
 	}
-{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ if or .Produces .Consumes }}	h = goa.NegotiatedHandler(h, {{ template "mimeTypes" .Produces }}, {{ template "mimeTypes" .Consumes }})
+{{ end }}{{ if .Idempotent }}	h = goa.IdempotentHandler(service, h)
+{{ end }}{{ if .ETag }}	h = goa.ETagHandler(service, h)
+{{ end }}{{ with .RateLimit }}	h = goa.RateLimitedHandler(service, h, {{ printf "%q" .Scope }}, {{ .Requests }}, {{ durationCode .Period }})
+{{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
-{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
+{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, {{/*
+*/}}{{ with .EffectiveDeprecation }}goa.DeprecatedHandler(h, {{ printf "%q" .Reason }}, {{ printf "%q" .SunsetHeader }}){{ else }}h{{ end }}, {{/*
+*/}}{{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
 {{ end }}{{ end }}{{ range .FileServers }}
-	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }})
+	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }}{{ with .Cache }}, {{ cachePolicyCode . }}{{ end }})
 {{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
 {{ end }}	service.Mux.Handle("GET", "{{ .RequestPath }}", ctrl.MuxHandler("serve", h, nil))
@@ -732,6 +1352,10 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 {{ end }}}
 `
 
+	// mimeTypesT generates the code of a slice literal listing the given MIME types.
+	// template input: []string
+	mimeTypesT = `{{ if . }}[]string{ {{- range $i, $m := . }}{{ if $i }}, {{ end }}{{ printf "%q" $m }}{{ end }}}{{ else }}nil{{ end }}`
+
 	// handleCORST generates the code that checks whether a CORS request is authorized
 	// template input: *ControllerTemplateData
 	handleCORST = `// handle{{ .Resource }}Origin applies the CORS response headers corresponding to the origin.
@@ -766,23 +1390,60 @@ func handle{{ .Resource }}Origin(h goa.Handler) goa.Handler {
 
 	// unmarshalT generates the code for an action payload unmarshal function.
 	// template input: *ControllerTemplateData
-	unmarshalT = `{{ range .Actions }}{{ if .Payload }}
+	unmarshalT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `{{ range .Actions }}{{ if .Payload }}
 // {{ .Unmarshal }} unmarshals the request body into the context request data Payload field.
 func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Request) error {
-	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	{{ if .PayloadMultipart }}form, err := service.DecodeMultipartRequest(req)
+	if err != nil {
+		return err
+	}
+	payload := &{{ gotypename .Payload nil 1 true }}{}
+{{ range $name, $att := .Payload.Type.ToObject }}{{ if eq $att.Type.Kind 14 }}{{/* FileKind */}}{{/*
+*/}}	if files := form.File["{{ $name }}"]; len(files) > 0 {
+		payload.{{ goifyatt $att $name true }} = files[0]
+	}
+{{ else }}	if values := form.Value["{{ $name }}"]; len(values) > 0 {
+		raw{{ goify $name true }} := values[0]
+{{ template "Coerce" (newCoerceData $name $att true (printf "payload.%s" (goifyatt $att $name true)) 2) }}{{/*
+*/}}	}
+{{ end }}{{ end }}	if err != nil {
+		return err
+	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
+	payload.Finalize(){{ end }}{{ else if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
-	payload.Finalize(){{ end }}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
+	payload.Finalize(){{ end }}{{ else if .Payload.IsUnion }}var payload {{ gotypename .Payload nil 1 true }}
+	if err := service.DecodeRequest(req, &payload); err != nil {
+		return err
+	}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
 	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
-	}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}
+	}{{ end }}{{ if .Payload.IsObject }}{{ range $name, $att := .Payload.Type.ToObject }}{{ with $att.Deprecation }}
+	if payload.{{ goifyatt $att $name true }} != nil {
+		goa.LogDeprecatedAttribute(ctx, "{{ $name }}", {{ printf "%q" .Reason }})
+	}{{ end }}{{ end }}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{/*
+*/}}{{ $privateValidation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 .Payload.IsObject }}{{/*
+*/}}{{ if .Payload.IsUnion }}
+	pub, err := payload.decode("raw")
+	if err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = pub{{ else }}{{ if and $validation $privateValidation }}
 	if err := payload.Validate(); err != nil {
 		// Initialize payload with private data structure so it can be logged
 		goa.ContextRequest(ctx).Payload = payload
 		return err
-	}{{ end }}
-	goa.ContextRequest(ctx).Payload = payload{{ if .Payload.IsObject }}.Publicize(){{ end }}
+	}{{ end }}{{ if and .Payload.IsObject (hasValidationFunctions .Payload.AttributeDefinition) }}
+	pub := payload.Publicize()
+	if err := pub.Validate(); err != nil {
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = pub{{ else }}
+	goa.ContextRequest(ctx).Payload = payload{{ if .Payload.IsObject }}.Publicize(){{ end }}{{ end }}{{ end }}
 	return nil
 }
 {{ end }}
@@ -854,6 +1515,63 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 }{{ end }}
 `
 
+	// unionTypeT generates the code for a union user type.
+	// template input: UserTypeTemplateData
+	unionTypeT = `{{ $union := .ToUnion }}{{ $privateTypeName := gotypename . nil 0 true }}{{ $typeName := gotypename . nil 0 false }}{{/*
+*/}}// {{ gotypedesc . false }}
+type {{ $privateTypeName }} {{ gotypedef . 0 true true }}
+
+// Validate validates the {{ $privateTypeName }} type instance.
+func (ut {{ $privateTypeName }}) Validate() (err error) {
+	_, err = ut.decode("response")
+	return
+}
+
+// Publicize creates {{ $typeName }} from {{ $privateTypeName }}. Publicize returns nil if the
+// instance does not hold a valid {{ $typeName }}, such instances do not pass Validate.
+func (ut {{ $privateTypeName }}) Publicize() {{ $typeName }} {
+	pub, err := ut.decode("response")
+	if err != nil {
+		return nil
+	}
+	return pub
+}
+
+// decode creates the {{ $typeName }} value identified by the {{ printf "%q" $union.Discriminator }} discriminator.
+// context is used to build the error returned when the discriminator is invalid.
+func (ut {{ $privateTypeName }}) decode(context string) ({{ $typeName }}, error) {
+	raw, err := json.Marshal(ut)
+	if err != nil {
+		return nil, err
+	}
+	switch ut[{{ printf "%q" $union.Discriminator }}] {
+{{ range $union.Types }}	case {{ printf "%q" .TypeName }}:
+		var v {{ gotypename . nil 0 true }}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+{{ if finalizeCode .AttributeDefinition "ut" 1 }}		v.Finalize()
+{{ end }}		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+		return v.Publicize(), nil
+{{ end }}	}
+	return nil, goa.InvalidEnumValueError(context+{{ printf "%q" (printf ".%s" $union.Discriminator) }}, ut[{{ printf "%q" $union.Discriminator }}], {{ printf "%#v" $union.Values }})
+}
+
+// Decode{{ $typeName }} creates the {{ $typeName }} value identified by the {{ printf "%q" $union.Discriminator }} discriminator
+// of the given raw value.
+func Decode{{ $typeName }}(raw map[string]interface{}) ({{ $typeName }}, error) {
+	return {{ $privateTypeName }}(raw).decode("response")
+}
+
+// {{ gotypedesc . true }}
+type {{ $typeName }} {{ gotypedef . 0 true false }}
+{{ range $union.Types }}
+// {{ unionMethod $ }} implements the {{ $typeName }} interface.
+func (ut {{ gotyperef . .AllRequired 0 false }}) {{ unionMethod $ }}() {}
+{{ end }}`
+
 	// securitySchemesT generates the code for the security module.
 	// template input: []*design.SecuritySchemeDefinition
 	securitySchemesT = `
@@ -872,7 +1590,7 @@ func {{ $funcName }}(service *goa.Service, middleware goa.Middleware) {
 func {{ $funcName }}() *goa.{{ .Context }} {
 	def := goa.{{ .Context }}{
 {{ if eq .Context "APIKeySecurity" }}{{/*
-*/}}		In:   {{ if eq .In "header" }}goa.LocHeader{{ else }}goa.LocQuery{{ end }},
+*/}}		In:   {{ if eq .In "header" }}goa.LocHeader{{ else if eq .In "cookie" }}goa.LocCookie{{ else }}goa.LocQuery{{ end }},
 		Name: {{ printf "%q" .Name }},
 {{ else if eq .Context "OAuth2Security" }}{{/*
 */}}		Flow:             {{ printf "%q" .Flow }},
@@ -884,7 +1602,7 @@ func {{ $funcName }}() *goa.{{ .Context }} {
 */}}		},{{ end }}{{/*
 */}}{{ else if eq .Context "BasicAuthSecurity" }}{{/*
 */}}{{ else if eq .Context "JWTSecurity" }}{{/*
-*/}}		In:   {{ if eq .In "header" }}goa.LocHeader{{ else }}goa.LocQuery{{ end }},
+*/}}		In:   {{ if eq .In "header" }}goa.LocHeader{{ else if eq .In "cookie" }}goa.LocCookie{{ else }}goa.LocQuery{{ end }},
 		Name:             {{ printf "%q" .Name }},
 		TokenURL:         {{ printf "%q" .TokenURL }},{{ with .Scopes }}
 		Scopes: map[string]string{
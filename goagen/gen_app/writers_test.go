@@ -3,6 +3,8 @@ package genapp_test
 import (
 	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/design/apidsl"
@@ -51,6 +53,7 @@ var _ = Describe("ContextsWriter", func() {
 			var params, headers *design.AttributeDefinition
 			var payload *design.UserTypeDefinition
 			var responses map[string]*design.ResponseDefinition
+			var errs []*genapp.ErrorTemplateData
 
 			var data *genapp.ContextTemplateData
 
@@ -59,6 +62,7 @@ var _ = Describe("ContextsWriter", func() {
 				headers = nil
 				payload = nil
 				responses = nil
+				errs = nil
 				data = nil
 			})
 
@@ -73,6 +77,7 @@ var _ = Describe("ContextsWriter", func() {
 					Responses:    responses,
 					API:          design.Design,
 					DefaultPkg:   "",
+					Errors:       errs,
 				}
 			})
 
@@ -175,6 +180,75 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a streamed response", func() {
+				BeforeEach(func() {
+					mediaType := &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							TypeName: "GoaTest",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"foo": {Type: design.String}},
+							},
+						},
+						Identifier: "application/vnd.goa.test",
+					}
+					defView := &design.ViewDefinition{
+						AttributeDefinition: mediaType.AttributeDefinition,
+						Name:                "default",
+						Parent:              mediaType,
+					}
+					mediaType.Views = map[string]*design.ViewDefinition{"default": defView}
+					design.Design = new(design.APIDefinition)
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(mediaType.Identifier): mediaType,
+					}
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:      "OK",
+						Status:    200,
+						MediaType: mediaType.Identifier,
+						Stream:    "text/event-stream",
+					}}
+				})
+
+				It("writes the stream sender code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(streamResponseContext))
+					Ω(written).ShouldNot(ContainSubstring("func (ctx *ListBottleContext) OK("))
+				})
+			})
+
+			Context("with a cursor paginated action", func() {
+				It("writes the page links helper", func() {
+					data.Pagination = design.CursorPagination
+					data.PageHref = `AccountHref(ctx.AccountID) + "/bottles"`
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(cursorPageLinks))
+				})
+			})
+
+			Context("with an action with entity tags", func() {
+				It("writes the entity tag helpers", func() {
+					data.ETag = true
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(etagHelpers))
+				})
+			})
+
 			Context("with an integer param", func() {
 				var (
 					intParam   *design.AttributeDefinition
@@ -794,6 +868,207 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with cookies", func() {
+				BeforeEach(func() {
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:   "OK",
+						Status: 200,
+						Cookies: &design.AttributeDefinition{
+							Type: design.Object{"session": {
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"cookie:path": {"/"}, "cookie:http-only": nil},
+							}},
+						},
+					}}
+				})
+
+				It("writes the contexts code", func() {
+					data.Cookies = &design.AttributeDefinition{
+						Type:       design.Object{"count": {Type: design.Integer}},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"count"}},
+					}
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(cookieContext))
+					Ω(written).Should(ContainSubstring(cookieContextFactory))
+					Ω(written).Should(ContainSubstring(cookieSetter))
+				})
+			})
+
+			Context("with response headers", func() {
+				BeforeEach(func() {
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:   "OK",
+						Status: 200,
+						Headers: &design.AttributeDefinition{
+							Type: design.Object{
+								"X-Rate-Limit": {Type: design.Integer},
+								"X-Mode": {
+									Type:       design.String,
+									Validation: &dslengine.ValidationDefinition{Values: []interface{}{"fast", "slow"}},
+								},
+							},
+							Validation: &dslengine.ValidationDefinition{Required: []string{"X-Rate-Limit"}},
+						},
+					}}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(headerSetters))
+					Ω(written).Should(ContainSubstring(requiredHeaderResponse))
+				})
+			})
+
+			Context("with response headers shared by several responses", func() {
+				var mode *design.AttributeDefinition
+
+				BeforeEach(func() {
+					mode = &design.AttributeDefinition{
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{Values: []interface{}{"fast", "slow"}},
+					}
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:   "OK",
+							Status: 200,
+							Headers: &design.AttributeDefinition{
+								Type: design.Object{
+									"X-Mode": {
+										Type:       design.String,
+										Validation: &dslengine.ValidationDefinition{Values: []interface{}{"fast", "slow"}},
+									},
+								},
+							},
+						},
+						"Accepted": {
+							Name:    "Accepted",
+							Status:  202,
+							Headers: &design.AttributeDefinition{Type: design.Object{"X-Mode": mode}},
+						},
+					}
+				})
+
+				It("writes a single setter", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(strings.Count(string(b), "func (ctx *ListBottleContext) SetXModeHeader(")).Should(Equal(1))
+				})
+
+				Context("with conflicting definitions", func() {
+					BeforeEach(func() {
+						mode.Validation = &dslengine.ValidationDefinition{Values: []interface{}{"fast"}}
+					})
+
+					It("returns an error", func() {
+						err := writer.Execute(data)
+						Ω(err).Should(HaveOccurred())
+						Ω(err.Error()).Should(ContainSubstring(`define header "X-Mode" with different types or validations`))
+					})
+				})
+			})
+
+			Context("with a cache policy", func() {
+				BeforeEach(func() {
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:   "OK",
+						Status: 200,
+						Cache: &design.CacheDefinition{
+							MaxAge:  60,
+							Private: true,
+							Vary:    []string{"Accept"},
+						},
+					}}
+				})
+
+				It("writes the cache headers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(cachedResponse))
+				})
+			})
+
+			Context("with a negotiated response content type", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:   "OK",
+						Status: 200,
+						Type:   design.String,
+					}}
+				})
+
+				It("does not set the Content-Type header in the response helpers", func() {
+					data.Negotiated = true
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(negotiatedResponse))
+				})
+			})
+
+			Context("with errors", func() {
+				BeforeEach(func() {
+					errs = []*genapp.ErrorTemplateData{
+						{Name: "order_limit", Status: 409, ContentType: design.ErrorMediaIdentifier},
+						{Name: "out_of_stock", Status: 409, ContentType: "application/vnd.out-of-stock", BodyRef: "*OutOfStock"},
+						{Name: "maintenance", Status: 503, ContentType: design.ProblemMediaIdentifier, Problem: true},
+					}
+				})
+
+				It("writes the error response helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(errorClassResponse))
+					Ω(written).Should(ContainSubstring(typedErrorResponse))
+					Ω(written).Should(ContainSubstring(problemErrorResponse))
+				})
+			})
+
+			Context("with a problem details response", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(design.ProblemMediaIdentifier): design.ProblemMedia,
+					}
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{"BadRequest": {
+						Name:      "BadRequest",
+						Status:    400,
+						MediaType: design.ProblemMediaIdentifier,
+					}}
+				})
+
+				It("sends the error as problem details", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(problemResponse))
+				})
+			})
+
 			Context("with a simple payload", func() {
 				BeforeEach(func() {
 					design.Design = new(design.APIDefinition)
@@ -908,12 +1183,14 @@ var _ = Describe("ControllersWriter", func() {
 			filePath := "swagger/swagger.json"
 			var origins []*design.CORSDefinition
 			var preflightPaths []string
+			var cache *design.CacheDefinition
 
 			var data []*genapp.ControllerTemplateData
 
 			BeforeEach(func() {
 				origins = nil
 				preflightPaths = nil
+				cache = nil
 			})
 
 			JustBeforeEach(func() {
@@ -921,6 +1198,7 @@ var _ = Describe("ControllersWriter", func() {
 				fileServer := &design.FileServerDefinition{
 					FilePath:    filePath,
 					RequestPath: requestPath,
+					Cache:       cache,
 				}
 				d := &genapp.ControllerTemplateData{
 					API:            &design.APIDefinition{},
@@ -967,11 +1245,30 @@ var _ = Describe("ControllersWriter", func() {
 					Ω(written).Should(ContainSubstring(fileServerOptionsHandler))
 				})
 			})
+
+			Context("with a cache policy", func() {
+				BeforeEach(func() {
+					cache = &design.CacheDefinition{MaxAge: 86400, Vary: []string{"Accept-Encoding"}}
+				})
+
+				It("passes the policy to the file handler", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(`h = ctrl.FileHandler("/swagger.json", "swagger/swagger.json", &goa.CachePolicy{MaxAge: 24 * time.Hour, Vary: []string{"Accept-Encoding"}})`))
+				})
+			})
 		})
 
 		Context("with data", func() {
 			var actions, verbs, paths, contexts, unmarshals []string
 			var payloads []*design.UserTypeDefinition
+			var multiparts, idempotents, etags []bool
+			var deprecations []*design.DeprecationDefinition
+			var rateLimits []*design.RateLimitDefinition
 			var encoders, decoders []*genapp.EncoderTemplateData
 			var origins []*design.CORSDefinition
 
@@ -984,6 +1281,11 @@ var _ = Describe("ControllersWriter", func() {
 				contexts = nil
 				unmarshals = nil
 				payloads = nil
+				multiparts = nil
+				idempotents = nil
+				etags = nil
+				deprecations = nil
+				rateLimits = nil
 				encoders = nil
 				decoders = nil
 				origins = nil
@@ -1000,22 +1302,45 @@ var _ = Describe("ControllersWriter", func() {
 				for i, a := range actions {
 					var unmarshal string
 					var payload *design.UserTypeDefinition
+					var multipart, idempotent, etag bool
+					var deprecation *design.DeprecationDefinition
+					var rateLimit *design.RateLimitDefinition
 					if i < len(unmarshals) {
 						unmarshal = unmarshals[i]
 					}
 					if i < len(payloads) {
 						payload = payloads[i]
 					}
+					if i < len(multiparts) {
+						multipart = multiparts[i]
+					}
+					if i < len(idempotents) {
+						idempotent = idempotents[i]
+					}
+					if i < len(etags) {
+						etag = etags[i]
+					}
+					if i < len(deprecations) {
+						deprecation = deprecations[i]
+					}
+					if i < len(rateLimits) {
+						rateLimit = rateLimits[i]
+					}
 					as[i] = map[string]interface{}{
 						"Name": a,
 						"Routes": []*design.RouteDefinition{
 							{
-								Verb: verbs[i],
-								Path: paths[i],
+								Verb:        verbs[i],
+								Path:        paths[i],
+								Deprecation: deprecation,
 							}},
-						"Context":   contexts[i],
-						"Unmarshal": unmarshal,
-						"Payload":   payload,
+						"Context":          contexts[i],
+						"Unmarshal":        unmarshal,
+						"Payload":          payload,
+						"PayloadMultipart": multipart,
+						"Idempotent":       idempotent,
+						"ETag":             etag,
+						"RateLimit":        rateLimit,
 					}
 				}
 				if len(as) > 0 {
@@ -1029,34 +1354,152 @@ var _ = Describe("ControllersWriter", func() {
 				}
 			})
 
-			Context("with missing data", func() {
-				It("returns an empty string", func() {
+			Context("with missing data", func() {
+				It("returns an empty string", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(BeEmpty())
+				})
+			})
+
+			Context("with a simple controller", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("writes the controller code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(simpleController))
+					Ω(written).Should(ContainSubstring(simpleMount))
+				})
+			})
+
+			Context("with a deprecated route", func() {
+				BeforeEach(func() {
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					deprecations = []*design.DeprecationDefinition{{
+						Reason: "use show instead",
+						Sunset: time.Date(2018, 6, 30, 0, 0, 0, 0, time.UTC),
+					}}
+				})
+
+				It("wraps the handler", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(`service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("List", goa.DeprecatedHandler(h, "use show instead", "Sat, 30 Jun 2018 00:00:00 GMT"), nil))`))
+				})
+			})
+
+			Context("with an idempotent action", func() {
+				BeforeEach(func() {
+					actions = []string{"Create"}
+					verbs = []string{"POST"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"CreateBottleContext"}
+					idempotents = []bool{true}
+				})
+
+				It("wraps the handler", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring("\th = goa.IdempotentHandler(service, h)\n\tservice.Mux.Handle(\"POST\""))
+				})
+			})
+
+			Context("with an action with entity tags", func() {
+				BeforeEach(func() {
+					actions = []string{"Show"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles/:id"}
+					contexts = []string{"ShowBottleContext"}
+					etags = []bool{true}
+				})
+
+				It("wraps the handler", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring("\th = goa.ETagHandler(service, h)\n\tservice.Mux.Handle(\"GET\""))
+				})
+			})
+
+			Context("with an action that restricts its MIME types", func() {
+				BeforeEach(func() {
+					actions = []string{"Create"}
+					verbs = []string{"POST"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"CreateBottleContext"}
+				})
+
+				It("wraps the handler", func() {
+					data[0].Actions[0]["Produces"] = []string{"application/json", "application/xml"}
+					data[0].Actions[0]["Consumes"] = []string{"application/json"}
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(`h = goa.NegotiatedHandler(h, []string{"application/json", "application/xml"}, []string{"application/json"})`))
+				})
+
+				It("omits the unrestricted MIME types", func() {
+					data[0].Actions[0]["Produces"] = []string{"application/xml"}
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).Should(BeEmpty())
+					Ω(written).Should(ContainSubstring(`h = goa.NegotiatedHandler(h, []string{"application/xml"}, nil)`))
 				})
 			})
 
-			Context("with a simple controller", func() {
+			Context("with a rate limited action", func() {
 				BeforeEach(func() {
 					actions = []string{"List"}
 					verbs = []string{"GET"}
 					paths = []string{"/accounts/:accountID/bottles"}
 					contexts = []string{"ListBottleContext"}
+					rateLimits = []*design.RateLimitDefinition{{
+						Requests: 100,
+						Period:   10 * time.Minute,
+						Parent:   &design.ResourceDefinition{Name: "bottle"},
+					}}
 				})
 
-				It("writes the controller code", func() {
+				It("wraps the handler", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(simpleController))
-					Ω(written).Should(ContainSubstring(simpleMount))
+					Ω(written).Should(ContainSubstring(`h = goa.RateLimitedHandler(service, h, "bottle", 100, 10 * time.Minute)`))
 				})
 			})
 
@@ -1090,6 +1533,32 @@ var _ = Describe("ControllersWriter", func() {
 					Ω(written).Should(ContainSubstring(payloadNoValidationsObjUnmarshal))
 				})
 			})
+			Context("with actions that take a union payload", func() {
+				BeforeEach(func() {
+					actions = []string{"Create"}
+					verbs = []string{"POST"}
+					paths = []string{"/notifications"}
+					contexts = []string{"CreateNotificationContext"}
+					unmarshals = []string{"unmarshalCreateNotificationPayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "CreateNotificationPayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: &design.Union{Discriminator: "type"},
+							},
+						},
+					}
+				})
+
+				It("decodes the payload with the payload context", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadUnionUnmarshal))
+				})
+			})
 			Context("with actions that take a payload with a required validation", func() {
 				BeforeEach(func() {
 					actions = []string{"List"}
@@ -1125,6 +1594,44 @@ var _ = Describe("ControllersWriter", func() {
 				})
 			})
 
+			Context("with actions that take a multipart form payload", func() {
+				BeforeEach(func() {
+					actions = []string{"Upload"}
+					verbs = []string{"POST"}
+					paths = []string{"/bottles/labels"}
+					contexts = []string{"UploadBottleContext"}
+					unmarshals = []string{"unmarshalUploadBottlePayload"}
+					multiparts = []bool{true}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "UploadBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"label": &design.AttributeDefinition{
+										Type: design.File,
+									},
+									"year": &design.AttributeDefinition{
+										Type: design.Integer,
+									},
+								},
+								Validation: &dslengine.ValidationDefinition{
+									Required: []string{"label"},
+								},
+							},
+						},
+					}
+				})
+
+				It("writes the multipart form unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadMultipartUnmarshal))
+				})
+			})
+
 			Context("with multiple controllers", func() {
 				BeforeEach(func() {
 					actions = []string{"List", "Show"}
@@ -1492,10 +1999,182 @@ var _ = Describe("UserTypesWriter", func() {
 					Ω(written).Should(ContainSubstring(userTypeIncludingHash))
 				})
 			})
+
+			Context("with a union user type", func() {
+				BeforeEach(func() {
+					alternative := func(name string) *design.UserTypeDefinition {
+						return &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"type": &design.AttributeDefinition{Type: design.String},
+								},
+								Validation: &dslengine.ValidationDefinition{Required: []string{"type"}},
+							},
+							TypeName: name,
+						}
+					}
+					attDef = &design.AttributeDefinition{
+						Type: &design.Union{
+							Types: []*design.UserTypeDefinition{
+								alternative("EmailNotification"),
+								alternative("SMSNotification"),
+							},
+							Discriminator: "type",
+						},
+					}
+					typeName = "Notification"
+				})
+				It("writes the union user type code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(unionUserTypeDecode))
+					Ω(written).Should(ContainSubstring(unionUserType))
+				})
+			})
 		})
 	})
 })
 
+var _ = Describe("CallbacksWriter", func() {
+	var writer *genapp.CallbacksWriter
+	var workspace *codegen.Workspace
+	var filename string
+	var api *design.APIDefinition
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("app")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+		writer, err = genapp.NewCallbacksWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		minOrderID := 1.0
+		api = &design.APIDefinition{Name: "test"}
+		action := &design.ActionDefinition{Name: "create"}
+		action.Callbacks = map[string]*design.CallbackDefinition{
+			"order_shipped": {
+				Name:        "order_shipped",
+				Description: "Sent when the order ships",
+				Parent:      action,
+				Payload: &design.UserTypeDefinition{
+					TypeName: "OrderShippedCallbackPayload",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"order_id": &design.AttributeDefinition{
+								Type:       design.Integer,
+								Validation: &dslengine.ValidationDefinition{Minimum: &minOrderID},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"order_id"}},
+					},
+				},
+			},
+		}
+		api.Resources = map[string]*design.ResourceDefinition{
+			"order": {
+				Name:    "order",
+				Actions: map[string]*design.ActionDefinition{"create": action},
+			},
+		}
+		api.Callbacks = map[string]*design.CallbackDefinition{
+			"ping": {Name: "ping", Parent: api},
+		}
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("writes the callback sender", func() {
+		err := writer.Execute(api)
+		Ω(err).ShouldNot(HaveOccurred())
+		b, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		written := string(b)
+		Ω(written).Should(ContainSubstring(callbackSender))
+		Ω(written).Should(ContainSubstring(callbackPayload))
+		Ω(written).Should(ContainSubstring(callbackWithPayload))
+		Ω(written).Should(ContainSubstring(callbackWithoutPayload))
+	})
+})
+
+var _ = Describe("ErrorsWriter", func() {
+	var writer *genapp.ErrorsWriter
+	var workspace *codegen.Workspace
+	var filename string
+	var api *design.APIDefinition
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("app")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+		writer, err = genapp.NewErrorsWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+		mt := &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{
+				TypeName: "OutOfStock",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"available": {Type: design.Integer}},
+				},
+			},
+			Identifier: "application/vnd.out-of-stock",
+		}
+		mt.Views = map[string]*design.ViewDefinition{
+			"default": {AttributeDefinition: mt.AttributeDefinition, Name: "default", Parent: mt},
+		}
+		api = &design.APIDefinition{
+			Name:       "test",
+			MediaTypes: map[string]*design.MediaTypeDefinition{"application/vnd.out-of-stock": mt},
+		}
+		action := &design.ActionDefinition{Name: "create"}
+		action.Errors = map[string]*design.ErrorDefinition{
+			"out_of_stock": {
+				Name:        "out_of_stock",
+				Description: "Not enough items",
+				Status:      409,
+				MediaType:   "application/vnd.out-of-stock",
+				Parent:      action,
+			},
+		}
+		api.Resources = map[string]*design.ResourceDefinition{
+			"order": {
+				Name:    "order",
+				Actions: map[string]*design.ActionDefinition{"create": action},
+			},
+		}
+		api.Errors = map[string]*design.ErrorDefinition{
+			"order_limit": {Name: "order_limit", Status: 409, MediaType: design.ErrorMediaIdentifier, Parent: api},
+		}
+		design.Design = api
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("writes the error constructors", func() {
+		err := writer.Execute(api)
+		Ω(err).ShouldNot(HaveOccurred())
+		b, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		written := string(b)
+		Ω(written).Should(ContainSubstring(errorClass))
+		Ω(written).Should(ContainSubstring(typedError))
+	})
+})
+
 const (
 	emptyContext = `
 type ListBottleContext struct {
@@ -1742,6 +2421,91 @@ func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Ser
 	}
 	return &rctx, err
 }
+`
+
+	cookieContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Count int
+}
+`
+
+	cookieContextFactory = `
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	if cookieCount, err2 := r.Cookie("count"); err2 == nil {
+		rawCount := cookieCount.Value
+		if count, err2 := strconv.Atoi(rawCount); err2 == nil {
+			rctx.Count = count
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("count", rawCount, "integer"))
+		}
+	} else {
+		err = goa.MergeErrors(err, goa.MissingCookieError("count"))
+	}
+	return &rctx, err
+}
+`
+
+	cookieSetter = `// SetSessionCookie sets the session cookie of the response.
+func (ctx *ListBottleContext) SetSessionCookie(v string) {
+	http.SetCookie(ctx.ResponseData, &http.Cookie{
+		Name:  "session",
+		Value: v,
+		Path: "/",
+		HttpOnly: true,
+	})
+}
+`
+
+	headerSetters = `
+// SetXModeHeader sets the X-Mode header of the response. It returns an error if
+// the value does not satisfy the validations defined in the design.
+func (ctx *ListBottleContext) SetXModeHeader(v string) (err error) {
+	if !(v == "fast" || v == "slow") {
+		err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `X-Mode` + "`" + `, v, []interface{}{"fast", "slow"}))
+	}
+	if err != nil {
+		return goa.InvalidResponseHeaderError("X-Mode", err)
+	}
+	ctx.ResponseData.Header().Set("X-Mode", v)
+	return nil
+}
+
+// SetXRateLimitHeader sets the X-Rate-Limit header of the response. It returns an error if
+// the value does not satisfy the validations defined in the design.
+func (ctx *ListBottleContext) SetXRateLimitHeader(v int) (err error) {
+	ctx.ResponseData.Header().Set("X-Rate-Limit", strconv.Itoa(v))
+	return nil
+}
+`
+
+	negotiatedResponse = `// OK sends a HTTP response with status code 200.
+func (ctx *ListBottleContext) OK(r string) error {
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+`
+
+	cachedResponse = `
+// OK sends a HTTP response with status code 200.
+func (ctx *ListBottleContext) OK() error {
+	ctx.ResponseData.Header().Set("Cache-Control", "private, max-age=60")
+	ctx.ResponseData.Header().Add("Vary", "Accept")
+	ctx.ResponseData.WriteHeader(200)
+	return nil
+}
+`
+
+	requiredHeaderResponse = `
+// OK sends a HTTP response with status code 200.
+func (ctx *ListBottleContext) OK() error {
+	if ctx.ResponseData.Header().Get("X-Rate-Limit") == "" {
+		return goa.MissingResponseHeaderError("X-Rate-Limit")
+	}
+	ctx.ResponseData.WriteHeader(200)
+	return nil
+}
 `
 
 	strHeaderParamContextFactory = `
@@ -2228,6 +2992,56 @@ func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *
 	return nil
 }
 `
+	payloadMultipartUnmarshal = `
+func unmarshalUploadBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	form, err := service.DecodeMultipartRequest(req)
+	if err != nil {
+		return err
+	}
+	payload := &uploadBottlePayload{}
+	if files := form.File["label"]; len(files) > 0 {
+		payload.Label = files[0]
+	}
+	if values := form.Value["year"]; len(values) > 0 {
+		rawYear := values[0]
+		if year, err2 := strconv.Atoi(rawYear); err2 == nil {
+			tmp2 := year
+			tmp1 := &tmp2
+			payload.Year = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("year", rawYear, "integer"))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+`
+
+	payloadUnionUnmarshal = `
+func unmarshalCreateNotificationPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	var payload createNotificationPayload
+	if err := service.DecodeRequest(req, &payload); err != nil {
+		return err
+	}
+	pub, err := payload.decode("raw")
+	if err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = pub
+	return nil
+}
+`
+
 	payloadNoValidationsObjUnmarshal = `
 func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
 	payload := &listBottlePayload{}
@@ -2458,6 +3272,50 @@ func (ut *simplePayload) Publicize() *SimplePayload {
 type SimplePayload struct {
 	Name *string ` + "`" + `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"` + "`" + `
 }
+`
+
+	unionUserTypeDecode = `// decode creates the Notification value identified by the "type" discriminator.
+// context is used to build the error returned when the discriminator is invalid.
+func (ut notification) decode(context string) (Notification, error) {
+	raw, err := json.Marshal(ut)
+	if err != nil {
+		return nil, err
+	}
+	switch ut["type"] {
+	case "EmailNotification":
+		var v emailNotification
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+		return v.Publicize(), nil
+	case "SMSNotification":
+		var v sMSNotification
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+		return v.Publicize(), nil
+	}
+	return nil, goa.InvalidEnumValueError(context+".type", ut["type"], []interface {}{"EmailNotification", "SMSNotification"})
+}
+`
+
+	unionUserType = `// Notification user type.
+type Notification interface {
+	isNotification()
+	Validate() error
+}
+
+// isNotification implements the Notification interface.
+func (ut *EmailNotification) isNotification() {}
+
+// isNotification implements the Notification interface.
+func (ut *SMSNotification) isNotification() {}
 `
 
 	userTypeIncludingHash = `// complexPayload user type.
@@ -2493,5 +3351,123 @@ type ComplexPayload struct {
 	Misc map[int]*MiscPayload ` + "`" + `form:"misc,omitempty" json:"misc,omitempty" xml:"misc,omitempty"` + "`" + `
 	Name *string ` + "`" + `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"` + "`" + `
 }
+`
+
+	streamResponseContext = `
+// ListBottleStream sends the messages of the list action OK response stream.
+type ListBottleStream struct {
+	*goa.ResponseStream
+}
+
+// Stream starts the OK response with status code 200 and returns the stream used to send
+// Server-Sent Events. Each message is flushed to the client as soon as it is sent.
+func (ctx *ListBottleContext) Stream() (*ListBottleStream, error) {
+	s, err := goa.NewResponseStream(ctx.Context, 200, "text/event-stream")
+	if err != nil {
+		return nil, err
+	}
+	return &ListBottleStream{ResponseStream: s}, nil
+}
+
+// Send writes a message to the stream, it returns an error if the request context is done.
+func (s *ListBottleStream) Send(r *GoaTest) error {
+	return s.ResponseStream.Send(r)
+}
+`
+
+	cursorPageLinks = `
+// SetPageLinks sets the RFC 5988 Link header of the response with a link to the page of results
+// starting at the given cursor. No link is set if next is empty.
+func (ctx *ListBottleContext) SetPageLinks(next string) {
+	if links := goa.CursorPageLinks(AccountHref(ctx.AccountID) + "/bottles", ctx.Request.URL.Query(), next); links != "" {
+		ctx.ResponseData.Header().Set("Link", links)
+	}
+}
+`
+
+	etagHelpers = `
+// SetETag sets the ETag header of the response to the entity tag of the current representation,
+// etag is quoted if needed.
+func (ctx *ListBottleContext) SetETag(etag string) {
+	ctx.ResponseData.Header().Set("ETag", goa.QuoteETag(etag))
+}
+
+// CheckPreconditions returns an error if the If-Match or If-None-Match request headers do not
+// match current, the entity tag of the current representation or the empty string if there is
+// none. Return the error to send a 412 Precondition Failed response.
+func (ctx *ListBottleContext) CheckPreconditions(current string) error {
+	return goa.CheckPreconditions(ctx.Request, current)
+}
+`
+
+	callbackSender = `// CallbackSender sends the test callbacks. It delivers the requests using a webhook
+// sender that signs them and retries failed deliveries.
+type CallbackSender struct {
+	*client.WebhookSender
+}
+`
+
+	callbackPayload = `// OrderShippedCallbackPayload is the order_shipped callback payload.
+type OrderShippedCallbackPayload struct {
+	OrderID int ` + "`" + `form:"order_id" json:"order_id" xml:"order_id"` + "`" + `
+}
+`
+
+	callbackWithPayload = `// SendOrderShipped sends the order_shipped callback to url.
+// Sent when the order ships
+func (s *CallbackSender) SendOrderShipped(ctx context.Context, url string, payload *OrderShippedCallbackPayload) error {
+	if err := payload.Validate(); err != nil {
+		return err
+	}
+	return s.SendJSON(ctx, url, "order_shipped", payload)
+}
+`
+
+	callbackWithoutPayload = `// SendPing sends the ping callback to url.
+func (s *CallbackSender) SendPing(ctx context.Context, url string) error {
+	return s.Send(ctx, url, "ping", nil)
+}
+`
+
+	errorClass = `// ErrOrderLimit creates order_limit errors.
+var ErrOrderLimit = goa.NewErrorClass("order_limit", 409)
+`
+
+	typedError = `// ErrOutOfStock creates out_of_stock errors with the given response body.
+// Not enough items
+func ErrOutOfStock(body *OutOfStock) error {
+	return goa.NewTypedError("out_of_stock", 409, "application/vnd.out-of-stock", body)
+}
+`
+
+	errorClassResponse = `
+// OrderLimitError sends a HTTP response with status code 409 describing the order_limit error.
+func (ctx *ListBottleContext) OrderLimitError(message interface{}, keyvals ...interface{}) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 409, ErrOrderLimit(message, keyvals...))
+}
+`
+
+	problemErrorResponse = `
+// MaintenanceError sends a HTTP response with status code 503 describing the maintenance error.
+func (ctx *ListBottleContext) MaintenanceError(message interface{}, keyvals ...interface{}) error {
+	return ctx.ResponseData.Service.SendProblem(ctx.Context, 503, ErrMaintenance(message, keyvals...))
+}
+`
+
+	problemResponse = `
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *ListBottleContext) BadRequest(r error) error {
+	return ctx.ResponseData.Service.SendProblem(ctx.Context, 400, r)
+}
+`
+
+	typedErrorResponse = `
+// OutOfStockError sends a HTTP response with status code 409 describing the out_of_stock error.
+func (ctx *ListBottleContext) OutOfStockError(r *OutOfStock) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.out-of-stock")
+	ctx.ResponseData.Header().Set(goa.ErrorCodeHeader, "out_of_stock")
+	return ctx.ResponseData.Service.Send(ctx.Context, 409, r)
+}
 `
 )
@@ -227,7 +227,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 				"Action":          action,
 				"Resource":        action.Parent,
 				"Package":         g.Target,
-				"HasMultiContent": len(g.API.Consumes) > 1,
+				"HasMultiContent": len(g.API.Consumes) > 1 && !action.PayloadMultipart,
 			}
 			var err error
 			if action.WebSocket() {
@@ -585,7 +585,7 @@ func shouldAddExample(ut *design.UserTypeDefinition) bool {
 	if ut == nil {
 		return false
 	}
-	return ut.Example != nil
+	return ut.Example != nil || len(ut.Examples) > 0
 }
 
 func formatExample(example interface{}) string {
@@ -666,9 +666,10 @@ func new{{ goify $security.SchemeName true }}Signer({{ signerSignature $security
 	}
 {{ else if eq .Type "apiKey" }}	return &goaclient.APIKeySigner{
 		SignQuery: {{ if eq $security.In "query" }}true{{ else }}false{{ end }},
+		SignCookie: {{ if eq $security.In "cookie" }}true{{ else }}false{{ end }},
 		KeyName: "{{ $security.Name }}",
 		KeyValue: key,
-		Format: {{ if eq $security.In "query" }}"%s"{{ else }}format{{ end }},
+		Format: {{ if or (eq $security.In "query") (eq $security.In "cookie") }}"%s"{{ else }}format{{ end }},
 	}
 {{ else if eq .Type "jwt" }}	return &goaclient.JWTSigner{
 		TokenSource: source,
@@ -685,12 +686,16 @@ const commandTypesTmpl = `{{ $cmdName := goify (printf "%s%s%s" .Name (title .Pa
 	{{ $cmdName }} struct {
 {{ if .Payload }}		Payload string
 		ContentType string
-{{ end }}{{ $params := defaultRouteParams . }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
+{{ end }}{{ if .PayloadMultipart }}{{ range $name, $att := .Payload.Type.ToObject }}{{ if eq $att.Type.Kind 14 }}		// {{ goify $name true }} is the path to the file uploaded in the {{ $name }} form field.
+		{{ goify $name true }} string
+{{ end }}{{ end }}{{ end }}{{ $params := defaultRouteParams . }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false }}
 {{ end }}{{ end }}{{ $params := .QueryParams }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false}}
 {{ end }}{{ end }}{{ $headers := .Headers }}{{ if $headers }}{{ range $name, $att := $headers.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false}}
+{{ end }}{{ end }}{{ $cookies := .Cookies }}{{ if $cookies }}{{ range $name, $att := $cookies.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
+{{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false}}
 {{ end }}{{ end }}		PrettyPrint bool
 	}
 
@@ -715,9 +720,9 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ else }}{{ $pparams := defaultRouteParams .Action }}	path = fmt.Sprintf({{ printf "%q" (defaultRouteTemplate .Action)}}, {{ joinRouteParams .Action $pparams }})
 {{ end }}	}
 	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
-	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
+	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers .Action.Cookies }}{{ $specialTypeResult.Output }}
 	ws, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{/*
-	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }})
+	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers .Action.Cookies }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }})
 	if err != nil {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
@@ -782,7 +787,9 @@ const registerTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Action.Name (t
 func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Client) {
 {{ if .Action.Payload }}	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
 	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
-{{ end }}{{ $pparams := defaultRouteParams .Action }}{{ if $pparams }}{{ range $pname, $pparam := $pparams.Type.ToObject }}{{ $tmp := goify $pname false }}{{/*
+{{ end }}{{ if .Action.PayloadMultipart }}{{ range $name, $att := .Action.Payload.Type.ToObject }}{{ if eq $att.Type.Kind 14 }}{{/*
+*/}}	cc.Flags().StringVar(&cmd.{{ goify $name true }}, "{{ $name }}", "", "Path to the file uploaded in the {{ $name }} form field")
+{{ end }}{{ end }}{{ end }}{{ $pparams := defaultRouteParams .Action }}{{ if $pparams }}{{ range $pname, $pparam := $pparams.Type.ToObject }}{{ $tmp := goify $pname false }}{{/*
 */}}{{ if not $pparam.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $pparam.Type false }}
 {{ end }}	cc.Flags().{{ flagType $pparam }}Var(&cmd.{{ goify $pname true }}, "{{ $pname }}", {{/*
 */}}{{ if $pparam.DefaultValue }}{{ printf "%#v" $pparam.DefaultValue }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $pparam.Description }}` + "`" + `)
@@ -793,6 +800,10 @@ func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Cl
 {{ end }}{{ end }}{{ $headers := .Action.Headers }}{{ if $headers }}{{ range $name, $header := $headers.Type.ToObject }}{{/*
 */}} cc.Flags().StringVar(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
 */}}{{ if $header.DefaultValue }}{{ printf "%q" $header.DefaultValue }}{{ else }}""{{ end }}, ` + "`" + `{{ escapeBackticks $header.Description }}` + "`" + `)
+{{ end }}{{ end }}{{ $cookies := .Action.Cookies }}{{ if $cookies }}{{ range $name, $cookie := $cookies.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
+*/}}{{ if not $cookie.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $cookie.Type false }}
+{{ end }}	cc.Flags().{{ flagType $cookie }}Var(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
+*/}}{{ if $cookie.DefaultValue }}{{ printf "%#v" $cookie.DefaultValue }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $cookie.Description }}` + "`" + `)
 {{ end }}{{ end }}}`
 
 const commandsTmpl = `
@@ -807,25 +818,35 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ end }}	}
 {{ if .Action.Payload }}var payload {{ gotyperefext .Action.Payload 2 .Package }}
 	if cmd.Payload != "" {
-		err := json.Unmarshal([]byte(cmd.Payload), &payload)
-		if err != nil {
+{{ if .Action.Payload.IsUnion }}		var raw map[string]interface{}
+		err := json.Unmarshal([]byte(cmd.Payload), &raw)
+		if err == nil {
+			payload, err = {{ .Package }}.Decode{{ goify .Action.Payload.TypeName true }}(raw)
+		}
+{{ else }}		err := json.Unmarshal([]byte(cmd.Payload), &payload)
+{{ end }}		if err != nil {
 {{ if eq .Action.Payload.Type.Kind 4 }}	payload = cmd.Payload
 {{ else }}			return fmt.Errorf("failed to deserialize payload: %s", err)
 {{ end }}		}
 	}
-{{ end }}	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
-	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
+{{ end }}{{ if .Action.PayloadMultipart }}{{ range $name, $att := .Action.Payload.Type.ToObject }}{{ if eq $att.Type.Kind 14 }}{{/*
+*/}}	if cmd.{{ goify $name true }} != "" {
+		payload.{{ goifyatt $att $name true }} = &cmd.{{ goify $name true }}
+	}
+{{ end }}{{ end }}{{ end }}	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers .Action.Cookies }}{{ $specialTypeResult.Output }}
 	resp, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{ if .Action.Payload }}, {{/*
 	*/}}{{ if or .Action.Payload.Type.IsObject .Action.Payload.IsPrimitive }}&{{ end }}payload{{ else }}{{ end }}{{/*
-	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }}{{/*
+	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers .Action.Cookies }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }}{{/*
 	*/}}{{ if and .Action.Payload .HasMultiContent }}, cmd.ContentType{{ end }})
 	if err != nil {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
 	}
 
-	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
-	return nil
+{{ if .Action.StreamResponse }}	goaclient.HandleStreamResponse(c.Client, resp, cmd.PrettyPrint)
+{{ else }}	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+{{ end }}	return nil
 }
 `
 
@@ -842,11 +863,15 @@ func RegisterCommands(app *cobra.Command, c *{{ .Package }}.Client) {
 	sub = &cobra.Command{
 		Use:   ` + "`" + `{{ kebabCase $action.Parent.Name }} {{ routes $action }}` + "`" + `,
 		Short: ` + "`" + `{{ escapeBackticks $action.Parent.Description }}` + "`" + `,{{ if shouldAddExample $action.Payload }}
-		Long:  ` + "`" + `{{ escapeBackticks $action.Parent.Description }}
+		Long:  ` + "`" + `{{ escapeBackticks $action.Parent.Description }}{{ if $action.Payload.Example }}
 
 Payload example:
 
-{{ formatExample $action.Payload.Example }}` + "`" + `,{{ end }}
+{{ formatExample $action.Payload.Example }}{{ end }}{{ range $action.Payload.Examples }}
+
+Payload example "{{ .Name }}":
+
+{{ formatExample .Value }}{{ end }}` + "`" + `,{{ end }}
 		RunE:  func(cmd *cobra.Command, args []string) error { return {{ $tmp }}.Run(c, args) },
 	}
 	{{ $tmp }}.RegisterFlags(sub, c)
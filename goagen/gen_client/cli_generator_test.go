@@ -142,6 +142,55 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with an action with a payload with named examples", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			payload := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name": &design.AttributeDefinition{Type: design.String},
+					},
+					Examples: []*design.ExampleDefinition{
+						{Name: "minimal", Value: map[string]interface{}{"name": "alice"}},
+					},
+				},
+				TypeName: "CreateFooPayload",
+			}
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"create": {
+								Name:    "create",
+								Payload: payload,
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "POST",
+										Path: "/foos",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			createAct := fooRes.Actions["create"]
+			createAct.Parent = fooRes
+			createAct.Routes[0].Parent = createAct
+		})
+
+		It("prints the examples in the command help", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring("Payload example \"minimal\":\n\n{\n   \"name\": \"alice\"\n}`,"))
+		})
+	})
+
 	Context("with a resource name with underscores characters", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -3,6 +3,7 @@ package genclient
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -145,6 +146,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 			"defaultPath":        defaultPath,
 			"escapeBackticks":    escapeBackticks,
 			"goify":              codegen.Goify,
+			"goifyatt":           codegen.GoifyAtt,
 			"gotypedef":          codegen.GoTypeDef,
 			"gotypedesc":         codegen.GoTypeDesc,
 			"gotypename":         codegen.GoTypeName,
@@ -162,6 +164,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 			"typeName":           typeName,
 			"format":             format,
 			"handleSpecialTypes": handleSpecialTypes,
+			"fromString":         fromString,
+			"canonicalHeaderKey": http.CanonicalHeaderKey,
+			"printVal":           codegen.PrintVal,
 		}
 		clientPkg, err = codegen.PackagePath(pkgDir)
 		if err != nil {
@@ -296,6 +301,9 @@ func (g *Generator) generateClientResources(pkgDir, clientPkg string, funcs temp
 	if err := g.generateUserTypes(pkgDir); err != nil {
 		return err
 	}
+	if err := g.generateErrors(pkgDir, funcs); err != nil {
+		return err
+	}
 
 	return g.generateMediaTypes(pkgDir, funcs)
 }
@@ -320,15 +328,18 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
 		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("mime/multipart"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("net/url"),
 		codegen.SimpleImport("os"),
 		codegen.SimpleImport("path"),
+		codegen.SimpleImport("path/filepath"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	title := fmt.Sprintf("%s: %s Resource Client", g.API.Context(), res.Name)
@@ -352,7 +363,11 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 				}
 			}
 			if !found {
-				if err := payloadTmpl.Execute(file, action); err != nil {
+				data := map[string]interface{}{
+					"Payload": withFilePaths(action.Payload),
+					"Action":  action,
+				}
+				if err := payloadTmpl.Execute(file, data); err != nil {
 					return err
 				}
 			}
@@ -443,10 +458,14 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		names         []string
 		queryParams   []*paramData
 		headers       []*paramData
+		cookies       []*paramData
 		signer        string
 		clientsTmpl   = template.Must(template.New("clients").Funcs(funcs).Parse(clientsTmpl))
 		requestsTmpl  = template.Must(template.New("requests").Funcs(funcs).Parse(requestsTmpl))
 		clientsWSTmpl = template.Must(template.New("clientsws").Funcs(funcs).Parse(clientsWSTmpl))
+		streamTmpl    = template.Must(template.New("stream").Funcs(funcs).Parse(clientStreamTmpl))
+		pagesTmpl     = template.Must(template.New("pages").Funcs(funcs).Parse(clientPagesTmpl))
+		headersTmpl   = template.Must(template.New("headers").Funcs(funcs).Parse(clientHeadersTmpl))
 	)
 	if action.Payload != nil {
 		params = append(params, "payload "+codegen.GoTypeRef(action.Payload, action.Payload.AllRequired(), 1, false))
@@ -472,10 +491,15 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 	}
 	queryParams = initParamsScoped(action.QueryParams)
 	headers = initParamsScoped(action.Headers)
+	cookies = initParamsScoped(action.Cookies)
 
 	if action.Security != nil {
 		signer = codegen.Goify(action.Security.Scheme.SchemeName, true)
 	}
+	clientPayload := action.Payload
+	if clientPayload != nil {
+		clientPayload = withFilePaths(clientPayload)
+	}
 	data := struct {
 		Name            string
 		ResourceName    string
@@ -483,25 +507,37 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Routes          []*design.RouteDefinition
 		HasPayload      bool
 		HasMultiContent bool
+		Multipart       bool
+		Idempotent      bool
+		ETag            bool
+		Payload         *design.UserTypeDefinition
+		ClientPayload   *design.UserTypeDefinition // Payload with file attributes replaced with file paths
 		Params          string
 		ParamNames      string
 		CanonicalScheme string
 		Signer          string
 		QueryParams     []*paramData
 		Headers         []*paramData
+		Cookies         []*paramData
 	}{
 		Name:            action.Name,
 		ResourceName:    action.Parent.Name,
 		Description:     action.Description,
 		Routes:          action.Routes,
 		HasPayload:      action.Payload != nil,
-		HasMultiContent: len(design.Design.Consumes) > 1,
+		HasMultiContent: len(design.Design.Consumes) > 1 && !action.PayloadMultipart,
+		Multipart:       action.PayloadMultipart,
+		Idempotent:      action.Idempotent,
+		ETag:            action.HasETag(),
+		Payload:         action.Payload,
+		ClientPayload:   clientPayload,
 		Params:          strings.Join(params, ", "),
 		ParamNames:      strings.Join(names, ", "),
 		CanonicalScheme: action.CanonicalScheme(),
 		Signer:          signer,
 		QueryParams:     queryParams,
 		Headers:         headers,
+		Cookies:         cookies,
 	}
 	if action.WebSocket() {
 		return clientsWSTmpl.Execute(file, data)
@@ -509,7 +545,90 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 	if err := clientsTmpl.Execute(file, data); err != nil {
 		return err
 	}
-	return requestsTmpl.Execute(file, data)
+	if err := requestsTmpl.Execute(file, data); err != nil {
+		return err
+	}
+	if resp := action.StreamResponse(); resp != nil {
+		if err := g.generateStream(action, resp, file, streamTmpl); err != nil {
+			return err
+		}
+	}
+	if err := g.generateResponseHeaders(action, file, headersTmpl); err != nil {
+		return err
+	}
+	if action.Pagination != "" {
+		return g.generatePages(action, data, file, pagesTmpl)
+	}
+	return nil
+}
+
+// generatePages generates the iterator used to retrieve the pages of results of a paginated
+// action. The iterator is only generated if the action OK response uses a media type defined in
+// the design.
+func (g *Generator) generatePages(action *design.ActionDefinition, data interface{}, file *codegen.SourceFile, tmpl *template.Template) error {
+	var mt *design.MediaTypeDefinition
+	var view string
+	for _, r := range action.Responses {
+		if r.Status == 200 && r.Stream == "" {
+			mt = design.Design.MediaTypeWithIdentifier(r.MediaType)
+			view = r.ViewName
+			break
+		}
+	}
+	if mt == nil {
+		return nil
+	}
+	if view == "" {
+		view = "default"
+	}
+	projected, _, err := mt.Project(view)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(file, map[string]interface{}{
+		"Action":    data,
+		"Projected": projected,
+	})
+}
+
+// generateStream generates the iterator used to read the messages of the given streamed
+// response.
+func (g *Generator) generateStream(action *design.ActionDefinition, resp *design.ResponseDefinition, file *codegen.SourceFile, tmpl *template.Template) error {
+	mt := design.Design.MediaTypeWithIdentifier(resp.MediaType)
+	if mt == nil {
+		return fmt.Errorf("unknown media type %#v for streamed response of %s", resp.MediaType, action.Context())
+	}
+	view := resp.ViewName
+	if view == "" {
+		view = "default"
+	}
+	projected, _, err := mt.Project(view)
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"Name":         action.Name,
+		"ResourceName": action.Parent.Name,
+		"Projected":    projected,
+	}
+	return tmpl.Execute(file, data)
+}
+
+// generateResponseHeaders generates the types and the functions used to decode the headers of the
+// action responses that define headers.
+func (g *Generator) generateResponseHeaders(action *design.ActionDefinition, file *codegen.SourceFile, tmpl *template.Template) error {
+	return action.IterateResponses(func(resp *design.ResponseDefinition) error {
+		if resp.Headers == nil || len(resp.Headers.Type.ToObject()) == 0 {
+			return nil
+		}
+		data := map[string]interface{}{
+			"Name":         action.Name,
+			"ResourceName": action.Parent.Name,
+			"Response":     resp,
+			"Headers":      resp.Headers,
+		}
+		return tmpl.Execute(file, data)
+	})
 }
 
 // fileServerMethod returns the name of the client method for downloading assets served by the given
@@ -547,6 +666,7 @@ func (g *Generator) fileServerMethod(fs *design.FileServerDefinition) string {
 func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) error {
 	funcs["decodegotyperef"] = decodeGoTypeRef
 	funcs["decodegotypename"] = decodeGoTypeName
+	funcs["hasUnion"] = hasUnion
 	typeDecodeTmpl := template.Must(template.New("typeDecode").Funcs(funcs).Parse(typeDecodeTmpl))
 	mtFile := filepath.Join(pkgDir, "media_types.go")
 	mtWr, err := genapp.NewMediaTypesWriter(mtFile)
@@ -566,6 +686,8 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) er
 		imports = codegen.AttributeImports(v.AttributeDefinition, imports, nil)
 	}
 	mtWr.WriteHeader(title, g.Target, imports)
+	fn := template.FuncMap{"validationCode": mtWr.Validator.Code}
+	privates := make(map[string]bool)
 	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
 		if (mt.Type.IsObject() || mt.Type.IsArray()) && !mt.IsError() {
 			if err := mtWr.Execute(mt); err != nil {
@@ -577,6 +699,17 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) er
 			if err != nil {
 				return err
 			}
+			if hasUnion(p) {
+				for _, pmt := range privateMediaTypes(p) {
+					if privates[pmt.TypeName] {
+						continue
+					}
+					privates[pmt.TypeName] = true
+					if err := mtWr.ExecuteTemplate("private", privateMediaTypeTmpl, fn, pmt); err != nil {
+						return err
+					}
+				}
+			}
 			if err := typeDecodeTmpl.Execute(mtWr.SourceFile, p); err != nil {
 				return err
 			}
@@ -591,6 +724,74 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) er
 	return mtWr.FormatCode()
 }
 
+// errorData contains the information used to render the client code of an error declared in the
+// design.
+type errorData struct {
+	Name        string // e.g. "out_of_stock"
+	Description string
+	TypeName    string // e.g. "OutOfStockError"
+	BodyRef     string // e.g. "*OutOfStock", empty if the error uses the goa error media type
+	DecodeFunc  string // e.g. "DecodeOutOfStock"
+}
+
+// generateErrors generates the typed errors and the DecodeError method that decodes error
+// responses into them if the design declares errors.
+func (g *Generator) generateErrors(pkgDir string, funcs template.FuncMap) error {
+	var errs []*errorData
+	err := g.API.IterateErrors(func(e *design.ErrorDefinition) error {
+		data := &errorData{
+			Name:        e.Name,
+			Description: e.Description,
+			TypeName:    codegen.Goify(e.Name, true) + "Error",
+		}
+		if !e.IsError() {
+			mt := g.API.MediaTypeWithIdentifier(e.MediaType)
+			if mt == nil {
+				return fmt.Errorf("unknown media type %#v for error %#v", e.MediaType, e.Name)
+			}
+			projected, _, err := mt.Project("default")
+			if err != nil {
+				return err
+			}
+			data.BodyRef = codegen.GoTypeRef(projected, projected.AllRequired(), 0, false)
+			data.DecodeFunc = "Decode" + typeName(projected)
+		}
+		errs = append(errs, data)
+		return nil
+	})
+	if err != nil || len(errs) == 0 {
+		return err
+	}
+	errFile := filepath.Join(pkgDir, "errors.go")
+	file, err := codegen.SourceFileFor(errFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Errors", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	file.WriteHeader(title, g.Target, imports)
+	g.genfiles = append(g.genfiles, errFile)
+	var typed, classes bool
+	for _, e := range errs {
+		typed = typed || e.BodyRef != ""
+		classes = classes || e.BodyRef == ""
+	}
+	data := map[string]interface{}{
+		"Errors":  errs,
+		"Typed":   typed,
+		"Classes": classes,
+		"Problem": g.API.ProblemDetails,
+	}
+	if err := file.ExecuteTemplate("errors", errorsTmpl, funcs, data); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
 // generateUserTypes iterates through the user types and generates the data structures and
 // marshaling code.
 func (g *Generator) generateUserTypes(pkgDir string) error {
@@ -602,17 +803,18 @@ func (g *Generator) generateUserTypes(pkgDir string) error {
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	for _, v := range g.API.Types {
-		imports = codegen.AttributeImports(v.AttributeDefinition, imports, nil)
+		imports = codegen.AttributeImports(withFilePaths(v).AttributeDefinition, imports, nil)
 	}
 	utWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
-		return utWr.Execute(t)
+		return utWr.Execute(withFilePaths(t))
 	})
 	g.genfiles = append(g.genfiles, utFile)
 	if err != nil {
@@ -670,6 +872,58 @@ func multiComment(text string) string {
 	return strings.Join(nl, "\n")
 }
 
+// hasUnion returns true if the data structure of the given media type holds union attributes.
+// Such media types are decoded through their private data structure, see privateMediaTypes.
+func hasUnion(mt *design.MediaTypeDefinition) bool {
+	found := false
+	mt.Walk(func(att *design.AttributeDefinition) error {
+		if att.Type.IsUnion() {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// privateMediaTypes returns the given media type and the media types it refers to. The client
+// decodes media types holding union attributes into private data structures that refer to the
+// private data structures of these media types.
+func privateMediaTypes(mt *design.MediaTypeDefinition) []*design.MediaTypeDefinition {
+	mts := []*design.MediaTypeDefinition{mt}
+	mt.Walk(func(att *design.AttributeDefinition) error {
+		if m, ok := att.Type.(*design.MediaTypeDefinition); ok && m.TypeName != mt.TypeName {
+			mts = append(mts, m)
+		}
+		return nil
+	})
+	return mts
+}
+
+// withFilePaths returns a copy of the given user type where the file attributes are replaced with
+// string attributes: client payloads refer to the files being uploaded by path. It returns the
+// user type itself if it has no file attribute.
+func withFilePaths(ut *design.UserTypeDefinition) *design.UserTypeDefinition {
+	hasFile := false
+	for _, att := range ut.Type.ToObject() {
+		if att.Type.Kind() == design.FileKind {
+			hasFile = true
+			break
+		}
+	}
+	if !hasFile {
+		return ut
+	}
+	dup := design.Dup(ut).(*design.UserTypeDefinition)
+	obj := design.Dup(dup.Type).(design.Object)
+	for _, att := range obj {
+		if att.Type.Kind() == design.FileKind {
+			att.Type = design.String
+		}
+	}
+	dup.Type = obj
+	return dup
+}
+
 // gotTypeRefExt computes the type reference for a type in a different package.
 func goTypeRefExt(t design.DataType, tabs int, pkg string) string {
 	ref := codegen.GoTypeRef(t, nil, tabs, false)
@@ -682,6 +936,9 @@ func goTypeRefExt(t design.DataType, tabs int, pkg string) string {
 // decodeGoTypeRef handles the case where the type being decoded is a error response media type.
 func decodeGoTypeRef(t design.DataType, required []string, tabs int, private bool) string {
 	mt, ok := t.(*design.MediaTypeDefinition)
+	if ok && mt.IsProblem() {
+		return "*goa.ProblemDetails"
+	}
 	if ok && mt.IsError() {
 		return "*goa.ErrorResponse"
 	}
@@ -691,6 +948,9 @@ func decodeGoTypeRef(t design.DataType, required []string, tabs int, private boo
 // decodeGoTypeName handles the case where the type being decoded is a error response media type.
 func decodeGoTypeName(t design.DataType, required []string, tabs int, private bool) string {
 	mt, ok := t.(*design.MediaTypeDefinition)
+	if ok && mt.IsProblem() {
+		return "goa.ProblemDetails"
+	}
 	if ok && mt.IsError() {
 		return "goa.ErrorResponse"
 	}
@@ -774,6 +1034,35 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 	}
 }
 
+// fromString returns the code that decodes the string held in the variable with the given name
+// into a new variable named target of the Go type corresponding to att. The generated code returns
+// an error mentioning header if the string cannot be decoded.
+func fromString(name, target, header string, att *design.AttributeDefinition) string {
+	var parse, expected string
+	switch att.Type.Kind() {
+	case design.BooleanKind:
+		parse, expected = "strconv.ParseBool(%s)", "boolean"
+	case design.IntegerKind:
+		parse, expected = "strconv.Atoi(%s)", "integer"
+	case design.NumberKind:
+		parse, expected = "strconv.ParseFloat(%s, 64)", "number"
+	case design.DateTimeKind:
+		parse, expected = "time.Parse(time.RFC3339, %s)", "datetime"
+	case design.UUIDKind:
+		parse, expected = "uuid.FromString(%s)", "uuid"
+	case design.StringKind:
+		return fmt.Sprintf("%s := %s", target, name)
+	case design.AnyKind:
+		return fmt.Sprintf("%s := interface{}(%s)", target, name)
+	default:
+		panic("cannot convert string to non simple type " + att.Type.Name()) // bug
+	}
+	return fmt.Sprintf("%s, err := %s\n", target, fmt.Sprintf(parse, name)) +
+		"if err != nil {\n" +
+		fmt.Sprintf("\treturn nil, fmt.Errorf(\"invalid value %%#v for header %%#v, must be a %s\", %s, %q)\n", expected, name, header) +
+		"}"
+}
+
 // defaultPath returns the first route path for the given action that does not take any wildcard,
 // empty string if none.
 func defaultPath(action *design.ActionDefinition) string {
@@ -818,6 +1107,9 @@ func pathParams(r *design.RouteDefinition) string {
 
 // typeName returns Go type name of given MediaType definition.
 func typeName(mt *design.MediaTypeDefinition) string {
+	if mt.IsProblem() {
+		return "ProblemDetails"
+	}
 	if mt.IsError() {
 		return "ErrorResponse"
 	}
@@ -895,15 +1187,44 @@ const (
 	}
 	{{ .Target }} := strings.Join({{ $tmp }}, ",")`
 
-	payloadTmpl = `// {{ gotypename .Payload nil 0 false }} is the {{ .Parent.Name }} {{ .Name }} action payload.
+	payloadTmpl = `// {{ gotypename .Payload nil 0 false }} is the {{ .Action.Parent.Name }} {{ .Action.Name }} action payload.
 type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
 `
 
 	typeDecodeTmpl = `{{ $typeName := typeName . }}{{ $funcName := printf "Decode%s" $typeName }}// {{ $funcName }} decodes the {{ $typeName }} instance encoded in resp body.
 func (c *Client) {{ $funcName }}(resp *http.Response) ({{ decodegotyperef . .AllRequired 0 false }}, error) {
-	var decoded {{ decodegotypename . .AllRequired 0 false }}
+{{ if hasUnion . }}	var decoded {{ gotypename . .AllRequired 0 true }}
+	if err := c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type")); err != nil {
+		return nil, err
+	}
+	if err := decoded.Validate(); err != nil {
+		return nil, err
+	}
+	return decoded.Publicize(), nil
+{{ else }}	var decoded {{ decodegotypename . .AllRequired 0 false }}
 	err := c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type"))
 	return {{ if .IsObject }}&{{ end }}decoded, err
+{{ end }}}
+`
+
+	// privateMediaTypeTmpl generates the private data structure used to decode media types that
+	// hold union attributes.
+	// template input: *design.MediaTypeDefinition
+	privateMediaTypeTmpl = `{{ $privateTypeName := gotypename . .AllRequired 0 true }}{{ $typeName := gotypename . .AllRequired 0 false }}{{/*
+*/}}// {{ $privateTypeName }} is the data structure used to decode {{ $typeName }} instances.
+type {{ $privateTypeName }} {{ gotypedef . 0 true true }}
+{{ $validation := validationCode .AttributeDefinition false false false "mt" "response" 1 true }}{{ if $validation }}
+// Validate validates the {{ $privateTypeName }} instance.
+func (mt {{ gotyperef . .AllRequired 0 true }}) Validate() (err error) {
+{{ $validation }}
+	return
+}
+{{ end }}
+// Publicize creates {{ $typeName }} from {{ $privateTypeName }}.
+func (mt {{ gotyperef . .AllRequired 0 true }}) Publicize() {{ gotyperef . .AllRequired 0 false }} {
+	var pub {{ $typeName }}
+	{{ recursivePublicizer .AttributeDefinition "mt" "pub" 1 }}
+	return {{ if .IsObject }}&{{ end }}pub
 }
 `
 
@@ -920,12 +1241,117 @@ func {{ $funcName }}({{ pathParams .Route }}) string {
 	clientsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
 */}}{{ if $desc }}{{ multiComment $desc }}{{ else }}{{/*
 */}}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}
-func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType string{{ end }}) (*http.Response, error) {
-	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType{{ end }})
+func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType string{{ end }}{{ if .ETag }}, conds ...goaclient.Condition{{ end }}) (*http.Response, error) {
+	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if and .HasPayload .HasMultiContent }}, contentType{{ end }}{{ if .ETag }}, conds...{{ end }})
 	if err != nil {
 		return nil, err
 	}
-	return c.Client.Do(ctx, req)
+	return c.Client.{{ if .Idempotent }}DoIdempotent{{ else }}Do{{ end }}(ctx, req)
+}
+`
+
+	clientStreamTmpl = `{{ $streamName := goify (printf "%s%sStream" .Name (title .ResourceName)) true }}{{/*
+*/}}// {{ $streamName }} iterates over the messages streamed in the response of the {{ .Name }} action of the {{ .ResourceName }} resource.
+type {{ $streamName }} struct {
+	*goaclient.StreamReader
+}
+
+// Decode{{ $streamName }} returns an iterator over the messages streamed in the body of resp.
+// The stream must be closed once done.
+func (c *Client) Decode{{ $streamName }}(resp *http.Response) *{{ $streamName }} {
+	return &{{ $streamName }}{StreamReader: goaclient.NewStreamReader(resp)}
+}
+
+// Next decodes the next message of the stream, it returns io.EOF once the stream ends.
+func (s *{{ $streamName }}) Next() ({{ gotyperef .Projected .Projected.AllRequired 0 false }}, error) {
+	var decoded {{ gotypename .Projected .Projected.AllRequired 0 false }}
+	if err := s.StreamReader.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return {{ if .Projected.IsObject }}&{{ end }}decoded, nil
+}
+`
+
+	clientHeadersTmpl = `{{ $typeName := goify (printf "%s%s%sHeaders" .Name (title .ResourceName) .Response.Name) true }}{{/*
+*/}}// {{ $typeName }} contains the headers of the {{ .Response.Name }} response of the {{ .Name }} action of the {{ .ResourceName }} resource.
+type {{ $typeName }} struct {
+{{ range $name, $att := .Headers.Type.ToObject }}{{ if $att.Description }}	{{ multiComment $att.Description }}
+{{ end }}	{{ goifyatt $att $name true }} {{ if $.Headers.IsPrimitivePointer $name }}*{{ end }}{{ gotyperef $att.Type nil 0 false }}
+{{ end }}}
+
+// Decode{{ $typeName }} decodes the headers of resp. It returns an error if a required header is
+// missing or if the value of a header cannot be decoded.
+func (c *Client) Decode{{ $typeName }}(resp *http.Response) (*{{ $typeName }}, error) {
+	var headers {{ $typeName }}
+{{ range $name, $att := .Headers.Type.ToObject }}{{ $field := goifyatt $att $name true }}{{/*
+*/}}	if raw := resp.Header["{{ canonicalHeaderKey $name }}"]; len(raw) > 0 {
+{{ if $att.Type.IsArray }}		for _, r := range raw {
+			{{ fromString "r" "e" $name $att.Type.ElemType }}
+			headers.{{ $field }} = append(headers.{{ $field }}, e)
+		}
+{{ else }}		{{ fromString "raw[0]" "v" $name $att }}
+		headers.{{ $field }} = {{ if $.Headers.IsPrimitivePointer $name }}&{{ end }}v
+{{ end }}	}{{ if $.Headers.HasDefaultValue $name }} else {
+		headers.{{ $field }} = {{ printVal $att.Type $att.DefaultValue }}
+	}{{ else if $.Headers.IsRequired $name }} else {
+		return nil, fmt.Errorf("missing required header %#v", "{{ $name }}")
+	}{{ end }}
+{{ end }}	return &headers, nil
+}
+`
+
+	clientPagesTmpl = `{{ $funcName := goify (printf "%s%s" .Action.Name (title .Action.ResourceName)) true }}{{/*
+*/}}{{ $iterName := printf "%sIterator" $funcName }}{{ $withContentType := and .Action.HasPayload .Action.HasMultiContent }}{{/*
+*/}}// {{ $iterName }} iterates over the pages of results returned by the {{ .Action.Name }} action of the {{ .Action.ResourceName }} resource.
+// It follows the RFC 5988 "next" links of the responses until there are no more pages.
+type {{ $iterName }} struct {
+	c     *Client
+	req   *http.Request
+	value {{ gotyperef .Projected .Projected.AllRequired 1 false }}
+	err   error
+}
+
+// {{ $funcName }}Pages returns an iterator over the pages of results of the {{ .Action.Name }} action starting with the
+// page identified by the given arguments.
+func (c *Client) {{ $funcName }}Pages(ctx context.Context, path string{{ if .Action.Params }}, {{ .Action.Params }}{{ end }}{{ if $withContentType }}, contentType string{{ end }}) *{{ $iterName }} {
+	req, err := c.New{{ $funcName }}Request(ctx, path{{ if .Action.ParamNames }}, {{ .Action.ParamNames }}{{ end }}{{ if $withContentType }}, contentType{{ end }})
+	return &{{ $iterName }}{c: c, req: req, err: err}
+}
+
+// Next retrieves the next page of results. It returns false once all the pages have been retrieved
+// or if an error occurred, Err returns the error if any.
+func (it *{{ $iterName }}) Next(ctx context.Context) bool {
+	if it.err != nil || it.req == nil {
+		return false
+	}
+	resp, err := it.c.Client.Do(ctx, it.req)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		it.err = fmt.Errorf("unexpected response status %s", resp.Status)
+		return false
+	}
+	var decoded {{ gotypename .Projected .Projected.AllRequired 1 false }}
+	if err := it.c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type")); err != nil {
+		it.err = fmt.Errorf("failed to decode page: %s", err)
+		return false
+	}
+	it.value = {{ if .Projected.IsObject }}&{{ end }}decoded
+	it.req, it.err = goaclient.NextPageRequest(it.req, resp)
+	return true
+}
+
+// Value returns the page of results retrieved by the last call to Next.
+func (it *{{ $iterName }}) Value() {{ gotyperef .Projected .Projected.AllRequired 0 false }} {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration if any.
+func (it *{{ $iterName }}) Err() error {
+	return it.err
 }
 `
 
@@ -963,7 +1389,11 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	}
 {{ range $header := .Headers }}{{ $tmp := tempvar }}	{{ toString $header.VarName $tmp $header.Attribute }}
 	cfg.Header["{{ $header.Name }}"] = []string{ {{ $tmp }} }
-{{ end }}	return websocket.DialConfig(cfg)
+{{ end }}{{ range $cookie := .Cookies }}{{ $tmp := tempvar }}{{ if $cookie.CheckNil }}	if {{ $cookie.VarName }} != nil {
+{{ end }}	{{ toString $cookie.ValueName $tmp $cookie.Attribute }}
+	cfg.Header.Add("Cookie", (&http.Cookie{Name: "{{ $cookie.Name }}", Value: {{ $tmp }}}).String())
+{{ if $cookie.CheckNil }}	}
+{{ end }}{{ end }}	return websocket.DialConfig(cfg)
 }
 `
 
@@ -1005,8 +1435,50 @@ func (c * Client) {{ .Name }}(ctx context.Context, {{ if .DirName }}filename, {{
 
 	requestsTmpl = `{{ $funcName := goify (printf "New%s%sRequest" (title .Name) (title .ResourceName)) true }}{{/*
 */}}// {{ $funcName }} create the request corresponding to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource.
-func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if .HasPayload }}{{ if .HasMultiContent }}, contentType string{{ end }}{{ end }}) (*http.Request, error) {
-{{ if .HasPayload }}	var body bytes.Buffer
+func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if .HasPayload }}{{ if .HasMultiContent }}, contentType string{{ end }}{{ end }}{{ if .ETag }}, conds ...goaclient.Condition{{ end }}) (*http.Request, error) {
+{{ if .Multipart }}	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+{{ range $name, $att := .Payload.Type.ToObject }}{{ $field := printf "payload.%s" (goifyatt $att $name true) }}{{/*
+*/}}{{ if eq $att.Type.Kind 14 }}{{/* FileKind */}}{{ if $.ClientPayload.IsPrimitivePointer $name }}	if {{ $field }} != nil {
+		fh, err := os.Open(*{{ $field }})
+		if err != nil {
+			return nil, err
+		}
+		defer fh.Close()
+		fw, err := w.CreateFormFile("{{ $name }}", filepath.Base(*{{ $field }}))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(fw, fh); err != nil {
+			return nil, err
+		}
+	}
+{{ else }}{{ $fh := tempvar }}{{ $fw := tempvar }}	{{ $fh }}, err := os.Open({{ $field }})
+	if err != nil {
+		return nil, err
+	}
+	defer {{ $fh }}.Close()
+	{{ $fw }}, err := w.CreateFormFile("{{ $name }}", filepath.Base({{ $field }}))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy({{ $fw }}, {{ $fh }}); err != nil {
+		return nil, err
+	}
+{{ end }}{{ else if $.Payload.IsPrimitivePointer $name }}	if {{ $field }} != nil {
+{{ $tmp := tempvar }}		{{ toString (printf "*%s" $field) $tmp $att }}
+		if err := w.WriteField("{{ $name }}", {{ $tmp }}); err != nil {
+			return nil, err
+		}
+	}
+{{ else }}{{ $tmp := tempvar }}	{{ toString $field $tmp $att }}
+	if err := w.WriteField("{{ $name }}", {{ $tmp }}); err != nil {
+		return nil, err
+	}
+{{ end }}{{ end }}	if err := w.Close(); err != nil {
+		return nil, err
+	}
+{{ else if .HasPayload }}	var body bytes.Buffer
 {{ if .HasMultiContent }}	if contentType == "" {
 		contentType = "*/*" // Use default encoder
 	}
@@ -1056,11 +1528,67 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	header.Set("{{ .Name }}", {{ $tmp }}){{ else }}
 	header.Set("{{ .Name }}", {{ .ValueName }})
 {{ end }}{{ if .CheckNil }}	}{{ end }}
-{{ end }}{{ end }}{{ if .Signer }}	if c.{{ .Signer }}Signer != nil {
+{{ end }}{{ end }}{{ range .Cookies }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
+{{ end }}{{ if .MustToString }}{{ $tmp := tempvar }}	{{ toString .ValueName $tmp .Attribute }}
+	req.AddCookie(&http.Cookie{Name: "{{ .Name }}", Value: {{ $tmp }}}){{ else }}
+	req.AddCookie(&http.Cookie{Name: "{{ .Name }}", Value: {{ .ValueName }}})
+{{ end }}{{ if .CheckNil }}	}{{ end }}
+{{ end }}{{ if .Multipart }}	req.Header.Set("Content-Type", w.FormDataContentType())
+{{ end }}{{ if .ETag }}	for _, cond := range conds {
+		cond(req)
+	}
+{{ end }}{{ if .Signer }}	if c.{{ .Signer }}Signer != nil {
 		c.{{ .Signer }}Signer.Sign(req)
 	}
 {{ end }}	return req, nil
 }
+`
+
+	errorsTmpl = `{{ range .Errors }}{{ if .BodyRef }}// {{ .TypeName }} is the error returned by DecodeError for {{ .Name }} error responses.{{ if .Description }}
+{{ multiComment .Description }}{{ end }}
+type {{ .TypeName }} struct {
+	// Status is the HTTP status code of the response.
+	Status int
+	// Body is the decoded response body.
+	Body {{ .BodyRef }}
+}
+
+// Error returns the error message.
+func (e *{{ .TypeName }}) Error() string {
+	return fmt.Sprintf("%d {{ .Name }}: %v", e.Status, e.Body)
+}
+{{ else }}// {{ .TypeName }} is the error returned by DecodeError for {{ .Name }} error responses.{{ if .Description }}
+{{ multiComment .Description }}{{ end }}
+type {{ .TypeName }} struct {
+	*goa.ErrorResponse
+}
+{{ end }}
+{{ end }}// DecodeError decodes the error response resp into the error declared in the design that matches
+// the response error code. It returns a *goa.ErrorResponse for the other errors.
+func (c *Client) DecodeError(resp *http.Response) error {
+{{ if .Typed }}	switch resp.Header.Get(goa.ErrorCodeHeader) {
+{{ range .Errors }}{{ if .BodyRef }}	case {{ printf "%q" .Name }}:
+		body, err := c.{{ .DecodeFunc }}(resp)
+		if err != nil {
+			return err
+		}
+		return &{{ .TypeName }}{Status: resp.StatusCode, Body: body}
+{{ end }}{{ end }}	}
+{{ end }}{{ if .Problem }}	var p goa.ProblemDetails
+	if err := c.Decoder.Decode(&p, resp.Body, resp.Header.Get("Content-Type")); err != nil {
+		return err
+	}
+	e := *p.ErrorResponse()
+{{ else }}	var e goa.ErrorResponse
+	if err := c.Decoder.Decode(&e, resp.Body, resp.Header.Get("Content-Type")); err != nil {
+		return err
+	}
+{{ end }}{{ if .Classes }}	switch e.Code {
+{{ range .Errors }}{{ if not .BodyRef }}	case {{ printf "%q" .Name }}:
+		return &{{ .TypeName }}{ErrorResponse: &e}
+{{ end }}{{ end }}	}
+{{ end }}	return &e
+}
 `
 
 	clientTmpl = `// Client is the {{ .API.Name }} service client.
@@ -172,6 +172,180 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with response headers", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{Verb: "GET", Path: ""}},
+								Responses: map[string]*design.ResponseDefinition{
+									"OK": {
+										Name:   "OK",
+										Status: 200,
+										Headers: &design.AttributeDefinition{
+											Type: design.Object{
+												"X-Rate-Limit": &design.AttributeDefinition{Type: design.Integer},
+												"X-Request-Id": &design.AttributeDefinition{Type: design.String},
+											},
+											Validation: &dslengine.ValidationDefinition{
+												Required: []string{"X-Rate-Limit"},
+											},
+										},
+									},
+								}}},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("generates the response headers decoder", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring("type ShowFooOKHeaders struct {\n\tXRateLimit int\n\tXRequestID *string\n}"))
+			Ω(content).Should(ContainSubstring("func (c *Client) DecodeShowFooOKHeaders(resp *http.Response) (*ShowFooOKHeaders, error) {"))
+			Ω(content).Should(ContainSubstring("v, err := strconv.Atoi(raw[0])"))
+			Ω(content).Should(ContainSubstring(`return nil, fmt.Errorf("missing required header %#v", "X-Rate-Limit")`))
+		})
+	})
+
+	Context("with errors", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+			mt := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "OutOfStock",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"available": {Type: design.Integer}},
+					},
+				},
+				Identifier: "application/vnd.out-of-stock",
+			}
+			mt.Views = map[string]*design.ViewDefinition{
+				"default": {AttributeDefinition: mt.AttributeDefinition, Name: "default", Parent: mt},
+			}
+			design.Design = &design.APIDefinition{
+				Name:       "testapi",
+				MediaTypes: map[string]*design.MediaTypeDefinition{"application/vnd.out-of-stock": mt},
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name:   "show",
+								Routes: []*design.RouteDefinition{{Verb: "GET", Path: ""}},
+								Errors: map[string]*design.ErrorDefinition{
+									"out_of_stock": {
+										Name:      "out_of_stock",
+										Status:    409,
+										MediaType: "application/vnd.out-of-stock",
+									},
+									"order_limit": {
+										Name:      "order_limit",
+										Status:    409,
+										MediaType: design.ErrorMediaIdentifier,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("generates the typed errors and the error decoder", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "errors.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring("type OrderLimitError struct {\n\t*goa.ErrorResponse\n}"))
+			Ω(content).Should(ContainSubstring("type OutOfStockError struct {"))
+			Ω(content).Should(ContainSubstring("func (c *Client) DecodeError(resp *http.Response) error {"))
+			Ω(content).Should(ContainSubstring(`case "out_of_stock":
+		body, err := c.DecodeOutOfStock(resp)`))
+			Ω(content).Should(ContainSubstring(`case "order_limit":
+		return &OrderLimitError{ErrorResponse: &e}`))
+		})
+
+		Context("with problem details", func() {
+			BeforeEach(func() {
+				design.Design.ProblemDetails = true
+				design.Design.MediaTypes[design.CanonicalIdentifier(design.ProblemMediaIdentifier)] = design.ProblemMedia
+				design.Design.Resources["foo"].Actions["show"].Errors["order_limit"].MediaType = design.ProblemMediaIdentifier
+			})
+
+			It("decodes the problem details", func() {
+				Ω(genErr).Should(BeNil())
+				c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "errors.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				content := string(c)
+				Ω(content).Should(ContainSubstring(`	var p goa.ProblemDetails
+	if err := c.Decoder.Decode(&p, resp.Body, resp.Header.Get("Content-Type")); err != nil {
+		return err
+	}
+	e := *p.ErrorResponse()`))
+				Ω(content).Should(ContainSubstring(`case "order_limit":
+		return &OrderLimitError{ErrorResponse: &e}`))
+
+				c, err = ioutil.ReadFile(filepath.Join(outDir, "client", "media_types.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(c)).Should(ContainSubstring("func (c *Client) DecodeProblemDetails(resp *http.Response) (*goa.ProblemDetails, error) {"))
+			})
+		})
+	})
+
+	Context("with entity tags", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name:   "show",
+								ETag:   true,
+								Routes: []*design.RouteDefinition{{Verb: "GET", Path: ""}},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("accepts conditions", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring("func (c *Client) ShowFoo(ctx context.Context, path string, conds ...goaclient.Condition) (*http.Response, error) {\n\treq, err := c.NewShowFooRequest(ctx, path, conds...)"))
+			Ω(content).Should(ContainSubstring("func (c *Client) NewShowFooRequest(ctx context.Context, path string, conds ...goaclient.Condition) (*http.Request, error) {"))
+			Ω(content).Should(ContainSubstring("\tfor _, cond := range conds {\n\t\tcond(req)\n\t}\n"))
+		})
+	})
+
 	Context("with querystring params in path", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -553,6 +727,141 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(ContainSubstring("uuid \"github.com/goadesign/goa/uuid\""))
 		})
 	})
+
+	Context("with a multipart form payload", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			uploadType := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"label": &design.AttributeDefinition{Type: design.File},
+						"name":  &design.AttributeDefinition{Type: design.String},
+					},
+				},
+				TypeName: "UploadType",
+			}
+			design.Design = &design.APIDefinition{
+				Types: map[string]*design.UserTypeDefinition{
+					"UploadType": uploadType,
+				},
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"upload": {
+								Name: "upload",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "POST",
+										Path: "",
+									},
+								},
+								Payload:          uploadType,
+								PayloadMultipart: true,
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			uploadAct := fooRes.Actions["upload"]
+			uploadAct.Parent = fooRes
+			uploadAct.Routes[0].Parent = uploadAct
+		})
+
+		It("refers to the uploaded files by path", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "user_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(MatchRegexp(`Label\s+\*string`))
+			Ω(string(content)).ShouldNot(ContainSubstring("multipart.FileHeader"))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("os.Open(*payload.Label)"))
+		})
+
+		It("leaves the file type used by the other generators untouched", func() {
+			Ω(codegen.FileType).Should(Equal("multipart.FileHeader"))
+			Ω(design.Design.Types["UploadType"].Type.ToObject()["label"].Type).Should(Equal(design.File))
+		})
+
+		Context("with a required file", func() {
+			BeforeEach(func() {
+				design.Design.Types["UploadType"].Validation = &dslengine.ValidationDefinition{
+					Required: []string{"label"},
+				}
+			})
+
+			It("refers to the uploaded file by value", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("os.Open(payload.Label)"))
+				Ω(string(content)).ShouldNot(ContainSubstring("payload.Label != nil"))
+			})
+		})
+	})
+
+	Context("with a media type holding a union attribute", func() {
+		BeforeEach(func() {
+			email := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"type":    &design.AttributeDefinition{Type: design.String},
+						"address": &design.AttributeDefinition{Type: design.String},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"type", "address"}},
+				},
+				TypeName: "Email",
+			}
+			notification := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: &design.Union{Types: []*design.UserTypeDefinition{email}, Discriminator: "type"},
+				},
+				TypeName: "Notification",
+			}
+			attDef := &design.AttributeDefinition{
+				Type: design.Object{
+					"notification": &design.AttributeDefinition{Type: notification},
+				},
+			}
+			booking := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					AttributeDefinition: attDef,
+					TypeName:            "Booking",
+				},
+				Identifier: "application/vnd.booking+json",
+				Views: map[string]*design.ViewDefinition{
+					"default": {
+						AttributeDefinition: attDef,
+						Name:                "default",
+					},
+				},
+			}
+			booking.Views["default"].Parent = booking
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Types: map[string]*design.UserTypeDefinition{
+					"Email":        email,
+					"Notification": notification,
+				},
+				MediaTypes: map[string]*design.MediaTypeDefinition{
+					"application/vnd.booking+json": booking,
+				},
+			}
+		})
+
+		It("decodes the media type through its private data structure", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "media_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(MatchRegexp(`type booking struct {\s+Notification notification`))
+			Ω(string(content)).Should(ContainSubstring("pub.Notification = mt.Notification.Publicize()"))
+			Ω(string(content)).Should(ContainSubstring(`var decoded booking`))
+			Ω(string(content)).Should(ContainSubstring(`return decoded.Publicize(), nil`))
+		})
+	})
 })
 
 var _ = Describe("NewGenerator", func() {
@@ -0,0 +1,12 @@
+/*
+Package gendesign provides a generator that serializes the evaluated design to a JSON document. The
+document describes the complete API definition including resources, actions, routes, user types,
+media types with their views and links, security schemes and metadata. The document top level
+"format_version" field identifies the version of the document structure.
+
+The document makes it possible for external tools and generators to work from the design without
+compiling the design package: Load reads a document and returns the corresponding API definition.
+Traits and response templates are Go functions and are only recorded by name, their effect is
+already reflected in the resources and actions that use them.
+*/
+package gendesign
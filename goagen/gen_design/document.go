@@ -0,0 +1,770 @@
+package gendesign
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/version"
+)
+
+// FormatVersion is the version of the design document format. It changes whenever the document
+// structure changes in a way that is not backwards compatible.
+const FormatVersion = "1"
+
+type (
+	// Document is the serializable representation of an evaluated API design.
+	Document struct {
+		// FormatVersion is the version of the document format.
+		FormatVersion string `json:"format_version"`
+		// GoaVersion is the version of goa that produced the document.
+		GoaVersion string `json:"goa_version"`
+		// API is the API definition.
+		API *APIDocument `json:"api"`
+	}
+
+	// APIDocument is the serializable representation of design.APIDefinition.
+	APIDocument struct {
+		Name            string                       `json:"name"`
+		Title           string                       `json:"title,omitempty"`
+		Description     string                       `json:"description,omitempty"`
+		Version         string                       `json:"version,omitempty"`
+		Host            string                       `json:"host,omitempty"`
+		Schemes         []string                     `json:"schemes,omitempty"`
+		BasePath        string                       `json:"base_path,omitempty"`
+		Params          *Attribute                   `json:"params,omitempty"`
+		Consumes        []*Encoding                  `json:"consumes,omitempty"`
+		Produces        []*Encoding                  `json:"produces,omitempty"`
+		Origins         map[string]*CORS             `json:"origins,omitempty"`
+		TermsOfService  string                       `json:"terms_of_service,omitempty"`
+		Contact         *design.ContactDefinition    `json:"contact,omitempty"`
+		License         *design.LicenseDefinition    `json:"license,omitempty"`
+		Docs            *design.DocsDefinition       `json:"docs,omitempty"`
+		Resources       map[string]*Resource         `json:"resources,omitempty"`
+		Types           map[string]*Attribute        `json:"types,omitempty"`
+		MediaTypes      map[string]*MediaType        `json:"media_types,omitempty"`
+		Traits          []string                     `json:"traits,omitempty"`
+		Responses       map[string]*Response         `json:"responses,omitempty"`
+		Templates       []string                     `json:"response_templates,omitempty"`
+		Metadata        dslengine.MetadataDefinition `json:"metadata,omitempty"`
+		SecuritySchemes []*SecurityScheme            `json:"security_schemes,omitempty"`
+		Security        *Security                    `json:"security,omitempty"`
+		NoExamples      bool                         `json:"no_examples,omitempty"`
+		ProblemDetails  bool                         `json:"problem_details,omitempty"`
+		Callbacks       map[string]*Callback         `json:"callbacks,omitempty"`
+		Errors          map[string]*Error            `json:"errors,omitempty"`
+		RateLimit       *RateLimit                   `json:"rate_limit,omitempty"`
+	}
+
+	// Resource is the serializable representation of design.ResourceDefinition.
+	Resource struct {
+		Description     string                       `json:"description,omitempty"`
+		Schemes         []string                     `json:"schemes,omitempty"`
+		BasePath        string                       `json:"base_path,omitempty"`
+		Params          *Attribute                   `json:"params,omitempty"`
+		ParentName      string                       `json:"parent,omitempty"`
+		MediaType       string                       `json:"media_type,omitempty"`
+		DefaultViewName string                       `json:"default_view,omitempty"`
+		CanonicalAction string                       `json:"canonical_action,omitempty"`
+		Actions         map[string]*Action           `json:"actions,omitempty"`
+		FileServers     []*FileServer                `json:"file_servers,omitempty"`
+		Responses       map[string]*Response         `json:"responses,omitempty"`
+		Headers         *Attribute                   `json:"headers,omitempty"`
+		Cookies         *Attribute                   `json:"cookies,omitempty"`
+		Origins         map[string]*CORS             `json:"origins,omitempty"`
+		Metadata        dslengine.MetadataDefinition `json:"metadata,omitempty"`
+		Security        *Security                    `json:"security,omitempty"`
+		RateLimit       *RateLimit                   `json:"rate_limit,omitempty"`
+		Consumes        []*Encoding                  `json:"consumes,omitempty"`
+		Produces        []*Encoding                  `json:"produces,omitempty"`
+	}
+
+	// Action is the serializable representation of design.ActionDefinition.
+	Action struct {
+		Description      string                       `json:"description,omitempty"`
+		Docs             *design.DocsDefinition       `json:"docs,omitempty"`
+		Schemes          []string                     `json:"schemes,omitempty"`
+		Routes           []*Route                     `json:"routes,omitempty"`
+		Responses        map[string]*Response         `json:"responses,omitempty"`
+		Params           *Attribute                   `json:"params,omitempty"`
+		QueryParams      *Attribute                   `json:"query_params,omitempty"`
+		Payload          *DataType                    `json:"payload,omitempty"`
+		PayloadOptional  bool                         `json:"payload_optional,omitempty"`
+		PayloadMultipart bool                         `json:"payload_multipart,omitempty"`
+		Pagination       string                       `json:"pagination,omitempty"`
+		Headers          *Attribute                   `json:"headers,omitempty"`
+		Cookies          *Attribute                   `json:"cookies,omitempty"`
+		Metadata         dslengine.MetadataDefinition `json:"metadata,omitempty"`
+		Security         *Security                    `json:"security,omitempty"`
+		Deprecation      *Deprecation                 `json:"deprecation,omitempty"`
+		Idempotent       bool                         `json:"idempotent,omitempty"`
+		ETag             bool                         `json:"etag,omitempty"`
+		Callbacks        map[string]*Callback         `json:"callbacks,omitempty"`
+		Errors           map[string]*Error            `json:"errors,omitempty"`
+		RateLimit        *RateLimit                   `json:"rate_limit,omitempty"`
+		Cache            *Cache                       `json:"cache,omitempty"`
+		Consumes         []*Encoding                  `json:"consumes,omitempty"`
+		Produces         []*Encoding                  `json:"produces,omitempty"`
+	}
+
+	// Route is the serializable representation of design.RouteDefinition.
+	Route struct {
+		Verb        string                       `json:"verb"`
+		Path        string                       `json:"path"`
+		Metadata    dslengine.MetadataDefinition `json:"metadata,omitempty"`
+		Deprecation *Deprecation                 `json:"deprecation,omitempty"`
+	}
+
+	// Response is the serializable representation of design.ResponseDefinition.
+	Response struct {
+		Status      int                          `json:"status"`
+		Description string                       `json:"description,omitempty"`
+		Type        *DataType                    `json:"type,omitempty"`
+		MediaType   string                       `json:"media_type,omitempty"`
+		ViewName    string                       `json:"view,omitempty"`
+		Stream      string                       `json:"stream,omitempty"`
+		Headers     *Attribute                   `json:"headers,omitempty"`
+		Cookies     *Attribute                   `json:"cookies,omitempty"`
+		Metadata    dslengine.MetadataDefinition `json:"metadata,omitempty"`
+		Standard    bool                         `json:"standard,omitempty"`
+		Examples    []*Example                   `json:"examples,omitempty"`
+		Cache       *Cache                       `json:"cache,omitempty"`
+	}
+
+	// FileServer is the serializable representation of design.FileServerDefinition.
+	FileServer struct {
+		Description string                       `json:"description,omitempty"`
+		Docs        *design.DocsDefinition       `json:"docs,omitempty"`
+		FilePath    string                       `json:"file_path"`
+		RequestPath string                       `json:"request_path"`
+		Metadata    dslengine.MetadataDefinition `json:"metadata,omitempty"`
+		Security    *Security                    `json:"security,omitempty"`
+		Cache       *Cache                       `json:"cache,omitempty"`
+	}
+
+	// MediaType is the serializable representation of design.MediaTypeDefinition.
+	MediaType struct {
+		*Attribute
+		TypeName    string                `json:"type_name"`
+		Identifier  string                `json:"identifier"`
+		ContentType string                `json:"content_type,omitempty"`
+		Links       map[string]*Link      `json:"links,omitempty"`
+		Views       map[string]*Attribute `json:"views,omitempty"`
+		Resource    string                `json:"resource,omitempty"`
+		ETag        bool                  `json:"etag,omitempty"`
+	}
+
+	// Link is the serializable representation of design.LinkDefinition.
+	Link struct {
+		View        string `json:"view,omitempty"`
+		URITemplate string `json:"uri_template,omitempty"`
+	}
+
+	// Attribute is the serializable representation of design.AttributeDefinition.
+	Attribute struct {
+		Type        *DataType                    `json:"type,omitempty"`
+		Reference   *DataType                    `json:"reference,omitempty"`
+		Description string                       `json:"description,omitempty"`
+		Validation  *Validation                  `json:"validation,omitempty"`
+		Metadata    dslengine.MetadataDefinition `json:"metadata,omitempty"`
+		Default     interface{}                  `json:"default,omitempty"`
+		Example     interface{}                  `json:"example,omitempty"`
+		Examples    []*Example                   `json:"examples,omitempty"`
+		View        string                       `json:"view,omitempty"`
+		NonZero     []string                     `json:"non_zero,omitempty"`
+		Deprecation *Deprecation                 `json:"deprecation,omitempty"`
+		ReadOnly    bool                         `json:"read_only,omitempty"`
+		WriteOnly   bool                         `json:"write_only,omitempty"`
+	}
+
+	// DataType is the serializable representation of design.DataType. User types and media
+	// types defined in the API are referenced by name and identifier respectively, anonymous
+	// user types (e.g. inline payloads) are inlined.
+	DataType struct {
+		// Kind is one of "boolean", "integer", "number", "string", "datetime", "uuid",
+		// "any", "file", "array", "object", "hash", "union", "user_type" or "media_type".
+		Kind string `json:"kind"`
+		// Ref is the name of the user type or the identifier of the media type.
+		Ref string `json:"ref,omitempty"`
+		// UserType is the definition of anonymous user types.
+		UserType *Attribute `json:"user_type,omitempty"`
+		// Fields lists the attributes of objects.
+		Fields map[string]*Attribute `json:"fields,omitempty"`
+		// Key is the key attribute of hashes.
+		Key *Attribute `json:"key,omitempty"`
+		// Elem is the element attribute of arrays and hashes.
+		Elem *Attribute `json:"elem,omitempty"`
+		// Types lists the union types.
+		Types []*DataType `json:"types,omitempty"`
+		// Discriminator is the union discriminator attribute name.
+		Discriminator string `json:"discriminator,omitempty"`
+	}
+
+	// Validation is the serializable representation of dslengine.ValidationDefinition.
+	Validation struct {
+		Values           []interface{}                       `json:"enum,omitempty"`
+		Format           string                              `json:"format,omitempty"`
+		Pattern          string                              `json:"pattern,omitempty"`
+		Minimum          *float64                            `json:"minimum,omitempty"`
+		Maximum          *float64                            `json:"maximum,omitempty"`
+		ExclusiveMinimum bool                                `json:"exclusive_minimum,omitempty"`
+		ExclusiveMaximum bool                                `json:"exclusive_maximum,omitempty"`
+		MultipleOf       *float64                            `json:"multiple_of,omitempty"`
+		MinLength        *int                                `json:"min_length,omitempty"`
+		MaxLength        *int                                `json:"max_length,omitempty"`
+		UniqueItems      bool                                `json:"unique_items,omitempty"`
+		MinProperties    *int                                `json:"min_properties,omitempty"`
+		MaxProperties    *int                                `json:"max_properties,omitempty"`
+		Required         []string                            `json:"required,omitempty"`
+		RequiredIf       []*dslengine.ConditionalRequirement `json:"required_if,omitempty"`
+		AtLeastOneOf     [][]string                          `json:"at_least_one_of,omitempty"`
+		Functions        []*dslengine.ValidationFunction     `json:"functions,omitempty"`
+	}
+
+	// Example is the serializable representation of design.ExampleDefinition.
+	Example struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+	}
+
+	// Error is the serializable representation of design.ErrorDefinition.
+	Error struct {
+		Description string `json:"description,omitempty"`
+		Status      int    `json:"status"`
+		MediaType   string `json:"media_type,omitempty"`
+	}
+
+	// Callback is the serializable representation of design.CallbackDefinition.
+	Callback struct {
+		Description string                       `json:"description,omitempty"`
+		URL         string                       `json:"url,omitempty"`
+		Payload     *DataType                    `json:"payload,omitempty"`
+		Metadata    dslengine.MetadataDefinition `json:"metadata,omitempty"`
+	}
+
+	// RateLimit is the serializable representation of design.RateLimitDefinition.
+	RateLimit struct {
+		Requests int `json:"requests"`
+		// Period is the duration of the rate limit window, e.g. "1m0s".
+		Period string `json:"period"`
+	}
+
+	// Cache is the serializable representation of design.CacheDefinition.
+	Cache struct {
+		MaxAge  uint     `json:"max_age,omitempty"`
+		Private bool     `json:"private,omitempty"`
+		NoStore bool     `json:"no_store,omitempty"`
+		Vary    []string `json:"vary,omitempty"`
+	}
+
+	// Deprecation is the serializable representation of design.DeprecationDefinition.
+	Deprecation struct {
+		Reason string `json:"reason,omitempty"`
+		// Sunset is the RFC 3339 sunset date if any.
+		Sunset string `json:"sunset,omitempty"`
+	}
+
+	// Encoding is the serializable representation of design.EncodingDefinition.
+	Encoding struct {
+		MIMETypes   []string `json:"mime_types"`
+		PackagePath string   `json:"package_path,omitempty"`
+		Function    string   `json:"function,omitempty"`
+	}
+
+	// CORS is the serializable representation of design.CORSDefinition.
+	CORS struct {
+		Headers     []string `json:"headers,omitempty"`
+		Methods     []string `json:"methods,omitempty"`
+		Exposed     []string `json:"exposed,omitempty"`
+		MaxAge      uint     `json:"max_age,omitempty"`
+		Credentials bool     `json:"credentials,omitempty"`
+		Regexp      bool     `json:"regexp,omitempty"`
+	}
+
+	// SecurityScheme is the serializable representation of design.SecuritySchemeDefinition.
+	SecurityScheme struct {
+		// Kind is one of "oauth2", "basic", "api_key" or "jwt".
+		Kind             string                       `json:"kind"`
+		SchemeName       string                       `json:"scheme"`
+		Type             string                       `json:"type"`
+		Description      string                       `json:"description,omitempty"`
+		In               string                       `json:"in,omitempty"`
+		Name             string                       `json:"name,omitempty"`
+		Scopes           map[string]string            `json:"scopes,omitempty"`
+		Flow             string                       `json:"flow,omitempty"`
+		TokenURL         string                       `json:"token_url,omitempty"`
+		AuthorizationURL string                       `json:"authorization_url,omitempty"`
+		Metadata         dslengine.MetadataDefinition `json:"metadata,omitempty"`
+	}
+
+	// Security is the serializable representation of design.SecurityDefinition.
+	Security struct {
+		// Scheme is the name of the security scheme, empty if None is true.
+		Scheme string `json:"scheme,omitempty"`
+		// None is true if the security requirement disables security (see NoSecurity).
+		None   bool     `json:"none,omitempty"`
+		Scopes []string `json:"scopes,omitempty"`
+	}
+)
+
+// securityKinds maps the security scheme kinds to their names in design documents.
+var securityKinds = map[design.SecuritySchemeKind]string{
+	design.OAuth2SecurityKind:    "oauth2",
+	design.BasicAuthSecurityKind: "basic",
+	design.APIKeySecurityKind:    "api_key",
+	design.JWTSecurityKind:       "jwt",
+}
+
+// primitiveKinds maps the primitive types to their kind names in design documents.
+var primitiveKinds = map[design.Primitive]string{
+	design.Boolean:  "boolean",
+	design.Integer:  "integer",
+	design.Number:   "number",
+	design.String:   "string",
+	design.DateTime: "datetime",
+	design.UUID:     "uuid",
+	design.Any:      "any",
+	design.File:     "file",
+}
+
+// exporter builds documents from API definitions.
+type exporter struct {
+	api *design.APIDefinition
+}
+
+// Export returns the document describing the given API definition. The definition must have been
+// evaluated (i.e. dslengine.Run must have been called).
+func Export(api *design.APIDefinition) *Document {
+	e := &exporter{api: api}
+	return &Document{
+		FormatVersion: FormatVersion,
+		GoaVersion:    version.String(),
+		API:           e.apiDef(),
+	}
+}
+
+func (e *exporter) apiDef() *APIDocument {
+	a := e.api
+	res := &APIDocument{
+		Name:           a.Name,
+		Title:          a.Title,
+		Description:    a.Description,
+		Version:        a.Version,
+		Host:           a.Host,
+		Schemes:        a.Schemes,
+		BasePath:       a.BasePath,
+		Params:         e.attribute(a.Params),
+		Consumes:       encodings(a.Consumes),
+		Produces:       encodings(a.Produces),
+		Origins:        origins(a.Origins),
+		TermsOfService: a.TermsOfService,
+		Contact:        a.Contact,
+		License:        a.License,
+		Docs:           a.Docs,
+		Responses:      e.responses(a.Responses),
+		Metadata:       a.Metadata,
+		Security:       security(a.Security),
+		NoExamples:     a.NoExamples,
+		ProblemDetails: a.ProblemDetails,
+		Callbacks:      e.callbacks(a.Callbacks),
+		Errors:         errorDefs(a.Errors),
+		RateLimit:      rateLimit(a.RateLimit),
+	}
+	for n := range a.Traits {
+		res.Traits = append(res.Traits, n)
+	}
+	sort.Strings(res.Traits)
+	for n := range a.ResponseTemplates {
+		res.Templates = append(res.Templates, n)
+	}
+	sort.Strings(res.Templates)
+	for _, s := range a.SecuritySchemes {
+		res.SecuritySchemes = append(res.SecuritySchemes, &SecurityScheme{
+			Kind:             securityKinds[s.Kind],
+			SchemeName:       s.SchemeName,
+			Type:             s.Type,
+			Description:      s.Description,
+			In:               s.In,
+			Name:             s.Name,
+			Scopes:           s.Scopes,
+			Flow:             s.Flow,
+			TokenURL:         s.TokenURL,
+			AuthorizationURL: s.AuthorizationURL,
+			Metadata:         s.Metadata,
+		})
+	}
+	if len(a.Types) > 0 {
+		res.Types = make(map[string]*Attribute, len(a.Types))
+		for n, ut := range a.Types {
+			res.Types[n] = e.attribute(ut.AttributeDefinition)
+		}
+	}
+	if len(a.MediaTypes) > 0 {
+		res.MediaTypes = make(map[string]*MediaType, len(a.MediaTypes))
+		for id, mt := range a.MediaTypes {
+			res.MediaTypes[id] = e.mediaType(mt)
+		}
+	}
+	if len(a.Resources) > 0 {
+		res.Resources = make(map[string]*Resource, len(a.Resources))
+		for n, r := range a.Resources {
+			res.Resources[n] = e.resource(r)
+		}
+	}
+	return res
+}
+
+func (e *exporter) resource(r *design.ResourceDefinition) *Resource {
+	res := &Resource{
+		Description:     r.Description,
+		Schemes:         r.Schemes,
+		BasePath:        r.BasePath,
+		Params:          e.attribute(r.Params),
+		ParentName:      r.ParentName,
+		MediaType:       r.MediaType,
+		DefaultViewName: r.DefaultViewName,
+		CanonicalAction: r.CanonicalActionName,
+		Responses:       e.responses(r.Responses),
+		Headers:         e.attribute(r.Headers),
+		Cookies:         e.attribute(r.Cookies),
+		Origins:         origins(r.Origins),
+		Metadata:        r.Metadata,
+		Security:        security(r.Security),
+		RateLimit:       rateLimit(r.RateLimit),
+		Consumes:        encodings(r.Consumes),
+		Produces:        encodings(r.Produces),
+	}
+	if len(r.Actions) > 0 {
+		res.Actions = make(map[string]*Action, len(r.Actions))
+		for n, a := range r.Actions {
+			res.Actions[n] = e.action(a)
+		}
+	}
+	for _, fs := range r.FileServers {
+		res.FileServers = append(res.FileServers, &FileServer{
+			Description: fs.Description,
+			Docs:        fs.Docs,
+			FilePath:    fs.FilePath,
+			RequestPath: fs.RequestPath,
+			Metadata:    fs.Metadata,
+			Security:    security(fs.Security),
+			Cache:       cache(fs.Cache),
+		})
+	}
+	return res
+}
+
+func (e *exporter) action(a *design.ActionDefinition) *Action {
+	res := &Action{
+		Description:      a.Description,
+		Docs:             a.Docs,
+		Schemes:          a.Schemes,
+		Responses:        e.responses(a.Responses),
+		Params:           e.attribute(a.Params),
+		QueryParams:      e.attribute(a.QueryParams),
+		PayloadOptional:  a.PayloadOptional,
+		PayloadMultipart: a.PayloadMultipart,
+		Pagination:       a.Pagination,
+		Headers:          e.attribute(a.Headers),
+		Cookies:          e.attribute(a.Cookies),
+		Metadata:         a.Metadata,
+		Security:         security(a.Security),
+		Deprecation:      deprecation(a.Deprecation),
+		Idempotent:       a.Idempotent,
+		ETag:             a.ETag,
+		Callbacks:        e.callbacks(a.Callbacks),
+		Errors:           errorDefs(a.Errors),
+		RateLimit:        rateLimit(a.RateLimit),
+		Cache:            cache(a.Cache),
+		Consumes:         encodings(a.Consumes),
+		Produces:         encodings(a.Produces),
+	}
+	if a.Payload != nil {
+		res.Payload = e.dataType(a.Payload)
+	}
+	for _, r := range a.Routes {
+		res.Routes = append(res.Routes, &Route{
+			Verb:        r.Verb,
+			Path:        r.Path,
+			Metadata:    r.Metadata,
+			Deprecation: deprecation(r.Deprecation),
+		})
+	}
+	return res
+}
+
+func (e *exporter) responses(resps map[string]*design.ResponseDefinition) map[string]*Response {
+	if len(resps) == 0 {
+		return nil
+	}
+	res := make(map[string]*Response, len(resps))
+	for n, r := range resps {
+		resp := &Response{
+			Status:      r.Status,
+			Description: r.Description,
+			MediaType:   r.MediaType,
+			ViewName:    r.ViewName,
+			Stream:      r.Stream,
+			Headers:     e.attribute(r.Headers),
+			Cookies:     e.attribute(r.Cookies),
+			Metadata:    r.Metadata,
+			Standard:    r.Standard,
+			Examples:    examples(r.Examples),
+			Cache:       cache(r.Cache),
+		}
+		if r.Type != nil {
+			resp.Type = e.dataType(r.Type)
+		}
+		res[n] = resp
+	}
+	return res
+}
+
+func (e *exporter) callbacks(cbs map[string]*design.CallbackDefinition) map[string]*Callback {
+	if len(cbs) == 0 {
+		return nil
+	}
+	res := make(map[string]*Callback, len(cbs))
+	for n, cb := range cbs {
+		c := &Callback{
+			Description: cb.Description,
+			URL:         cb.URL,
+			Metadata:    cb.Metadata,
+		}
+		if cb.Payload != nil {
+			c.Payload = e.dataType(cb.Payload)
+		}
+		res[n] = c
+	}
+	return res
+}
+
+func (e *exporter) mediaType(mt *design.MediaTypeDefinition) *MediaType {
+	res := &MediaType{
+		Attribute:   e.attribute(mt.AttributeDefinition),
+		TypeName:    mt.TypeName,
+		Identifier:  mt.Identifier,
+		ContentType: mt.ContentType,
+		ETag:        mt.ETag,
+	}
+	if len(mt.Links) > 0 {
+		res.Links = make(map[string]*Link, len(mt.Links))
+		for n, l := range mt.Links {
+			res.Links[n] = &Link{View: l.View, URITemplate: l.URITemplate}
+		}
+	}
+	if len(mt.Views) > 0 {
+		res.Views = make(map[string]*Attribute, len(mt.Views))
+		for n, v := range mt.Views {
+			res.Views[n] = e.attribute(v.AttributeDefinition)
+		}
+	}
+	if mt.Resource != nil {
+		res.Resource = mt.Resource.Name
+	}
+	return res
+}
+
+func (e *exporter) attribute(att *design.AttributeDefinition) *Attribute {
+	if att == nil {
+		return nil
+	}
+	res := &Attribute{
+		Description: att.Description,
+		Validation:  e.validation(att.Validation),
+		Metadata:    att.Metadata,
+		Default:     exportValue(att.DefaultValue),
+		Example:     exportValue(att.Example),
+		Examples:    examples(att.Examples),
+		View:        att.View,
+		Deprecation: deprecation(att.Deprecation),
+		ReadOnly:    att.ReadOnly,
+		WriteOnly:   att.WriteOnly,
+	}
+	if att.Type != nil {
+		res.Type = e.dataType(att.Type)
+	}
+	if att.Reference != nil {
+		res.Reference = e.dataType(att.Reference)
+	}
+	for n, nz := range att.NonZeroAttributes {
+		if nz {
+			res.NonZero = append(res.NonZero, n)
+		}
+	}
+	sort.Strings(res.NonZero)
+	return res
+}
+
+func (e *exporter) dataType(dt design.DataType) *DataType {
+	switch actual := dt.(type) {
+	case design.Primitive:
+		return &DataType{Kind: primitiveKinds[actual]}
+	case *design.Array:
+		return &DataType{Kind: "array", Elem: e.attribute(actual.ElemType)}
+	case *design.Hash:
+		return &DataType{Kind: "hash", Key: e.attribute(actual.KeyType), Elem: e.attribute(actual.ElemType)}
+	case design.Object:
+		fields := make(map[string]*Attribute, len(actual))
+		for n, att := range actual {
+			fields[n] = e.attribute(att)
+		}
+		return &DataType{Kind: "object", Fields: fields}
+	case *design.Union:
+		res := &DataType{Kind: "union", Discriminator: actual.Discriminator}
+		for _, ut := range actual.Types {
+			res.Types = append(res.Types, e.dataType(ut))
+		}
+		return res
+	case *design.MediaTypeDefinition:
+		return &DataType{Kind: "media_type", Ref: actual.Identifier}
+	case *design.UserTypeDefinition:
+		if e.api.Types[actual.TypeName] == actual {
+			return &DataType{Kind: "user_type", Ref: actual.TypeName}
+		}
+		return &DataType{Kind: "user_type", Ref: actual.TypeName, UserType: e.attribute(actual.AttributeDefinition)}
+	default:
+		panic(fmt.Sprintf("unknown data type %T", dt)) // bug
+	}
+}
+
+func (e *exporter) validation(v *dslengine.ValidationDefinition) *Validation {
+	if v == nil {
+		return nil
+	}
+	res := &Validation{
+		Format:           v.Format,
+		Pattern:          v.Pattern,
+		Minimum:          v.Minimum,
+		Maximum:          v.Maximum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		MultipleOf:       v.MultipleOf,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+		UniqueItems:      v.UniqueItems,
+		MinProperties:    v.MinProperties,
+		MaxProperties:    v.MaxProperties,
+		Required:         v.Required,
+		RequiredIf:       v.RequiredIf,
+		AtLeastOneOf:     v.AtLeastOneOf,
+		Functions:        v.Functions,
+	}
+	for _, val := range v.Values {
+		res.Values = append(res.Values, exportValue(val))
+	}
+	return res
+}
+
+func encodings(encs []*design.EncodingDefinition) []*Encoding {
+	var res []*Encoding
+	for _, enc := range encs {
+		res = append(res, &Encoding{MIMETypes: enc.MIMETypes, PackagePath: enc.PackagePath, Function: enc.Function})
+	}
+	return res
+}
+
+func errorDefs(errs map[string]*design.ErrorDefinition) map[string]*Error {
+	if len(errs) == 0 {
+		return nil
+	}
+	res := make(map[string]*Error, len(errs))
+	for n, e := range errs {
+		res[n] = &Error{Description: e.Description, Status: e.Status, MediaType: e.MediaType}
+	}
+	return res
+}
+
+func rateLimit(r *design.RateLimitDefinition) *RateLimit {
+	if r == nil {
+		return nil
+	}
+	return &RateLimit{Requests: r.Requests, Period: r.Period.String()}
+}
+
+func cache(c *design.CacheDefinition) *Cache {
+	if c == nil {
+		return nil
+	}
+	return &Cache{MaxAge: c.MaxAge, Private: c.Private, NoStore: c.NoStore, Vary: c.Vary}
+}
+
+func origins(o map[string]*design.CORSDefinition) map[string]*CORS {
+	if len(o) == 0 {
+		return nil
+	}
+	res := make(map[string]*CORS, len(o))
+	for n, c := range o {
+		res[n] = &CORS{
+			Headers:     c.Headers,
+			Methods:     c.Methods,
+			Exposed:     c.Exposed,
+			MaxAge:      c.MaxAge,
+			Credentials: c.Credentials,
+			Regexp:      c.Regexp,
+		}
+	}
+	return res
+}
+
+func security(s *design.SecurityDefinition) *Security {
+	if s == nil || s.Scheme == nil {
+		return nil
+	}
+	if s.Scheme.Kind == design.NoSecurityKind {
+		return &Security{None: true}
+	}
+	return &Security{Scheme: s.Scheme.SchemeName, Scopes: s.Scopes}
+}
+
+func deprecation(d *design.DeprecationDefinition) *Deprecation {
+	if d == nil {
+		return nil
+	}
+	res := &Deprecation{Reason: d.Reason}
+	if !d.Sunset.IsZero() {
+		res.Sunset = d.Sunset.Format(time.RFC3339)
+	}
+	return res
+}
+
+func examples(exs []*design.ExampleDefinition) []*Example {
+	var res []*Example
+	for _, ex := range exs {
+		res = append(res, &Example{Name: ex.Name, Value: exportValue(ex.Value)})
+	}
+	return res
+}
+
+// exportValue converts the maps with non-string keys used by design values to maps that can be
+// serialized to JSON.
+func exportValue(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case design.HashVal:
+		return exportValue(actual.ToMap())
+	case design.ArrayVal:
+		return exportValue(actual.ToSlice())
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[fmt.Sprint(k)] = exportValue(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[k] = exportValue(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = exportValue(e)
+		}
+		return res
+	default:
+		return v
+	}
+}
@@ -0,0 +1,717 @@
+package gendesign
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// loader builds API definitions from documents.
+type loader struct {
+	api        *design.APIDefinition
+	mediaTypes map[string]*design.MediaTypeDefinition
+	schemes    map[string]*design.SecuritySchemeDefinition
+}
+
+// Load reads the design document in the file at the given path and returns the corresponding API
+// definition.
+func Load(path string) (*design.APIDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f)
+}
+
+// Decode reads a design document from r and returns the corresponding API definition. The
+// definition is equivalent to the evaluated design the document was produced from with the
+// exception of the DSL functions (e.g. traits) which cannot be serialized. Generators that rely on
+// the global design.Design variable should set it to the returned definition.
+func Decode(r io.Reader) (*design.APIDefinition, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid design document: %s", err)
+	}
+	return doc.APIDefinition()
+}
+
+// APIDefinition builds the API definition described by the document.
+func (d *Document) APIDefinition() (*design.APIDefinition, error) {
+	if d.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported design document format version %#v, expected %#v", d.FormatVersion, FormatVersion)
+	}
+	if d.API == nil {
+		return nil, fmt.Errorf("invalid design document: missing API definition")
+	}
+	l := &loader{
+		api:        design.NewAPIDefinition(),
+		mediaTypes: make(map[string]*design.MediaTypeDefinition),
+		schemes:    make(map[string]*design.SecuritySchemeDefinition),
+	}
+	if err := l.load(d.API); err != nil {
+		return nil, err
+	}
+	return l.api, nil
+}
+
+func (l *loader) load(a *APIDocument) error {
+	api := l.api
+	api.Name = a.Name
+	api.Title = a.Title
+	api.Description = a.Description
+	api.Version = a.Version
+	api.Host = a.Host
+	api.Schemes = a.Schemes
+	api.BasePath = a.BasePath
+	api.TermsOfService = a.TermsOfService
+	api.Contact = a.Contact
+	api.License = a.License
+	api.Docs = a.Docs
+	api.Metadata = a.Metadata
+	api.NoExamples = a.NoExamples
+	api.ProblemDetails = a.ProblemDetails
+	api.Errors = newErrors(a.Errors, api)
+	api.Consumes = newEncodings(a.Consumes, false)
+	api.Produces = newEncodings(a.Produces, true)
+	api.Origins = newOrigins(a.Origins, api)
+	if len(a.Traits) > 0 {
+		api.Traits = make(map[string]*dslengine.TraitDefinition, len(a.Traits))
+		for _, n := range a.Traits {
+			api.Traits[n] = &dslengine.TraitDefinition{Name: n}
+		}
+	}
+	for _, s := range a.SecuritySchemes {
+		scheme := &design.SecuritySchemeDefinition{
+			SchemeName:       s.SchemeName,
+			Type:             s.Type,
+			Description:      s.Description,
+			In:               s.In,
+			Name:             s.Name,
+			Scopes:           s.Scopes,
+			Flow:             s.Flow,
+			TokenURL:         s.TokenURL,
+			AuthorizationURL: s.AuthorizationURL,
+			Metadata:         s.Metadata,
+		}
+		for k, n := range securityKinds {
+			if n == s.Kind {
+				scheme.Kind = k
+			}
+		}
+		if scheme.Kind == 0 {
+			return fmt.Errorf("security scheme %#v: unknown kind %#v", s.SchemeName, s.Kind)
+		}
+		api.SecuritySchemes = append(api.SecuritySchemes, scheme)
+		l.schemes[s.SchemeName] = scheme
+	}
+	var err error
+	if api.Security, err = l.security(a.Security); err != nil {
+		return err
+	}
+	if api.RateLimit, err = newRateLimit(a.RateLimit, api); err != nil {
+		return err
+	}
+
+	// Create the user types and media types first so that attributes may refer to them.
+	api.Types = make(map[string]*design.UserTypeDefinition, len(a.Types))
+	for n := range a.Types {
+		api.Types[n] = &design.UserTypeDefinition{TypeName: n, AttributeDefinition: &design.AttributeDefinition{}}
+	}
+	api.MediaTypes = make(map[string]*design.MediaTypeDefinition, len(a.MediaTypes))
+	for id, m := range a.MediaTypes {
+		var mt *design.MediaTypeDefinition
+		switch design.CanonicalIdentifier(m.Identifier) {
+		case design.CanonicalIdentifier(design.ErrorMediaIdentifier):
+			mt = design.ErrorMedia
+		case design.CanonicalIdentifier(design.ProblemMediaIdentifier):
+			mt = design.ProblemMedia
+		default:
+			mt = &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName:            m.TypeName,
+					AttributeDefinition: &design.AttributeDefinition{},
+				},
+				Identifier:  m.Identifier,
+				ContentType: m.ContentType,
+				ETag:        m.ETag,
+			}
+		}
+		api.MediaTypes[id] = mt
+		l.mediaTypes[design.CanonicalIdentifier(m.Identifier)] = mt
+	}
+	for n, t := range a.Types {
+		if err := l.attribute(t, api.Types[n].AttributeDefinition); err != nil {
+			return fmt.Errorf("type %#v: %s", n, err)
+		}
+	}
+	for id, m := range a.MediaTypes {
+		mt := api.MediaTypes[id]
+		if mt == design.ErrorMedia || mt == design.ProblemMedia {
+			continue
+		}
+		if err := l.mediaType(m, mt); err != nil {
+			return fmt.Errorf("media type %#v: %s", m.Identifier, err)
+		}
+	}
+
+	if api.Params, err = l.newAttribute(a.Params); err != nil {
+		return err
+	}
+	if api.Responses, err = l.responses(a.Responses, api); err != nil {
+		return err
+	}
+	if api.Callbacks, err = l.callbacks(a.Callbacks, api); err != nil {
+		return err
+	}
+	api.Resources = make(map[string]*design.ResourceDefinition, len(a.Resources))
+	for n, r := range a.Resources {
+		res, err := l.resource(n, r)
+		if err != nil {
+			return fmt.Errorf("resource %#v: %s", n, err)
+		}
+		api.Resources[n] = res
+	}
+	for id, m := range a.MediaTypes {
+		if m.Resource != "" {
+			api.MediaTypes[id].Resource = api.Resources[m.Resource]
+		}
+	}
+	return nil
+}
+
+func (l *loader) mediaType(m *MediaType, mt *design.MediaTypeDefinition) error {
+	if m.Attribute != nil {
+		if err := l.attribute(m.Attribute, mt.AttributeDefinition); err != nil {
+			return err
+		}
+	}
+	if len(m.Links) > 0 {
+		mt.Links = make(map[string]*design.LinkDefinition, len(m.Links))
+		for n, lk := range m.Links {
+			mt.Links[n] = &design.LinkDefinition{Name: n, View: lk.View, URITemplate: lk.URITemplate, Parent: mt}
+		}
+	}
+	mt.Views = make(map[string]*design.ViewDefinition, len(m.Views))
+	for n, v := range m.Views {
+		att, err := l.newAttribute(v)
+		if err != nil {
+			return fmt.Errorf("view %#v: %s", n, err)
+		}
+		mt.Views[n] = &design.ViewDefinition{AttributeDefinition: att, Name: n, Parent: mt}
+	}
+	return nil
+}
+
+func (l *loader) resource(name string, r *Resource) (*design.ResourceDefinition, error) {
+	res := design.NewResourceDefinition(name, nil)
+	res.Description = r.Description
+	res.Schemes = r.Schemes
+	res.BasePath = r.BasePath
+	res.ParentName = r.ParentName
+	res.MediaType = r.MediaType
+	res.DefaultViewName = r.DefaultViewName
+	res.CanonicalActionName = r.CanonicalAction
+	res.Metadata = r.Metadata
+	res.Origins = newOrigins(r.Origins, res)
+	res.Consumes = newEncodings(r.Consumes, false)
+	res.Produces = newEncodings(r.Produces, true)
+	var err error
+	if res.RateLimit, err = newRateLimit(r.RateLimit, res); err != nil {
+		return nil, err
+	}
+	if res.Params, err = l.newAttribute(r.Params); err != nil {
+		return nil, err
+	}
+	if res.Headers, err = l.newAttribute(r.Headers); err != nil {
+		return nil, err
+	}
+	if res.Cookies, err = l.newAttribute(r.Cookies); err != nil {
+		return nil, err
+	}
+	if res.Responses, err = l.responses(r.Responses, res); err != nil {
+		return nil, err
+	}
+	if res.Security, err = l.security(r.Security); err != nil {
+		return nil, err
+	}
+	for _, fs := range r.FileServers {
+		f := &design.FileServerDefinition{
+			Parent:      res,
+			Description: fs.Description,
+			Docs:        fs.Docs,
+			FilePath:    fs.FilePath,
+			RequestPath: fs.RequestPath,
+			Metadata:    fs.Metadata,
+		}
+		f.Cache = newCache(fs.Cache, f)
+		if f.Security, err = l.security(fs.Security); err != nil {
+			return nil, err
+		}
+		res.FileServers = append(res.FileServers, f)
+	}
+	res.Actions = make(map[string]*design.ActionDefinition, len(r.Actions))
+	for n, a := range r.Actions {
+		act, err := l.action(n, a, res)
+		if err != nil {
+			return nil, fmt.Errorf("action %#v: %s", n, err)
+		}
+		res.Actions[n] = act
+	}
+	return res, nil
+}
+
+func (l *loader) action(name string, a *Action, parent *design.ResourceDefinition) (*design.ActionDefinition, error) {
+	act := &design.ActionDefinition{
+		Name:             name,
+		Description:      a.Description,
+		Docs:             a.Docs,
+		Parent:           parent,
+		Schemes:          a.Schemes,
+		PayloadOptional:  a.PayloadOptional,
+		PayloadMultipart: a.PayloadMultipart,
+		Pagination:       a.Pagination,
+		Metadata:         a.Metadata,
+		Idempotent:       a.Idempotent,
+		ETag:             a.ETag,
+		Consumes:         newEncodings(a.Consumes, false),
+		Produces:         newEncodings(a.Produces, true),
+	}
+	if act.Metadata == nil {
+		act.Metadata = make(dslengine.MetadataDefinition)
+	}
+	act.Errors = newErrors(a.Errors, act)
+	act.Cache = newCache(a.Cache, act)
+	var err error
+	if act.RateLimit, err = newRateLimit(a.RateLimit, act); err != nil {
+		return nil, err
+	}
+	if act.Deprecation, err = newDeprecation(a.Deprecation); err != nil {
+		return nil, err
+	}
+	for _, r := range a.Routes {
+		route := &design.RouteDefinition{Verb: r.Verb, Path: r.Path, Parent: act, Metadata: r.Metadata}
+		if route.Deprecation, err = newDeprecation(r.Deprecation); err != nil {
+			return nil, err
+		}
+		act.Routes = append(act.Routes, route)
+	}
+	if act.Params, err = l.newAttribute(a.Params); err != nil {
+		return nil, err
+	}
+	if act.QueryParams, err = l.newAttribute(a.QueryParams); err != nil {
+		return nil, err
+	}
+	if act.Headers, err = l.newAttribute(a.Headers); err != nil {
+		return nil, err
+	}
+	if act.Cookies, err = l.newAttribute(a.Cookies); err != nil {
+		return nil, err
+	}
+	if a.Payload != nil {
+		dt, err := l.dataType(a.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("payload: %s", err)
+		}
+		ut, ok := dt.(*design.UserTypeDefinition)
+		if !ok {
+			return nil, fmt.Errorf("payload must be a user type")
+		}
+		act.Payload = ut
+	}
+	if act.Responses, err = l.responses(a.Responses, act); err != nil {
+		return nil, err
+	}
+	if act.Security, err = l.security(a.Security); err != nil {
+		return nil, err
+	}
+	if act.Callbacks, err = l.callbacks(a.Callbacks, act); err != nil {
+		return nil, err
+	}
+	return act, nil
+}
+
+func (l *loader) callbacks(cbs map[string]*Callback, parent dslengine.Definition) (map[string]*design.CallbackDefinition, error) {
+	if len(cbs) == 0 {
+		return nil, nil
+	}
+	res := make(map[string]*design.CallbackDefinition, len(cbs))
+	for n, c := range cbs {
+		cb := &design.CallbackDefinition{
+			Name:        n,
+			Description: c.Description,
+			Parent:      parent,
+			URL:         c.URL,
+			Metadata:    c.Metadata,
+		}
+		if c.Payload != nil {
+			dt, err := l.dataType(c.Payload)
+			if err != nil {
+				return nil, fmt.Errorf("callback %#v: %s", n, err)
+			}
+			ut, ok := dt.(*design.UserTypeDefinition)
+			if !ok {
+				return nil, fmt.Errorf("callback %#v: payload must be a user type", n)
+			}
+			cb.Payload = ut
+		}
+		res[n] = cb
+	}
+	return res, nil
+}
+
+func (l *loader) responses(resps map[string]*Response, parent dslengine.Definition) (map[string]*design.ResponseDefinition, error) {
+	if len(resps) == 0 {
+		return nil, nil
+	}
+	res := make(map[string]*design.ResponseDefinition, len(resps))
+	for n, r := range resps {
+		resp := &design.ResponseDefinition{
+			Name:        n,
+			Status:      r.Status,
+			Description: r.Description,
+			MediaType:   r.MediaType,
+			ViewName:    r.ViewName,
+			Stream:      r.Stream,
+			Parent:      parent,
+			Metadata:    r.Metadata,
+			Standard:    r.Standard,
+		}
+		resp.Cache = newCache(r.Cache, resp)
+		var err error
+		if r.Type != nil {
+			if resp.Type, err = l.dataType(r.Type); err != nil {
+				return nil, fmt.Errorf("response %#v: %s", n, err)
+			}
+		}
+		if resp.Headers, err = l.newAttribute(r.Headers); err != nil {
+			return nil, fmt.Errorf("response %#v: %s", n, err)
+		}
+		if resp.Cookies, err = l.newAttribute(r.Cookies); err != nil {
+			return nil, fmt.Errorf("response %#v: %s", n, err)
+		}
+		for _, ex := range r.Examples {
+			resp.Examples = append(resp.Examples, &design.ExampleDefinition{Name: ex.Name, Value: importValue(resp.Type, ex.Value)})
+		}
+		res[n] = resp
+	}
+	return res, nil
+}
+
+func (l *loader) newAttribute(a *Attribute) (*design.AttributeDefinition, error) {
+	if a == nil {
+		return nil, nil
+	}
+	att := &design.AttributeDefinition{}
+	if err := l.attribute(a, att); err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+// attribute initializes att with the content of a.
+func (l *loader) attribute(a *Attribute, att *design.AttributeDefinition) error {
+	var err error
+	if a.Type != nil {
+		if att.Type, err = l.dataType(a.Type); err != nil {
+			return err
+		}
+	}
+	if a.Reference != nil {
+		if att.Reference, err = l.dataType(a.Reference); err != nil {
+			return err
+		}
+	}
+	att.Description = a.Description
+	att.Metadata = a.Metadata
+	att.View = a.View
+	att.ReadOnly = a.ReadOnly
+	att.WriteOnly = a.WriteOnly
+	if att.Deprecation, err = newDeprecation(a.Deprecation); err != nil {
+		return err
+	}
+	if a.Default != nil {
+		att.SetDefault(importValue(att.Type, a.Default))
+	}
+	att.Example = importValue(att.Type, a.Example)
+	for _, ex := range a.Examples {
+		att.Examples = append(att.Examples, &design.ExampleDefinition{Name: ex.Name, Value: importValue(att.Type, ex.Value)})
+	}
+	if len(a.NonZero) > 0 {
+		att.NonZeroAttributes = make(map[string]bool, len(a.NonZero))
+		for _, n := range a.NonZero {
+			att.NonZeroAttributes[n] = true
+		}
+	}
+	if v := a.Validation; v != nil {
+		att.Validation = &dslengine.ValidationDefinition{
+			Format:           v.Format,
+			Pattern:          v.Pattern,
+			Minimum:          v.Minimum,
+			Maximum:          v.Maximum,
+			ExclusiveMinimum: v.ExclusiveMinimum,
+			ExclusiveMaximum: v.ExclusiveMaximum,
+			MultipleOf:       v.MultipleOf,
+			MinLength:        v.MinLength,
+			MaxLength:        v.MaxLength,
+			UniqueItems:      v.UniqueItems,
+			MinProperties:    v.MinProperties,
+			MaxProperties:    v.MaxProperties,
+			Required:         v.Required,
+			RequiredIf:       v.RequiredIf,
+			AtLeastOneOf:     v.AtLeastOneOf,
+			Functions:        v.Functions,
+		}
+		for _, val := range v.Values {
+			att.Validation.Values = append(att.Validation.Values, importValue(att.Type, val))
+		}
+		var obj design.Object
+		if att.Type != nil {
+			obj = att.Type.ToObject()
+		}
+		for _, req := range att.Validation.RequiredIf {
+			if dep, ok := obj[req.DependsOn]; ok {
+				for i, val := range req.Values {
+					req.Values[i] = importValue(dep.Type, val)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (l *loader) dataType(dt *DataType) (design.DataType, error) {
+	for p, k := range primitiveKinds {
+		if k == dt.Kind {
+			return p, nil
+		}
+	}
+	switch dt.Kind {
+	case "array":
+		elem, err := l.newAttribute(dt.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return &design.Array{ElemType: elem}, nil
+	case "hash":
+		key, err := l.newAttribute(dt.Key)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := l.newAttribute(dt.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return &design.Hash{KeyType: key, ElemType: elem}, nil
+	case "object":
+		obj := make(design.Object, len(dt.Fields))
+		for n, f := range dt.Fields {
+			att, err := l.newAttribute(f)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %#v: %s", n, err)
+			}
+			obj[n] = att
+		}
+		return obj, nil
+	case "union":
+		u := &design.Union{Discriminator: dt.Discriminator}
+		for _, t := range dt.Types {
+			ut, err := l.dataType(t)
+			if err != nil {
+				return nil, err
+			}
+			actual, ok := ut.(*design.UserTypeDefinition)
+			if !ok {
+				return nil, fmt.Errorf("union types must be user types")
+			}
+			u.Types = append(u.Types, actual)
+		}
+		return u, nil
+	case "user_type":
+		if dt.UserType != nil {
+			att, err := l.newAttribute(dt.UserType)
+			if err != nil {
+				return nil, err
+			}
+			return &design.UserTypeDefinition{TypeName: dt.Ref, AttributeDefinition: att}, nil
+		}
+		ut, ok := l.api.Types[dt.Ref]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %#v", dt.Ref)
+		}
+		return ut, nil
+	case "media_type":
+		mt, ok := l.mediaTypes[design.CanonicalIdentifier(dt.Ref)]
+		if !ok {
+			return nil, fmt.Errorf("unknown media type %#v", dt.Ref)
+		}
+		return mt, nil
+	}
+	return nil, fmt.Errorf("unknown data type kind %#v", dt.Kind)
+}
+
+func (l *loader) security(s *Security) (*design.SecurityDefinition, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if s.None {
+		return &design.SecurityDefinition{Scheme: &design.SecuritySchemeDefinition{Kind: design.NoSecurityKind}}, nil
+	}
+	scheme, ok := l.schemes[s.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unknown security scheme %#v", s.Scheme)
+	}
+	return &design.SecurityDefinition{Scheme: scheme, Scopes: s.Scopes}, nil
+}
+
+func newEncodings(encs []*Encoding, encoder bool) []*design.EncodingDefinition {
+	var res []*design.EncodingDefinition
+	for _, enc := range encs {
+		res = append(res, &design.EncodingDefinition{MIMETypes: enc.MIMETypes, PackagePath: enc.PackagePath, Function: enc.Function, Encoder: encoder})
+	}
+	return res
+}
+
+func newErrors(errs map[string]*Error, parent dslengine.Definition) map[string]*design.ErrorDefinition {
+	if len(errs) == 0 {
+		return nil
+	}
+	res := make(map[string]*design.ErrorDefinition, len(errs))
+	for n, e := range errs {
+		res[n] = &design.ErrorDefinition{
+			Name:        n,
+			Description: e.Description,
+			Status:      e.Status,
+			MediaType:   e.MediaType,
+			Parent:      parent,
+		}
+	}
+	return res
+}
+
+func newRateLimit(r *RateLimit, parent dslengine.Definition) (*design.RateLimitDefinition, error) {
+	if r == nil {
+		return nil, nil
+	}
+	period, err := time.ParseDuration(r.Period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit period %#v: %s", r.Period, err)
+	}
+	return &design.RateLimitDefinition{Requests: r.Requests, Period: period, Parent: parent}, nil
+}
+
+func newCache(c *Cache, parent dslengine.Definition) *design.CacheDefinition {
+	if c == nil {
+		return nil
+	}
+	return &design.CacheDefinition{MaxAge: c.MaxAge, Private: c.Private, NoStore: c.NoStore, Vary: c.Vary, Parent: parent}
+}
+
+func newOrigins(o map[string]*CORS, parent dslengine.Definition) map[string]*design.CORSDefinition {
+	if len(o) == 0 {
+		return nil
+	}
+	res := make(map[string]*design.CORSDefinition, len(o))
+	for n, c := range o {
+		res[n] = &design.CORSDefinition{
+			Parent:      parent,
+			Origin:      n,
+			Headers:     c.Headers,
+			Methods:     c.Methods,
+			Exposed:     c.Exposed,
+			MaxAge:      c.MaxAge,
+			Credentials: c.Credentials,
+			Regexp:      c.Regexp,
+		}
+	}
+	return res
+}
+
+func newDeprecation(d *Deprecation) (*design.DeprecationDefinition, error) {
+	if d == nil {
+		return nil, nil
+	}
+	res := &design.DeprecationDefinition{Reason: d.Reason}
+	if d.Sunset != "" {
+		t, err := time.Parse(time.RFC3339, d.Sunset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sunset date %#v: %s", d.Sunset, err)
+		}
+		res.Sunset = t
+	}
+	return res, nil
+}
+
+// importValue converts a value decoded from JSON to the Go type used by the design for values of
+// the given data type: JSON numbers are converted to int for integer attributes and hash values
+// use map[interface{}]interface{}.
+func importValue(t design.DataType, v interface{}) interface{} {
+	if v == nil || t == nil {
+		return v
+	}
+	switch actual := t.(type) {
+	case design.Primitive:
+		if f, ok := v.(float64); ok && actual == design.Integer {
+			return int(f)
+		}
+	case *design.Array:
+		if vals, ok := v.([]interface{}); ok {
+			res := make([]interface{}, len(vals))
+			for i, e := range vals {
+				res[i] = importValue(actual.ElemType.Type, e)
+			}
+			return res
+		}
+	case *design.Hash:
+		if m, ok := v.(map[string]interface{}); ok {
+			res := make(map[interface{}]interface{}, len(m))
+			for k, e := range m {
+				res[importKey(actual.KeyType.Type, k)] = importValue(actual.ElemType.Type, e)
+			}
+			return res
+		}
+	case design.Object:
+		if m, ok := v.(map[string]interface{}); ok {
+			res := make(map[string]interface{}, len(m))
+			for k, e := range m {
+				if att, ok := actual[k]; ok {
+					res[k] = importValue(att.Type, e)
+				} else {
+					res[k] = e
+				}
+			}
+			return res
+		}
+	case *design.UserTypeDefinition:
+		return importValue(actual.Type, v)
+	case *design.MediaTypeDefinition:
+		return importValue(actual.Type, v)
+	}
+	return v
+}
+
+// importKey converts a JSON object key to the Go type used by the design for hash keys of the
+// given data type.
+func importKey(t design.DataType, k string) interface{} {
+	switch t {
+	case design.Integer:
+		if i, err := strconv.Atoi(k); err == nil {
+			return i
+		}
+	case design.Number:
+		if f, err := strconv.ParseFloat(k, 64); err == nil {
+			return f
+		}
+	case design.Boolean:
+		if b, err := strconv.ParseBool(k); err == nil {
+			return b
+		}
+	}
+	return k
+}
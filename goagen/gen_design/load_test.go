@@ -0,0 +1,247 @@
+package gendesign_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_design"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// testDesign exercises most of the DSL.
+func testDesign() {
+	var BasicAuth = BasicAuthSecurity("basic")
+	API("cellar", func() {
+		Title("The virtual wine cellar")
+		Host("cellar.goa.design")
+		Scheme("https")
+		BasePath("/cellar")
+		Metadata("swagger:tag:cellar")
+		Origin("http://swagger.goa.design", func() {
+			Methods("GET", "POST")
+			MaxAge(600)
+		})
+		Security(BasicAuth)
+		Error("unavailable", 503, func() {
+			Description("The cellar is closed")
+		})
+		RateLimit(1000, time.Hour)
+		Callback("inventory_low", func() {
+			URL("{$request.body#/callback_url}")
+			Payload(func() {
+				Member("count", Integer)
+			})
+		})
+	})
+	var Vintage = Type("Vintage", func() {
+		Attribute("year", Integer, func() {
+			Minimum(1900)
+			Default(2000)
+		})
+		Attribute("tags", HashOf(Integer, String))
+	})
+	var Winery = MediaType("application/vnd.winery", func() {
+		Attributes(func() {
+			Attribute("name")
+		})
+		View("default", func() {
+			Attribute("name")
+		})
+		View("link", func() {
+			Attribute("name")
+		})
+	})
+	var Bottle = MediaType("application/vnd.bottle", func() {
+		TypeName("Bottle")
+		ETag()
+		Attributes(func() {
+			Attribute("id", Integer)
+			Attribute("name", String, func() {
+				MinLength(2)
+				Example("Number 8")
+			})
+			Attribute("color", String, func() {
+				Enum("red", "white")
+			})
+			Attribute("vintage", Vintage)
+			Attribute("winery", Winery)
+			Attribute("created_at", DateTime)
+			Links(func() {
+				Link("winery")
+			})
+			Required("id", "name")
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("name")
+			Attribute("color")
+			Attribute("links")
+		})
+		View("tiny", func() {
+			Attribute("id")
+		})
+	})
+	Resource("bottle", func() {
+		BasePath("/bottles")
+		DefaultMedia(Bottle)
+		RateLimit(100, time.Minute)
+		Consumes("application/json")
+		Action("show", func() {
+			Routing(GET("/:id"))
+			Params(func() {
+				Param("id", Integer)
+			})
+			Produces("application/json")
+			Cache(func() {
+				MaxAge(60)
+				Private()
+				Vary("Accept")
+			})
+			Response(OK)
+			Response(NotFound, func() {
+				Cache(func() {
+					NoStore()
+				})
+			})
+		})
+		Action("create", func() {
+			Routing(POST(""))
+			Idempotent()
+			ETag()
+			RateLimit(10, time.Minute)
+			Error("out_of_stock", 409)
+			Callback("bottle_shipped", func() {
+				Description("Sent when the bottle ships")
+				Payload(func() {
+					Member("id", Integer)
+					Required("id")
+				})
+			})
+			Payload(func() {
+				Member("name")
+				Member("vintage", Vintage)
+				Required("name")
+			})
+			Response(Created, func() {
+				Headers(func() {
+					Header("Location")
+				})
+			})
+			Response(BadRequest, ErrorMedia)
+		})
+	})
+}
+
+// roundTrip evaluates the DSL, exports the design, loads it back and returns both documents.
+func roundTrip(dsl func()) (exported, loaded []byte, api *APIDefinition) {
+	dslengine.Reset()
+	dsl()
+	Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	exported, err := json.Marshal(gendesign.Export(Design))
+	Ω(err).ShouldNot(HaveOccurred())
+	api, err = gendesign.Decode(bytes.NewReader(exported))
+	Ω(err).ShouldNot(HaveOccurred())
+	loaded, err = json.Marshal(gendesign.Export(api))
+	Ω(err).ShouldNot(HaveOccurred())
+	return
+}
+
+var _ = Describe("Decode", func() {
+	var exported, loaded []byte
+	var api *APIDefinition
+
+	BeforeEach(func() {
+		exported, loaded, api = roundTrip(testDesign)
+	})
+
+	It("loads an equivalent design", func() {
+		Ω(string(loaded)).Should(MatchJSON(string(exported)))
+	})
+
+	It("rehydrates the API definition", func() {
+		Ω(api.Name).Should(Equal("cellar"))
+		Ω(api.Host).Should(Equal("cellar.goa.design"))
+		Ω(api.Metadata).Should(HaveKey("swagger:tag:cellar"))
+		Ω(api.Origins).Should(HaveKey("http://swagger.goa.design"))
+		Ω(api.Security).ShouldNot(BeNil())
+		Ω(api.Security.Scheme.SchemeName).Should(Equal("basic"))
+	})
+
+	It("sets the parent definitions", func() {
+		r, ok := api.Resources["bottle"]
+		Ω(ok).Should(BeTrue())
+		Ω(r.Actions).Should(HaveKey("show"))
+		show := r.Actions["show"]
+		Ω(show.Parent).Should(Equal(r))
+		Ω(show.Routes).Should(HaveLen(1))
+		Ω(show.Routes[0].Parent).Should(Equal(show))
+		Ω(show.Routes[0].FullPath()).Should(Equal("/cellar/bottles/:id"))
+		Ω(show.Responses["OK"].Parent).Should(Equal(show))
+	})
+
+	It("references the user and media type definitions", func() {
+		vintage, ok := api.Types["Vintage"]
+		Ω(ok).Should(BeTrue())
+		mt := api.MediaTypeWithIdentifier("application/vnd.bottle")
+		Ω(mt).ShouldNot(BeNil())
+		Ω(mt.Type.ToObject()["vintage"].Type).Should(BeIdenticalTo(vintage))
+		payload := api.Resources["bottle"].Actions["create"].Payload
+		Ω(payload.Type.ToObject()["vintage"].Type).Should(BeIdenticalTo(vintage))
+		Ω(api.Resources["bottle"].Actions["create"].Responses["BadRequest"].MediaType).Should(Equal(ErrorMedia.Identifier))
+	})
+
+	It("restores the errors, callbacks and policies", func() {
+		Ω(api.Errors).Should(HaveKey("unavailable"))
+		Ω(api.Errors["unavailable"].Status).Should(Equal(503))
+		Ω(api.Errors["unavailable"].Description).Should(Equal("The cellar is closed"))
+		Ω(api.Errors["unavailable"].Parent).Should(Equal(api))
+		Ω(api.RateLimit.Requests).Should(Equal(1000))
+		Ω(api.RateLimit.Period).Should(Equal(time.Hour))
+		Ω(api.Callbacks).Should(HaveKey("inventory_low"))
+		Ω(api.Callbacks["inventory_low"].Payload.Type.ToObject()).Should(HaveKey("count"))
+		Ω(api.MediaTypeWithIdentifier("application/vnd.bottle").ETag).Should(BeTrue())
+
+		r := api.Resources["bottle"]
+		Ω(r.RateLimit.Requests).Should(Equal(100))
+		Ω(r.RateLimit.Parent).Should(Equal(r))
+		Ω(r.Consumes).Should(HaveLen(1))
+		Ω(r.Consumes[0].MIMETypes).Should(Equal([]string{"application/json"}))
+
+		show := r.Actions["show"]
+		Ω(show.EffectiveProduces()).Should(Equal([]string{"application/json"}))
+		Ω(show.EffectiveConsumes()).Should(Equal([]string{"application/json"}))
+		Ω(show.Cache.MaxAge).Should(Equal(uint(60)))
+		Ω(show.Cache.Vary).Should(Equal([]string{"Accept"}))
+		Ω(show.Responses["NotFound"].Cache.NoStore).Should(BeTrue())
+
+		create := r.Actions["create"]
+		Ω(create.Idempotent).Should(BeTrue())
+		Ω(create.ETag).Should(BeTrue())
+		Ω(create.RateLimit.Requests).Should(Equal(10))
+		Ω(create.Errors).Should(HaveKey("out_of_stock"))
+		Ω(create.Errors["out_of_stock"].Status).Should(Equal(409))
+		Ω(create.Errors["out_of_stock"].Parent).Should(Equal(create))
+		Ω(create.Callbacks).Should(HaveKey("bottle_shipped"))
+		Ω(create.Callbacks["bottle_shipped"].Parent).Should(Equal(create))
+		Ω(create.Callbacks["bottle_shipped"].Payload.Type.ToObject()).Should(HaveKey("id"))
+	})
+
+	It("restores the default values with the attribute type", func() {
+		year := api.Types["Vintage"].Type.ToObject()["year"]
+		Ω(year.DefaultValue).Should(Equal(2000))
+	})
+
+	Context("with a document using a different format version", func() {
+		It("returns an error", func() {
+			doc := gendesign.Export(api)
+			doc.FormatVersion = "0"
+			_, err := doc.APIDefinition()
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
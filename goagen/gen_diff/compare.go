@@ -0,0 +1,539 @@
+package gendiff
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Change describes a difference between two designs.
+type Change struct {
+	// Breaking is true if the change may break existing clients.
+	Breaking bool `json:"breaking"`
+	// Path identifies the changed definition, e.g. "resources/bottle/actions/show/params/id".
+	Path string `json:"path"`
+	// Message describes the change.
+	Message string `json:"message"`
+}
+
+// differ accumulates the changes found while comparing two snapshots.
+type differ struct {
+	changes []*Change
+}
+
+// Compare returns the changes between the base and head snapshots. The changes are classified as
+// breaking or non-breaking from the point of view of clients built against base: for example a new
+// required parameter breaks requests while a new response field does not.
+func Compare(base, head *Snapshot) []*Change {
+	d := &differ{}
+	d.resources(base.Resources, head.Resources)
+	d.callbacks("callbacks", base.Callbacks, head.Callbacks)
+	for _, n := range union(typeKeys(base.Types), typeKeys(head.Types)) {
+		d.userType("types/"+n, base.Types[n], head.Types[n])
+	}
+	for _, n := range union(mediaTypeKeys(base.MediaTypes), mediaTypeKeys(head.MediaTypes)) {
+		d.mediaType("media_types/"+n, base.MediaTypes[n], head.MediaTypes[n])
+	}
+	return d.changes
+}
+
+// String returns the change in the "breaking|non-breaking: path: message" format.
+func (c *Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("%s: %s: %s", kind, c.Path, c.Message)
+}
+
+func (d *differ) add(breaking bool, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, &Change{Breaking: breaking, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) resources(base, head map[string]*Resource) {
+	var bn, hn []string
+	for n := range base {
+		bn = append(bn, n)
+	}
+	for n := range head {
+		hn = append(hn, n)
+	}
+	for _, n := range union(bn, hn) {
+		path := "resources/" + n
+		b, h := base[n], head[n]
+		switch {
+		case h == nil:
+			d.add(true, path, "resource removed")
+		case b == nil:
+			d.add(false, path, "resource added")
+		default:
+			var ban, han []string
+			for an := range b.Actions {
+				ban = append(ban, an)
+			}
+			for an := range h.Actions {
+				han = append(han, an)
+			}
+			for _, an := range union(ban, han) {
+				d.action(path+"/actions/"+an, b.Actions[an], h.Actions[an])
+			}
+		}
+	}
+}
+
+func (d *differ) action(path string, base, head *Action) {
+	switch {
+	case head == nil:
+		d.add(true, path, "action removed")
+		return
+	case base == nil:
+		d.add(false, path, "action added")
+		return
+	}
+	for _, r := range union(base.Routes, head.Routes) {
+		switch {
+		case !contains(head.Routes, r):
+			d.add(true, path, "route %s removed", r)
+		case !contains(base.Routes, r):
+			d.add(false, path, "route %s added", r)
+		}
+	}
+	d.attribute(path+"/params", base.Params, head.Params, RequestUsage)
+	d.attribute(path+"/headers", base.Headers, head.Headers, RequestUsage)
+	d.attribute(path+"/cookies", base.Cookies, head.Cookies, RequestUsage)
+	d.security(path+"/security", base.Security, head.Security)
+	switch {
+	case base.Payload == nil && head.Payload != nil:
+		d.add(!head.PayloadOptional, path+"/payload", "payload added")
+	case base.Payload != nil && head.Payload == nil:
+		d.add(false, path+"/payload", "payload removed")
+	case base.Payload != nil:
+		if base.PayloadOptional && !head.PayloadOptional {
+			d.add(true, path+"/payload", "payload is now required")
+		}
+		d.attribute(path+"/payload", base.Payload, head.Payload, RequestUsage)
+	}
+	var bn, hn []string
+	for n := range base.Responses {
+		bn = append(bn, n)
+	}
+	for n := range head.Responses {
+		hn = append(hn, n)
+	}
+	for _, n := range union(bn, hn) {
+		rpath := path + "/responses/" + n
+		b, h := base.Responses[n], head.Responses[n]
+		switch {
+		case h == nil:
+			d.add(true, rpath, "response removed")
+		case b == nil:
+			d.add(false, rpath, "response added")
+		default:
+			if b.Status != h.Status {
+				d.add(true, rpath, "status changed from %d to %d", b.Status, h.Status)
+			}
+			if b.MediaType != h.MediaType {
+				d.add(true, rpath, "media type changed from %#v to %#v", b.MediaType, h.MediaType)
+			}
+			d.attribute(rpath+"/headers", b.Headers, h.Headers, ResponseUsage)
+		}
+	}
+	bn, hn = nil, nil
+	for n := range base.Errors {
+		bn = append(bn, n)
+	}
+	for n := range head.Errors {
+		hn = append(hn, n)
+	}
+	for _, n := range union(bn, hn) {
+		epath := path + "/errors/" + n
+		b, h := base.Errors[n], head.Errors[n]
+		switch {
+		case h == nil:
+			d.add(true, epath, "error removed")
+		case b == nil:
+			d.add(false, epath, "error added")
+		default:
+			if b.Status != h.Status {
+				d.add(true, epath, "status changed from %d to %d", b.Status, h.Status)
+			}
+			if b.MediaType != h.MediaType {
+				d.add(true, epath, "media type changed from %#v to %#v", b.MediaType, h.MediaType)
+			}
+		}
+	}
+	for _, m := range union(base.Consumes, head.Consumes) {
+		switch {
+		case !contains(head.Consumes, m):
+			d.add(true, path+"/consumes", "%s no longer accepted", m)
+		case !contains(base.Consumes, m):
+			d.add(false, path+"/consumes", "%s now accepted", m)
+		}
+	}
+	for _, m := range union(base.Produces, head.Produces) {
+		switch {
+		case !contains(head.Produces, m):
+			d.add(true, path+"/produces", "%s no longer produced", m)
+		case !contains(base.Produces, m):
+			d.add(false, path+"/produces", "%s now produced", m)
+		}
+	}
+	if base.Idempotent != head.Idempotent {
+		if head.Idempotent {
+			d.add(false, path, "Idempotency-Key header now supported")
+		} else {
+			d.add(true, path, "Idempotency-Key header no longer supported")
+		}
+	}
+	if base.ETag != head.ETag {
+		if head.ETag {
+			d.add(false, path, "entity tags added")
+		} else {
+			d.add(true, path, "entity tags removed")
+		}
+	}
+	d.rateLimit(path+"/rate_limit", base.RateLimit, head.RateLimit)
+	if !reflect.DeepEqual(base.Cache, head.Cache) {
+		d.add(false, path+"/cache", "cache policy changed")
+	}
+	d.callbacks(path+"/callbacks", base.Callbacks, head.Callbacks)
+}
+
+// security compares security requirements. Adding a requirement, changing the scheme or requiring
+// new scopes breaks clients that do not send the corresponding credentials.
+func (d *differ) security(path string, base, head *Security) {
+	switch {
+	case base == nil && head == nil:
+		return
+	case base == nil:
+		d.add(true, path, "security requirement %s added", head.Scheme)
+		return
+	case head == nil:
+		d.add(false, path, "security requirement %s removed", base.Scheme)
+		return
+	case base.Scheme != head.Scheme:
+		d.add(true, path, "security scheme changed from %s to %s", base.Scheme, head.Scheme)
+		return
+	}
+	for _, s := range union(base.Scopes, head.Scopes) {
+		switch {
+		case !contains(head.Scopes, s):
+			d.add(false, path, "scope %#v no longer required", s)
+		case !contains(base.Scopes, s):
+			d.add(true, path, "scope %#v now required", s)
+		}
+	}
+}
+
+// rateLimit compares rate limits. Adding a rate limit or lowering the allowed request rate breaks
+// clients that make requests at the old rate.
+func (d *differ) rateLimit(path string, base, head *RateLimit) {
+	switch {
+	case base == nil && head == nil:
+		return
+	case base == nil:
+		d.add(true, path, "rate limit of %d requests per %s added", head.Requests, head.Period)
+		return
+	case head == nil:
+		d.add(false, path, "rate limit removed")
+		return
+	}
+	br, hr := rate(base), rate(head)
+	switch {
+	case hr < br:
+		d.add(true, path, "rate limit lowered to %d requests per %s", head.Requests, head.Period)
+	case hr > br:
+		d.add(false, path, "rate limit raised to %d requests per %s", head.Requests, head.Period)
+	}
+}
+
+// callbacks compares callbacks. The subscribers receive the callback requests so the payloads are
+// compared as responses.
+func (d *differ) callbacks(path string, base, head map[string]*Callback) {
+	var bn, hn []string
+	for n := range base {
+		bn = append(bn, n)
+	}
+	for n := range head {
+		hn = append(hn, n)
+	}
+	for _, n := range union(bn, hn) {
+		cpath := path + "/" + n
+		b, h := base[n], head[n]
+		switch {
+		case h == nil:
+			d.add(true, cpath, "callback removed")
+		case b == nil:
+			d.add(false, cpath, "callback added")
+		default:
+			if b.URL != h.URL {
+				d.add(true, cpath, "URL changed from %#v to %#v", b.URL, h.URL)
+			}
+			d.attribute(cpath+"/payload", b.Payload, h.Payload, ResponseUsage)
+		}
+	}
+}
+
+func (d *differ) userType(path string, base, head *Type) {
+	switch {
+	case head == nil:
+		d.add(base.Usage != 0, path, "type removed")
+	case base == nil:
+		d.add(false, path, "type added")
+	default:
+		d.attribute(path, base.Attribute, head.Attribute, base.Usage)
+	}
+}
+
+func (d *differ) mediaType(path string, base, head *MediaType) {
+	switch {
+	case head == nil:
+		d.add(base.Usage != 0, path, "media type removed")
+		return
+	case base == nil:
+		d.add(false, path, "media type added")
+		return
+	}
+	if base.Identifier != head.Identifier {
+		d.add(base.Usage != 0, path, "identifier changed from %#v to %#v", base.Identifier, head.Identifier)
+	}
+	d.attribute(path, base.Attribute, head.Attribute, base.Usage)
+	var bn, hn []string
+	for n := range base.Views {
+		bn = append(bn, n)
+	}
+	for n := range head.Views {
+		hn = append(hn, n)
+	}
+	for _, n := range union(bn, hn) {
+		vpath := path + "/views/" + n
+		b, h := base.Views[n], head.Views[n]
+		switch {
+		case h == nil:
+			d.add(base.Usage&ResponseUsage != 0, vpath, "view removed")
+		case b == nil:
+			d.add(false, vpath, "view added")
+		default:
+			for _, a := range union(b, h) {
+				switch {
+				case !contains(h, a):
+					d.add(base.Usage&ResponseUsage != 0, vpath, "attribute %#v no longer rendered", a)
+				case !contains(b, a):
+					d.add(false, vpath, "attribute %#v now rendered", a)
+				}
+			}
+		}
+	}
+}
+
+// attribute compares two attributes used as indicated by usage. reqBreaking and respBreaking
+// in the calls to breaking below indicate whether a change breaks clients sending or receiving
+// values of the attribute respectively.
+func (d *differ) attribute(path string, base, head *Attribute, usage Usage) {
+	breaking := func(reqBreaking, respBreaking bool) bool {
+		return usage&RequestUsage != 0 && reqBreaking || usage&ResponseUsage != 0 && respBreaking
+	}
+	switch {
+	case base == nil && head == nil:
+		return
+	case base == nil:
+		base = &Attribute{Type: head.Type, TypeName: head.TypeName}
+	case head == nil:
+		head = &Attribute{Type: base.Type, TypeName: base.TypeName}
+	}
+	if base.Type != head.Type || base.TypeName != head.TypeName {
+		d.add(breaking(true, true), path, "type changed from %s to %s", typeDesc(base), typeDesc(head))
+		return
+	}
+
+	var bn, hn []string
+	for n := range base.Fields {
+		bn = append(bn, n)
+	}
+	for n := range head.Fields {
+		hn = append(hn, n)
+	}
+	for _, n := range union(bn, hn) {
+		fpath := path + "/" + n
+		b, h := base.Fields[n], head.Fields[n]
+		breq, hreq := contains(base.Required, n), contains(head.Required, n)
+		switch {
+		case h == nil:
+			d.add(breaking(false, true), fpath, "attribute removed")
+		case b == nil && hreq:
+			d.add(breaking(true, false), fpath, "required attribute added")
+		case b == nil:
+			d.add(false, fpath, "attribute added")
+		default:
+			if !breq && hreq {
+				d.add(breaking(true, false), fpath, "attribute is now required")
+			} else if breq && !hreq {
+				d.add(breaking(false, true), fpath, "attribute is no longer required")
+			}
+			d.attribute(fpath, b, h, usage)
+		}
+	}
+	d.attribute(path+"/[key]", base.Key, head.Key, usage)
+	d.attribute(path+"/[]", base.Elem, head.Elem, usage)
+
+	d.enum(path, base.Enum, head.Enum, breaking)
+	if base.Format != head.Format {
+		d.add(breaking(head.Format != "", false), path, "format changed from %#v to %#v", base.Format, head.Format)
+	}
+	if base.Pattern != head.Pattern {
+		d.add(breaking(head.Pattern != "", false), path, "pattern changed from %#v to %#v", base.Pattern, head.Pattern)
+	}
+	if tighter(base.Minimum, head.Minimum, false) {
+		d.add(breaking(true, false), path, "minimum raised to %v", *head.Minimum)
+	} else if head.Minimum != nil && *head.Minimum == *base.Minimum && head.ExclusiveMinimum && !base.ExclusiveMinimum {
+		d.add(breaking(true, false), path, "minimum %v is now exclusive", *head.Minimum)
+	}
+	if tighter(base.Maximum, head.Maximum, true) {
+		d.add(breaking(true, false), path, "maximum lowered to %v", *head.Maximum)
+	} else if head.Maximum != nil && *head.Maximum == *base.Maximum && head.ExclusiveMaximum && !base.ExclusiveMaximum {
+		d.add(breaking(true, false), path, "maximum %v is now exclusive", *head.Maximum)
+	}
+	if head.MultipleOf != nil && (base.MultipleOf == nil || math.Mod(*base.MultipleOf, *head.MultipleOf) != 0) {
+		d.add(breaking(true, false), path, "multiple of changed to %v", *head.MultipleOf)
+	}
+	if tighter(intToFloat(base.MinLength), intToFloat(head.MinLength), false) {
+		d.add(breaking(true, false), path, "minimum length raised to %d", *head.MinLength)
+	}
+	if tighter(intToFloat(base.MaxLength), intToFloat(head.MaxLength), true) {
+		d.add(breaking(true, false), path, "maximum length lowered to %d", *head.MaxLength)
+	}
+	if head.UniqueItems && !base.UniqueItems {
+		d.add(breaking(true, false), path, "elements must now be unique")
+	}
+	if tighter(intToFloat(base.MinProperties), intToFloat(head.MinProperties), false) {
+		d.add(breaking(true, false), path, "minimum properties raised to %d", *head.MinProperties)
+	}
+	if tighter(intToFloat(base.MaxProperties), intToFloat(head.MaxProperties), true) {
+		d.add(breaking(true, false), path, "maximum properties lowered to %d", *head.MaxProperties)
+	}
+}
+
+// enum compares enum validations. Narrowing an enum breaks requests while widening it breaks
+// responses.
+func (d *differ) enum(path string, base, head []interface{}, breaking func(bool, bool) bool) {
+	switch {
+	case len(base) == 0 && len(head) == 0:
+		return
+	case len(base) == 0:
+		d.add(breaking(true, false), path, "enum %s added", enumDesc(head))
+		return
+	case len(head) == 0:
+		d.add(breaking(false, true), path, "enum %s removed", enumDesc(base))
+		return
+	}
+	bv, hv := enumValues(base), enumValues(head)
+	var removed, added []string
+	for _, v := range union(bv, hv) {
+		switch {
+		case !contains(hv, v):
+			removed = append(removed, v)
+		case !contains(bv, v):
+			added = append(added, v)
+		}
+	}
+	if len(removed) > 0 {
+		d.add(breaking(true, false), path, "enum values %s removed", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		d.add(breaking(false, true), path, "enum values %s added", strings.Join(added, ", "))
+	}
+}
+
+// tighter returns true if the head bound is more restrictive than the base bound. max indicates
+// whether the bounds are upper bounds.
+func tighter(base, head *float64, max bool) bool {
+	switch {
+	case head == nil:
+		return false
+	case base == nil:
+		return true
+	case max:
+		return *head < *base
+	default:
+		return *head > *base
+	}
+}
+
+// rate returns the number of requests allowed per second by the given rate limit, 0 if the period
+// cannot be parsed.
+func rate(r *RateLimit) float64 {
+	p, err := time.ParseDuration(r.Period)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / p.Seconds()
+}
+
+func intToFloat(i *int) *float64 {
+	if i == nil {
+		return nil
+	}
+	f := float64(*i)
+	return &f
+}
+
+func typeDesc(a *Attribute) string {
+	if a.TypeName != "" {
+		return a.TypeName
+	}
+	return a.Type
+}
+
+func enumValues(vals []interface{}) []string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = fmt.Sprintf("%#v", v)
+	}
+	return res
+}
+
+func enumDesc(vals []interface{}) string {
+	return "[" + strings.Join(enumValues(vals), ", ") + "]"
+}
+
+// union returns the sorted union of the given string slices.
+func union(a, b []string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			res = append(res, s)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func typeKeys(m map[string]*Type) []string {
+	var res []string
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
+
+func mediaTypeKeys(m map[string]*MediaType) []string {
+	var res []string
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
@@ -0,0 +1,454 @@
+package gendiff_test
+
+import (
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// snapshotOf runs the given DSL and returns the snapshot of the resulting design.
+func snapshotOf(dsl func()) *gendiff.Snapshot {
+	dslengine.Reset()
+	dsl()
+	Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	return gendiff.NewSnapshot(Design)
+}
+
+// baseDesign is the design used as base of the comparisons.
+func baseDesign() {
+	API("test", nil)
+	var Bottle = MediaType("application/vnd.bottle", func() {
+		Attributes(func() {
+			Attribute("id", Integer)
+			Attribute("name")
+			Attribute("color", String, func() {
+				Enum("red", "white")
+			})
+			Required("id", "name")
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("name")
+			Attribute("color")
+		})
+		View("tiny", func() {
+			Attribute("id")
+		})
+	})
+	Resource("bottle", func() {
+		BasePath("/bottles")
+		Action("show", func() {
+			Routing(GET("/:id"))
+			Params(func() {
+				Param("id", Integer)
+			})
+			Response(OK, Bottle)
+			Response(NotFound)
+		})
+		Action("create", func() {
+			Routing(POST(""))
+			Payload(func() {
+				Member("name")
+				Member("color", String, func() {
+					Enum("red", "white")
+				})
+				Required("name")
+			})
+			Response(Created)
+		})
+		Action("delete", func() {
+			Routing(DELETE("/:id"))
+			Response(NoContent)
+		})
+	})
+}
+
+var _ = Describe("Compare", func() {
+	var base *gendiff.Snapshot
+	var head func()
+	var tweak func(*gendiff.Snapshot)
+	var changes []*gendiff.Change
+
+	// find returns the changes with the given path.
+	find := func(path string) []*gendiff.Change {
+		var res []*gendiff.Change
+		for _, c := range changes {
+			if c.Path == path {
+				res = append(res, c)
+			}
+		}
+		return res
+	}
+
+	BeforeEach(func() {
+		base = snapshotOf(baseDesign)
+		head = baseDesign
+		tweak = nil
+	})
+
+	JustBeforeEach(func() {
+		h := snapshotOf(head)
+		if tweak != nil {
+			tweak(h)
+		}
+		changes = gendiff.Compare(base, h)
+	})
+
+	It("does not report changes for identical designs", func() {
+		Ω(changes).Should(BeEmpty())
+	})
+
+	Context("with breaking and non-breaking changes", func() {
+		BeforeEach(func() {
+			head = func() {
+				API("test", nil)
+				var Bottle = MediaType("application/vnd.bottle", func() {
+					Attributes(func() {
+						Attribute("id", Integer)
+						Attribute("name")
+						Attribute("vintage", Integer)
+						Required("id", "name")
+					})
+					View("default", func() {
+						Attribute("id")
+						Attribute("name")
+						Attribute("vintage")
+					})
+					View("tiny", func() {
+						Attribute("id")
+					})
+				})
+				Resource("bottle", func() {
+					BasePath("/bottles")
+					Action("show", func() {
+						Routing(GET("/:id"))
+						Params(func() {
+							Param("id", Integer)
+							Param("fields", String)
+							Required("fields")
+						})
+						Response(OK, Bottle)
+						Response(NotFound)
+					})
+					Action("create", func() {
+						Routing(POST(""))
+						Payload(func() {
+							Member("name")
+							Member("color", String, func() {
+								Enum("red")
+							})
+							Member("vintage", Integer)
+							Required("name")
+						})
+						Response(Created)
+					})
+					Action("list", func() {
+						Routing(GET(""))
+						Response(OK)
+					})
+				})
+			}
+		})
+
+		It("reports removed actions as breaking", func() {
+			Ω(find("resources/bottle/actions/delete")).Should(ConsistOf(&gendiff.Change{
+				Breaking: true, Path: "resources/bottle/actions/delete", Message: "action removed",
+			}))
+		})
+
+		It("reports added actions as non-breaking", func() {
+			Ω(find("resources/bottle/actions/list")).Should(ConsistOf(&gendiff.Change{
+				Breaking: false, Path: "resources/bottle/actions/list", Message: "action added",
+			}))
+		})
+
+		It("reports new required params as breaking", func() {
+			Ω(find("resources/bottle/actions/show/params/fields")).Should(ConsistOf(&gendiff.Change{
+				Breaking: true, Path: "resources/bottle/actions/show/params/fields", Message: "required attribute added",
+			}))
+		})
+
+		It("reports narrowed enums as breaking", func() {
+			Ω(find("resources/bottle/actions/create/payload/color")).Should(ConsistOf(&gendiff.Change{
+				Breaking: true, Path: "resources/bottle/actions/create/payload/color", Message: `enum values "white" removed`,
+			}))
+		})
+
+		It("reports new optional payload attributes as non-breaking", func() {
+			Ω(find("resources/bottle/actions/create/payload/vintage")).Should(ConsistOf(&gendiff.Change{
+				Breaking: false, Path: "resources/bottle/actions/create/payload/vintage", Message: "attribute added",
+			}))
+		})
+
+		It("reports removed media type fields as breaking", func() {
+			Ω(find("media_types/Bottle/color")).Should(ConsistOf(&gendiff.Change{
+				Breaking: true, Path: "media_types/Bottle/color", Message: "attribute removed",
+			}))
+			Ω(find("media_types/Bottle/views/default")).Should(ConsistOf(
+				&gendiff.Change{Breaking: true, Path: "media_types/Bottle/views/default", Message: `attribute "color" no longer rendered`},
+				&gendiff.Change{Breaking: false, Path: "media_types/Bottle/views/default", Message: `attribute "vintage" now rendered`},
+			))
+		})
+
+		It("reports new media type fields as non-breaking", func() {
+			Ω(find("media_types/Bottle/vintage")).Should(ConsistOf(&gendiff.Change{
+				Breaking: false, Path: "media_types/Bottle/vintage", Message: "attribute added",
+			}))
+		})
+	})
+
+	Context("with changed types", func() {
+		BeforeEach(func() {
+			base.Resources["bottle"].Actions["show"].Params.Fields["id"].Type = "string"
+		})
+
+		It("reports the type change as breaking", func() {
+			Ω(changes).Should(ConsistOf(&gendiff.Change{
+				Breaking: true, Path: "resources/bottle/actions/show/params/id", Message: "type changed from string to integer",
+			}))
+		})
+	})
+
+	Context("with a widened response enum", func() {
+		BeforeEach(func() {
+			base.MediaTypes["Bottle"].Fields["color"].Enum = []interface{}{"red"}
+		})
+
+		It("reports the new values as breaking", func() {
+			Ω(find("media_types/Bottle/color")).Should(ConsistOf(&gendiff.Change{
+				Breaking: true, Path: "media_types/Bottle/color", Message: `enum values "white" added`,
+			}))
+		})
+	})
+
+	Context("with a removed error", func() {
+		BeforeEach(func() {
+			base.Resources["bottle"].Actions["show"].Errors["unavailable"] = &gendiff.Error{Status: 503, MediaType: ErrorMediaIdentifier}
+		})
+
+		It("reports the removal as breaking", func() {
+			Ω(changes).Should(ConsistOf(&gendiff.Change{
+				Breaking: true, Path: "resources/bottle/actions/show/errors/unavailable", Message: "error removed",
+			}))
+		})
+	})
+
+	Context("with narrowed produces", func() {
+		BeforeEach(func() {
+			tweak = func(h *gendiff.Snapshot) {
+				h.Resources["bottle"].Actions["show"].Produces = []string{"application/json"}
+			}
+		})
+
+		It("reports the mime types no longer produced as breaking", func() {
+			changes := find("resources/bottle/actions/show/produces")
+			Ω(changes).ShouldNot(BeEmpty())
+			for _, c := range changes {
+				Ω(c.Breaking).Should(BeTrue())
+				Ω(c.Message).Should(HaveSuffix("no longer produced"))
+			}
+			Ω(find("resources/bottle/actions/show/consumes")).Should(BeEmpty())
+		})
+	})
+
+	Context("with changed policies and callbacks", func() {
+		BeforeEach(func() {
+			base.Resources["bottle"].Actions["create"].Idempotent = true
+			base.Resources["bottle"].Actions["show"].RateLimit = &gendiff.RateLimit{Requests: 100, Period: "1m0s"}
+			base.Callbacks["bottle_created"] = &gendiff.Callback{URL: "{$request.body#/callback_url}"}
+			tweak = func(h *gendiff.Snapshot) {
+				h.Resources["bottle"].Actions["show"].RateLimit = &gendiff.RateLimit{Requests: 10, Period: "1m0s"}
+				h.Resources["bottle"].Actions["show"].Cache = &gendiff.Cache{MaxAge: 60}
+			}
+		})
+
+		It("classifies the changes", func() {
+			Ω(changes).Should(ConsistOf(
+				&gendiff.Change{Breaking: true, Path: "callbacks/bottle_created", Message: "callback removed"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/create", Message: "Idempotency-Key header no longer supported"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/show/rate_limit", Message: "rate limit lowered to 10 requests per 1m0s"},
+				&gendiff.Change{Breaking: false, Path: "resources/bottle/actions/show/cache", Message: "cache policy changed"},
+			))
+		})
+	})
+
+	Context("with new cookies and security requirements", func() {
+		BeforeEach(func() {
+			base.Resources["bottle"].Actions["create"].Security = &gendiff.Security{Scheme: "jwt", Scopes: []string{"bottle:read"}}
+			tweak = func(h *gendiff.Snapshot) {
+				show := h.Resources["bottle"].Actions["show"]
+				show.Cookies = &gendiff.Attribute{
+					Type:     "object",
+					Fields:   map[string]*gendiff.Attribute{"session": {Type: "string"}},
+					Required: []string{"session"},
+				}
+				show.Security = &gendiff.Security{Scheme: "jwt"}
+				h.Resources["bottle"].Actions["create"].Security = &gendiff.Security{Scheme: "jwt", Scopes: []string{"bottle:write"}}
+			}
+		})
+
+		It("reports them as breaking", func() {
+			Ω(changes).Should(ConsistOf(
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/show/cookies/session", Message: "required attribute added"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/show/security", Message: "security requirement jwt added"},
+				&gendiff.Change{Breaking: false, Path: "resources/bottle/actions/create/security", Message: `scope "bottle:read" no longer required`},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/create/security", Message: `scope "bottle:write" now required`},
+			))
+		})
+	})
+
+	Context("with tighter validations", func() {
+		BeforeEach(func() {
+			ten, four, one := 10.0, 4.0, 1
+			tags := func() *gendiff.Attribute {
+				return &gendiff.Attribute{Type: "array", Elem: &gendiff.Attribute{Type: "string"}}
+			}
+			id := base.Resources["bottle"].Actions["show"].Params.Fields["id"]
+			id.Minimum = &ten
+			id.MultipleOf = &ten
+			base.Resources["bottle"].Actions["create"].Payload.Fields["tags"] = tags()
+			tweak = func(h *gendiff.Snapshot) {
+				id := h.Resources["bottle"].Actions["show"].Params.Fields["id"]
+				id.Minimum = &ten
+				id.ExclusiveMinimum = true
+				id.Maximum = &four
+				id.ExclusiveMaximum = true
+				id.MultipleOf = &four
+				payload := h.Resources["bottle"].Actions["create"].Payload
+				payload.MinProperties = &one
+				payload.MaxProperties = &one
+				payload.Fields["tags"] = tags()
+				payload.Fields["tags"].UniqueItems = true
+			}
+		})
+
+		It("reports them as breaking", func() {
+			Ω(changes).Should(ConsistOf(
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/show/params/id", Message: "minimum 10 is now exclusive"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/show/params/id", Message: "maximum lowered to 4"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/show/params/id", Message: "multiple of changed to 4"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/create/payload", Message: "minimum properties raised to 1"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/create/payload", Message: "maximum properties lowered to 1"},
+				&gendiff.Change{Breaking: true, Path: "resources/bottle/actions/create/payload/tags", Message: "elements must now be unique"},
+			))
+		})
+	})
+
+	Context("with a looser multiple of validation", func() {
+		BeforeEach(func() {
+			ten := 10.0
+			base.Resources["bottle"].Actions["show"].Params.Fields["id"].MultipleOf = &ten
+			tweak = func(h *gendiff.Snapshot) {
+				five := 5.0
+				h.Resources["bottle"].Actions["show"].Params.Fields["id"].MultipleOf = &five
+			}
+		})
+
+		It("does not report it", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("NewSnapshot", func() {
+	var snapshot *gendiff.Snapshot
+
+	BeforeEach(func() {
+		snapshot = snapshotOf(func() {
+			API("test", func() {
+				Error("unavailable", 503)
+				RateLimit(1000, time.Hour)
+			})
+			Resource("bottle", func() {
+				Action("create", func() {
+					Routing(POST(""))
+					Produces("application/json")
+					Idempotent()
+					Error("out_of_stock", 409)
+					Callback("bottle_shipped", func() {
+						Payload(func() {
+							Member("id", Integer)
+						})
+					})
+					Response(Created)
+				})
+			})
+		})
+	})
+
+	It("records the errors, mime types, policies and callbacks of the actions", func() {
+		create := snapshot.Resources["bottle"].Actions["create"]
+		Ω(create.Errors).Should(Equal(map[string]*gendiff.Error{
+			"unavailable":  {Status: 503, MediaType: ErrorMediaIdentifier},
+			"out_of_stock": {Status: 409, MediaType: ErrorMediaIdentifier},
+		}))
+		Ω(create.Produces).Should(Equal([]string{"application/json"}))
+		Ω(create.Consumes).Should(ContainElement("application/json"))
+		Ω(create.Idempotent).Should(BeTrue())
+		Ω(create.RateLimit).Should(Equal(&gendiff.RateLimit{Requests: 1000, Period: "1h0m0s"}))
+		Ω(create.Callbacks).Should(HaveKey("bottle_shipped"))
+		Ω(create.Callbacks["bottle_shipped"].Payload.Fields).Should(HaveKey("id"))
+	})
+	Context("with cookies, security requirements and validations", func() {
+		BeforeEach(func() {
+			snapshot = snapshotOf(func() {
+				API("test", nil)
+				var JWT = JWTSecurity("jwt", func() {
+					Header("Authorization")
+					Scope("bottle:write")
+					Scope("bottle:read")
+				})
+				Resource("bottle", func() {
+					Cookies(func() {
+						Cookie("session", String)
+						Required("session")
+					})
+					Action("create", func() {
+						Routing(POST(""))
+						Security(JWT, func() {
+							Scope("bottle:write")
+							Scope("bottle:read")
+						})
+						Cookies(func() {
+							Cookie("locale", String)
+						})
+						Payload(func() {
+							Member("price", Number, func() {
+								ExclusiveMinimum(0)
+								MultipleOf(0.01)
+							})
+							Member("tags", ArrayOf(String), func() {
+								UniqueItems()
+							})
+							Member("labels", HashOf(String, String), func() {
+								MinProperties(1)
+								MaxProperties(10)
+							})
+						})
+						Response(Created)
+					})
+				})
+			})
+		})
+
+		It("records them", func() {
+			create := snapshot.Resources["bottle"].Actions["create"]
+			Ω(create.Cookies.Fields).Should(HaveKey("session"))
+			Ω(create.Cookies.Fields).Should(HaveKey("locale"))
+			Ω(create.Cookies.Required).Should(Equal([]string{"session"}))
+			Ω(create.Security).Should(Equal(&gendiff.Security{Scheme: "jwt", Scopes: []string{"bottle:read", "bottle:write"}}))
+			fields := create.Payload.Fields
+			Ω(*fields["price"].Minimum).Should(Equal(0.0))
+			Ω(fields["price"].ExclusiveMinimum).Should(BeTrue())
+			Ω(*fields["price"].MultipleOf).Should(Equal(0.01))
+			Ω(fields["tags"].UniqueItems).Should(BeTrue())
+			Ω(*fields["labels"].MinProperties).Should(Equal(1))
+			Ω(*fields["labels"].MaxProperties).Should(Equal(10))
+		})
+	})
+})
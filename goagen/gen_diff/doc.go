@@ -0,0 +1,19 @@
+/*
+Package gendiff provides a generator that compares two versions of a design and reports the
+changes, classifying each change as breaking or non-breaking for existing clients. Breaking
+changes include removed resources, actions, routes or responses, new required parameters, cookies or
+payload attributes, new security requirements or scopes, narrowed enums, tighter validations,
+changed attribute types and attributes removed from media types.
+
+The design is compared to a base design given either as a design package import path or as the
+path to a JSON snapshot file created with the --snapshot flag or a design document created with
+"goagen design". The --snapshot flag writes the snapshot to the file snapshot.json in the output
+directory:
+
+	goagen diff -d github.com/me/api/design --snapshot -o api-v1
+	goagen diff -d github.com/me/api/design --base api-v1/snapshot.json --format=markdown
+
+The report may be produced as text, JSON or Markdown and is written to stdout. The diff command
+exits with a non-zero status if there are breaking changes.
+*/
+package gendiff
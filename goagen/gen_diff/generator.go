@@ -0,0 +1,202 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_design"
+)
+
+// NewGenerator returns an initialized instance of a design diff generator.
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{Format: "text", Output: os.Stdout}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// SnapshotFile is the name of the file the snapshot of the design is written to.
+const SnapshotFile = "snapshot.json"
+
+// Generator compares the API definition with a base snapshot. It does not generate files, instead
+// Generate writes the report to Output. Generate writes the snapshot of the API definition to the
+// file SnapshotFile in OutDir instead if Snapshot is true.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	Base     *Snapshot             // The snapshot of the base design
+	Format   string                // Report format, "text", "json" or "markdown"
+	Snapshot bool                  // Whether to write the snapshot of API instead of comparing
+	OutDir   string                // Path to output directory of snapshot file
+	Output   io.Writer             // Writer the report is written to, stdout by default
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, base, format, ver string
+	var snapshot bool
+	set := flag.NewFlagSet("diff", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&base, "base", "", "")
+	set.StringVar(&format, "format", "text", "")
+	set.BoolVar(&snapshot, "snapshot", false, "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{
+		API:      design.Design,
+		Format:   format,
+		Snapshot: snapshot,
+		OutDir:   outDir,
+		Output:   os.Stdout,
+	}
+	if !snapshot {
+		if base == "" {
+			return nil, fmt.Errorf("missing base design, use --base to specify it")
+		}
+		if g.Base, err = LoadSnapshot(base); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.Generate()
+}
+
+// Generate compares the API definition with the base snapshot and writes the report to g.Output.
+// It returns dslengine.ErrSilentFailure if there are breaking changes so that the diff command exits
+// with a non-zero status without printing anything else. Generate writes the JSON representation
+// of the API snapshot to the file SnapshotFile in g.OutDir and returns its path instead if
+// g.Snapshot is true.
+func (g *Generator) Generate() ([]string, error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	head := NewSnapshot(g.API)
+	if g.Snapshot {
+		js, err := json.MarshalIndent(head, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(g.OutDir, 0755); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(g.OutDir, SnapshotFile)
+		if err := ioutil.WriteFile(path, append(js, '\n'), 0644); err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+
+	changes := Compare(g.Base, head)
+	var breaking int
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+
+	var lines []string
+	switch g.Format {
+	case "text", "":
+		for _, c := range changes {
+			lines = append(lines, c.String())
+		}
+	case "json":
+		if changes == nil {
+			changes = []*Change{}
+		}
+		report := struct {
+			Breaking bool      `json:"breaking"`
+			Changes  []*Change `json:"changes"`
+		}{breaking > 0, changes}
+		js, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		lines = strings.Split(string(js), "\n")
+	case "markdown":
+		lines = markdown(changes)
+	default:
+		return nil, fmt.Errorf("invalid format %#v, must be text, json or markdown", g.Format)
+	}
+
+	out := g.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(out, l); err != nil {
+			return nil, err
+		}
+	}
+
+	if breaking > 0 {
+		return nil, dslengine.ErrSilentFailure
+	}
+	return nil, nil
+}
+
+// LoadSnapshot reads the snapshot stored in the given JSON file. The file may also contain a
+// design document produced by "goagen design".
+func LoadSnapshot(path string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc gendesign.Document
+	if err := json.Unmarshal(b, &doc); err == nil && doc.FormatVersion != "" {
+		api, err := doc.APIDefinition()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load base design document %s: %s", path, err)
+		}
+		return NewSnapshot(api), nil
+	}
+	var s Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("failed to load base design snapshot %s: %s", path, err)
+	}
+	return &s, nil
+}
+
+// markdown renders the changes as a Markdown changelog.
+func markdown(changes []*Change) []string {
+	if len(changes) == 0 {
+		return []string{"No API changes."}
+	}
+	var breaking, others []string
+	for _, c := range changes {
+		line := fmt.Sprintf("- `%s`: %s", c.Path, c.Message)
+		if c.Breaking {
+			breaking = append(breaking, line)
+		} else {
+			others = append(others, line)
+		}
+	}
+	lines := []string{"# API changes"}
+	if len(breaking) > 0 {
+		lines = append(lines, "", "## Breaking changes", "")
+		lines = append(lines, breaking...)
+	}
+	if len(others) > 0 {
+		lines = append(lines, "", "## Non-breaking changes", "")
+		lines = append(lines, others...)
+	}
+	return lines
+}
@@ -0,0 +1,166 @@
+package gendiff_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_design"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var outDir, baseFile string
+	var args []string
+	var files, lines []string
+	var genErr error
+
+	BeforeEach(func() {
+		var err error
+		outDir, err = ioutil.TempDir("", "gendiff")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--version=" + version.String(), "--snapshot"}
+		snapshotOf(baseDesign)
+		snap, err := gendiff.Generate()
+		Ω(err).ShouldNot(HaveOccurred())
+		baseFile = filepath.Join(outDir, gendiff.SnapshotFile)
+		Ω(snap).Should(Equal([]string{baseFile}))
+		args = []string{"--design=foo", "--version=" + version.String(), "--base=" + baseFile}
+	})
+
+	JustBeforeEach(func() {
+		os.Args = append([]string{"goagen"}, args...)
+		stdout, err := ioutil.TempFile("", "gendiff")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.Remove(stdout.Name())
+		orig := os.Stdout
+		os.Stdout = stdout
+		files, genErr = gendiff.Generate()
+		os.Stdout = orig
+		stdout.Close()
+		out, err := ioutil.ReadFile(stdout.Name())
+		Ω(err).ShouldNot(HaveOccurred())
+		lines = nil
+		if len(out) > 0 {
+			lines = strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	It("loads the base snapshot", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		Ω(lines).Should(BeEmpty())
+	})
+
+	Context("with non-breaking changes", func() {
+		BeforeEach(func() {
+			snapshotOf(func() {
+				baseDesign()
+				Resource("account", nil)
+			})
+		})
+
+		It("reports the changes", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(lines).Should(Equal([]string{"non-breaking: resources/account: resource added"}))
+		})
+
+		Context("using the markdown format", func() {
+			BeforeEach(func() {
+				args = append(args, "--format=markdown")
+			})
+
+			It("produces a changelog", func() {
+				Ω(genErr).ShouldNot(HaveOccurred())
+				Ω(strings.Join(lines, "\n")).Should(Equal("# API changes\n\n## Non-breaking changes\n\n- `resources/account`: resource added"))
+			})
+		})
+	})
+
+	Context("with breaking changes", func() {
+		BeforeEach(func() {
+			snapshotOf(func() {
+				API("test", nil)
+			})
+		})
+
+		It("prints the report and fails silently", func() {
+			Ω(genErr).Should(Equal(dslengine.ErrSilentFailure))
+			Ω(files).Should(BeEmpty())
+			Ω(lines).Should(Equal([]string{
+				"breaking: resources/bottle: resource removed",
+				"breaking: media_types/Bottle: media type removed",
+			}))
+		})
+
+		Context("using the JSON format", func() {
+			BeforeEach(func() {
+				args = append(args, "--format=json")
+			})
+
+			It("prints the JSON report", func() {
+				Ω(genErr).Should(Equal(dslengine.ErrSilentFailure))
+				var report struct {
+					Breaking bool
+					Changes  []*gendiff.Change
+				}
+				Ω(json.Unmarshal([]byte(strings.Join(lines, "\n")), &report)).Should(Succeed())
+				Ω(report.Breaking).Should(BeTrue())
+				Ω(report.Changes).Should(HaveLen(2))
+			})
+		})
+	})
+
+	Context("with a design document as base", func() {
+		BeforeEach(func() {
+			js, err := json.Marshal(gendesign.Export(Design))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ioutil.WriteFile(baseFile, js, 0644)).Should(Succeed())
+			snapshotOf(func() {
+				baseDesign()
+				Resource("account", nil)
+			})
+		})
+
+		It("compares the designs", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(lines).Should(Equal([]string{"non-breaking: resources/account: resource added"}))
+		})
+
+		Context("declaring errors", func() {
+			BeforeEach(func() {
+				snapshotOf(func() {
+					API("test", func() {
+						Error("unavailable", 503)
+					})
+					Resource("bottle", func() {
+						Action("delete", func() {
+							Routing(DELETE("/bottles/:id"))
+							Response(NoContent)
+						})
+					})
+				})
+				js, err := json.Marshal(gendesign.Export(Design))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(ioutil.WriteFile(baseFile, js, 0644)).Should(Succeed())
+				snapshotOf(baseDesign)
+			})
+
+			It("reports removed errors", func() {
+				Ω(genErr).Should(Equal(dslengine.ErrSilentFailure))
+				Ω(lines).Should(ContainElement("breaking: resources/bottle/actions/delete/errors/unavailable: error removed"))
+			})
+		})
+	})
+})
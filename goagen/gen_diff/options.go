@@ -0,0 +1,45 @@
+package gendiff
+
+import (
+	"io"
+
+	"github.com/goadesign/goa/design"
+)
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//Base The snapshot of the base design
+func Base(base *Snapshot) Option {
+	return func(g *Generator) {
+		g.Base = base
+	}
+}
+
+//Format The report format, "text", "json" or "markdown"
+func Format(format string) Option {
+	return func(g *Generator) {
+		g.Format = format
+	}
+}
+
+//OutDir Path to output directory of snapshot file
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+//Output The writer the report is written to
+func Output(w io.Writer) Option {
+	return func(g *Generator) {
+		g.Output = w
+	}
+}
@@ -0,0 +1,438 @@
+package gendiff
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+const (
+	// RequestUsage indicates that a type is used to build requests.
+	RequestUsage Usage = 1 << iota
+	// ResponseUsage indicates that a type is used to build responses.
+	ResponseUsage
+)
+
+type (
+	// Snapshot is the structural summary of an API design compared by Compare. Snapshots only
+	// retain the properties of the design that affect clients and can be serialized to JSON so
+	// that designs can be compared without compiling both.
+	Snapshot struct {
+		// API is the name of the API.
+		API string `json:"api"`
+		// Resources indexes the resources by name.
+		Resources map[string]*Resource `json:"resources,omitempty"`
+		// Types indexes the user types by name.
+		Types map[string]*Type `json:"types,omitempty"`
+		// MediaTypes indexes the media types by type name.
+		MediaTypes map[string]*MediaType `json:"media_types,omitempty"`
+		// Callbacks indexes the API callbacks by name.
+		Callbacks map[string]*Callback `json:"callbacks,omitempty"`
+	}
+
+	// Resource is the snapshot of a resource.
+	Resource struct {
+		// Actions indexes the resource actions by name.
+		Actions map[string]*Action `json:"actions,omitempty"`
+	}
+
+	// Action is the snapshot of an action.
+	Action struct {
+		// Routes lists the action routes in the form "VERB /path".
+		Routes []string `json:"routes,omitempty"`
+		// Params describes the path and query string parameters.
+		Params *Attribute `json:"params,omitempty"`
+		// Headers describes the request headers.
+		Headers *Attribute `json:"headers,omitempty"`
+		// Cookies describes the request cookies including the cookies of the resource.
+		Cookies *Attribute `json:"cookies,omitempty"`
+		// Payload describes the request body if any.
+		Payload *Attribute `json:"payload,omitempty"`
+		// PayloadOptional is true if the request body may be omitted.
+		PayloadOptional bool `json:"payload_optional,omitempty"`
+		// Responses indexes the action responses by name.
+		Responses map[string]*Response `json:"responses,omitempty"`
+		// Errors indexes the errors the action may return by name, including the API errors.
+		Errors map[string]*Error `json:"errors,omitempty"`
+		// Consumes lists the mime types of the request bodies accepted by the action.
+		Consumes []string `json:"consumes,omitempty"`
+		// Produces lists the mime types of the response bodies generated by the action.
+		Produces []string `json:"produces,omitempty"`
+		// Idempotent is true if the action supports the Idempotency-Key request header.
+		Idempotent bool `json:"idempotent,omitempty"`
+		// ETag is true if the action responses carry an entity tag.
+		ETag bool `json:"etag,omitempty"`
+		// RateLimit is the rate limit that applies to the action if any.
+		RateLimit *RateLimit `json:"rate_limit,omitempty"`
+		// Cache is the cache policy of the action successful responses if any.
+		Cache *Cache `json:"cache,omitempty"`
+		// Callbacks indexes the action callbacks by name.
+		Callbacks map[string]*Callback `json:"callbacks,omitempty"`
+		// Security is the security requirement of the action if any.
+		Security *Security `json:"security,omitempty"`
+	}
+
+	// Security is the snapshot of a security requirement.
+	Security struct {
+		// Scheme is the name of the security scheme.
+		Scheme string `json:"scheme"`
+		// Scopes lists the scopes required by the action.
+		Scopes []string `json:"scopes,omitempty"`
+	}
+
+	// Error is the snapshot of an error.
+	Error struct {
+		// Status is the HTTP status code of the error responses.
+		Status int `json:"status"`
+		// MediaType is the identifier of the error response media type.
+		MediaType string `json:"media_type,omitempty"`
+	}
+
+	// Callback is the snapshot of a callback.
+	Callback struct {
+		// URL is the runtime expression that computes the subscriber URL.
+		URL string `json:"url,omitempty"`
+		// Payload describes the request body sent to subscribers if any.
+		Payload *Attribute `json:"payload,omitempty"`
+	}
+
+	// RateLimit is the snapshot of a rate limit.
+	RateLimit struct {
+		// Requests is the maximum number of requests allowed in Period.
+		Requests int `json:"requests"`
+		// Period is the duration of the rate limit window, e.g. "1m0s".
+		Period string `json:"period"`
+	}
+
+	// Cache is the snapshot of a cache policy.
+	Cache struct {
+		// MaxAge is the number of seconds the responses may be cached.
+		MaxAge uint `json:"max_age,omitempty"`
+		// Private is true if the responses may not be cached by shared caches.
+		Private bool `json:"private,omitempty"`
+		// NoStore is true if the responses may not be cached at all.
+		NoStore bool `json:"no_store,omitempty"`
+		// Vary lists the request headers that select the cached representation.
+		Vary []string `json:"vary,omitempty"`
+	}
+
+	// Response is the snapshot of an action response.
+	Response struct {
+		// Status is the response HTTP status code.
+		Status int `json:"status"`
+		// MediaType is the response media type identifier if any.
+		MediaType string `json:"media_type,omitempty"`
+		// Headers describes the response headers.
+		Headers *Attribute `json:"headers,omitempty"`
+	}
+
+	// Type is the snapshot of a user type.
+	Type struct {
+		*Attribute
+		// Usage indicates whether the type is used in requests and/or responses.
+		Usage Usage `json:"usage,omitempty"`
+	}
+
+	// MediaType is the snapshot of a media type.
+	MediaType struct {
+		*Type
+		// Identifier is the media type identifier.
+		Identifier string `json:"identifier"`
+		// Views lists the attributes rendered by each view indexed by view name.
+		Views map[string][]string `json:"views,omitempty"`
+	}
+
+	// Attribute is the snapshot of an attribute.
+	Attribute struct {
+		// Type is the name of the attribute data type kind, e.g. "string" or "object".
+		Type string `json:"type"`
+		// TypeName is the name of the user type or media type if the attribute uses one.
+		// The definition of the type is in the snapshot Types or MediaTypes field.
+		TypeName string `json:"type_name,omitempty"`
+		// Fields indexes the child attributes of object attributes by name.
+		Fields map[string]*Attribute `json:"fields,omitempty"`
+		// Required lists the names of the required fields.
+		Required []string `json:"required,omitempty"`
+		// Key is the key attribute of hashes.
+		Key *Attribute `json:"key,omitempty"`
+		// Elem is the element attribute of arrays and hashes.
+		Elem *Attribute `json:"elem,omitempty"`
+		// Enum lists the allowed values if any.
+		Enum []interface{} `json:"enum,omitempty"`
+		// Format is the format validation if any.
+		Format string `json:"format,omitempty"`
+		// Pattern is the pattern validation if any.
+		Pattern string `json:"pattern,omitempty"`
+		// Minimum is the minimum value validation if any.
+		Minimum *float64 `json:"minimum,omitempty"`
+		// Maximum is the maximum value validation if any.
+		Maximum *float64 `json:"maximum,omitempty"`
+		// ExclusiveMinimum is true if the value must be strictly greater than Minimum.
+		ExclusiveMinimum bool `json:"exclusive_minimum,omitempty"`
+		// ExclusiveMaximum is true if the value must be strictly less than Maximum.
+		ExclusiveMaximum bool `json:"exclusive_maximum,omitempty"`
+		// MultipleOf is the "multiple of" validation if any.
+		MultipleOf *float64 `json:"multiple_of,omitempty"`
+		// MinLength is the minimum length validation if any.
+		MinLength *int `json:"min_length,omitempty"`
+		// MaxLength is the maximum length validation if any.
+		MaxLength *int `json:"max_length,omitempty"`
+		// UniqueItems is true if the array elements must be unique.
+		UniqueItems bool `json:"unique_items,omitempty"`
+		// MinProperties is the minimum number of properties validation if any.
+		MinProperties *int `json:"min_properties,omitempty"`
+		// MaxProperties is the maximum number of properties validation if any.
+		MaxProperties *int `json:"max_properties,omitempty"`
+	}
+
+	// Usage is a bit field indicating how a type is used.
+	Usage int
+)
+
+// NewSnapshot builds the snapshot of the given API definition.
+func NewSnapshot(api *design.APIDefinition) *Snapshot {
+	s := &Snapshot{
+		API:        api.Name,
+		Resources:  make(map[string]*Resource),
+		Types:      make(map[string]*Type),
+		MediaTypes: make(map[string]*MediaType),
+	}
+	usages := make(map[string]Usage)
+	use := func(att *design.AttributeDefinition, u Usage) {
+		if att == nil || att.Type == nil {
+			return
+		}
+		att.Walk(func(a *design.AttributeDefinition) error {
+			switch actual := a.Type.(type) {
+			case *design.UserTypeDefinition:
+				usages[actual.TypeName] |= u
+			case *design.MediaTypeDefinition:
+				usages[actual.TypeName] |= u
+			case *design.Union:
+				for _, ut := range actual.Types {
+					usages[ut.TypeName] |= u
+				}
+			}
+			return nil
+		})
+	}
+
+	callbacks := func(cbs map[string]*design.CallbackDefinition) map[string]*Callback {
+		res := make(map[string]*Callback, len(cbs))
+		for n, cb := range cbs {
+			c := &Callback{URL: cb.URL}
+			if cb.Payload != nil {
+				c.Payload = newAttribute(cb.Payload.AttributeDefinition)
+				usages[cb.Payload.TypeName] |= ResponseUsage
+				use(cb.Payload.AttributeDefinition, ResponseUsage)
+			}
+			res[n] = c
+		}
+		return res
+	}
+	s.Callbacks = callbacks(api.Callbacks)
+
+	// The actions that do not restrict the mime types use the API mime types or the default
+	// encoders and decoders if the API does not define any.
+	apiConsumes, apiProduces := mimeTypes(api.Consumes), mimeTypes(api.Produces)
+	if len(apiConsumes) == 0 {
+		apiConsumes = mimeTypes(design.DefaultDecoders)
+	}
+	if len(apiProduces) == 0 {
+		apiProduces = mimeTypes(design.DefaultEncoders)
+	}
+
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		res := &Resource{Actions: make(map[string]*Action)}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			act := &Action{
+				Params:          newAttribute(a.AllParams()),
+				Headers:         newAttribute(a.Headers),
+				Cookies:         mergeAttributes(newAttribute(a.Cookies), newAttribute(r.Cookies)),
+				PayloadOptional: a.PayloadOptional,
+				Responses:       make(map[string]*Response),
+				Errors:          make(map[string]*Error),
+				Consumes:        a.EffectiveConsumes(),
+				Produces:        a.EffectiveProduces(),
+				Idempotent:      a.Idempotent,
+				ETag:            a.ETag,
+				Callbacks:       callbacks(a.Callbacks),
+			}
+			if act.Consumes == nil {
+				act.Consumes = append([]string{}, apiConsumes...)
+			}
+			if act.Produces == nil {
+				act.Produces = append([]string{}, apiProduces...)
+			}
+			sort.Strings(act.Consumes)
+			sort.Strings(act.Produces)
+			for _, errs := range []map[string]*design.ErrorDefinition{api.Errors, a.Errors} {
+				for n, e := range errs {
+					act.Errors[n] = &Error{Status: e.Status, MediaType: e.MediaType}
+				}
+			}
+			switch {
+			case a.RateLimit != nil:
+				act.RateLimit = newRateLimit(a.RateLimit)
+			case r.RateLimit != nil:
+				act.RateLimit = newRateLimit(r.RateLimit)
+			default:
+				act.RateLimit = newRateLimit(api.RateLimit)
+			}
+			if a.Security != nil && a.Security.Scheme != nil {
+				act.Security = &Security{Scheme: a.Security.Scheme.SchemeName}
+				if len(a.Security.Scopes) > 0 {
+					act.Security.Scopes = append([]string{}, a.Security.Scopes...)
+					sort.Strings(act.Security.Scopes)
+				}
+			}
+			if a.Cache != nil {
+				act.Cache = &Cache{MaxAge: a.Cache.MaxAge, Private: a.Cache.Private, NoStore: a.Cache.NoStore, Vary: a.Cache.Vary}
+			}
+			use(a.AllParams(), RequestUsage)
+			use(a.Headers, RequestUsage)
+			use(a.Cookies, RequestUsage)
+			use(r.Cookies, RequestUsage)
+			for _, r := range a.Routes {
+				act.Routes = append(act.Routes, r.Verb+" "+r.FullPath())
+			}
+			sort.Strings(act.Routes)
+			if a.Payload != nil {
+				act.Payload = newAttribute(a.Payload.AttributeDefinition)
+				usages[a.Payload.TypeName] |= RequestUsage
+				use(a.Payload.AttributeDefinition, RequestUsage)
+			}
+			for n, resp := range a.Responses {
+				act.Responses[n] = &Response{
+					Status:    resp.Status,
+					MediaType: resp.MediaType,
+					Headers:   newAttribute(resp.Headers),
+				}
+				if mt := api.MediaTypeWithIdentifier(resp.MediaType); mt != nil {
+					usages[mt.TypeName] |= ResponseUsage
+					use(mt.AttributeDefinition, ResponseUsage)
+					act.ETag = act.ETag || mt.ETag
+				}
+				if resp.Type != nil {
+					use(&design.AttributeDefinition{Type: resp.Type}, ResponseUsage)
+				}
+			}
+			res.Actions[a.Name] = act
+			return nil
+		})
+		s.Resources[r.Name] = res
+		return nil
+	})
+
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		s.Types[ut.TypeName] = &Type{Attribute: newAttribute(ut.AttributeDefinition), Usage: usages[ut.TypeName]}
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		m := &MediaType{
+			Type:       &Type{Attribute: newAttribute(mt.AttributeDefinition), Usage: usages[mt.TypeName]},
+			Identifier: mt.Identifier,
+			Views:      make(map[string][]string),
+		}
+		mt.IterateViews(func(v *design.ViewDefinition) error {
+			var names []string
+			for n := range v.Type.ToObject() {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			m.Views[v.Name] = names
+			return nil
+		})
+		s.MediaTypes[mt.TypeName] = m
+		return nil
+	})
+
+	return s
+}
+
+// newRateLimit builds the snapshot of the given rate limit, nil if r is nil.
+func newRateLimit(r *design.RateLimitDefinition) *RateLimit {
+	if r == nil {
+		return nil
+	}
+	return &RateLimit{Requests: r.Requests, Period: r.Period.String()}
+}
+
+// mimeTypes returns the mime types listed by the given encoding definitions.
+func mimeTypes(encs []*design.EncodingDefinition) []string {
+	var res []string
+	for _, enc := range encs {
+		res = append(res, enc.MIMETypes...)
+	}
+	return res
+}
+
+// newAttribute builds the snapshot of the given attribute. User types and media types are
+// referenced by name.
+func newAttribute(att *design.AttributeDefinition) *Attribute {
+	if att == nil || att.Type == nil {
+		return nil
+	}
+	a := &Attribute{Type: att.Type.Name()}
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		a.TypeName = actual.TypeName
+	case *design.MediaTypeDefinition:
+		a.TypeName = actual.TypeName
+	case design.Object:
+		a.Fields = make(map[string]*Attribute, len(actual))
+		for n, cat := range actual {
+			a.Fields[n] = newAttribute(cat)
+		}
+	case *design.Array:
+		a.Elem = newAttribute(actual.ElemType)
+	case *design.Hash:
+		a.Key = newAttribute(actual.KeyType)
+		a.Elem = newAttribute(actual.ElemType)
+	}
+	if v := att.Validation; v != nil {
+		if len(v.Required) > 0 {
+			a.Required = append([]string{}, v.Required...)
+			sort.Strings(a.Required)
+		}
+		a.Enum = v.Values
+		a.Format = v.Format
+		a.Pattern = v.Pattern
+		a.Minimum = v.Minimum
+		a.Maximum = v.Maximum
+		a.MinLength = v.MinLength
+		a.MaxLength = v.MaxLength
+		a.ExclusiveMinimum = v.ExclusiveMinimum
+		a.ExclusiveMaximum = v.ExclusiveMaximum
+		a.MultipleOf = v.MultipleOf
+		a.UniqueItems = v.UniqueItems
+		a.MinProperties = v.MinProperties
+		a.MaxProperties = v.MaxProperties
+	}
+	return a
+}
+
+// mergeAttributes adds the fields of parent that a does not define to a. a and parent are object
+// attribute snapshots, either may be nil.
+func mergeAttributes(a, parent *Attribute) *Attribute {
+	if a == nil {
+		return parent
+	}
+	if parent == nil {
+		return a
+	}
+	if a.Fields == nil {
+		a.Fields = make(map[string]*Attribute, len(parent.Fields))
+	}
+	for n, f := range parent.Fields {
+		if _, ok := a.Fields[n]; ok {
+			continue
+		}
+		a.Fields[n] = f
+		if contains(parent.Required, n) {
+			a.Required = append(a.Required, n)
+		}
+	}
+	sort.Strings(a.Required)
+	return a
+}
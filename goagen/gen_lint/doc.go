@@ -0,0 +1,22 @@
+/*
+Package genlint provides an opinionated design linter. The linter runs a set of rules against the
+API definition and reports issues such as missing descriptions, inconsistent naming, unused types,
+actions without error responses or unused security schemes.
+
+Each diagnostic is reported with the location of the offending definition in the design package.
+The default severity of a rule may be overridden with the --severity flag, for example:
+
+	goagen lint -d github.com/me/api/design --severity=description=off,unused-type=error
+
+Rules may also be disabled for a given definition and its children using the "lint:ignore"
+metadata. The metadata values list the names of the rules to disable, all rules are disabled if
+there is no value.
+
+The report is written to stdout. The --format=json flag produces a JSON report suitable for
+consumption by CI tools. The lint command exits with a non-zero status if any diagnostic has the
+error severity.
+
+Third-party generators invoked with "goagen gen" may register additional rules with RegisterRule
+before calling Generate.
+*/
+package genlint
@@ -0,0 +1,137 @@
+package genlint
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// NewGenerator returns an initialized instance of a design linter.
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{Format: "text", Severities: make(map[string]Severity), Output: os.Stdout}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design linter. It does not generate files, instead Generate writes the lint
+// report to Output.
+type Generator struct {
+	API        *design.APIDefinition // The API definition
+	Format     string                // Report format, "text" or "json"
+	Severities map[string]Severity   // Rule severity overrides indexed by rule name
+	Output     io.Writer             // Writer the report is written to, stdout by default
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var format, severities, ver string
+	set := flag.NewFlagSet("lint", flag.PanicOnError)
+	set.String("out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&format, "format", "text", "")
+	set.StringVar(&severities, "severity", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	sevs, err := ParseSeverities(severities)
+	if err != nil {
+		return nil, err
+	}
+	g := &Generator{API: design.Design, Format: format, Severities: sevs, Output: os.Stdout}
+
+	return g.Generate()
+}
+
+// Generate runs the linter and writes the report to g.Output. It returns
+// dslengine.ErrSilentFailure if any diagnostic has the error severity so that the lint command
+// exits with a non-zero status without printing anything else. Generate does not generate files.
+func (g *Generator) Generate() ([]string, error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	l := NewLinter()
+	for n, s := range g.Severities {
+		l.Severities[n] = s
+	}
+	diags := l.Lint(g.API)
+
+	var lines []string
+	switch g.Format {
+	case "text", "":
+		for _, d := range diags {
+			lines = append(lines, d.String())
+		}
+	case "json":
+		if diags == nil {
+			diags = []*Diagnostic{}
+		}
+		js, err := json.MarshalIndent(diags, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		lines = strings.Split(string(js), "\n")
+	default:
+		return nil, fmt.Errorf("invalid format %#v, must be text or json", g.Format)
+	}
+
+	out := g.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(out, l); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return nil, dslengine.ErrSilentFailure
+		}
+	}
+	return nil, nil
+}
+
+// ParseSeverities parses a comma separated list of rule severity overrides of the form
+// "rule=severity", e.g. "description=off,unused-type=error".
+func ParseSeverities(s string) (map[string]Severity, error) {
+	res := make(map[string]Severity)
+	if s == "" {
+		return res, nil
+	}
+	known := make(map[string]bool)
+	for _, r := range Rules() {
+		known[r.Name] = true
+	}
+	for _, elem := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(elem), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid severity override %#v, must be of the form rule=severity", elem)
+		}
+		if !known[parts[0]] {
+			return nil, fmt.Errorf("unknown lint rule %#v", parts[0])
+		}
+		sev, err := ParseSeverity(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		res[parts[0]] = sev
+	}
+	return res, nil
+}
@@ -0,0 +1,118 @@
+package genlint_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_lint"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var args []string
+	var files, lines []string
+	var genErr error
+
+	BeforeEach(func() {
+		args = []string{"--design=foo", "--version=" + version.String()}
+		dslengine.Reset()
+		API("test", func() {
+			Description("test API")
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Description("Show a bottle")
+				Routing(GET("/:id"))
+				Response(NoContent)
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		os.Args = append([]string{"goagen"}, args...)
+		stdout, err := ioutil.TempFile("", "genlint")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.Remove(stdout.Name())
+		orig := os.Stdout
+		os.Stdout = stdout
+		files, genErr = genlint.Generate()
+		os.Stdout = orig
+		stdout.Close()
+		out, err := ioutil.ReadFile(stdout.Name())
+		Ω(err).ShouldNot(HaveOccurred())
+		lines = nil
+		if len(out) > 0 {
+			lines = strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+		}
+	})
+
+	It("prints file:line diagnostics", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		Ω(files).Should(BeEmpty())
+		Ω(lines).Should(HaveLen(2))
+		Ω(lines[0]).Should(MatchRegexp(`^generator_test.go:\d+: warning: resource "bottle": missing description \(description\)$`))
+		Ω(lines[1]).Should(MatchRegexp(`^generator_test.go:\d+: warning: resource "bottle" action "show": action does not define any error response \(error-response\)$`))
+	})
+
+	Context("with the JSON format", func() {
+		BeforeEach(func() {
+			args = append(args, "--format=json")
+		})
+
+		It("prints a JSON report", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			var diags []*genlint.Diagnostic
+			Ω(json.Unmarshal([]byte(strings.Join(lines, "\n")), &diags)).Should(Succeed())
+			Ω(diags).Should(HaveLen(2))
+			Ω(diags[0].Rule).Should(Equal("description"))
+			Ω(diags[0].Severity).Should(Equal(genlint.SeverityWarning))
+			Ω(diags[0].File).Should(Equal("generator_test.go"))
+		})
+	})
+
+	Context("with a rule raised to error", func() {
+		BeforeEach(func() {
+			args = append(args, "--severity=description=error,error-response=off")
+		})
+
+		It("prints the report and fails silently", func() {
+			Ω(genErr).Should(Equal(dslengine.ErrSilentFailure))
+			Ω(files).Should(BeEmpty())
+			Ω(lines).Should(HaveLen(1))
+			Ω(lines[0]).Should(ContainSubstring(`error: resource "bottle": missing description (description)`))
+		})
+
+		Context("with the JSON format", func() {
+			BeforeEach(func() {
+				args = append(args, "--format=json")
+			})
+
+			It("prints the JSON report", func() {
+				Ω(genErr).Should(Equal(dslengine.ErrSilentFailure))
+				var diags []*genlint.Diagnostic
+				Ω(json.Unmarshal([]byte(strings.Join(lines, "\n")), &diags)).Should(Succeed())
+				Ω(diags).Should(HaveLen(1))
+				Ω(diags[0].Rule).Should(Equal("description"))
+				Ω(diags[0].Severity).Should(Equal(genlint.SeverityError))
+			})
+		})
+	})
+
+	Context("with an unknown rule", func() {
+		BeforeEach(func() {
+			args = append(args, "--severity=foo=off")
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(MatchError(`unknown lint rule "foo"`))
+		})
+	})
+})
@@ -0,0 +1,300 @@
+package genlint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// IgnoreMetadataKey is the metadata key used to disable rules on a definition and its children.
+// The metadata values list the names of the rules to disable, all rules are disabled if there is
+// no value:
+//
+//	Action("show", func() {
+//		Metadata("lint:ignore", "error-response")
+//		// ...
+//	})
+const IgnoreMetadataKey = "lint:ignore"
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+	// SeverityInfo is the severity of diagnostics that are purely informational.
+	SeverityInfo
+	// SeverityWarning is the severity of diagnostics that should be looked at.
+	SeverityWarning
+	// SeverityError is the severity of diagnostics that cause the lint run to fail.
+	SeverityError
+)
+
+type (
+	// Severity indicates how important a diagnostic is.
+	Severity int
+
+	// Rule is a single design check. Rules are run by a Linter against the API definition
+	// and report issues via the given Reporter.
+	Rule struct {
+		// Name identifies the rule in diagnostics, severity overrides and lint:ignore
+		// metadata.
+		Name string
+		// Description explains what the rule checks.
+		Description string
+		// Severity is the default severity of the rule diagnostics.
+		Severity Severity
+		// Check runs the rule.
+		Check func(api *design.APIDefinition, r *Reporter)
+	}
+
+	// Diagnostic describes an issue found by a rule.
+	Diagnostic struct {
+		// Rule is the name of the rule that reported the issue.
+		Rule string `json:"rule"`
+		// Severity is the diagnostic severity.
+		Severity Severity `json:"severity"`
+		// Definition describes the offending definition, e.g. `action "show" of resource "bottle"`.
+		Definition string `json:"definition"`
+		// Message describes the issue.
+		Message string `json:"message"`
+		// File is the path to the design file declaring the definition if known.
+		File string `json:"file,omitempty"`
+		// Line is the line in File.
+		Line int `json:"line,omitempty"`
+	}
+
+	// Reporter collects the diagnostics reported by a rule.
+	Reporter struct {
+		rule        *Rule
+		severity    Severity
+		diagnostics []*Diagnostic
+	}
+
+	// Linter runs a set of rules against an API definition.
+	Linter struct {
+		// Rules lists the rules run by the linter.
+		Rules []*Rule
+		// Severities overrides the default severity of rules indexed by rule name.
+		Severities map[string]Severity
+	}
+)
+
+// rules lists the registered rules.
+var rules []*Rule
+
+// RegisterRule adds a rule to the set of rules run by NewLinter linters. It panics if a rule with
+// the same name is already registered.
+func RegisterRule(r *Rule) {
+	for _, o := range rules {
+		if o.Name == r.Name {
+			panic(fmt.Sprintf("goagen lint: rule %#v registered twice", r.Name)) // bug
+		}
+	}
+	rules = append(rules, r)
+}
+
+// Rules returns the registered rules sorted by name.
+func Rules() []*Rule {
+	res := make([]*Rule, len(rules))
+	copy(res, rules)
+	sort.Sort(byName(res))
+	return res
+}
+
+// NewLinter returns a linter that runs all the registered rules.
+func NewLinter() *Linter {
+	return &Linter{Rules: Rules(), Severities: make(map[string]Severity)}
+}
+
+// Lint runs the rules against the given API definition and returns the resulting diagnostics
+// sorted by file and line.
+func (l *Linter) Lint(api *design.APIDefinition) []*Diagnostic {
+	var diags []*Diagnostic
+	for _, rule := range l.Rules {
+		sev := rule.Severity
+		if s, ok := l.Severities[rule.Name]; ok {
+			sev = s
+		}
+		if sev == SeverityOff {
+			continue
+		}
+		r := &Reporter{rule: rule, severity: sev}
+		rule.Check(api, r)
+		diags = append(diags, r.diagnostics...)
+	}
+	sort.Stable(byLocation(diags))
+	return diags
+}
+
+// Report records a diagnostic for the given definition unless the rule is disabled via lint:ignore
+// metadata on the definition or one of its parents.
+func (r *Reporter) Report(def dslengine.Definition, format string, args ...interface{}) {
+	r.report(def.Context(), append([]dslengine.Definition{def}, parents(def)...), format, args...)
+}
+
+// ReportAttribute records a diagnostic for the attribute with the given name defined in parent.
+// parent may be any definition, e.g. a type, an action or a response.
+func (r *Reporter) ReportAttribute(name string, att *design.AttributeDefinition, parent dslengine.Definition, format string, args ...interface{}) {
+	defs := append([]dslengine.Definition{att, parent}, parents(parent)...)
+	r.report(fmt.Sprintf("attribute %#v of %s", name, parent.Context()), defs, format, args...)
+}
+
+// report records a diagnostic using the first definition in defs with a known location and
+// taking into account the lint:ignore metadata of all the definitions.
+func (r *Reporter) report(context string, defs []dslengine.Definition, format string, args ...interface{}) {
+	d := &Diagnostic{
+		Rule:       r.rule.Name,
+		Severity:   r.severity,
+		Definition: context,
+		Message:    fmt.Sprintf(format, args...),
+	}
+	for _, def := range defs {
+		if ignores(metadata(def), r.rule.Name) {
+			return
+		}
+		if d.File == "" {
+			if loc := dslengine.LocationOf(def); loc != nil {
+				d.File = loc.File
+				d.Line = loc.Line
+			}
+		}
+	}
+	r.diagnostics = append(r.diagnostics, d)
+}
+
+// String returns the diagnostic in the "file:line: severity: definition: message (rule)" format.
+func (d *Diagnostic) String() string {
+	loc := "design"
+	if d.File != "" {
+		loc = fmt.Sprintf("%s:%d", d.File, d.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s (%s)", loc, d.Severity, d.Definition, d.Message, d.Rule)
+}
+
+// ParseSeverity returns the severity with the given name.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "off":
+		return SeverityOff, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityOff, fmt.Errorf("invalid severity %#v, must be one of off, info, warning or error", s)
+}
+
+// String returns the severity name.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "off"
+	}
+}
+
+// MarshalJSON encodes the severity using its name.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes the severity from its name.
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	v, err := ParseSeverity(strings.Trim(string(b), `"`))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// parents returns the parent definitions of def starting with the closest.
+func parents(def dslengine.Definition) []dslengine.Definition {
+	var res []dslengine.Definition
+	for {
+		var p dslengine.Definition
+		switch actual := def.(type) {
+		case *design.ActionDefinition:
+			if actual.Parent != nil {
+				p = actual.Parent
+			}
+		case *design.ResponseDefinition:
+			if actual.Parent != nil {
+				p = actual.Parent
+			}
+		case *design.ResourceDefinition, *design.UserTypeDefinition, *design.MediaTypeDefinition,
+			*design.SecuritySchemeDefinition:
+			p = design.Design
+		}
+		if p == nil {
+			return res
+		}
+		res = append(res, p)
+		def = p
+	}
+}
+
+// metadata returns the metadata of def if any.
+func metadata(def dslengine.Definition) dslengine.MetadataDefinition {
+	switch actual := def.(type) {
+	case *design.APIDefinition:
+		return actual.Metadata
+	case *design.ResourceDefinition:
+		return actual.Metadata
+	case *design.ActionDefinition:
+		return actual.Metadata
+	case *design.ResponseDefinition:
+		return actual.Metadata
+	case *design.MediaTypeDefinition:
+		return actual.Metadata
+	case *design.UserTypeDefinition:
+		return actual.Metadata
+	case *design.AttributeDefinition:
+		return actual.Metadata
+	case *design.SecuritySchemeDefinition:
+		return actual.Metadata
+	}
+	return nil
+}
+
+// ignores returns true if the metadata disables the rule with the given name.
+func ignores(md dslengine.MetadataDefinition, rule string) bool {
+	names, ok := md[IgnoreMetadataKey]
+	if !ok {
+		return false
+	}
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == rule {
+			return true
+		}
+	}
+	return false
+}
+
+type byName []*Rule
+
+func (b byName) Len() int           { return len(b) }
+func (b byName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byName) Less(i, j int) bool { return b[i].Name < b[j].Name }
+
+type byLocation []*Diagnostic
+
+func (b byLocation) Len() int      { return len(b) }
+func (b byLocation) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byLocation) Less(i, j int) bool {
+	if b[i].File != b[j].File {
+		return b[i].File < b[j].File
+	}
+	return b[i].Line < b[j].Line
+}
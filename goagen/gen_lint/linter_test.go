@@ -0,0 +1,249 @@
+package genlint_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_lint"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Linter", func() {
+	var linter *genlint.Linter
+	var diags []*genlint.Diagnostic
+
+	// find returns the diagnostics reported by the given rule.
+	find := func(rule string) []*genlint.Diagnostic {
+		var res []*genlint.Diagnostic
+		for _, d := range diags {
+			if d.Rule == rule {
+				res = append(res, d)
+			}
+		}
+		return res
+	}
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		linter = genlint.NewLinter()
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		diags = linter.Lint(Design)
+	})
+
+	Context("with a clean design", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Description("test API")
+			})
+			var Bottle = MediaType("application/vnd.bottle", func() {
+				Description("A bottle")
+				Attributes(func() {
+					Attribute("id", Integer)
+					Attribute("vintage_year", Integer)
+					Required("id")
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("vintage_year")
+				})
+			})
+			Resource("bottle", func() {
+				Description("Bottles")
+				Action("show", func() {
+					Description("Show a bottle")
+					Routing(GET("/:id"))
+					Response(OK, Bottle)
+					Response(NotFound)
+				})
+			})
+		})
+
+		It("does not report anything", func() {
+			Ω(diags).Should(BeEmpty())
+		})
+	})
+
+	Context("with errors and callbacks", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Description("test API")
+			})
+			var OutOfStock = MediaType("application/vnd.out-of-stock", func() {
+				Description("Out of stock error")
+				Attributes(func() {
+					Attribute("available", Integer)
+				})
+				View("default", func() {
+					Attribute("available")
+				})
+			})
+			var ShippedPayload = Type("ShippedPayload", func() {
+				Description("Order shipped notification")
+				Attribute("order_id", Integer)
+			})
+			Resource("order", func() {
+				Description("Orders")
+				Action("create", func() {
+					Description("Create an order")
+					Routing(POST(""))
+					Response(NoContent)
+					Error("out_of_stock", OutOfStock, 409)
+					Callback("order_shipped", func() {
+						Payload(ShippedPayload)
+					})
+				})
+			})
+		})
+
+		It("considers the errors as error responses", func() {
+			Ω(find("error-response")).Should(BeEmpty())
+		})
+
+		It("considers the error and callback types as used", func() {
+			Ω(find("unused-type")).Should(BeEmpty())
+		})
+	})
+
+	Context("with issues", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Description("test API")
+				BasicAuthSecurity("basic")
+			})
+			Type("Unused", func() {
+				Attribute("name")
+			})
+			var Bottle = MediaType("application/vnd.bottle", func() {
+				Description("A bottle")
+				Attributes(func() {
+					Attribute("id", Integer)
+					Attribute("vintage_year", Integer)
+					Attribute("origin_country")
+					Attribute("grapeVariety")
+					Required("id", "vintage_year")
+				})
+				View("default", func() {
+					Attribute("id")
+				})
+			})
+			Resource("Bottle", func() {
+				Description("Bottles")
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(OK, Bottle)
+				})
+			})
+		})
+
+		It("reports missing descriptions", func() {
+			res := find("description")
+			Ω(res).Should(HaveLen(2))
+			Ω(res[0].Definition).Should(Equal(`type "Unused"`))
+			Ω(res[1].Definition).Should(Equal(`resource "Bottle" action "show"`))
+			Ω(res[0].Severity).Should(Equal(genlint.SeverityWarning))
+		})
+
+		It("reports inconsistent naming", func() {
+			res := find("naming")
+			Ω(res).Should(HaveLen(2))
+			Ω(res[0].Message).Should(Equal("name is camelCase while other attributes use snake_case"))
+			Ω(res[0].Definition).Should(ContainSubstring(`attribute "grapeVariety"`))
+			Ω(res[1].Message).Should(Equal(`name "Bottle" is not snake_case`))
+		})
+
+		It("reports unused types", func() {
+			res := find("unused-type")
+			Ω(res).Should(HaveLen(1))
+			Ω(res[0].Definition).Should(Equal(`type "Unused"`))
+		})
+
+		It("reports actions without error responses", func() {
+			res := find("error-response")
+			Ω(res).Should(HaveLen(1))
+			Ω(res[0].Definition).Should(Equal(`resource "Bottle" action "show"`))
+		})
+
+		It("reports incomplete default views", func() {
+			res := find("default-view")
+			Ω(res).Should(HaveLen(1))
+			Ω(res[0].Severity).Should(Equal(genlint.SeverityError))
+			Ω(res[0].Message).Should(Equal(`default view does not render required attribute "vintage_year"`))
+		})
+
+		It("reports unused security schemes", func() {
+			res := find("unused-security-scheme")
+			Ω(res).Should(HaveLen(1))
+			Ω(res[0].Message).Should(Equal(`security scheme "basic" is not used`))
+		})
+
+		It("reports the design locations", func() {
+			for _, d := range diags {
+				Ω(d.File).Should(HaveSuffix("linter_test.go"))
+				Ω(d.Line).Should(BeNumerically(">", 0))
+			}
+		})
+
+		Context("with severity overrides", func() {
+			BeforeEach(func() {
+				linter.Severities["description"] = genlint.SeverityOff
+				linter.Severities["unused-type"] = genlint.SeverityError
+			})
+
+			It("uses the overridden severities", func() {
+				Ω(find("description")).Should(BeEmpty())
+				Ω(find("unused-type")[0].Severity).Should(Equal(genlint.SeverityError))
+			})
+		})
+
+		Context("with lint:ignore metadata", func() {
+			BeforeEach(func() {
+				Design.Resources["Bottle"].Metadata = dslengine.MetadataDefinition{
+					"lint:ignore": {"error-response", "naming"},
+				}
+				Design.Types["Unused"].Metadata = dslengine.MetadataDefinition{
+					"lint:ignore": {},
+				}
+			})
+
+			It("suppresses the ignored rules", func() {
+				Ω(find("error-response")).Should(BeEmpty())
+				Ω(find("naming")).Should(HaveLen(1))
+				Ω(find("unused-type")).Should(BeEmpty())
+				for _, d := range find("description") {
+					Ω(d.Definition).ShouldNot(Equal(`type "Unused"`))
+				}
+			})
+		})
+	})
+})
+
+var _ = Describe("RegisterRule", func() {
+	It("adds the rule to new linters", func() {
+		genlint.RegisterRule(&genlint.Rule{
+			Name:     "test-rule",
+			Severity: genlint.SeverityInfo,
+			Check: func(api *APIDefinition, r *genlint.Reporter) {
+				if api.Name == "custom" {
+					r.Report(api, "checked")
+				}
+			},
+		})
+		dslengine.Reset()
+		API("custom", nil)
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		diags := genlint.NewLinter().Lint(Design)
+		var found bool
+		for _, d := range diags {
+			if d.Rule == "test-rule" {
+				found = true
+				Ω(d.Severity).Should(Equal(genlint.SeverityInfo))
+				Ω(d.Message).Should(Equal("checked"))
+			}
+		}
+		Ω(found).Should(BeTrue())
+	})
+})
@@ -0,0 +1,38 @@
+package genlint
+
+import (
+	"io"
+
+	"github.com/goadesign/goa/design"
+)
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//Format The report format, "text" or "json"
+func Format(format string) Option {
+	return func(g *Generator) {
+		g.Format = format
+	}
+}
+
+//Severities Rule severity overrides indexed by rule name
+func Severities(sevs map[string]Severity) Option {
+	return func(g *Generator) {
+		g.Severities = sevs
+	}
+}
+
+//Output The writer the report is written to
+func Output(w io.Writer) Option {
+	return func(g *Generator) {
+		g.Output = w
+	}
+}
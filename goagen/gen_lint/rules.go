@@ -0,0 +1,322 @@
+package genlint
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+func init() {
+	RegisterRule(&Rule{
+		Name:        "description",
+		Description: "resources, actions, user types and media types should have a description",
+		Severity:    SeverityWarning,
+		Check:       checkDescriptions,
+	})
+	RegisterRule(&Rule{
+		Name:        "naming",
+		Description: "resource and action names should be snake_case and attribute names of a type should use the same case convention",
+		Severity:    SeverityWarning,
+		Check:       checkNaming,
+	})
+	RegisterRule(&Rule{
+		Name:        "unused-type",
+		Description: "user types and media types should be used by at least one action",
+		Severity:    SeverityWarning,
+		Check:       checkUnusedTypes,
+	})
+	RegisterRule(&Rule{
+		Name:        "error-response",
+		Description: "actions should define at least one error response",
+		Severity:    SeverityWarning,
+		Check:       checkErrorResponses,
+	})
+	RegisterRule(&Rule{
+		Name:        "default-view",
+		Description: "the default view of media types should render all the required attributes",
+		Severity:    SeverityError,
+		Check:       checkDefaultViews,
+	})
+	RegisterRule(&Rule{
+		Name:        "unused-security-scheme",
+		Description: "security schemes should be used by the API, a resource or an action",
+		Severity:    SeverityWarning,
+		Check:       checkUnusedSecuritySchemes,
+	})
+}
+
+// snakeCase matches snake_case identifiers.
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+func checkDescriptions(api *design.APIDefinition, r *Reporter) {
+	if api.Description == "" {
+		r.Report(api, "missing description")
+	}
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		if res.Description == "" {
+			r.Report(res, "missing description")
+		}
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Description == "" {
+				r.Report(a, "missing description")
+			}
+			return nil
+		})
+	})
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		if ut.Description == "" {
+			r.Report(ut, "missing description")
+		}
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.Description == "" && declared(mt) {
+			r.Report(mt, "missing description")
+		}
+		return nil
+	})
+}
+
+func checkNaming(api *design.APIDefinition, r *Reporter) {
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		if !snakeCase.MatchString(res.Name) {
+			r.Report(res, "name %#v is not snake_case", res.Name)
+		}
+		checkAttributeNames(res.Params, res, r)
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if !snakeCase.MatchString(a.Name) {
+				r.Report(a, "name %#v is not snake_case", a.Name)
+			}
+			checkAttributeNames(a.Params, a, r)
+			if a.Payload != nil {
+				checkAttributeNames(a.Payload.AttributeDefinition, a, r)
+			}
+			return nil
+		})
+	})
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		checkAttributeNames(ut.AttributeDefinition, ut, r)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if declared(mt) {
+			checkAttributeNames(mt.AttributeDefinition, mt, r)
+		}
+		return nil
+	})
+}
+
+// checkAttributeNames reports the attributes of att whose names do not follow the case convention
+// used by the majority of the attributes.
+func checkAttributeNames(att *design.AttributeDefinition, parent dslengine.Definition, r *Reporter) {
+	if att == nil {
+		return
+	}
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return
+	}
+	counts := make(map[string]int)
+	for n := range obj {
+		if c := caseOf(n); c != "" {
+			counts[c]++
+		}
+	}
+	if len(counts) < 2 {
+		return
+	}
+	var best string
+	for c, n := range counts {
+		if n > counts[best] || n == counts[best] && c < best {
+			best = c
+		}
+	}
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if c := caseOf(n); c != "" && c != best {
+			r.ReportAttribute(n, obj[n], parent, "name is %s while other attributes use %s", c, best)
+		}
+	}
+}
+
+// caseOf returns the case convention used by name: "snake_case", "camelCase", "PascalCase" or
+// "kebab-case". caseOf returns the empty string for names that are compatible with any
+// convention, e.g. "id".
+func caseOf(name string) string {
+	switch {
+	case strings.Contains(name, "_"):
+		return "snake_case"
+	case strings.Contains(name, "-"):
+		return "kebab-case"
+	case strings.ToLower(name) == name:
+		return ""
+	case strings.ToUpper(name[:1]) == name[:1]:
+		return "PascalCase"
+	default:
+		return "camelCase"
+	}
+}
+
+func checkUnusedTypes(api *design.APIDefinition, r *Reporter) {
+	used := make(map[string]bool)
+	mark := func(att *design.AttributeDefinition) {
+		if att == nil || att.Type == nil {
+			return
+		}
+		att.Walk(func(a *design.AttributeDefinition) error {
+			switch actual := a.Type.(type) {
+			case *design.UserTypeDefinition:
+				used[actual.TypeName] = true
+			case *design.MediaTypeDefinition:
+				used[actual.TypeName] = true
+			case *design.Union:
+				for _, ut := range actual.Types {
+					used[ut.TypeName] = true
+				}
+			}
+			return nil
+		})
+	}
+	markMediaType := func(id string) {
+		if mt := api.MediaTypeWithIdentifier(id); mt != nil {
+			used[mt.TypeName] = true
+			mark(mt.AttributeDefinition)
+		}
+	}
+	markResponse := func(resp *design.ResponseDefinition) {
+		if resp.Type != nil {
+			mark(&design.AttributeDefinition{Type: resp.Type})
+		}
+		if resp.MediaType != "" {
+			markMediaType(resp.MediaType)
+		}
+	}
+	markErrors := func(errs map[string]*design.ErrorDefinition) {
+		for _, e := range errs {
+			if e.MediaType != "" {
+				markMediaType(e.MediaType)
+			}
+		}
+	}
+	markCallbacks := func(cbs map[string]*design.CallbackDefinition) {
+		for _, cb := range cbs {
+			if cb.Payload != nil {
+				used[cb.Payload.TypeName] = true
+				mark(cb.Payload.AttributeDefinition)
+			}
+		}
+	}
+	mark(api.Params)
+	for _, resp := range api.Responses {
+		markResponse(resp)
+	}
+	markErrors(api.Errors)
+	markCallbacks(api.Callbacks)
+	for _, res := range api.Resources {
+		if res.MediaType != "" {
+			markMediaType(res.MediaType)
+		}
+		mark(res.Params)
+		mark(res.Headers)
+		mark(res.Cookies)
+		for _, resp := range res.Responses {
+			markResponse(resp)
+		}
+		for _, a := range res.Actions {
+			mark(a.Params)
+			mark(a.Headers)
+			mark(a.Cookies)
+			if a.Payload != nil {
+				used[a.Payload.TypeName] = true
+				mark(a.Payload.AttributeDefinition)
+			}
+			for _, resp := range a.Responses {
+				markResponse(resp)
+			}
+			markErrors(a.Errors)
+			markCallbacks(a.Callbacks)
+		}
+	}
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		if !used[ut.TypeName] {
+			r.Report(ut, "type is not used by any action")
+		}
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if !used[mt.TypeName] && declared(mt) {
+			r.Report(mt, "media type is not used by any action")
+		}
+		return nil
+	})
+}
+
+func checkErrorResponses(api *design.APIDefinition, r *Reporter) {
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Errors) > 0 || len(api.Errors) > 0 {
+				return nil
+			}
+			for _, resp := range a.Responses {
+				if resp.Status >= 400 {
+					return nil
+				}
+			}
+			r.Report(a, "action does not define any error response")
+			return nil
+		})
+	})
+}
+
+func checkDefaultViews(api *design.APIDefinition, r *Reporter) {
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		view, ok := mt.Views["default"]
+		if !ok || !declared(mt) || mt.Validation == nil {
+			return nil
+		}
+		rendered := view.Type.ToObject()
+		for _, req := range mt.Validation.Required {
+			if _, ok := rendered[req]; !ok {
+				r.Report(mt, "default view does not render required attribute %#v", req)
+			}
+		}
+		return nil
+	})
+}
+
+func checkUnusedSecuritySchemes(api *design.APIDefinition, r *Reporter) {
+	used := make(map[string]bool)
+	mark := func(sec *design.SecurityDefinition) {
+		if sec != nil && sec.Scheme != nil {
+			used[sec.Scheme.SchemeName] = true
+		}
+	}
+	mark(api.Security)
+	for _, res := range api.Resources {
+		mark(res.Security)
+		for _, a := range res.Actions {
+			mark(a.Security)
+		}
+		for _, fs := range res.FileServers {
+			mark(fs.Security)
+		}
+	}
+	for _, s := range api.SecuritySchemes {
+		if !used[s.SchemeName] {
+			r.Report(s, "security scheme %#v is not used", s.SchemeName)
+		}
+	}
+}
+
+// declared returns true if the media type was declared in the design as opposed to built-in (e.g.
+// the error media type) or generated (e.g. collections).
+func declared(mt *design.MediaTypeDefinition) bool {
+	return dslengine.LocationOf(mt) != nil
+}
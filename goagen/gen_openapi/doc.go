@@ -0,0 +1,7 @@
+/*
+Package genopenapi provides a generator for the OpenAPI 3 specification of the API.
+The specification is written both in JSON and YAML to the "openapi" directory. The JSON schemas
+of the types and media types are produced by the genschema package and listed in the
+"components/schemas" section of the specification.
+*/
+package genopenapi
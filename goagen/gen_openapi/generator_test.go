@@ -0,0 +1,83 @@
+package genopenapi_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genopenapi.Generator
+
+	var args = struct {
+		api    *design.APIDefinition
+		outDir string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir: "out_dir",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+
+			generator = genopenapi.NewGenerator(
+				genopenapi.API(args.api),
+				genopenapi.OutDir(args.outDir),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+		})
+	})
+})
+
+var _ = Describe("Generate", func() {
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		var err error
+		outDir, err = ioutil.TempDir("", "genopenapi")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		api := &design.APIDefinition{Name: "test api", Title: "Test API"}
+		files, genErr = genopenapi.NewGenerator(
+			genopenapi.API(api),
+			genopenapi.OutDir(outDir),
+		).Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	It("writes the JSON and YAML specifications", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		jsonFile := filepath.Join(outDir, "openapi", "openapi.json")
+		yamlFile := filepath.Join(outDir, "openapi", "openapi.yaml")
+		Ω(files).Should(ContainElement(jsonFile))
+		Ω(files).Should(ContainElement(yamlFile))
+		b, err := ioutil.ReadFile(jsonFile)
+		Ω(err).ShouldNot(HaveOccurred())
+		var doc map[string]interface{}
+		Ω(json.Unmarshal(b, &doc)).Should(Succeed())
+		Ω(doc["openapi"]).Should(Equal("3.0.3"))
+		b, err = ioutil.ReadFile(yamlFile)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(ContainSubstring("openapi: 3.0.3"))
+	})
+})
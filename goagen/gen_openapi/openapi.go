@@ -0,0 +1,952 @@
+package genopenapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+type (
+	// OpenAPI represents an instance of an OpenAPI 3 document.
+	// See https://spec.openapis.org/oas/v3.0.3
+	OpenAPI struct {
+		OpenAPI      string               `json:"openapi"`
+		Info         *Info                `json:"info"`
+		Servers      []*Server            `json:"servers,omitempty"`
+		Paths        map[string]*PathItem `json:"paths"`
+		Components   *Components          `json:"components,omitempty"`
+		Tags         []*Tag               `json:"tags,omitempty"`
+		ExternalDocs *ExternalDocs        `json:"externalDocs,omitempty"`
+		// Webhooks describes the callbacks that are not tied to an operation indexed by name.
+		// OpenAPI 3.0 has no support for such callbacks so they are described using an
+		// extension that follows the OpenAPI 3.1 "webhooks" field format.
+		Webhooks map[string]*PathItem `json:"x-webhooks,omitempty"`
+	}
+
+	// Info provides metadata about the API.
+	Info struct {
+		Title          string                    `json:"title"`
+		Description    string                    `json:"description,omitempty"`
+		TermsOfService string                    `json:"termsOfService,omitempty"`
+		Contact        *design.ContactDefinition `json:"contact,omitempty"`
+		License        *design.LicenseDefinition `json:"license,omitempty"`
+		Version        string                    `json:"version"`
+	}
+
+	// Server represents a server hosting the API.
+	Server struct {
+		// URL of the target host, the paths are relative to this URL.
+		URL string `json:"url"`
+		// Description of the host.
+		Description string `json:"description,omitempty"`
+	}
+
+	// PathItem describes the operations available on a single path.
+	PathItem struct {
+		// Get defines a GET operation on this path.
+		Get *Operation `json:"get,omitempty"`
+		// Put defines a PUT operation on this path.
+		Put *Operation `json:"put,omitempty"`
+		// Post defines a POST operation on this path.
+		Post *Operation `json:"post,omitempty"`
+		// Delete defines a DELETE operation on this path.
+		Delete *Operation `json:"delete,omitempty"`
+		// Options defines a OPTIONS operation on this path.
+		Options *Operation `json:"options,omitempty"`
+		// Head defines a HEAD operation on this path.
+		Head *Operation `json:"head,omitempty"`
+		// Patch defines a PATCH operation on this path.
+		Patch *Operation `json:"patch,omitempty"`
+		// Trace defines a TRACE operation on this path.
+		Trace *Operation `json:"trace,omitempty"`
+	}
+
+	// Operation describes a single API operation on a path.
+	Operation struct {
+		// Tags is a list of tags for API documentation control.
+		Tags []string `json:"tags,omitempty"`
+		// Summary is a short summary of what the operation does.
+		Summary string `json:"summary,omitempty"`
+		// Description is a verbose explanation of the operation behavior.
+		Description string `json:"description,omitempty"`
+		// ExternalDocs points to additional external documentation for this operation.
+		ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+		// OperationID is a unique string used to identify the operation.
+		OperationID string `json:"operationId,omitempty"`
+		// Parameters is the list of parameters that are applicable for this operation.
+		Parameters []*Parameter `json:"parameters,omitempty"`
+		// RequestBody describes the request body if any.
+		RequestBody *RequestBody `json:"requestBody,omitempty"`
+		// Responses lists the possible responses indexed by HTTP status code.
+		Responses map[string]*Response `json:"responses"`
+		// Deprecated declares this operation to be deprecated.
+		Deprecated bool `json:"deprecated,omitempty"`
+		// Security lists the security requirements of the operation.
+		Security []map[string][]string `json:"security,omitempty"`
+		// Callbacks lists the requests sent as a result of calling the operation indexed
+		// by name.
+		Callbacks map[string]Callback `json:"callbacks,omitempty"`
+	}
+
+	// Callback maps runtime expressions computing the subscriber URLs to the description of
+	// the requests sent to these URLs.
+	Callback map[string]*PathItem
+
+	// Parameter describes a single operation parameter.
+	Parameter struct {
+		// Name of the parameter. Parameter names are case sensitive.
+		Name string `json:"name"`
+		// In is the location of the parameter.
+		// Possible values are "query", "header", "path" or "cookie".
+		In string `json:"in"`
+		// Description is a brief description of the parameter.
+		Description string `json:"description,omitempty"`
+		// Required determines whether this parameter is mandatory.
+		Required bool `json:"required,omitempty"`
+		// Schema defines the type used for the parameter.
+		Schema *genschema.JSONSchema `json:"schema,omitempty"`
+	}
+
+	// RequestBody describes a request body.
+	RequestBody struct {
+		// Description is a brief description of the request body.
+		Description string `json:"description,omitempty"`
+		// Content describes the request body for each supported content type.
+		Content map[string]*MediaType `json:"content"`
+		// Required determines whether the request body is mandatory.
+		Required bool `json:"required,omitempty"`
+	}
+
+	// MediaType describes the body of requests or responses for a given content type.
+	MediaType struct {
+		// Schema defines the type of the body.
+		Schema *genschema.JSONSchema `json:"schema,omitempty"`
+		// Examples lists named examples of the body.
+		Examples map[string]*Example `json:"examples,omitempty"`
+	}
+
+	// Example describes an example value.
+	Example struct {
+		// Value is the example value.
+		Value interface{} `json:"value,omitempty"`
+	}
+
+	// Response describes an operation response.
+	Response struct {
+		// Description of the response.
+		Description string `json:"description"`
+		// Headers lists the headers sent with the response.
+		Headers map[string]*Header `json:"headers,omitempty"`
+		// Content describes the response body for each content type.
+		Content map[string]*MediaType `json:"content,omitempty"`
+	}
+
+	// Header describes a response header.
+	Header struct {
+		// Description is a brief description of the header.
+		Description string `json:"description,omitempty"`
+		// Required determines whether the header is always sent.
+		Required bool `json:"required,omitempty"`
+		// Schema defines the type used for the header.
+		Schema *genschema.JSONSchema `json:"schema,omitempty"`
+	}
+
+	// Components holds the reusable objects referenced by the rest of the document.
+	Components struct {
+		// Schemas lists the type and media type schemas indexed by name.
+		Schemas map[string]*Schema `json:"schemas,omitempty"`
+		// Responses lists the API responses indexed by name.
+		Responses map[string]*Response `json:"responses,omitempty"`
+		// SecuritySchemes lists the security schemes indexed by name.
+		SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	// Schema is a JSON schema extended with the OpenAPI discriminator object used by the
+	// schemas of union types.
+	Schema struct {
+		*genschema.JSONSchema
+		// Discriminator identifies the property used to select the union type.
+		Discriminator *Discriminator `json:"discriminator,omitempty"`
+	}
+
+	// Discriminator identifies the property used for polymorphism.
+	Discriminator struct {
+		// PropertyName is the name of the discriminator property.
+		PropertyName string `json:"propertyName"`
+		// Mapping maps the discriminator values to schema references.
+		Mapping map[string]string `json:"mapping,omitempty"`
+	}
+
+	// SecurityScheme defines a security scheme that can be used by the operations.
+	SecurityScheme struct {
+		// Type of the security scheme. Valid values are "apiKey", "http" or "oauth2".
+		Type string `json:"type"`
+		// Description of the security scheme.
+		Description string `json:"description,omitempty"`
+		// Name of the header, query parameter or cookie when type is "apiKey".
+		Name string `json:"name,omitempty"`
+		// In is the location of the API key when type is "apiKey".
+		// Valid values are "query", "header" or "cookie".
+		In string `json:"in,omitempty"`
+		// Scheme is the HTTP authorization scheme when type is "http", e.g. "basic" or
+		// "bearer".
+		Scheme string `json:"scheme,omitempty"`
+		// BearerFormat is a hint describing the format of bearer tokens.
+		BearerFormat string `json:"bearerFormat,omitempty"`
+		// Flows describes the OAuth2 flows when type is "oauth2".
+		Flows *OAuthFlows `json:"flows,omitempty"`
+	}
+
+	// OAuthFlows lists the supported OAuth2 flows.
+	OAuthFlows struct {
+		Implicit          *OAuthFlow `json:"implicit,omitempty"`
+		Password          *OAuthFlow `json:"password,omitempty"`
+		ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+		AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	}
+
+	// OAuthFlow describes the configuration of an OAuth2 flow.
+	OAuthFlow struct {
+		// AuthorizationURL is the authorization URL used by the implicit and authorization
+		// code flows.
+		AuthorizationURL string `json:"authorizationUrl,omitempty"`
+		// TokenURL is the token URL used by the password, client credentials and
+		// authorization code flows.
+		TokenURL string `json:"tokenUrl,omitempty"`
+		// RefreshURL is the URL used to obtain refresh tokens.
+		RefreshURL string `json:"refreshUrl,omitempty"`
+		// Scopes lists the available scopes.
+		Scopes map[string]string `json:"scopes"`
+	}
+
+	// Tag adds metadata to a tag used by the operations.
+	Tag struct {
+		// Name of the tag.
+		Name string `json:"name"`
+		// Description is a short description of the tag.
+		Description string `json:"description,omitempty"`
+		// ExternalDocs is additional external documentation for this tag.
+		ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+	}
+
+	// ExternalDocs allows referencing an external resource for extended documentation.
+	ExternalDocs struct {
+		// Description is a short description of the target documentation.
+		Description string `json:"description,omitempty"`
+		// URL for the target documentation.
+		URL string `json:"url"`
+	}
+)
+
+const (
+	// Version is the version of the OpenAPI specification implemented by the generated
+	// documents.
+	Version = "3.0.3"
+
+	// DefaultCallbackURL is the runtime expression used to compute the subscriber URL of
+	// callbacks that do not define one.
+	DefaultCallbackURL = "{$request.body#/callback_url}"
+
+	// definitionsRef is the prefix of the references produced by genschema.
+	definitionsRef = "#/definitions/"
+
+	// schemasRef is the prefix of the references to the component schemas.
+	schemasRef = "#/components/schemas/"
+)
+
+// New creates an OpenAPI document from an API definition.
+func New(api *design.APIDefinition) (*OpenAPI, error) {
+	if api == nil {
+		return nil, nil
+	}
+	basePath := api.BasePath
+	if hasAbsoluteRoutes(api) || len(design.ExtractWildcards(basePath)) > 0 {
+		basePath = ""
+	}
+	o := &OpenAPI{
+		OpenAPI: Version,
+		Info: &Info{
+			Title:          api.Title,
+			Description:    api.Description,
+			TermsOfService: api.TermsOfService,
+			Contact:        api.Contact,
+			License:        api.License,
+			Version:        api.Version,
+		},
+		Servers:      serversFromDefinition(api, basePath),
+		Paths:        make(map[string]*PathItem),
+		Components:   &Components{SecuritySchemes: securitySchemesFromDefinition(api.SecuritySchemes)},
+		ExternalDocs: docsFromDefinition(api.Docs),
+	}
+	if o.Info.Title == "" {
+		o.Info.Title = api.Name
+	}
+
+	err := api.IterateResponses(func(r *design.ResponseDefinition) error {
+		if o.Components.Responses == nil {
+			o.Components.Responses = make(map[string]*Response)
+		}
+		o.Components.Responses[r.Name] = responseFromDefinition(api, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for n, cb := range api.Callbacks {
+		if o.Webhooks == nil {
+			o.Webhooks = make(map[string]*PathItem)
+		}
+		o.Webhooks[n] = &PathItem{Post: callbackOperation(api, cb)}
+	}
+	err = api.IterateResources(func(res *design.ResourceDefinition) error {
+		if res.Description != "" {
+			o.Tags = append(o.Tags, &Tag{Name: res.Name, Description: res.Description})
+		}
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if !mustGenerate(a.Metadata) {
+				return nil
+			}
+			for _, route := range a.Routes {
+				if err := buildPathFromDefinition(o, api, route, basePath); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(genschema.Definitions) > 0 {
+		o.Components.Schemas = make(map[string]*Schema, len(genschema.Definitions))
+		for n, d := range genschema.Definitions {
+			s := &Schema{JSONSchema: toOpenAPISchema(d)}
+			if ut, ok := api.Types[n]; ok && ut.IsUnion() {
+				s.Discriminator = discriminatorFromDefinition(ut.ToUnion())
+			}
+			o.Components.Schemas[n] = s
+		}
+	}
+	if o.Components.Schemas == nil && o.Components.Responses == nil && o.Components.SecuritySchemes == nil {
+		o.Components = nil
+	}
+	return o, nil
+}
+
+// serversFromDefinition returns the servers hosting the API, one per scheme.
+func serversFromDefinition(api *design.APIDefinition, basePath string) []*Server {
+	if api.Host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []*Server{{URL: basePath}}
+	}
+	schemes := api.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	servers := make([]*Server, len(schemes))
+	for i, s := range schemes {
+		u := url.URL{Scheme: s, Host: api.Host, Path: basePath}
+		servers[i] = &Server{URL: u.String()}
+	}
+	return servers
+}
+
+// mustGenerate returns true if the metadata indicates that the action should be documented, false
+// otherwise. Actions excluded from the Swagger specification are excluded from the OpenAPI
+// document as well.
+func mustGenerate(meta dslengine.MetadataDefinition) bool {
+	if m, ok := meta["swagger:generate"]; ok {
+		if len(m) > 0 && m[0] == "false" {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAbsoluteRoutes returns true if any action exposed by the API uses an absolute route. The
+// paths are then relative to the API host and the server URLs do not include the base path.
+func hasAbsoluteRoutes(api *design.APIDefinition) bool {
+	for _, res := range api.Resources {
+		for _, a := range res.Actions {
+			if !mustGenerate(a.Metadata) {
+				continue
+			}
+			for _, ro := range a.Routes {
+				if ro.IsAbsolute() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func securitySchemesFromDefinition(schemes []*design.SecuritySchemeDefinition) map[string]*SecurityScheme {
+	if len(schemes) == 0 {
+		return nil
+	}
+	res := make(map[string]*SecurityScheme, len(schemes))
+	for _, scheme := range schemes {
+		s := &SecurityScheme{Description: scheme.Description}
+		switch scheme.Kind {
+		case design.BasicAuthSecurityKind:
+			s.Type = "http"
+			s.Scheme = "basic"
+		case design.APIKeySecurityKind:
+			s.Type = "apiKey"
+			s.Name = scheme.Name
+			s.In = scheme.In
+		case design.JWTSecurityKind:
+			s.Type = "http"
+			s.Scheme = "bearer"
+			s.BearerFormat = "JWT"
+			if scheme.TokenURL != "" {
+				s.Description += fmt.Sprintf("\n\n**Token URL**: %s", scheme.TokenURL)
+			}
+			if len(scheme.Scopes) != 0 {
+				s.Description += fmt.Sprintf("\n\n**Security Scopes**:\n%s", scopesMapList(scheme.Scopes))
+			}
+			s.Description = strings.TrimPrefix(s.Description, "\n\n")
+		case design.OAuth2SecurityKind:
+			s.Type = "oauth2"
+			scopes := scheme.Scopes
+			if scopes == nil {
+				scopes = make(map[string]string)
+			}
+			flow := &OAuthFlow{
+				AuthorizationURL: scheme.AuthorizationURL,
+				TokenURL:         scheme.TokenURL,
+				Scopes:           scopes,
+			}
+			switch scheme.Flow {
+			case "implicit":
+				s.Flows = &OAuthFlows{Implicit: flow}
+			case "password":
+				s.Flows = &OAuthFlows{Password: flow}
+			case "application":
+				s.Flows = &OAuthFlows{ClientCredentials: flow}
+			default:
+				s.Flows = &OAuthFlows{AuthorizationCode: flow}
+			}
+		default:
+			continue
+		}
+		res[scheme.SchemeName] = s
+	}
+	return res
+}
+
+func scopesMapList(scopes map[string]string) string {
+	names := make([]string, 0, len(scopes))
+	for name := range scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf("  * `%s`: %s", name, scopes[name])
+	}
+	return strings.Join(lines, "\n")
+}
+
+func docsFromDefinition(docs *design.DocsDefinition) *ExternalDocs {
+	if docs == nil {
+		return nil
+	}
+	return &ExternalDocs{
+		Description: docs.Description,
+		URL:         docs.URL,
+	}
+}
+
+// discriminatorFromDefinition returns the discriminator object of a union type, nil if the union
+// does not define a discriminator.
+func discriminatorFromDefinition(u *design.Union) *Discriminator {
+	if u.Discriminator == "" {
+		return nil
+	}
+	d := &Discriminator{PropertyName: u.Discriminator, Mapping: make(map[string]string, len(u.Types))}
+	for _, ut := range u.Types {
+		d.Mapping[ut.TypeName] = schemasRef + ut.TypeName
+	}
+	return d
+}
+
+// toOpenAPISchema returns a copy of the given JSON schema where the references point to the
+// component schemas and the JSON hyper schema and draft 7 properties not supported by OpenAPI 3.0
+// are removed.
+func toOpenAPISchema(s *genschema.JSONSchema) *genschema.JSONSchema {
+	if s == nil {
+		return nil
+	}
+	res := *s
+	res.Schema = ""
+	res.Media = nil
+	res.Links = nil
+	res.PathStart = ""
+	res.Definitions = nil
+	res.DependentRequired = nil
+	res.Discriminator = ""
+	res.If = nil
+	res.Then = nil
+	if strings.HasPrefix(res.Ref, definitionsRef) {
+		res.Ref = schemasRef + strings.TrimPrefix(res.Ref, definitionsRef)
+	}
+	if res.Type == genschema.JSONFile {
+		res.Type = genschema.JSONString
+		res.Format = "binary"
+	}
+	res.Items = toOpenAPISchema(s.Items)
+	if len(s.Properties) > 0 {
+		res.Properties = make(map[string]*genschema.JSONSchema, len(s.Properties))
+		for n, p := range s.Properties {
+			res.Properties[n] = toOpenAPISchema(p)
+		}
+	}
+	res.AllOf = nil
+	for _, c := range s.AllOf {
+		// conditional requirements are not supported by OpenAPI 3.0
+		if c.If == nil {
+			res.AllOf = append(res.AllOf, toOpenAPISchema(c))
+		}
+	}
+	res.AnyOf = toOpenAPISchemas(s.AnyOf)
+	res.OneOf = toOpenAPISchemas(s.OneOf)
+	return &res
+}
+
+func toOpenAPISchemas(schemas []*genschema.JSONSchema) []*genschema.JSONSchema {
+	if schemas == nil {
+		return nil
+	}
+	res := make([]*genschema.JSONSchema, len(schemas))
+	for i, s := range schemas {
+		res[i] = toOpenAPISchema(s)
+	}
+	return res
+}
+
+// attributeSchema returns the OpenAPI schema of the given attribute. The description is returned
+// separately as parameters and headers carry their own.
+func attributeSchema(api *design.APIDefinition, at *design.AttributeDefinition) (*genschema.JSONSchema, string) {
+	s := toOpenAPISchema(genschema.AttributeSchema(api, at))
+	desc := s.Description
+	s.Description = ""
+	return s, desc
+}
+
+func paramsFromDefinition(api *design.APIDefinition, params *design.AttributeDefinition, path string) ([]*Parameter, error) {
+	if params == nil {
+		return nil, nil
+	}
+	obj := params.Type.ToObject()
+	if obj == nil {
+		return nil, fmt.Errorf("invalid parameters definition, not an object")
+	}
+	var res []*Parameter
+	wildcards := design.ExtractWildcards(path)
+	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		in := "query"
+		required := params.IsRequired(n)
+		for _, w := range wildcards {
+			if n == w {
+				in = "path"
+				required = true
+				break
+			}
+		}
+		res = append(res, paramFor(api, at, n, in, required))
+		return nil
+	})
+	return res, nil
+}
+
+func paramsFromHeaders(api *design.APIDefinition, action *design.ActionDefinition) []*Parameter {
+	var params []*Parameter
+	action.IterateHeaders(func(name string, required bool, header *design.AttributeDefinition) error {
+		params = append(params, paramFor(api, header, name, "header", required))
+		return nil
+	})
+	return params
+}
+
+// paramsFromCookies returns the cookie parameters of the resource and action.
+func paramsFromCookies(api *design.APIDefinition, action *design.ActionDefinition) []*Parameter {
+	cookies := make(map[string]*Parameter)
+	for _, c := range []*design.AttributeDefinition{action.Parent.Cookies, action.Cookies} {
+		if c == nil {
+			continue
+		}
+		c.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+			cookies[n] = paramFor(api, at, n, "cookie", c.IsRequired(n))
+			return nil
+		})
+	}
+	names := make([]string, 0, len(cookies))
+	for n := range cookies {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	params := make([]*Parameter, len(names))
+	for i, n := range names {
+		params[i] = cookies[n]
+	}
+	return params
+}
+
+func paramFor(api *design.APIDefinition, at *design.AttributeDefinition, name, in string, required bool) *Parameter {
+	schema, desc := attributeSchema(api, at)
+	return &Parameter{
+		Name:        name,
+		In:          in,
+		Description: desc,
+		Required:    required,
+		Schema:      schema,
+	}
+}
+
+// requestBodyFromDefinition returns the request body of the action, nil if the action has no
+// payload. The body is described for each MIME type consumed by the action.
+func requestBodyFromDefinition(api *design.APIDefinition, action *design.ActionDefinition) *RequestBody {
+	if action.Payload == nil {
+		return nil
+	}
+	schema := toOpenAPISchema(genschema.TypeSchema(api, action.Payload))
+	var mimeTypes []string
+	if action.PayloadMultipart {
+		mimeTypes = []string{"multipart/form-data"}
+	} else if consumes := action.EffectiveConsumes(); len(consumes) > 0 {
+		mimeTypes = consumes
+	} else {
+		for _, c := range api.Consumes {
+			mimeTypes = append(mimeTypes, c.MIMETypes...)
+		}
+		if len(mimeTypes) == 0 {
+			mimeTypes = []string{"application/json"}
+		}
+	}
+	content := make(map[string]*MediaType, len(mimeTypes))
+	for _, m := range mimeTypes {
+		content[m] = &MediaType{Schema: schema}
+	}
+	return &RequestBody{
+		Description: action.Payload.Description,
+		Content:     content,
+		Required:    !action.PayloadOptional,
+	}
+}
+
+func responseFromDefinition(api *design.APIDefinition, r *design.ResponseDefinition) *Response {
+	var (
+		schema      *genschema.JSONSchema
+		contentType string
+	)
+	if r.MediaType != "" {
+		if mt, ok := api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]; ok {
+			view := r.ViewName
+			if view == "" {
+				view = design.DefaultView
+			}
+			schema = &genschema.JSONSchema{Ref: toOpenAPIRef(genschema.MediaTypeRef(api, mt, view))}
+			contentType = mt.ContentType
+			if contentType == "" {
+				contentType = r.MediaType
+			}
+		}
+	} else if r.Type != nil {
+		schema = toOpenAPISchema(genschema.TypeSchema(api, r.Type))
+		contentType = "application/json"
+	}
+	if r.Stream != "" {
+		contentType = r.Stream
+	}
+	resp := &Response{
+		Description: r.Description,
+		Headers:     headersFromDefinition(api, r.Headers),
+	}
+	if resp.Description == "" {
+		// the description is required by OpenAPI
+		resp.Description = http.StatusText(r.Status)
+	}
+	if r.Cookies != nil {
+		var names []string
+		r.Cookies.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+			names = append(names, "`"+n+"`")
+			return nil
+		})
+		if len(names) > 0 {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]*Header)
+			}
+			resp.Headers["Set-Cookie"] = &Header{
+				Description: "Response cookies: " + strings.Join(names, ", "),
+				Schema:      &genschema.JSONSchema{Type: genschema.JSONString},
+			}
+		}
+	}
+	if schema != nil {
+		mt := &MediaType{Schema: schema}
+		if len(r.Examples) > 0 {
+			mt.Examples = make(map[string]*Example, len(r.Examples))
+			for _, e := range r.Examples {
+				mt.Examples[e.Name] = &Example{Value: toStringMap(e.Value)}
+			}
+		}
+		resp.Content = map[string]*MediaType{contentType: mt}
+	}
+	return resp
+}
+
+// restrictContent describes the body in content for each of the given MIME types produced by an
+// action, content has at most one entry as built by responseFromDefinition.
+func restrictContent(content map[string]*MediaType, mimeTypes []string) map[string]*MediaType {
+	if len(content) == 0 {
+		return content
+	}
+	var body *MediaType
+	for _, mt := range content {
+		body = mt
+	}
+	res := make(map[string]*MediaType, len(mimeTypes))
+	for _, m := range mimeTypes {
+		res[m] = body
+	}
+	return res
+}
+
+func headersFromDefinition(api *design.APIDefinition, headers *design.AttributeDefinition) map[string]*Header {
+	if headers == nil {
+		return nil
+	}
+	obj := headers.Type.ToObject()
+	if len(obj) == 0 {
+		return nil
+	}
+	res := make(map[string]*Header, len(obj))
+	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		schema, desc := attributeSchema(api, at)
+		res[n] = &Header{Description: desc, Required: headers.IsRequired(n), Schema: schema}
+		return nil
+	})
+	return res
+}
+
+func buildPathFromDefinition(o *OpenAPI, api *design.APIDefinition, route *design.RouteDefinition, basePath string) error {
+	action := route.Parent
+
+	params, err := paramsFromDefinition(api, action.AllParams(), route.FullPath())
+	if err != nil {
+		return err
+	}
+	params = append(params, paramsFromHeaders(api, action)...)
+	params = append(params, paramsFromCookies(api, action)...)
+
+	responses := make(map[string]*Response, len(action.Responses))
+	produces := action.EffectiveProduces()
+	for _, r := range action.Responses {
+		resp := responseFromDefinition(api, r)
+		if len(produces) > 0 && r.Stream == "" {
+			resp.Content = restrictContent(resp.Content, produces)
+		}
+		if action.Pagination != "" && r.Status == 200 {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]*Header)
+			}
+			resp.Headers["Link"] = &Header{
+				Description: "RFC 5988 links to the other pages of results",
+				Schema:      &genschema.JSONSchema{Type: genschema.JSONString},
+			}
+		}
+		responses[strconv.Itoa(r.Status)] = resp
+	}
+	if len(responses) == 0 {
+		// at least one response is required by OpenAPI
+		responses["default"] = &Response{Description: "Unspecified response"}
+	}
+
+	operationID := fmt.Sprintf("%s#%s", action.Parent.Name, action.Name)
+	for i, rt := range action.Routes {
+		if rt == route && i > 0 {
+			operationID = fmt.Sprintf("%s#%d", operationID, i)
+			break
+		}
+	}
+
+	summary := action.Name + " " + action.Parent.Name
+	if m := action.Metadata["swagger:summary"]; len(m) > 0 {
+		summary = m[0]
+	}
+
+	operation := &Operation{
+		Tags:         []string{action.Parent.Name},
+		Summary:      summary,
+		Description:  action.Description,
+		ExternalDocs: docsFromDefinition(action.Docs),
+		OperationID:  operationID,
+		Parameters:   params,
+		RequestBody:  requestBodyFromDefinition(api, action),
+		Responses:    responses,
+		Deprecated:   route.EffectiveDeprecation() != nil,
+		Callbacks:    callbacksFromDefinition(api, action.Callbacks),
+	}
+	applySecurity(operation, action.Security)
+
+	key := design.WildcardRegex.ReplaceAllStringFunc(
+		route.FullPath(),
+		func(w string) string {
+			return fmt.Sprintf("/{%s}", w[2:])
+		},
+	)
+	if basePath != "" && basePath != "/" {
+		key = strings.TrimPrefix(key, basePath)
+	}
+	if key == "" {
+		key = "/"
+	}
+	p, ok := o.Paths[key]
+	if !ok {
+		p = new(PathItem)
+		o.Paths[key] = p
+	}
+	switch route.Verb {
+	case "GET":
+		p.Get = operation
+	case "PUT":
+		p.Put = operation
+	case "POST":
+		p.Post = operation
+	case "DELETE":
+		p.Delete = operation
+	case "OPTIONS":
+		p.Options = operation
+	case "HEAD":
+		p.Head = operation
+	case "PATCH":
+		p.Patch = operation
+	case "TRACE":
+		p.Trace = operation
+	}
+	return nil
+}
+
+// callbacksFromDefinition returns the OpenAPI callbacks describing the given action callbacks.
+func callbacksFromDefinition(api *design.APIDefinition, callbacks map[string]*design.CallbackDefinition) map[string]Callback {
+	if len(callbacks) == 0 {
+		return nil
+	}
+	res := make(map[string]Callback, len(callbacks))
+	for n, cb := range callbacks {
+		expr := cb.URL
+		if expr == "" {
+			expr = DefaultCallbackURL
+		}
+		res[n] = Callback{expr: &PathItem{Post: callbackOperation(api, cb)}}
+	}
+	return res
+}
+
+// callbackOperation returns the operation describing the request sent by the API when delivering
+// the callback. The request headers are the ones set by client.WebhookSender.
+func callbackOperation(api *design.APIDefinition, cb *design.CallbackDefinition) *Operation {
+	header := func(name, desc string) *Parameter {
+		return &Parameter{
+			Name:        name,
+			In:          "header",
+			Description: desc,
+			Required:    true,
+			Schema:      &genschema.JSONSchema{Type: genschema.JSONString},
+		}
+	}
+	op := &Operation{
+		Summary:     cb.Name,
+		Description: cb.Description,
+		Parameters: []*Parameter{
+			header(goa.WebhookEventHeader, fmt.Sprintf("Name of the callback, always %#v", cb.Name)),
+			header(goa.WebhookDeliveryHeader, "Unique delivery identifier, identical for all the attempts of a delivery"),
+			header(goa.WebhookTimestampHeader, "Time the request was sent in seconds since the Unix epoch"),
+			header(goa.WebhookSignatureHeader, "HMAC-SHA256 signature of the timestamp, a dot and the request body"),
+		},
+		Responses: map[string]*Response{
+			"2XX": {Description: "The callback was received"},
+		},
+	}
+	if cb.Payload != nil {
+		op.RequestBody = &RequestBody{
+			Description: cb.Payload.Description,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: toOpenAPISchema(genschema.TypeSchema(api, cb.Payload))},
+			},
+			Required: true,
+		}
+	}
+	return op
+}
+
+// applySecurity sets the security requirements of the operation. OpenAPI 3.0 only supports scopes
+// for OAuth2 so the scopes required by JWT security schemes are listed in the description.
+func applySecurity(operation *Operation, security *design.SecurityDefinition) {
+	if security == nil || security.Scheme.Kind == design.NoSecurityKind {
+		return
+	}
+	scopes := security.Scopes
+	if security.Scheme.Kind != design.OAuth2SecurityKind {
+		if security.Scheme.Kind == design.JWTSecurityKind && len(scopes) > 0 {
+			if operation.Description != "" {
+				operation.Description += "\n\n"
+			}
+			operation.Description += fmt.Sprintf("Required security scopes:\n%s", scopesList(scopes))
+		}
+		scopes = nil
+	}
+	if scopes == nil {
+		scopes = make([]string, 0)
+	}
+	operation.Security = []map[string][]string{{security.Scheme.SchemeName: scopes}}
+}
+
+func scopesList(scopes []string) string {
+	sorted := make([]string, len(scopes))
+	copy(sorted, scopes)
+	sort.Strings(sorted)
+	lines := make([]string, len(sorted))
+	for i, scope := range sorted {
+		lines[i] = fmt.Sprintf("  * `%s`", scope)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toOpenAPIRef converts a reference produced by genschema into a reference to the component
+// schemas.
+func toOpenAPIRef(ref string) string {
+	return schemasRef + strings.TrimPrefix(ref, definitionsRef)
+}
+
+// toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
+func toStringMap(val interface{}) interface{} {
+	switch actual := val.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{})
+		for k, v := range actual {
+			m[fmt.Sprintf("%v", k)] = toStringMap(v)
+		}
+		return m
+	case []interface{}:
+		mapSlice := make([]interface{}, len(actual))
+		for i, e := range actual {
+			mapSlice[i] = toStringMap(e)
+		}
+		return mapSlice
+	default:
+		return actual
+	}
+}
@@ -0,0 +1,411 @@
+package genopenapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var o *genopenapi.OpenAPI
+	var newErr error
+
+	BeforeEach(func() {
+		o = nil
+		newErr = nil
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		o, newErr = genopenapi.New(Design)
+	})
+
+	Context("with a valid API definition", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Title("title")
+				Description("description")
+				Version("1.0")
+				Host("goa.design")
+				Scheme("http", "https")
+				BasePath("/base")
+				Docs(func() {
+					URL("http://docs.goa.design")
+				})
+			})
+		})
+
+		It("sets the version and info", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(o.OpenAPI).Should(Equal("3.0.3"))
+			Ω(o.Info.Title).Should(Equal("title"))
+			Ω(o.Info.Description).Should(Equal("description"))
+			Ω(o.Info.Version).Should(Equal("1.0"))
+			Ω(o.ExternalDocs.URL).Should(Equal("http://docs.goa.design"))
+		})
+
+		It("derives the servers from the host, schemes and base path", func() {
+			Ω(o.Servers).Should(HaveLen(2))
+			Ω(o.Servers[0].URL).Should(Equal("http://goa.design/base"))
+			Ω(o.Servers[1].URL).Should(Equal("https://goa.design/base"))
+		})
+
+		It("serializes into valid JSON", func() {
+			b, err := json.Marshal(o)
+			Ω(err).ShouldNot(HaveOccurred())
+			var m map[string]interface{}
+			Ω(json.Unmarshal(b, &m)).Should(Succeed())
+			Ω(m).Should(HaveKey("paths"))
+			Ω(m).ShouldNot(HaveKey("components"))
+		})
+	})
+
+	Context("with actions", func() {
+		BeforeEach(func() {
+			var Bottle = MediaType("application/vnd.goa.bottle", func() {
+				Attributes(func() {
+					Attribute("id", Integer)
+					Attribute("name", String)
+					Attribute("label", File)
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("name")
+				})
+				View("tiny", func() {
+					Attribute("id")
+				})
+			})
+			var BottlePayload = Type("BottlePayload", func() {
+				Attribute("name", String)
+				Required("name")
+			})
+			API("test", func() {
+				BasePath("/api")
+				Consumes("application/json", "application/xml")
+			})
+			Resource("bottle", func() {
+				Description("Bottle resource")
+				BasePath("/bottles")
+				Cookies(func() {
+					Cookie("session", String, "Session ID")
+					Required("session")
+				})
+				Action("show", func() {
+					Routing(GET("/:id"), GET("/named/:id"))
+					Params(func() {
+						Param("id", Integer, "Bottle ID")
+						Param("fields", ArrayOf(String))
+					})
+					Headers(func() {
+						Header("X-Trace", String)
+					})
+					Response(OK, func() {
+						Media(Bottle, "tiny")
+						Headers(func() {
+							Header("ETag", String, "Entity tag")
+						})
+					})
+					Response(NotFound)
+				})
+				Action("create", func() {
+					Routing(POST(""))
+					Payload(BottlePayload)
+					Response(Created, Bottle)
+				})
+				Action("update", func() {
+					Routing(PUT("/:id"))
+					Consumes("application/json")
+					Produces("application/json")
+					Payload(BottlePayload)
+					Response(OK, Bottle)
+					Response(NotFound)
+				})
+				Action("upload", func() {
+					Routing(POST("/upload"))
+					Payload(func() {
+						Member("label", File)
+					})
+					MultipartForm()
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("builds the paths relative to the base path", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(o.Servers).Should(HaveLen(1))
+			Ω(o.Servers[0].URL).Should(Equal("/api"))
+			Ω(o.Paths).Should(HaveKey("/bottles/{id}"))
+			Ω(o.Paths).Should(HaveKey("/bottles/named/{id}"))
+			Ω(o.Paths["/bottles/{id}"].Get.OperationID).Should(Equal("bottle#show"))
+			Ω(o.Paths["/bottles/named/{id}"].Get.OperationID).Should(Equal("bottle#show#1"))
+		})
+
+		It("lists the path, query, header and cookie parameters", func() {
+			op := o.Paths["/bottles/{id}"].Get
+			Ω(op.Tags).Should(Equal([]string{"bottle"}))
+			Ω(op.Parameters).Should(HaveLen(4))
+			id, fields, trace, session := op.Parameters[1], op.Parameters[0], op.Parameters[2], op.Parameters[3]
+			Ω(id.Name).Should(Equal("id"))
+			Ω(id.In).Should(Equal("path"))
+			Ω(id.Required).Should(BeTrue())
+			Ω(id.Description).Should(Equal("Bottle ID"))
+			Ω(id.Schema.Type).Should(Equal(genschema.JSONType(genschema.JSONInteger)))
+			Ω(id.Schema.Description).Should(BeEmpty())
+			Ω(fields.In).Should(Equal("query"))
+			Ω(fields.Schema.Type).Should(Equal(genschema.JSONArray))
+			Ω(trace.In).Should(Equal("header"))
+			Ω(session.Name).Should(Equal("session"))
+			Ω(session.In).Should(Equal("cookie"))
+			Ω(session.Required).Should(BeTrue())
+		})
+
+		It("describes the responses for each content type", func() {
+			op := o.Paths["/bottles/{id}"].Get
+			Ω(op.Responses).Should(HaveKey("200"))
+			Ω(op.Responses).Should(HaveKey("404"))
+			ok := op.Responses["200"]
+			Ω(ok.Description).Should(Equal("OK"))
+			Ω(ok.Content).Should(HaveKey("application/vnd.goa.bottle"))
+			Ω(ok.Content["application/vnd.goa.bottle"].Schema.Ref).Should(Equal("#/components/schemas/GoaBottleTiny"))
+			Ω(ok.Headers).Should(HaveKey("ETag"))
+			Ω(ok.Headers["ETag"].Description).Should(Equal("Entity tag"))
+			Ω(op.Responses["404"].Content).Should(BeEmpty())
+		})
+
+		It("describes the request body for each content type", func() {
+			op := o.Paths["/bottles"].Post
+			Ω(op.RequestBody).ShouldNot(BeNil())
+			Ω(op.RequestBody.Required).Should(BeTrue())
+			Ω(op.RequestBody.Content).Should(HaveLen(2))
+			for _, ct := range []string{"application/json", "application/xml"} {
+				Ω(op.RequestBody.Content).Should(HaveKey(ct))
+				Ω(op.RequestBody.Content[ct].Schema.Ref).Should(Equal("#/components/schemas/BottlePayload"))
+			}
+		})
+
+		It("uses the encodings of the action", func() {
+			op := o.Paths["/bottles/{id}"].Put
+			Ω(op.RequestBody.Content).Should(HaveLen(1))
+			Ω(op.RequestBody.Content).Should(HaveKey("application/json"))
+			ok := op.Responses["200"]
+			Ω(ok.Content).Should(HaveLen(1))
+			Ω(ok.Content).Should(HaveKey("application/json"))
+			Ω(ok.Content["application/json"].Schema.Ref).Should(Equal("#/components/schemas/GoaBottle"))
+			Ω(op.Responses["404"].Content).Should(BeEmpty())
+		})
+
+		It("describes multipart payloads", func() {
+			op := o.Paths["/bottles/upload"].Post
+			Ω(op.RequestBody.Content).Should(HaveLen(1))
+			Ω(op.RequestBody.Content).Should(HaveKey("multipart/form-data"))
+			ref := op.RequestBody.Content["multipart/form-data"].Schema.Ref
+			Ω(ref).Should(Equal("#/components/schemas/UploadBottlePayload"))
+			label := o.Components.Schemas["UploadBottlePayload"].Properties["label"]
+			Ω(label).ShouldNot(BeNil())
+			Ω(label.Type).Should(Equal(genschema.JSONType(genschema.JSONString)))
+			Ω(label.Format).Should(Equal("binary"))
+		})
+
+		It("lists the schemas in the components", func() {
+			Ω(o.Components.Schemas).Should(HaveKey("BottlePayload"))
+			Ω(o.Components.Schemas).Should(HaveKey("GoaBottle"))
+			Ω(o.Components.Schemas).Should(HaveKey("GoaBottleTiny"))
+			b, err := json.Marshal(o.Components.Schemas["GoaBottle"])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).ShouldNot(ContainSubstring("#/definitions/"))
+			Ω(string(b)).ShouldNot(ContainSubstring(`"media"`))
+		})
+
+		It("lists the resources as tags", func() {
+			Ω(o.Tags).Should(HaveLen(1))
+			Ω(o.Tags[0].Name).Should(Equal("bottle"))
+			Ω(o.Tags[0].Description).Should(Equal("Bottle resource"))
+		})
+	})
+
+	Context("with union types", func() {
+		BeforeEach(func() {
+			var Cat = Type("Cat", func() {
+				Attribute("type", String)
+				Attribute("lives", Integer)
+				Required("type")
+			})
+			var Dog = Type("Dog", func() {
+				Attribute("type", String)
+				Attribute("breed", String)
+				Required("type")
+			})
+			var Pet = Type("Pet", func() {
+				OneOf(Cat, Dog)
+				Discriminator("type")
+			})
+			API("test", nil)
+			Resource("pet", func() {
+				Action("create", func() {
+					Routing(POST("/pets"))
+					Payload(Pet)
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("uses oneOf and a discriminator object", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			pet := o.Components.Schemas["Pet"]
+			Ω(pet).ShouldNot(BeNil())
+			Ω(pet.OneOf).Should(HaveLen(2))
+			Ω(pet.OneOf[0].Ref).Should(Equal("#/components/schemas/Cat"))
+			Ω(pet.OneOf[1].Ref).Should(Equal("#/components/schemas/Dog"))
+			Ω(pet.Discriminator).ShouldNot(BeNil())
+			Ω(pet.Discriminator.PropertyName).Should(Equal("type"))
+			Ω(pet.Discriminator.Mapping).Should(Equal(map[string]string{
+				"Cat": "#/components/schemas/Cat",
+				"Dog": "#/components/schemas/Dog",
+			}))
+			b, err := json.Marshal(pet)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(ContainSubstring(`"discriminator":{"propertyName":"type"`))
+		})
+	})
+
+	Context("with security schemes", func() {
+		BeforeEach(func() {
+			var Basic = BasicAuthSecurity("basic")
+			var Key = APIKeySecurity("key", func() {
+				Cookie("token")
+			})
+			var JWT = JWTSecurity("jwt", func() {
+				Header("Authorization")
+				TokenURL("http://goa.design/token")
+				Scope("api:read", "Read access")
+			})
+			var OAuth = OAuth2Security("oauth", func() {
+				ApplicationFlow("http://goa.design/token")
+				Scope("api:write", "Write access")
+			})
+			API("test", func() {
+				Security(Basic)
+			})
+			Resource("res", func() {
+				Action("basic", func() {
+					Routing(GET("/basic"))
+					Response(NoContent)
+				})
+				Action("key", func() {
+					Routing(GET("/key"))
+					Security(Key)
+					Response(NoContent)
+				})
+				Action("jwt", func() {
+					Routing(GET("/jwt"))
+					Security(JWT, func() {
+						Scope("api:read")
+					})
+					Response(NoContent)
+				})
+				Action("oauth", func() {
+					Routing(GET("/oauth"))
+					Security(OAuth, func() {
+						Scope("api:write")
+					})
+					Response(NoContent)
+				})
+				Action("none", func() {
+					Routing(GET("/none"))
+					NoSecurity()
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("produces the security schemes", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			schemes := o.Components.SecuritySchemes
+			Ω(schemes).Should(HaveLen(4))
+			Ω(schemes["basic"].Type).Should(Equal("http"))
+			Ω(schemes["basic"].Scheme).Should(Equal("basic"))
+			Ω(schemes["key"].Type).Should(Equal("apiKey"))
+			Ω(schemes["key"].In).Should(Equal("cookie"))
+			Ω(schemes["key"].Name).Should(Equal("token"))
+			Ω(schemes["jwt"].Type).Should(Equal("http"))
+			Ω(schemes["jwt"].Scheme).Should(Equal("bearer"))
+			Ω(schemes["jwt"].BearerFormat).Should(Equal("JWT"))
+			Ω(schemes["jwt"].Description).Should(ContainSubstring("http://goa.design/token"))
+			Ω(schemes["oauth"].Type).Should(Equal("oauth2"))
+			Ω(schemes["oauth"].Flows.ClientCredentials).ShouldNot(BeNil())
+			Ω(schemes["oauth"].Flows.ClientCredentials.TokenURL).Should(Equal("http://goa.design/token"))
+			Ω(schemes["oauth"].Flows.ClientCredentials.Scopes).Should(HaveKey("api:write"))
+		})
+
+		It("sets the operation security requirements", func() {
+			Ω(o.Paths["/basic"].Get.Security).Should(Equal([]map[string][]string{{"basic": {}}}))
+			Ω(o.Paths["/key"].Get.Security).Should(Equal([]map[string][]string{{"key": {}}}))
+			Ω(o.Paths["/jwt"].Get.Security).Should(Equal([]map[string][]string{{"jwt": {}}}))
+			Ω(o.Paths["/jwt"].Get.Description).Should(ContainSubstring("api:read"))
+			Ω(o.Paths["/oauth"].Get.Security).Should(Equal([]map[string][]string{{"oauth": {"api:write"}}}))
+			Ω(o.Paths["/none"].Get.Security).Should(BeNil())
+		})
+	})
+
+	Context("with callbacks", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Callback("maintenance", func() {
+					Description("Sent before maintenance windows")
+				})
+			})
+			Resource("order", func() {
+				Action("create", func() {
+					Routing(POST(""))
+					Response(NoContent)
+					Callback("order_shipped", func() {
+						URL("{$request.body#/hook}")
+						Payload(func() {
+							Member("order_id", Integer)
+						})
+					})
+					Callback("order_cancelled", nil)
+				})
+			})
+		})
+
+		It("describes the operation callbacks", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			cbs := o.Paths["/"].Post.Callbacks
+			Ω(cbs).Should(HaveLen(2))
+			Ω(cbs["order_shipped"]).Should(HaveKey("{$request.body#/hook}"))
+			Ω(cbs["order_cancelled"]).Should(HaveKey(genopenapi.DefaultCallbackURL))
+			op := cbs["order_shipped"]["{$request.body#/hook}"].Post
+			Ω(op).ShouldNot(BeNil())
+			Ω(op.RequestBody.Content["application/json"].Schema.Ref).Should(Equal("#/components/schemas/OrderShippedCallbackPayload"))
+			Ω(o.Components.Schemas).Should(HaveKey("OrderShippedCallbackPayload"))
+			var names []string
+			for _, p := range op.Parameters {
+				Ω(p.In).Should(Equal("header"))
+				names = append(names, p.Name)
+			}
+			Ω(names).Should(ContainElement("X-Webhook-Signature"))
+			Ω(op.Responses).Should(HaveKey("2XX"))
+			Ω(cbs["order_cancelled"][genopenapi.DefaultCallbackURL].Post.RequestBody).Should(BeNil())
+		})
+
+		It("describes the API callbacks as webhooks", func() {
+			Ω(o.Webhooks).Should(HaveKey("maintenance"))
+			Ω(o.Webhooks["maintenance"].Post.Description).Should(Equal("Sent before maintenance windows"))
+			b, err := json.Marshal(o)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(ContainSubstring(`"x-webhooks":{"maintenance":{"post"`))
+		})
+	})
+})
@@ -26,10 +26,16 @@ type (
 		Description  string                 `json:"description,omitempty"`
 		DefaultValue interface{}            `json:"default,omitempty"`
 		Example      interface{}            `json:"example,omitempty"`
+		// Deprecated is true if the attribute is deprecated.
+		Deprecated bool `json:"deprecated,omitempty"`
+		// XDeprecated is the Swagger extension that marks deprecated properties as Swagger
+		// does not support the deprecated keyword.
+		XDeprecated bool `json:"x-deprecated,omitempty"`
 
 		// Hyper schema
 		Media     *JSONMedia  `json:"media,omitempty"`
 		ReadOnly  bool        `json:"readOnly,omitempty"`
+		WriteOnly bool        `json:"writeOnly,omitempty"`
 		PathStart string      `json:"pathStart,omitempty"`
 		Links     []*JSONLink `json:"links,omitempty"`
 		Ref       string      `json:"$ref,omitempty"`
@@ -39,14 +45,34 @@ type (
 		Format               string        `json:"format,omitempty"`
 		Pattern              string        `json:"pattern,omitempty"`
 		Minimum              *float64      `json:"minimum,omitempty"`
+		ExclusiveMinimum     bool          `json:"exclusiveMinimum,omitempty"`
 		Maximum              *float64      `json:"maximum,omitempty"`
+		ExclusiveMaximum     bool          `json:"exclusiveMaximum,omitempty"`
+		MultipleOf           *float64      `json:"multipleOf,omitempty"`
 		MinLength            *int          `json:"minLength,omitempty"`
 		MaxLength            *int          `json:"maxLength,omitempty"`
+		UniqueItems          bool          `json:"uniqueItems,omitempty"`
+		MinProperties        *int          `json:"minProperties,omitempty"`
+		MaxProperties        *int          `json:"maxProperties,omitempty"`
 		Required             []string      `json:"required,omitempty"`
 		AdditionalProperties bool          `json:"additionalProperties,omitempty"`
+		// DependentRequired lists the properties that are required when a given property
+		// is present.
+		DependentRequired map[string][]string `json:"dependentRequired,omitempty"`
+
+		// Conditionals
+		AllOf []*JSONSchema `json:"allOf,omitempty"`
+		If    *JSONSchema   `json:"if,omitempty"`
+		Then  *JSONSchema   `json:"then,omitempty"`
 
 		// Union
 		AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+		OneOf []*JSONSchema `json:"oneOf,omitempty"`
+		// Discriminator is the Swagger extension that identifies the property used for
+		// polymorphism.
+		Discriminator string `json:"discriminator,omitempty"`
+		// Examples lists the named examples indexed by name.
+		Examples map[string]interface{} `json:"x-examples,omitempty"`
 	}
 
 	// JSONType is the JSON type enum.
@@ -309,6 +335,12 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	case *design.Hash:
 		s.Type = JSONObject
 		s.AdditionalProperties = true
+	case *design.Union:
+		for _, ut := range actual.Types {
+			alt := NewJSONSchema()
+			alt.Ref = TypeRef(api, ut)
+			s.OneOf = append(s.OneOf, alt)
+		}
 	case *design.UserTypeDefinition:
 		s.Ref = TypeRef(api, actual)
 	case *design.MediaTypeDefinition:
@@ -318,12 +350,23 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	return s
 }
 
+// AttributeSchema produces the JSON schema corresponding to the given attribute including its
+// description, default value, example and validations.
+func AttributeSchema(api *design.APIDefinition, at *design.AttributeDefinition) *JSONSchema {
+	return buildAttributeSchema(api, NewJSONSchema(), at)
+}
+
 type mergeItems []struct {
 	a, b   interface{}
 	needed bool
 }
 
 func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
+	// the exclusive flags apply to the bounds they are merged with
+	minimum := (s.Minimum == nil && other.Minimum != nil) ||
+		(s.Minimum != nil && other.Minimum != nil && *s.Minimum > *other.Minimum)
+	maximum := (s.Maximum == nil && other.Maximum != nil) ||
+		(s.Maximum != nil && other.Maximum != nil && *s.Maximum < *other.Maximum)
 	return mergeItems{
 		{&s.ID, other.ID, s.ID == ""},
 		{&s.Type, other.Type, s.Type == ""},
@@ -333,28 +376,32 @@ func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
 		{&s.Title, other.Title, s.Title == ""},
 		{&s.Media, other.Media, s.Media == nil},
 		{&s.ReadOnly, other.ReadOnly, s.ReadOnly == false},
+		{&s.WriteOnly, other.WriteOnly, s.WriteOnly == false},
 		{&s.PathStart, other.PathStart, s.PathStart == ""},
 		{&s.Enum, other.Enum, s.Enum == nil},
 		{&s.Format, other.Format, s.Format == ""},
 		{&s.Pattern, other.Pattern, s.Pattern == ""},
 		{&s.AdditionalProperties, other.AdditionalProperties, s.AdditionalProperties == false},
+		{&s.UniqueItems, other.UniqueItems, s.UniqueItems == false},
+		{&s.MultipleOf, other.MultipleOf, s.MultipleOf == nil},
+		{&s.MinProperties, other.MinProperties, s.MinProperties == nil},
+		{&s.MaxProperties, other.MaxProperties, s.MaxProperties == nil},
+		{&s.OneOf, other.OneOf, s.OneOf == nil},
+		{&s.DependentRequired, other.DependentRequired, s.DependentRequired == nil},
+		{&s.AllOf, other.AllOf, s.AllOf == nil},
+		{&s.Discriminator, other.Discriminator, s.Discriminator == ""},
+		{&s.Examples, other.Examples, s.Examples == nil},
+		{&s.Minimum, other.Minimum, minimum},
+		{&s.ExclusiveMinimum, other.ExclusiveMinimum, minimum},
+		{&s.Maximum, other.Maximum, maximum},
+		{&s.ExclusiveMaximum, other.ExclusiveMaximum, maximum},
 		{
-			a: s.Minimum, b: other.Minimum,
-			needed: (s.Minimum == nil && s.Minimum != nil) ||
-				(s.Minimum != nil && other.Minimum != nil && *s.Minimum > *other.Minimum),
-		},
-		{
-			a: s.Maximum, b: other.Maximum,
-			needed: (s.Maximum == nil && other.Maximum != nil) ||
-				(s.Maximum != nil && other.Maximum != nil && *s.Maximum < *other.Maximum),
-		},
-		{
-			a: s.MinLength, b: other.MinLength,
+			a: &s.MinLength, b: other.MinLength,
 			needed: (s.MinLength == nil && other.MinLength != nil) ||
 				(s.MinLength != nil && other.MinLength != nil && *s.MinLength > *other.MinLength),
 		},
 		{
-			a: s.MaxLength, b: other.MaxLength,
+			a: &s.MaxLength, b: other.MaxLength,
 			needed: (s.MaxLength == nil && other.MaxLength != nil) ||
 				(s.MaxLength != nil && other.MaxLength != nil && *s.MaxLength > *other.MaxLength),
 		},
@@ -405,6 +452,9 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Title:                s.Title,
 		Media:                s.Media,
 		ReadOnly:             s.ReadOnly,
+		WriteOnly:            s.WriteOnly,
+		Deprecated:           s.Deprecated,
+		XDeprecated:          s.XDeprecated,
 		PathStart:            s.PathStart,
 		Links:                s.Links,
 		Ref:                  s.Ref,
@@ -412,11 +462,24 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Format:               s.Format,
 		Pattern:              s.Pattern,
 		Minimum:              s.Minimum,
+		ExclusiveMinimum:     s.ExclusiveMinimum,
 		Maximum:              s.Maximum,
+		ExclusiveMaximum:     s.ExclusiveMaximum,
+		MultipleOf:           s.MultipleOf,
 		MinLength:            s.MinLength,
 		MaxLength:            s.MaxLength,
+		UniqueItems:          s.UniqueItems,
+		MinProperties:        s.MinProperties,
+		MaxProperties:        s.MaxProperties,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
+		OneOf:                s.OneOf,
+		DependentRequired:    s.DependentRequired,
+		AllOf:                s.AllOf,
+		If:                   s.If,
+		Then:                 s.Then,
+		Discriminator:        s.Discriminator,
+		Examples:             s.Examples,
 	}
 	for n, p := range s.Properties {
 		js.Properties[n] = p.Dup()
@@ -446,6 +509,15 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.DefaultValue = toStringMap(at.DefaultValue)
 	s.Description = at.Description
 	s.Example = at.GenerateExample(api.RandomGenerator(), nil)
+	if len(at.Examples) > 0 {
+		s.Examples = make(map[string]interface{}, len(at.Examples))
+		for _, e := range at.Examples {
+			s.Examples[e.Name] = toStringMap(e.Value)
+		}
+	}
+	s.ReadOnly = at.ReadOnly
+	s.WriteOnly = at.WriteOnly
+	s.Deprecated = at.Deprecation != nil
 	val := at.Validation
 	if val == nil {
 		return s
@@ -455,9 +527,14 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.Pattern = val.Pattern
 	if val.Minimum != nil {
 		s.Minimum = val.Minimum
+		s.ExclusiveMinimum = val.ExclusiveMinimum
 	}
 	if val.Maximum != nil {
 		s.Maximum = val.Maximum
+		s.ExclusiveMaximum = val.ExclusiveMaximum
+	}
+	if val.MultipleOf != nil {
+		s.MultipleOf = val.MultipleOf
 	}
 	if val.MinLength != nil {
 		s.MinLength = val.MinLength
@@ -465,7 +542,39 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	if val.MaxLength != nil {
 		s.MaxLength = val.MaxLength
 	}
+	s.UniqueItems = val.UniqueItems
+	if val.MinProperties != nil {
+		s.MinProperties = val.MinProperties
+	}
+	if val.MaxProperties != nil {
+		s.MaxProperties = val.MaxProperties
+	}
 	s.Required = val.Required
+	for _, r := range val.RequiredIf {
+		if len(r.Values) == 0 {
+			if s.DependentRequired == nil {
+				s.DependentRequired = make(map[string][]string)
+			}
+			s.DependentRequired[r.DependsOn] = append(s.DependentRequired[r.DependsOn], r.Name)
+			continue
+		}
+		cond := &JSONSchema{
+			Properties: map[string]*JSONSchema{r.DependsOn: {Enum: r.Values}},
+			Required:   []string{r.DependsOn},
+		}
+		s.AllOf = append(s.AllOf, &JSONSchema{If: cond, Then: &JSONSchema{Required: []string{r.Name}}})
+	}
+	for _, names := range val.AtLeastOneOf {
+		anyOf := make([]*JSONSchema, len(names))
+		for i, n := range names {
+			anyOf[i] = &JSONSchema{Required: []string{n}}
+		}
+		if s.AnyOf == nil {
+			s.AnyOf = anyOf
+		} else {
+			s.AllOf = append(s.AllOf, &JSONSchema{AnyOf: anyOf})
+		}
+	}
 	return s
 }
 
@@ -78,4 +78,137 @@ var _ = Describe("TypeSchema", func() {
 		})
 
 	})
+
+	Context("with a union type", func() {
+		BeforeEach(func() {
+			email := Type("EmailNotification", func() {
+				Attribute("type", design.String)
+				Attribute("address", design.String)
+				Required("type")
+			})
+			sms := Type("SMSNotification", func() {
+				Attribute("type", design.String)
+				Attribute("phone", design.String)
+				Required("type")
+			})
+			Type("Notification", func() {
+				OneOf(email, sms)
+				Discriminator("type")
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Notification"].Type
+		})
+
+		It("returns a oneOf JSON schema", func() {
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.OneOf).Should(HaveLen(2))
+			Ω(s.OneOf[0].Ref).Should(Equal("#/definitions/EmailNotification"))
+			Ω(s.OneOf[1].Ref).Should(Equal("#/definitions/SMSNotification"))
+		})
+	})
+
+	Context("with a type with conditional requirements", func() {
+		BeforeEach(func() {
+			Type("Payment", func() {
+				Attribute("method", design.String)
+				Attribute("card_number", design.String)
+				Attribute("iban", design.String)
+				Attribute("email", design.String)
+				Attribute("phone", design.String)
+				RequiredIf("card_number", "method", "card")
+				RequiredIf("email", "iban")
+				AtLeastOneOf("email", "phone")
+				Attribute("id", design.Integer, func() {
+					ReadOnly()
+				})
+				Attribute("secret", design.String, func() {
+					WriteOnly()
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Payment"]
+		})
+
+		It("documents the requirements and access modes in the type definition", func() {
+			Ω(s.Ref).Should(Equal("#/definitions/Payment"))
+			d := genschema.Definitions["Payment"]
+			Ω(d).ShouldNot(BeNil())
+			Ω(d.DependentRequired).Should(Equal(map[string][]string{"iban": {"email"}}))
+			Ω(d.AllOf).Should(HaveLen(1))
+			Ω(d.AllOf[0].If.Properties["method"].Enum).Should(Equal([]interface{}{"card"}))
+			Ω(d.AllOf[0].Then.Required).Should(Equal([]string{"card_number"}))
+			Ω(d.AnyOf).Should(HaveLen(2))
+			Ω(d.AnyOf[0].Required).Should(Equal([]string{"email"}))
+			Ω(d.AnyOf[1].Required).Should(Equal([]string{"phone"}))
+			Ω(d.Properties["id"].ReadOnly).Should(BeTrue())
+			Ω(d.Properties["secret"].WriteOnly).Should(BeTrue())
+		})
+	})
+
+	Context("with a type with deprecated attributes", func() {
+		BeforeEach(func() {
+			Type("Order", func() {
+				Attribute("item", design.String)
+				Attribute("note", design.String, func() {
+					Deprecated("use comments instead")
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Order"]
+		})
+
+		It("marks the attributes as deprecated", func() {
+			d := genschema.Definitions["Order"]
+			Ω(d).ShouldNot(BeNil())
+			Ω(d.Properties["note"].Deprecated).Should(BeTrue())
+			Ω(d.Properties["item"].Deprecated).Should(BeFalse())
+		})
+	})
+
+	Context("with a type with named examples", func() {
+		BeforeEach(func() {
+			Type("Account", func() {
+				Attribute("name", design.String, func() {
+					Examples("short", "al")
+				})
+				Examples("minimal", map[string]interface{}{"name": "alice"})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Account"]
+		})
+
+		It("lists the examples in the type definition", func() {
+			d := genschema.Definitions["Account"]
+			Ω(d).ShouldNot(BeNil())
+			Ω(d.Examples).Should(Equal(map[string]interface{}{"minimal": map[string]interface{}{"name": "alice"}}))
+			Ω(d.Properties["name"].Examples).Should(Equal(map[string]interface{}{"short": "al"}))
+		})
+	})
+})
+
+var _ = Describe("Merge", func() {
+	var s, other *genschema.JSONSchema
+
+	BeforeEach(func() {
+		zero, five := 0.0, 5.0
+		s = &genschema.JSONSchema{Minimum: &five, Maximum: &five}
+		other = &genschema.JSONSchema{
+			Minimum:          &zero,
+			ExclusiveMinimum: true,
+			Maximum:          &zero,
+			ExclusiveMaximum: true,
+		}
+		s.Merge(other)
+	})
+
+	It("carries the exclusive flags with the merged bounds", func() {
+		Ω(*s.Minimum).Should(Equal(0.0))
+		Ω(s.ExclusiveMinimum).Should(BeTrue())
+		Ω(*s.Maximum).Should(Equal(5.0))
+		Ω(s.ExclusiveMaximum).Should(BeFalse())
+	})
 })
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/gen_schema"
@@ -30,6 +31,8 @@ type (
 		SecurityDefinitions map[string]*SecurityDefinition   `json:"securityDefinitions,omitempty"`
 		Tags                []*Tag                           `json:"tags,omitempty"`
 		ExternalDocs        *ExternalDocs                    `json:"externalDocs,omitempty"`
+		// Extensions defines the swagger extensions.
+		Extensions map[string]interface{} `json:"-"`
 	}
 
 	// Info provides metadata about the API. The metadata can be used by the clients if needed,
@@ -168,6 +171,8 @@ type (
 		// Ref references a global API response.
 		// This field is exclusive with the other fields of Response.
 		Ref string `json:"$ref,omitempty"`
+		// Examples gives an example of the response body for each MIME type.
+		Examples map[string]interface{} `json:"examples,omitempty"`
 		// Extensions defines the swagger extensions.
 		Extensions map[string]interface{} `json:"-"`
 	}
@@ -291,6 +296,7 @@ type (
 	}
 
 	// These types are used in marshalJSON() to avoid recursive call of json.Marshal().
+	_Swagger            Swagger
 	_Info               Info
 	_Path               Path
 	_Operation          Operation
@@ -323,6 +329,11 @@ func marshalJSON(v interface{}, extensions map[string]interface{}) ([]byte, erro
 	return merged, nil
 }
 
+// MarshalJSON returns the JSON encoding of s.
+func (s Swagger) MarshalJSON() ([]byte, error) {
+	return marshalJSON(_Swagger(s), s.Extensions)
+}
+
 // MarshalJSON returns the JSON encoding of i.
 func (i Info) MarshalJSON() ([]byte, error) {
 	return marshalJSON(_Info(i), i.Extensions)
@@ -452,18 +463,96 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cbs := callbacksFromDefinition(api, api.Callbacks); cbs != nil {
+		s.Extensions = map[string]interface{}{"x-webhooks": cbs}
+	}
+	if errs := errorCatalogFromDefinition(api); errs != nil {
+		if s.Extensions == nil {
+			s.Extensions = make(map[string]interface{})
+		}
+		s.Extensions["x-errors"] = errs
+	}
 	if len(genschema.Definitions) > 0 {
 		s.Definitions = make(map[string]*genschema.JSONSchema)
+		unions := make(map[string][]string)
+		api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+			if ut.IsUnion() {
+				for _, m := range ut.ToUnion().Types {
+					unions[m.TypeName] = append(unions[m.TypeName], genschema.TypeRef(api, ut))
+				}
+			}
+			return nil
+		})
 		for n, d := range genschema.Definitions {
-			// sad but swagger doesn't support these
-			d.Media = nil
-			d.Links = nil
+			d = swaggerSchema(d)
+			if ut, ok := api.Types[n]; ok && ut.IsUnion() {
+				initDiscriminator(d, ut.ToUnion())
+			}
+			if refs, ok := unions[n]; ok {
+				d = variantSchema(d, refs)
+			}
 			s.Definitions[n] = d
 		}
 	}
 	return s, nil
 }
 
+// swaggerSchema returns a copy of the given JSON schema without the keywords that Swagger does not
+// support. The keywords are also removed from the schemas of the properties and items.
+func swaggerSchema(s *genschema.JSONSchema) *genschema.JSONSchema {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	// sad but swagger doesn't support these
+	c.Media = nil
+	c.Links = nil
+	c.WriteOnly = false
+	c.XDeprecated = s.Deprecated
+	c.Deprecated = false
+	c.DependentRequired = nil
+	c.AllOf = nil
+	c.If = nil
+	c.Then = nil
+	c.AnyOf = nil
+	c.OneOf = nil
+	c.Items = swaggerSchema(s.Items)
+	if s.Properties != nil {
+		c.Properties = make(map[string]*genschema.JSONSchema, len(s.Properties))
+		for n, p := range s.Properties {
+			c.Properties[n] = swaggerSchema(p)
+		}
+	}
+	return &c
+}
+
+// initDiscriminator sets the discriminator of the schema of a union type. Swagger requires the
+// discriminator to be a required property of the schema and does not support oneOf, the schemas
+// of the alternatives refer to the union schema instead, see variantSchema.
+func initDiscriminator(d *genschema.JSONSchema, u *design.Union) {
+	d.Type = genschema.JSONObject
+	d.Discriminator = u.Discriminator
+	d.Required = []string{u.Discriminator}
+	d.Properties = map[string]*genschema.JSONSchema{
+		u.Discriminator: {Type: genschema.JSONString, Enum: u.Values()},
+	}
+}
+
+// variantSchema returns the schema of a member of union types given the schema of the member type
+// and the references to the schemas of the unions. Swagger describes polymorphism with allOf: the
+// schema of each member extends the schemas of its unions.
+func variantSchema(d *genschema.JSONSchema, refs []string) *genschema.JSONSchema {
+	v := &genschema.JSONSchema{Title: d.Title, Description: d.Description}
+	for _, ref := range refs {
+		v.AllOf = append(v.AllOf, &genschema.JSONSchema{Ref: ref})
+	}
+	own := *d
+	own.Title = ""
+	own.Description = ""
+	v.AllOf = append(v.AllOf, &own)
+	return v
+}
+
 // mustGenerate returns true if the metadata indicates that a Swagger specification should be
 // generated, false otherwise.
 func mustGenerate(meta dslengine.MetadataDefinition) bool {
@@ -527,6 +616,12 @@ func securityDefsFromDefinition(schemes []*design.SecuritySchemeDefinition) map[
 			Scopes:           scheme.Scopes,
 			Extensions:       extensionsFromDefinition(scheme.Metadata),
 		}
+		if scheme.In == "cookie" {
+			// Swagger 2.0 does not support API keys stored in cookies.
+			def.In = "header"
+			def.Name = "Cookie"
+			def.Description += fmt.Sprintf("\n\n**Cookie**: %s", scheme.Name)
+		}
 		if scheme.Kind == design.JWTSecurityKind {
 			if def.TokenURL != "" {
 				def.Description += fmt.Sprintf("\n\n**Token URL**: %s", def.TokenURL)
@@ -690,6 +785,56 @@ func paramsFromHeaders(action *design.ActionDefinition) []*Parameter {
 	return params
 }
 
+// paramFromCookies returns the "Cookie" header parameter documenting the resource and action
+// request cookies, nil if there are none. Swagger 2.0 does not support cookie parameters so the
+// cookies are listed in the parameter description and described in the "x-cookies" extension.
+func paramFromCookies(action *design.ActionDefinition) *Parameter {
+	cookies := make(map[string]*Parameter)
+	for _, c := range []*design.AttributeDefinition{action.Parent.Cookies, action.Cookies} {
+		if c == nil {
+			continue
+		}
+		c.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+			cookies[n] = paramFor(at, n, "cookie", c.IsRequired(n))
+			return nil
+		})
+	}
+	if len(cookies) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(cookies))
+	for n := range cookies {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	p := &Parameter{Name: "Cookie", In: "header", Type: "string"}
+	params := make([]*Parameter, len(names))
+	desc := make([]string, len(names))
+	for i, n := range names {
+		params[i] = cookies[n]
+		typ := cookies[n].Type
+		if cookies[n].Required {
+			typ += ", required"
+			p.Required = true
+		}
+		desc[i] = fmt.Sprintf("`%s` (%s)", n, typ)
+	}
+	p.Description = "Request cookies: " + strings.Join(desc, ", ")
+	p.Extensions = map[string]interface{}{"x-cookies": params}
+	return p
+}
+
+// paramsFromMultipartForm returns the "formData" parameters corresponding to the attributes of
+// the action multipart form payload.
+func paramsFromMultipartForm(action *design.ActionDefinition) []*Parameter {
+	var params []*Parameter
+	action.Payload.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		params = append(params, paramFor(at, n, "formData", action.Payload.IsRequired(n)))
+		return nil
+	})
+	return params
+}
+
 func paramFor(at *design.AttributeDefinition, name, in string, required bool) *Parameter {
 	p := &Parameter{
 		In:          in,
@@ -704,10 +849,31 @@ func paramFor(at *design.AttributeDefinition, name, in string, required bool) *P
 		p.CollectionFormat = "multi"
 	}
 	p.Extensions = extensionsFromDefinition(at.Metadata)
+	if len(at.Examples) > 0 {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["x-examples"] = examplesMap(at.Examples)
+	}
+	if at.Deprecation != nil {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["x-deprecated"] = true
+	}
 	initValidations(at, p)
 	return p
 }
 
+// examplesMap returns the given named examples indexed by name.
+func examplesMap(examples []*design.ExampleDefinition) map[string]interface{} {
+	m := make(map[string]interface{}, len(examples))
+	for _, e := range examples {
+		m[e.Name] = toStringMap(e.Value)
+	}
+	return m
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -769,12 +935,45 @@ func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design
 	if err != nil {
 		return nil, err
 	}
-	return &Response{
+	if r.Cookies != nil {
+		var names []string
+		r.Cookies.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+			names = append(names, "`"+n+"`")
+			return nil
+		})
+		if len(names) > 0 {
+			if headers == nil {
+				headers = make(map[string]*Header)
+			}
+			headers["Set-Cookie"] = &Header{
+				Description: "Response cookies: " + strings.Join(names, ", "),
+				Type:        "string",
+			}
+		}
+	}
+	resp := &Response{
 		Description: r.Description,
 		Schema:      schema,
 		Headers:     headers,
 		Extensions:  extensionsFromDefinition(r.Metadata),
-	}, nil
+	}
+	if len(r.Examples) > 0 {
+		// Swagger only supports one example per MIME type, use the first one and list all
+		// the named examples in the x-examples extension.
+		mediaType := r.MediaType
+		if mediaType == "" {
+			mediaType = "application/json"
+		}
+		if mt := api.MediaTypeWithIdentifier(mediaType); mt != nil {
+			mediaType = mt.ContentType
+		}
+		resp.Examples = map[string]interface{}{mediaType: toStringMap(r.Examples[0].Value)}
+		if resp.Extensions == nil {
+			resp.Extensions = make(map[string]interface{})
+		}
+		resp.Extensions["x-examples"] = examplesMap(r.Examples)
+	}
+	return resp, nil
 }
 
 func responseFromDefinition(s *Swagger, api *design.APIDefinition, r *design.ResponseDefinition) (*Response, error) {
@@ -801,6 +1000,25 @@ func responseFromDefinition(s *Swagger, api *design.APIDefinition, r *design.Res
 	return response, nil
 }
 
+// addCacheHeaders documents the Cache-Control and Vary headers set by the cache policy c.
+func addCacheHeaders(resp *Response, c *design.CacheDefinition) {
+	if resp.Headers == nil {
+		resp.Headers = make(map[string]*Header)
+	}
+	resp.Headers["Cache-Control"] = &Header{
+		Description: "Cache policy of the response",
+		Type:        "string",
+		Default:     c.CacheControl(),
+	}
+	if len(c.Vary) > 0 {
+		resp.Headers["Vary"] = &Header{
+			Description: "Request headers that select the cached representation",
+			Type:        "string",
+			Default:     strings.Join(c.Vary, ", "),
+		}
+	}
+}
+
 func headersFromDefinition(headers *design.AttributeDefinition) (map[string]*Header, error) {
 	if headers == nil {
 		return nil, nil
@@ -843,9 +1061,12 @@ func buildPathFromFileServer(s *Swagger, api *design.APIDefinition, fs *design.F
 		},
 	}
 	if len(wcs) > 0 {
-		schema := genschema.TypeSchema(api, design.ErrorMedia)
+		schema := genschema.TypeSchema(api, api.ErrorMedia())
 		responses["404"] = &Response{Description: "File not found", Schema: schema}
 	}
+	if fs.Cache != nil {
+		addCacheHeaders(responses["200"], fs.Cache)
+	}
 
 	operationID := fmt.Sprintf("%s#%s", fs.Parent.Name, fs.RequestPath)
 	schemes := api.Schemes
@@ -884,6 +1105,183 @@ func buildPathFromFileServer(s *Swagger, api *design.APIDefinition, fs *design.F
 	return nil
 }
 
+// callbacksFromDefinition describes the given callbacks in the format used by the "x-callbacks"
+// operation extension and the "x-webhooks" top level extension. Swagger 2.0 has no native support
+// for callbacks.
+func callbacksFromDefinition(api *design.APIDefinition, callbacks map[string]*design.CallbackDefinition) map[string]interface{} {
+	if len(callbacks) == 0 {
+		return nil
+	}
+	res := make(map[string]interface{}, len(callbacks))
+	for n, cb := range callbacks {
+		c := map[string]interface{}{"method": "POST"}
+		if cb.Description != "" {
+			c["description"] = cb.Description
+		}
+		if cb.URL != "" {
+			c["url"] = cb.URL
+		}
+		if cb.Payload != nil {
+			c["payload"] = genschema.TypeSchema(api, cb.Payload)
+		}
+		for k, v := range extensionsFromDefinition(cb.Metadata) {
+			c[k] = v
+		}
+		res[n] = c
+	}
+	return res
+}
+
+// errorCatalogFromDefinition describes all the errors declared in the design in the format used
+// by the "x-errors" top level extension. The catalog lists the status, description and response
+// body schema of each error indexed by error code.
+func errorCatalogFromDefinition(api *design.APIDefinition) map[string]interface{} {
+	res := make(map[string]interface{})
+	api.IterateErrors(func(e *design.ErrorDefinition) error {
+		c := map[string]interface{}{"status": e.Status}
+		if e.Description != "" {
+			c["description"] = e.Description
+		}
+		if schema := errorSchema(api, e); schema != nil {
+			c["schema"] = schema
+		}
+		res[e.Name] = c
+		return nil
+	})
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// errorResponses adds the responses for the errors that may be returned by the action to
+// responses unless a response with the same status is already defined. Errors that share a status
+// share the same response which uses the schema of the first error. It returns the codes of the
+// errors.
+func errorResponses(api *design.APIDefinition, action *design.ActionDefinition, responses map[string]*Response) []string {
+	var codes []string
+	added := make(map[string]bool)
+	action.IterateErrors(func(e *design.ErrorDefinition) error {
+		codes = append(codes, e.Name)
+		key := strconv.Itoa(e.Status)
+		if resp, ok := responses[key]; ok {
+			if added[key] {
+				resp.Description += ", " + e.Name
+			}
+			return nil
+		}
+		resp := &Response{Description: "Error " + e.Name, Schema: errorSchema(api, e)}
+		if !e.IsError() {
+			resp.Headers = map[string]*Header{
+				goa.ErrorCodeHeader: {Description: "Error code", Type: "string"},
+			}
+		}
+		responses[key] = resp
+		added[key] = true
+		return nil
+	})
+	return codes
+}
+
+// etagParams returns the conditional request header parameters of the action route that are
+// not already defined by the action.
+func etagParams(action *design.ActionDefinition, route *design.RouteDefinition) []*Parameter {
+	var defined design.Object
+	if action.Headers != nil {
+		defined = action.Headers.Type.ToObject()
+	}
+	var params []*Parameter
+	add := func(name, description string) {
+		if _, ok := defined[name]; ok {
+			return
+		}
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          "header",
+			Description: description,
+			Type:        "string",
+		})
+	}
+	if isSafeMethod(route.Verb) {
+		add("If-None-Match", "Entity tags of the cached representations, the response is 304 Not Modified if one matches")
+	} else {
+		add("If-Match", "Entity tags the current representation must match for the request to succeed")
+		add("If-None-Match", "Entity tags the current representation must not match for the request to succeed")
+	}
+	return params
+}
+
+// etagResponses documents the ETag header of the successful responses and adds the 304 or 412
+// responses sent to conditional requests unless already defined.
+func etagResponses(api *design.APIDefinition, route *design.RouteDefinition, responses map[string]*Response) {
+	for code, resp := range responses {
+		if resp.Ref != "" || len(code) != 3 || code[0] != '2' {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]*Header)
+		}
+		resp.Headers["ETag"] = &Header{
+			Description: "Entity tag of the representation",
+			Type:        "string",
+		}
+	}
+	if isSafeMethod(route.Verb) {
+		if _, ok := responses["304"]; !ok {
+			responses["304"] = &Response{Description: "Not Modified"}
+		}
+		return
+	}
+	if _, ok := responses["412"]; !ok {
+		responses["412"] = &Response{
+			Description: "Precondition Failed",
+			Schema:      genschema.TypeSchema(api, api.ErrorMedia()),
+		}
+	}
+}
+
+// isSafeMethod returns true if verb is GET or HEAD.
+func isSafeMethod(verb string) bool {
+	return verb == "GET" || verb == "HEAD"
+}
+
+// rateLimitResponse returns the response sent when a client exceeds the given rate limit.
+func rateLimitResponse(api *design.APIDefinition, rl *design.RateLimitDefinition) *Response {
+	return &Response{
+		Description: fmt.Sprintf("Rate limit of %d requests per %s exceeded", rl.Requests, rl.Period),
+		Schema:      genschema.TypeSchema(api, api.ErrorMedia()),
+		Headers: map[string]*Header{
+			"Retry-After": {
+				Description: "Number of seconds to wait before making another request",
+				Type:        "integer",
+			},
+			"X-RateLimit-Limit": {
+				Description: "Maximum number of requests allowed in the rate limit period",
+				Type:        "integer",
+			},
+			"X-RateLimit-Remaining": {
+				Description: "Number of requests left in the current period",
+				Type:        "integer",
+			},
+			"X-RateLimit-Reset": {
+				Description: "Time at which the limit resets in seconds since the Unix epoch",
+				Type:        "integer",
+			},
+		},
+	}
+}
+
+// errorSchema returns the schema that references the media type of the error response bodies.
+func errorSchema(api *design.APIDefinition, e *design.ErrorDefinition) *genschema.JSONSchema {
+	mt := api.MediaTypeWithIdentifier(e.MediaType)
+	if mt == nil {
+		return nil
+	}
+	schema := genschema.NewJSONSchema()
+	schema.Ref = genschema.MediaTypeRef(api, mt, design.DefaultView)
+	return schema
+}
+
 func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *design.RouteDefinition, basePath string) error {
 	action := route.Parent
 
@@ -898,6 +1296,9 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 	}
 
 	params = append(params, paramsFromHeaders(action)...)
+	if p := paramFromCookies(action); p != nil {
+		params = append(params, p)
+	}
 
 	responses := make(map[string]*Response, len(action.Responses))
 	for _, r := range action.Responses {
@@ -905,10 +1306,29 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		if err != nil {
 			return err
 		}
+		if action.Pagination != "" && r.Status == 200 && resp.Ref == "" {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]*Header)
+			}
+			resp.Headers["Link"] = &Header{
+				Description: "RFC 5988 links to the other pages of results",
+				Type:        "string",
+			}
+		}
+		if c := r.EffectiveCache(); c != nil && resp.Ref == "" {
+			addCacheHeaders(resp, c)
+		}
 		responses[strconv.Itoa(r.Status)] = resp
 	}
 
-	if action.Payload != nil {
+	if action.HasETag() {
+		params = append(params, etagParams(action, route)...)
+		etagResponses(api, route, responses)
+	}
+
+	if action.PayloadMultipart {
+		params = append(params, paramsFromMultipartForm(action)...)
+	} else if action.Payload != nil {
 		payloadSchema := genschema.TypeSchema(api, action.Payload)
 		pp := &Parameter{
 			Name:        "payload",
@@ -946,11 +1366,41 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		Parameters:   params,
 		Responses:    responses,
 		Schemes:      schemes,
-		Deprecated:   false,
+		Deprecated:   route.EffectiveDeprecation() != nil,
 		Extensions:   extensionsFromDefinition(route.Metadata),
 	}
 
+	if action.PayloadMultipart {
+		operation.Consumes = []string{"multipart/form-data"}
+	}
+
+	if rl := action.EffectiveRateLimit(); rl != nil {
+		if _, ok := responses["429"]; !ok {
+			responses["429"] = rateLimitResponse(api, rl)
+		}
+	}
+
+	if codes := errorResponses(api, action, responses); len(codes) > 0 {
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions["x-errors"] = codes
+	}
+
+	if cbs := callbacksFromDefinition(api, action.Callbacks); cbs != nil {
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions["x-callbacks"] = cbs
+	}
+
 	computeProduces(operation, s, action)
+	if produces := action.EffectiveProduces(); len(produces) > 0 {
+		operation.Produces = produces
+	}
+	if consumes := action.EffectiveConsumes(); len(consumes) > 0 && action.Payload != nil && !action.PayloadMultipart {
+		operation.Consumes = consumes
+	}
 	applySecurity(operation, action.Security)
 
 	key := design.WildcardRegex.ReplaceAllStringFunc(
@@ -1001,11 +1451,17 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 func computeProduces(operation *Operation, s *Swagger, action *design.ActionDefinition) {
 	produces := make(map[string]bool)
 	action.IterateResponses(func(resp *design.ResponseDefinition) error {
-		if resp.MediaType != "" {
+		if resp.Stream != "" {
+			produces[resp.Stream] = true
+		} else if resp.MediaType != "" {
 			produces[resp.MediaType] = true
 		}
 		return nil
 	})
+	action.IterateErrors(func(e *design.ErrorDefinition) error {
+		produces[e.MediaType] = true
+		return nil
+	})
 	subset := true
 	for p := range produces {
 		found := false
@@ -1100,31 +1556,53 @@ func initPatternValidation(def interface{}, pattern string) {
 	}
 }
 
-func initMinimumValidation(def interface{}, min *float64) {
+func initMinimumValidation(def interface{}, min *float64, exclusive bool) {
 	switch actual := def.(type) {
 	case *Parameter:
 		actual.Minimum = min
-		actual.ExclusiveMinimum = false
+		actual.ExclusiveMinimum = exclusive
 	case *Header:
 		actual.Minimum = min
-		actual.ExclusiveMinimum = false
+		actual.ExclusiveMinimum = exclusive
 	case *Items:
 		actual.Minimum = min
-		actual.ExclusiveMinimum = false
+		actual.ExclusiveMinimum = exclusive
 	}
 }
 
-func initMaximumValidation(def interface{}, max *float64) {
+func initMaximumValidation(def interface{}, max *float64, exclusive bool) {
 	switch actual := def.(type) {
 	case *Parameter:
 		actual.Maximum = max
-		actual.ExclusiveMaximum = false
+		actual.ExclusiveMaximum = exclusive
 	case *Header:
 		actual.Maximum = max
-		actual.ExclusiveMaximum = false
+		actual.ExclusiveMaximum = exclusive
 	case *Items:
 		actual.Maximum = max
-		actual.ExclusiveMaximum = false
+		actual.ExclusiveMaximum = exclusive
+	}
+}
+
+func initMultipleOfValidation(def interface{}, multiple float64) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.MultipleOf = multiple
+	case *Header:
+		actual.MultipleOf = multiple
+	case *Items:
+		actual.MultipleOf = multiple
+	}
+}
+
+func initUniqueItemsValidation(def interface{}, unique bool) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.UniqueItems = unique
+	case *Header:
+		actual.UniqueItems = unique
+	case *Items:
+		actual.UniqueItems = unique
 	}
 }
 
@@ -1167,11 +1645,15 @@ func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	initFormatValidation(def, val.Format)
 	initPatternValidation(def, val.Pattern)
 	if val.Minimum != nil {
-		initMinimumValidation(def, val.Minimum)
+		initMinimumValidation(def, val.Minimum, val.ExclusiveMinimum)
 	}
 	if val.Maximum != nil {
-		initMaximumValidation(def, val.Maximum)
+		initMaximumValidation(def, val.Maximum, val.ExclusiveMaximum)
+	}
+	if val.MultipleOf != nil {
+		initMultipleOfValidation(def, *val.MultipleOf)
 	}
+	initUniqueItemsValidation(def, val.UniqueItems)
 	if val.MinLength != nil {
 		initMinLengthValidation(def, attr.Type.IsArray(), val.MinLength)
 	}
@@ -3,6 +3,7 @@ package genswagger_test
 import (
 	"bytes"
 	"encoding/json"
+	"time"
 
 	"github.com/go-openapi/loads"
 	_ "github.com/goadesign/goa-cellar/design"
@@ -236,6 +237,476 @@ var _ = Describe("New", func() {
 			})
 		})
 
+		Context("with a union payload", func() {
+			BeforeEach(func() {
+				email := Type("EmailNotification", func() {
+					Attribute("type", String)
+					Attribute("address", String)
+					Required("type")
+				})
+				sms := Type("SMSNotification", func() {
+					Attribute("type", String)
+					Attribute("phone", String)
+					Required("type")
+				})
+				p := Type("Notification", func() {
+					OneOf(email, sms)
+					Discriminator("type")
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Payload(p)
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"discriminator":"type"`),
+				})
+			})
+
+			It("relies on the discriminator only", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				d := swagger.Definitions["Notification"]
+				Ω(d).ShouldNot(BeNil())
+				Ω(d.OneOf).Should(BeEmpty())
+				Ω(d.Properties["type"].Enum).Should(Equal([]interface{}{"EmailNotification", "SMSNotification"}))
+			})
+
+			It("describes the alternatives as extensions of the union", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				d := swagger.Definitions["EmailNotification"]
+				Ω(d).ShouldNot(BeNil())
+				Ω(d.Properties).Should(BeEmpty())
+				Ω(d.AllOf).Should(HaveLen(2))
+				Ω(d.AllOf[0].Ref).Should(Equal("#/definitions/Notification"))
+				Ω(d.AllOf[1].Properties).Should(HaveKey("address"))
+				Ω(d.AllOf[1].Required).Should(Equal([]string{"type"}))
+			})
+		})
+
+		Context("with a payload with conditional requirements", func() {
+			BeforeEach(func() {
+				p := Type("Contact", func() {
+					Attribute("method", String)
+					Attribute("email", String)
+					Attribute("phone", String)
+					RequiredIf("email", "method", "email")
+					AtLeastOneOf("email", "phone")
+					Attribute("address", func() {
+						Attribute("street", String)
+						Attribute("po_box", String)
+						AtLeastOneOf("street", "po_box")
+					})
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Payload(p)
+					})
+				})
+			})
+
+			It("does not use schema keywords unsupported by swagger", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				validateSwagger(swagger)
+				b, err := json.Marshal(swagger)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).ShouldNot(ContainSubstring(`"anyOf"`))
+				Ω(string(b)).ShouldNot(ContainSubstring(`"allOf"`))
+				Ω(string(b)).ShouldNot(ContainSubstring(`"dependentRequired"`))
+			})
+
+			It("removes the unsupported keywords from nested attributes", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				address := swagger.Definitions["Contact"].Properties["address"]
+				Ω(address).ShouldNot(BeNil())
+				Ω(address.Properties).Should(HaveKey("po_box"))
+				Ω(address.AnyOf).Should(BeEmpty())
+			})
+		})
+
+		Context("with a payload with write-only attributes", func() {
+			BeforeEach(func() {
+				p := Type("Account", func() {
+					Attribute("name", String)
+					Attribute("secret", String, func() {
+						WriteOnly()
+					})
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Payload(p)
+					})
+				})
+			})
+
+			It("omits the writeOnly keyword", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				validateSwagger(swagger)
+				Ω(swagger.Definitions["Account"].Properties).Should(HaveKey("secret"))
+				b, err := json.Marshal(swagger)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).ShouldNot(ContainSubstring(`"writeOnly"`))
+			})
+
+			It("keeps the writeOnly keyword in the JSON schema definitions", func() {
+				Ω(genschema.Definitions["Account"].Properties["secret"].WriteOnly).Should(BeTrue())
+			})
+		})
+
+		Context("with a multipart form payload", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							POST("/"),
+						)
+						MultipartForm()
+						Payload(func() {
+							Member("title", String)
+							Member("file", File)
+							Required("file")
+						})
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"consumes":["multipart/form-data"]`),
+					[]byte(`{"name":"file","in":"formData","required":true,"type":"file"}`),
+					[]byte(`{"name":"title","in":"formData","required":false,"type":"string"}`),
+				})
+			})
+		})
+
+		Context("with a paginated action", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/"),
+						)
+						Paginated(OffsetPagination)
+						Response(OK, "text/plain")
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"headers":{"Link":{"description":"RFC 5988 links to the other pages of results","type":"string"}}`),
+					[]byte(`{"name":"page","in":"query","description":"Page number starting at 1","required":false,"type":"integer","default":1,"minimum":1}`),
+				})
+			})
+		})
+
+		Context("with a deprecated action", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Deprecated("use other instead")
+						Routing(
+							GET("/"),
+						)
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"deprecated":true`),
+				})
+			})
+		})
+
+		Context("with deprecated attributes and params", func() {
+			BeforeEach(func() {
+				p := Type("Order", func() {
+					Attribute("item", String)
+					Attribute("note", String, func() {
+						Deprecated("use comments instead")
+					})
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							POST("/"),
+						)
+						Params(func() {
+							Param("legacy", String, func() {
+								Deprecated("ignored")
+							})
+						})
+						Payload(p)
+					})
+				})
+			})
+
+			It("marks them with the x-deprecated extension", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`{"in":"query","name":"legacy","required":false,"type":"string","x-deprecated":true}`),
+				})
+				note := swagger.Definitions["Order"].Properties["note"]
+				Ω(note.XDeprecated).Should(BeTrue())
+				Ω(note.Deprecated).Should(BeFalse())
+				Ω(swagger.Definitions["Order"].Properties["item"].XDeprecated).Should(BeFalse())
+			})
+		})
+
+		Context("with cookies", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/"),
+						)
+						Cookies(func() {
+							Cookie("session", String)
+							Required("session")
+						})
+						Response(OK, func() {
+							Cookies(func() {
+								Cookie("theme", String)
+							})
+						})
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`{"description":"Request cookies: ` + "`session`" + ` (string, required)","in":"header","name":"Cookie","required":true,"type":"string","x-cookies":[{"name":"session","in":"cookie","required":true,"type":"string"}]}`),
+					[]byte(`"Set-Cookie":{"description":"Response cookies: ` + "`theme`" + `","type":"string"}`),
+				})
+			})
+		})
+
+		Context("with named examples", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Params(func() {
+							Param("limit", Integer, func() {
+								Examples("small", 10)
+							})
+						})
+						Payload(func() {
+							Member("name", String)
+							Examples("minimal", map[string]interface{}{"name": "alice"})
+						})
+						Response(OK, "application/json", func() {
+							Examples("full", map[string]interface{}{"id": 1})
+						})
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`{"in":"query","name":"limit","required":false,"type":"integer","x-examples":{"small":10}}`),
+					[]byte(`"x-examples":{"minimal":{"name":"alice"}}`),
+					[]byte(`"examples":{"application/json":{"id":1}}`),
+					[]byte(`"x-examples":{"full":{"id":1}}`),
+				})
+			})
+		})
+
+		Context("with callbacks", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							POST("/"),
+						)
+						Callback("done", func() {
+							Description("Sent when done")
+							URL("{$request.body#/hook}")
+							Payload(func() {
+								Member("id", Integer)
+							})
+						})
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"x-callbacks":{"done":{"description":"Sent when done","method":"POST","payload":{"$ref":"#/definitions/DoneCallbackPayload"},"url":"{$request.body#/hook}"}}`),
+					[]byte(`"DoneCallbackPayload":{"title":"DoneCallbackPayload","type":"object"`),
+				})
+			})
+		})
+
+		Context("with errors", func() {
+			BeforeEach(func() {
+				base := Design.DSLFunc
+				Design.DSLFunc = func() {
+					base()
+					Error("maintenance", 503)
+				}
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							POST("/"),
+						)
+						Error("invalid_state", 409, func() {
+							Description("Invalid state")
+						})
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"409":{"description":"Error invalid_state","schema":{"$ref":"#/definitions/error"}}`),
+					[]byte(`"503":{"description":"Error maintenance","schema":{"$ref":"#/definitions/error"}}`),
+					[]byte(`"x-errors":["invalid_state","maintenance"]`),
+					[]byte(`"x-errors":{"invalid_state":{"description":"Invalid state","schema":{"$ref":"#/definitions/error"},"status":409},"maintenance":{"schema":{"$ref":"#/definitions/error"},"status":503}}`),
+				})
+			})
+
+			Context("and problem details", func() {
+				BeforeEach(func() {
+					base := Design.DSLFunc
+					Design.DSLFunc = func() {
+						base()
+						ProblemDetails()
+					}
+				})
+
+				It("uses the problem details media type", func() {
+					validateSwaggerWithFragments(swagger, [][]byte{
+						[]byte(`"409":{"description":"Error invalid_state","schema":{"$ref":"#/definitions/problem"}}`),
+						[]byte(`"503":{"description":"Error maintenance","schema":{"$ref":"#/definitions/problem"}}`),
+						[]byte(`"produces":["application/problem+json"]`),
+					})
+					Ω(swagger.Definitions).Should(HaveKey("problem"))
+					Ω(swagger.Definitions).ShouldNot(HaveKey("error"))
+				})
+			})
+		})
+
+		Context("with per action MIME types", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Consumes("application/json")
+					Action("create", func() {
+						Routing(POST("/bottles"))
+						Payload(func() {
+							Attribute("name", String)
+						})
+						Produces("application/xml")
+						Response(OK, "application/vnd.goa.example")
+					})
+				})
+			})
+
+			It("sets the operation MIME types", func() {
+				Ω(swagger.Paths).Should(HaveKey("/bottles"))
+				op := swagger.Paths["/bottles"].(*genswagger.Path).Post
+				Ω(op.Produces).Should(Equal([]string{"application/xml"}))
+				Ω(op.Consumes).Should(Equal([]string{"application/json"}))
+			})
+		})
+
+		Context("with a cache policy", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Files("/public/*filepath", "/www/data", func() {
+						Cache(func() {
+							MaxAge(86400)
+						})
+					})
+					Action("show", func() {
+						Routing(GET("/:id"))
+						Cache(func() {
+							MaxAge(60)
+							Private()
+							Vary("Accept")
+						})
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"headers":{"Cache-Control":{"description":"Cache policy of the response","type":"string","default":"private, max-age=60"},"Vary":{"description":"Request headers that select the cached representation","type":"string","default":"Accept"}}`),
+					[]byte(`"headers":{"Cache-Control":{"description":"Cache policy of the response","type":"string","default":"public, max-age=86400"}}`),
+				})
+			})
+		})
+
+		Context("with entity tags", func() {
+			BeforeEach(func() {
+				mt := MediaType("application/vnd.bottle", func() {
+					ETag()
+					Attributes(func() {
+						Attribute("id", Integer)
+					})
+					View("default", func() {
+						Attribute("id")
+					})
+				})
+				Resource("res", func() {
+					Action("show", func() {
+						Routing(GET("/:id"))
+						Response(OK, mt)
+					})
+					Action("update", func() {
+						Routing(PUT("/:id"))
+						ETag()
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"304":{"description":"Not Modified"}`),
+					[]byte(`"412":{"description":"Precondition Failed","schema":{"$ref":"#/definitions/error"}}`),
+					[]byte(`"headers":{"ETag":{"description":"Entity tag of the representation","type":"string"}}`),
+					[]byte(`{"name":"If-Match","in":"header","description":"Entity tags the current representation must match for the request to succeed","required":false,"type":"string"}`),
+				})
+			})
+		})
+
+		Context("with a rate limit", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					RateLimit(10, time.Minute)
+					Action("act", func() {
+						Routing(
+							POST("/"),
+						)
+					})
+				})
+			})
+
+			It("serializes into valid swagger JSON", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"429":{"description":"Rate limit of 10 requests per 1m0s exceeded","schema":{"$ref":"#/definitions/error"}`),
+					[]byte(`"Retry-After":{"description":"Number of seconds to wait before making another request","type":"integer"}`),
+					[]byte(`"X-RateLimit-Remaining":{"description":"Number of requests left in the current period","type":"integer"}`),
+				})
+			})
+		})
+
 		Context("with a payload of type Any", func() {
 			BeforeEach(func() {
 				Resource("res", func() {
@@ -0,0 +1,12 @@
+/*
+Package importer produces goa designs from existing API specifications.
+
+The Swagger function generates the source code of a design package from a Swagger 2.0 document
+encoded in JSON or YAML. The mapping is the inverse of the one implemented by the
+goagen/gen_swagger package: definitions produced from media types are grouped back into media
+types with their views and links, operations are grouped into resources and actions using the
+"resource#action" operation IDs if present and the operation tags otherwise. Constructs that
+cannot be represented in the design language are reported as warnings and omitted from the
+generated design.
+*/
+package importer
@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// spec is the subset of a Swagger 2.0 specification read by the importer. The definitions
+	// in goagen/gen_swagger cannot be used directly as they are tailored to producing
+	// specifications, e.g. they do not accept schemas as additionalProperties values.
+	spec struct {
+		Swagger             string                         `json:"swagger"`
+		Info                *info                          `json:"info"`
+		Host                string                         `json:"host"`
+		BasePath            string                         `json:"basePath"`
+		Schemes             []string                       `json:"schemes"`
+		Consumes            []string                       `json:"consumes"`
+		Produces            []string                       `json:"produces"`
+		Paths               map[string]*pathItem           `json:"paths"`
+		Definitions         map[string]*schema             `json:"definitions"`
+		Parameters          map[string]*parameter          `json:"parameters"`
+		Responses           map[string]*response           `json:"responses"`
+		SecurityDefinitions map[string]*securityDefinition `json:"securityDefinitions"`
+		Security            []map[string][]string          `json:"security"`
+		Tags                []*tag                         `json:"tags"`
+		ExternalDocs        *externalDocs                  `json:"externalDocs"`
+	}
+
+	info struct {
+		Title          string   `json:"title"`
+		Description    string   `json:"description"`
+		TermsOfService string   `json:"termsOfService"`
+		Contact        *contact `json:"contact"`
+		License        *license `json:"license"`
+		Version        string   `json:"version"`
+	}
+
+	contact struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		URL   string `json:"url"`
+	}
+
+	license struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+
+	externalDocs struct {
+		Description string `json:"description"`
+		URL         string `json:"url"`
+	}
+
+	tag struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	pathItem struct {
+		Ref        string       `json:"$ref"`
+		Get        *operation   `json:"get"`
+		Put        *operation   `json:"put"`
+		Post       *operation   `json:"post"`
+		Delete     *operation   `json:"delete"`
+		Options    *operation   `json:"options"`
+		Head       *operation   `json:"head"`
+		Patch      *operation   `json:"patch"`
+		Parameters []*parameter `json:"parameters"`
+	}
+
+	operation struct {
+		Tags        []string             `json:"tags"`
+		Summary     string               `json:"summary"`
+		Description string               `json:"description"`
+		OperationID string               `json:"operationId"`
+		Consumes    []string             `json:"consumes"`
+		Produces    []string             `json:"produces"`
+		Parameters  []*parameter         `json:"parameters"`
+		Responses   map[string]*response `json:"responses"`
+		Deprecated  bool                 `json:"deprecated"`
+		// Security is nil if the operation uses the default security requirements.
+		Security *[]map[string][]string `json:"security"`
+	}
+
+	// parameter describes an operation parameter. The type and validations of non-body
+	// parameters are read into the embedded schema.
+	parameter struct {
+		rawSchema
+		Ref         string  `json:"$ref"`
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description"`
+		Required    bool    `json:"required"`
+		Schema      *schema `json:"schema"`
+	}
+
+	response struct {
+		Ref         string             `json:"$ref"`
+		Description string             `json:"description"`
+		Schema      *schema            `json:"schema"`
+		Headers     map[string]*schema `json:"headers"`
+	}
+
+	// schema describes a Swagger schema object, it is also used for parameter, header and
+	// items objects which share the same type and validation properties.
+	schema struct {
+		Ref                  string             `json:"$ref"`
+		Type                 string             `json:"type"`
+		Format               string             `json:"format"`
+		Title                string             `json:"title"`
+		Description          string             `json:"description"`
+		Items                *schema            `json:"items"`
+		Properties           map[string]*schema `json:"properties"`
+		AdditionalProperties *schema            `json:"-"`
+		Required             []string           `json:"required"`
+		Enum                 []interface{}      `json:"enum"`
+		Default              interface{}        `json:"default"`
+		Example              interface{}        `json:"example"`
+		Pattern              string             `json:"pattern"`
+		Minimum              *float64           `json:"minimum"`
+		Maximum              *float64           `json:"maximum"`
+		ExclusiveMinimum     bool               `json:"exclusiveMinimum"`
+		ExclusiveMaximum     bool               `json:"exclusiveMaximum"`
+		MultipleOf           *float64           `json:"multipleOf"`
+		MinLength            *int               `json:"minLength"`
+		MaxLength            *int               `json:"maxLength"`
+		MinItems             *int               `json:"minItems"`
+		MaxItems             *int               `json:"maxItems"`
+		UniqueItems          bool               `json:"uniqueItems"`
+		MinProperties        *int               `json:"minProperties"`
+		MaxProperties        *int               `json:"maxProperties"`
+		ReadOnly             bool               `json:"readOnly"`
+		AllOf                []*schema          `json:"allOf"`
+		Discriminator        string             `json:"discriminator"`
+		CollectionFormat     string             `json:"collectionFormat"`
+	}
+
+	securityDefinition struct {
+		Type             string            `json:"type"`
+		Description      string            `json:"description"`
+		Name             string            `json:"name"`
+		In               string            `json:"in"`
+		Flow             string            `json:"flow"`
+		AuthorizationURL string            `json:"authorizationUrl"`
+		TokenURL         string            `json:"tokenUrl"`
+		Scopes           map[string]string `json:"scopes"`
+	}
+
+	// rawSchema is used to decode schemas without recursing into UnmarshalJSON.
+	rawSchema schema
+)
+
+// parseSpec decodes the Swagger specification encoded in JSON or YAML.
+func parseSpec(data []byte) (*spec, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("invalid Swagger specification: %s", err)
+		}
+		js, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Swagger specification: %s", err)
+		}
+		data = js
+	}
+	var s spec
+	if err := decode(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid Swagger specification: %s", err)
+	}
+	if s.Swagger != "2.0" {
+		return nil, fmt.Errorf("unsupported Swagger version %#v, only 2.0 is supported", s.Swagger)
+	}
+	return &s, nil
+}
+
+// UnmarshalJSON decodes the schema, additionalProperties may be a boolean or a schema.
+func (s *schema) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		*rawSchema
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	raw.rawSchema = (*rawSchema)(s)
+	if err := decode(b, &raw); err != nil {
+		return err
+	}
+	ap := bytes.TrimSpace(raw.AdditionalProperties)
+	switch {
+	case len(ap) > 0 && ap[0] == '{':
+		s.AdditionalProperties = new(schema)
+		return decode(ap, s.AdditionalProperties)
+	case string(ap) == "true":
+		// Any value is allowed, use an empty schema.
+		s.AdditionalProperties = new(schema)
+	}
+	return nil
+}
+
+// decode decodes JSON keeping numbers as json.Number so that large integer examples and default
+// values retain their precision.
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// jsonValue converts the maps produced by the YAML decoder into values that can be encoded
+// to JSON.
+func jsonValue(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			m[fmt.Sprintf("%v", k)] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(actual))
+		for i, e := range actual {
+			l[i] = jsonValue(e)
+		}
+		return l
+	default:
+		return v
+	}
+}
@@ -0,0 +1,1555 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"mime"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// mediaTypeTitlePrefix is the prefix of the titles of the definitions that goagen produces from
+// media types, it is followed by the identifier of the projected media type.
+const mediaTypeTitlePrefix = "Mediatype identifier: "
+
+type (
+	// swaggerImporter maps a Swagger specification to the design language.
+	swaggerImporter struct {
+		spec *spec
+		// buf contains the generated source.
+		buf *bytes.Buffer
+		// warnings lists the constructs that could not be mapped.
+		warnings []string
+		// defs indexes the mapped definitions by name.
+		defs map[string]*definition
+		// mediaTypes and types list the media types and user types to generate.
+		mediaTypes, types []*node
+		// edges indexes the nodes referenced by each node.
+		edges map[*node][]*node
+		// current is the node being generated if any.
+		current *node
+		// inlining records the definitions being inlined to detect recursion.
+		inlining map[string]bool
+		// schemes indexes the variables holding the security schemes by scheme name.
+		schemes map[string]string
+		// names records the variable names in use.
+		names map[string]bool
+		// statuses indexes the names of the standard responses by HTTP status.
+		statuses map[int]string
+		// usesDesign is true if the generated code uses identifiers of the design package.
+		usesDesign bool
+		// inHash is true while generating the type of hash values, HashOf does not accept
+		// type names.
+		inHash bool
+	}
+
+	// definition describes how a Swagger definition maps to the design.
+	definition struct {
+		// node is the media type or user type built from the definition, nil for the goa
+		// error media type and for inlined definitions.
+		node *node
+		// view is the name of the media type view rendered by the definition if any.
+		view string
+		// collection is true if the definition describes a collection of node.
+		collection bool
+		// inline is the schema of definitions that do not describe objects. The design
+		// language only supports named object types so these are inlined where used.
+		inline *schema
+	}
+
+	// node is a user type or media type produced by the import.
+	node struct {
+		// Var is the name of the variable holding the type.
+		Var string
+		// TypeName is the type name.
+		TypeName string
+		// Identifier is the media type identifier, empty for user types.
+		Identifier string
+		// Schema describes the type attributes.
+		Schema *schema
+		// Views lists the media type views.
+		Views []*view
+		// Links lists the media type links.
+		Links []*link
+		// projections indexes the goa media type projections by view name.
+		projections map[string]*schema
+		// owner is the definition node that contains the schema of anonymous types.
+		owner *node
+	}
+
+	// view is a media type view.
+	view struct {
+		Name       string
+		Attributes []string
+		// Views indexes the views used to render the attributes that are media types.
+		Views map[string]string
+	}
+
+	// link is a media type link.
+	link struct {
+		Name, View string
+	}
+
+	// resource groups the operations that map to the actions of a resource.
+	resource struct {
+		Name, Description string
+		Actions           []*action
+	}
+
+	// action describes an action built from one or more operations.
+	action struct {
+		Name   string
+		Op     *operation
+		Params []*parameter
+		Routes []string
+		where  string
+	}
+)
+
+var (
+	// dslPackages lists the import paths of the packages dot imported by the generated code.
+	dslPackages = []string{"github.com/goadesign/goa/design", "github.com/goadesign/goa/design/apidsl"}
+
+	// pathParamRegex matches the parameters of Swagger paths.
+	pathParamRegex = regexp.MustCompile(`{([^}]+)}`)
+
+	// validParamRegex matches the names of path parameters supported by goa.
+	validParamRegex = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+	// verbs lists the HTTP methods of path items in the order they are imported.
+	verbs = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH"}
+)
+
+// Swagger returns the source code of a goa design package describing the API specified by the
+// given Swagger 2.0 document. The document may be encoded in JSON or YAML, pkg is the name of the
+// generated Go package. Swagger also returns warnings describing the constructs that could not be
+// mapped to the design language and that are omitted from the generated design.
+func Swagger(data []byte, pkg string) ([]byte, []string, error) {
+	s, err := parseSpec(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	i := &swaggerImporter{
+		spec:     s,
+		buf:      new(bytes.Buffer),
+		defs:     make(map[string]*definition),
+		inlining: make(map[string]bool),
+		schemes:  make(map[string]string),
+		names:    make(map[string]bool),
+		statuses: make(map[int]string),
+	}
+	reserved, err := reservedNames()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, n := range reserved {
+		i.names[n] = true
+	}
+	for n, r := range design.NewAPIDefinition().DefaultResponses {
+		i.statuses[r.Status] = n
+		i.names[n] = true
+	}
+	i.mapDefinitions()
+	body := i.generate()
+
+	var src bytes.Buffer
+	title := "the API"
+	if s.Info != nil && s.Info.Title != "" {
+		title = strconv.Quote(s.Info.Title)
+	}
+	fmt.Fprintf(&src, "// Package %s contains the design of %s.\n// It was generated by goagen from a Swagger specification.\n", pkg, title)
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", pkg)
+	if i.usesDesign {
+		src.WriteString("\t. \"github.com/goadesign/goa/design\"\n")
+	}
+	src.WriteString("\t. \"github.com/goadesign/goa/design/apidsl\"\n)\n\n")
+	src.Write(body)
+	res, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to format generated design: %s", err) // bug
+	}
+	return res, i.warnings, nil
+}
+
+// reservedNames returns the exported identifiers of the packages dot imported by the generated
+// code. The generated variables must not use these names.
+func reservedNames() ([]string, error) {
+	var names []string
+	for _, path := range dslPackages {
+		dir, err := codegen.PackageSourcePath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate package %s: %s", path, err)
+		}
+		fset := token.NewFileSet()
+		notest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+		pkgs, err := parser.ParseDir(fset, dir, notest, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse package %s: %s", path, err)
+		}
+		for _, pkg := range pkgs {
+			for _, f := range pkg.Files {
+				for n := range f.Scope.Objects {
+					if ast.IsExported(n) {
+						names = append(names, n)
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// mapDefinitions decides how each Swagger definition maps to the design. Definitions produced by
+// goagen from media types are grouped back into media types with views, other object definitions
+// used in responses become media types with a default view and the remaining object definitions
+// become user types.
+func (i *swaggerImporter) mapDefinitions() {
+	defs := i.spec.Definitions
+	names := make([]string, 0, len(defs))
+	for n := range defs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	responseDefs := i.responseDefinitions()
+
+	// Media types produced by goagen
+	byID := make(map[string]*node)
+	linkDefs := make(map[string]bool)
+	var collections []string
+	for _, n := range names {
+		s := defs[n]
+		id, view, collection, ok := mediaTypeOf(s)
+		if !ok {
+			continue
+		}
+		switch {
+		case design.CanonicalIdentifier(id) == design.CanonicalIdentifier(design.ErrorMediaIdentifier):
+			i.defs[n] = &definition{}
+		case collection:
+			collections = append(collections, n)
+		default:
+			nd, ok := byID[id]
+			if !ok {
+				nd = &node{Identifier: id, projections: make(map[string]*schema)}
+				byID[id] = nd
+				i.mediaTypes = append(i.mediaTypes, nd)
+			}
+			nd.projections[view] = s
+			if view == "default" {
+				nd.TypeName = n
+			} else if nd.TypeName == "" {
+				nd.TypeName = strings.TrimSuffix(n, codegen.Goify(view, true))
+			}
+			i.defs[n] = &definition{node: nd, view: view}
+			if l, ok := s.Properties["links"]; ok && l.Ref != "" {
+				if ln, ok := definitionName(l.Ref); ok {
+					linkDefs[ln] = true
+				}
+			}
+		}
+	}
+	for _, n := range collections {
+		s := defs[n]
+		if s.Items == nil {
+			continue
+		}
+		if en, ok := definitionName(s.Items.Ref); ok {
+			if d, ok := i.defs[en]; ok && d.node != nil {
+				_, view, _, _ := mediaTypeOf(s)
+				i.defs[n] = &definition{node: d.node, view: view, collection: true}
+			}
+		}
+	}
+	for _, nd := range i.mediaTypes {
+		i.mergeProjections(nd, linkDefs)
+	}
+
+	// Other definitions
+	for _, n := range names {
+		if _, ok := i.defs[n]; ok || linkDefs[n] {
+			continue
+		}
+		s := i.flatten(defs[n], "definitions."+n)
+		if !isObject(s) {
+			continue
+		}
+		nd := &node{TypeName: n, Schema: s}
+		if responseDefs[n] {
+			nd.Identifier = "application/vnd." + strings.ToLower(codegen.KebabCase(n)) + "+json"
+			nd.Views = []*view{{Name: "default", Attributes: sortedKeys(s.Properties)}}
+			i.mediaTypes = append(i.mediaTypes, nd)
+			i.defs[n] = &definition{node: nd, view: "default"}
+		} else {
+			i.types = append(i.types, nd)
+			i.defs[n] = &definition{node: nd}
+		}
+	}
+	for _, n := range names {
+		if _, ok := i.defs[n]; ok || linkDefs[n] {
+			continue
+		}
+		s := defs[n]
+		if s.Type == "array" && s.Items != nil && responseDefs[n] {
+			if en, ok := definitionName(s.Items.Ref); ok {
+				if d, ok := i.defs[en]; ok && d.node != nil && d.node.Identifier != "" && !d.collection {
+					i.defs[n] = &definition{node: d.node, view: d.view, collection: true}
+					continue
+				}
+			}
+		}
+		i.defs[n] = &definition{inline: s}
+	}
+
+	// Variable names
+	for _, nd := range i.mediaTypes {
+		nd.Var = i.varName(strings.TrimSuffix(codegen.Goify(nd.TypeName, true), "Media") + "Media")
+	}
+	for _, nd := range i.types {
+		v := codegen.Goify(nd.TypeName, true)
+		if i.names[v] {
+			v += "Type"
+		}
+		nd.Var = i.varName(v)
+	}
+
+	// References between nodes
+	i.edges = make(map[*node][]*node)
+	for _, nd := range append(append([]*node{}, i.mediaTypes...), i.types...) {
+		seen := make(map[*node]bool)
+		i.walkRefs(nd.Schema, make(map[string]bool), func(target *node) {
+			if !seen[target] {
+				seen[target] = true
+				i.edges[nd] = append(i.edges[nd], target)
+			}
+		})
+	}
+}
+
+// mergeProjections builds the attributes and views of a media type produced by goagen from the
+// definitions of its projections.
+func (i *swaggerImporter) mergeProjections(nd *node, linkDefs map[string]bool) {
+	viewNames := sortedKeys(nd.projections)
+	sort.SliceStable(viewNames, func(a, b int) bool { return viewNames[a] == "default" && viewNames[b] != "default" })
+	merged := &schema{Type: "object", Properties: make(map[string]*schema)}
+	var links []string
+	for _, vn := range viewNames {
+		p := nd.projections[vn]
+		if merged.Description == "" {
+			desc := strings.TrimSuffix(p.Description, " ("+vn+" view)")
+			if desc != nd.TypeName+" media type" {
+				merged.Description = desc
+			}
+		}
+		v := &view{Name: vn, Views: make(map[string]string)}
+		for _, an := range sortedKeys(p.Properties) {
+			ps := p.Properties[an]
+			v.Attributes = append(v.Attributes, an)
+			if ln, ok := definitionName(ps.Ref); ok && an == "links" && linkDefs[ln] {
+				if ls, ok := i.spec.Definitions[ln]; ok {
+					for _, l := range sortedKeys(ls.Properties) {
+						lv := "link"
+						if tn, ok := definitionName(ls.Properties[l].Ref); ok {
+							if d, ok := i.defs[tn]; ok && d.view != "" {
+								lv = d.view
+							}
+						}
+						if !contains(links, l) {
+							links = append(links, l)
+							nd.Links = append(nd.Links, &link{Name: l, View: lv})
+						}
+					}
+				}
+				continue
+			}
+			if _, ok := merged.Properties[an]; !ok {
+				merged.Properties[an] = ps
+			}
+			if tn, ok := definitionName(ps.Ref); ok {
+				if d, ok := i.defs[tn]; ok && d.view != "" && d.view != "default" {
+					v.Views[an] = d.view
+				}
+			}
+		}
+		for _, r := range p.Required {
+			if !contains(merged.Required, r) {
+				merged.Required = append(merged.Required, r)
+			}
+		}
+		nd.Views = append(nd.Views, v)
+	}
+	if _, ok := nd.projections["default"]; !ok {
+		def := &view{Name: "default", Attributes: sortedKeys(merged.Properties)}
+		nd.Views = append([]*view{def}, nd.Views...)
+	}
+	sort.Strings(merged.Required)
+	nd.Schema = merged
+}
+
+// responseDefinitions returns the names of the definitions used to describe response bodies.
+func (i *swaggerImporter) responseDefinitions() map[string]bool {
+	res := make(map[string]bool)
+	mark := func(r *response) {
+		if r = i.resolveResponse(r); r == nil || r.Schema == nil {
+			return
+		}
+		s := r.Schema
+		if s.Type == "array" && s.Items != nil {
+			s = s.Items
+		}
+		if n, ok := definitionName(s.Ref); ok {
+			res[n] = true
+			if d, ok := i.spec.Definitions[n]; ok && d.Type == "array" && d.Items != nil {
+				if en, ok := definitionName(d.Items.Ref); ok {
+					res[en] = true
+				}
+			}
+		}
+	}
+	for _, r := range i.spec.Responses {
+		mark(r)
+	}
+	for _, item := range i.spec.Paths {
+		for _, op := range operations(item) {
+			if op == nil {
+				continue
+			}
+			for _, r := range op.Responses {
+				mark(r)
+			}
+		}
+	}
+	return res
+}
+
+// walkRefs calls fn for each node referenced by the schema.
+func (i *swaggerImporter) walkRefs(s *schema, inlined map[string]bool, fn func(*node)) {
+	if s == nil {
+		return
+	}
+	if n, ok := definitionName(s.Ref); ok {
+		if d, ok := i.defs[n]; ok {
+			if d.node != nil {
+				fn(d.node)
+			} else if d.inline != nil && !inlined[n] {
+				inlined[n] = true
+				i.walkRefs(d.inline, inlined, fn)
+			}
+		}
+		return
+	}
+	i.walkRefs(s.Items, inlined, fn)
+	i.walkRefs(s.AdditionalProperties, inlined, fn)
+	for _, p := range s.Properties {
+		i.walkRefs(p, inlined, fn)
+	}
+	for _, p := range s.AllOf {
+		i.walkRefs(p, inlined, fn)
+	}
+}
+
+// reaches returns true if there is a chain of references from the node from to the node to.
+func (i *swaggerImporter) reaches(from, to *node) bool {
+	visited := make(map[*node]bool)
+	var visit func(*node) bool
+	visit = func(n *node) bool {
+		if n == to {
+			return true
+		}
+		if visited[n] {
+			return false
+		}
+		visited[n] = true
+		for _, e := range i.edges[n] {
+			if visit(e) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(from)
+}
+
+// generate writes the API, security schemes, resources, media types and user types DSL.
+func (i *swaggerImporter) generate() []byte {
+	i.api()
+	for _, r := range i.resources() {
+		i.resource(r)
+	}
+	for _, nd := range i.mediaTypes {
+		i.mediaType(nd)
+	}
+	// anonymous types may be added to i.types while generating
+	for n := 0; n < len(i.types); n++ {
+		i.userType(i.types[n])
+	}
+	return i.buf.Bytes()
+}
+
+// api writes the API and security scheme definitions.
+func (i *swaggerImporter) api() {
+	s := i.spec
+	for _, n := range sortedKeys(s.SecurityDefinitions) {
+		i.securityScheme(n, s.SecurityDefinitions[n])
+	}
+
+	name := "api"
+	if s.Info != nil && s.Info.Title != "" {
+		name = codegen.SnakeCase(codegen.Goify(s.Info.Title, true))
+	}
+	i.p("var _ = API(%q, func() {", name)
+	if inf := s.Info; inf != nil {
+		i.stringDSL("Title", inf.Title)
+		i.stringDSL("Description", inf.Description)
+		i.stringDSL("Version", inf.Version)
+		i.stringDSL("TermsOfService", inf.TermsOfService)
+		if c := inf.Contact; c != nil {
+			i.p("Contact(func() {")
+			i.stringDSL("Name", c.Name)
+			i.stringDSL("Email", c.Email)
+			i.stringDSL("URL", c.URL)
+			i.p("})")
+		}
+		if l := inf.License; l != nil {
+			i.p("License(func() {")
+			i.stringDSL("Name", l.Name)
+			i.stringDSL("URL", l.URL)
+			i.p("})")
+		}
+	}
+	if d := s.ExternalDocs; d != nil {
+		i.p("Docs(func() {")
+		i.stringDSL("Description", d.Description)
+		i.stringDSL("URL", d.URL)
+		i.p("})")
+	}
+	i.stringDSL("Host", s.Host)
+	if len(s.Schemes) > 0 {
+		i.p("Scheme(%s)", quoteAll(s.Schemes))
+	}
+	i.stringDSL("BasePath", s.BasePath)
+	for _, enc := range []struct {
+		fn    string
+		mimes []string
+	}{{"Consumes", s.Consumes}, {"Produces", s.Produces}} {
+		for _, m := range enc.mimes {
+			if design.HasKnownEncoder(m) {
+				i.p("%s(%q)", enc.fn, m)
+			} else {
+				i.warn(strings.ToLower(enc.fn), "no known encoder for MIME type %#v, use %s with Package to specify one", m, enc.fn)
+			}
+		}
+	}
+	if len(s.Security) > 0 {
+		i.security(s.Security, "security")
+	}
+	i.p("})")
+	i.p("")
+}
+
+// securityScheme writes a security scheme definition.
+func (i *swaggerImporter) securityScheme(name string, d *securityDefinition) {
+	where := "securityDefinitions." + name
+	var fn string
+	body := i.capture(func() {
+		i.stringDSL("Description", d.Description)
+		switch d.Type {
+		case "basic":
+			fn = "BasicAuthSecurity"
+		case "apiKey":
+			fn = "APIKeySecurity"
+			switch d.In {
+			case "header":
+				i.p("Header(%q)", d.Name)
+			case "query":
+				i.p("Query(%q)", d.Name)
+			default:
+				i.warn(where, "unsupported API key location %#v", d.In)
+			}
+		case "oauth2":
+			fn = "OAuth2Security"
+			switch d.Flow {
+			case "accessCode":
+				i.p("AccessCodeFlow(%q, %q)", d.AuthorizationURL, d.TokenURL)
+			case "implicit":
+				i.p("ImplicitFlow(%q)", d.AuthorizationURL)
+			case "password":
+				i.p("PasswordFlow(%q)", d.TokenURL)
+			case "application":
+				i.p("ApplicationFlow(%q)", d.TokenURL)
+			default:
+				i.warn(where, "unsupported OAuth2 flow %#v", d.Flow)
+			}
+			for _, sc := range sortedKeys(d.Scopes) {
+				i.p("Scope(%q, %q)", sc, d.Scopes[sc])
+			}
+		}
+	})
+	if fn == "" {
+		i.warn(where, "unsupported security scheme type %#v", d.Type)
+		return
+	}
+	v := codegen.Goify(name, true)
+	if !strings.HasSuffix(v, "Auth") {
+		v += "Auth"
+	}
+	v = i.varName(v)
+	i.schemes[name] = v
+	if body == "" {
+		i.p("var %s = %s(%q)", v, fn, name)
+	} else {
+		i.p("var %s = %s(%q, func() {\n%s})", v, fn, name, body)
+	}
+	i.p("")
+}
+
+// security writes the Security DSL for the given security requirements.
+func (i *swaggerImporter) security(reqs []map[string][]string, where string) {
+	if len(reqs) == 0 {
+		i.p("NoSecurity()")
+		return
+	}
+	if len(reqs) > 1 {
+		i.warn(where, "alternative security requirements are not supported, using the first one")
+	}
+	names := sortedKeys(reqs[0])
+	if len(names) == 0 {
+		i.p("NoSecurity()")
+		return
+	}
+	if len(names) > 1 {
+		i.warn(where, "combined security schemes are not supported, using %#v", names[0])
+	}
+	v, ok := i.schemes[names[0]]
+	if !ok {
+		i.warn(where, "unknown security scheme %#v", names[0])
+		return
+	}
+	scopes := reqs[0][names[0]]
+	if len(scopes) == 0 {
+		i.p("Security(%s)", v)
+		return
+	}
+	i.p("Security(%s, func() {", v)
+	for _, sc := range scopes {
+		i.p("Scope(%q)", sc)
+	}
+	i.p("})")
+}
+
+// resources groups the operations into resources and actions. The resource and action names
+// are taken from the operation IDs produced by goagen ("resource#action") if present, otherwise
+// the operation tags and IDs are used.
+func (i *swaggerImporter) resources() []*resource {
+	var res []*resource
+	byName := make(map[string]*resource)
+	descs := make(map[string]string)
+	for _, t := range i.spec.Tags {
+		descs[t.Name] = t.Description
+	}
+	type entry struct {
+		item              *pathItem
+		op                *operation
+		path, verb, where string
+		res, act          string
+		index             int
+	}
+	var entries []*entry
+	for _, path := range sortedKeys(i.spec.Paths) {
+		item := i.spec.Paths[path]
+		if item.Ref != "" {
+			i.warn("paths."+path, "path item references are not supported")
+			continue
+		}
+		for _, verb := range verbs {
+			op := operations(item)[verb]
+			if op == nil {
+				continue
+			}
+			where := "paths." + path + "." + strings.ToLower(verb)
+			rn, an, index := operationNames(verb, path, op)
+			if strings.HasPrefix(an, "/") {
+				i.warn(where, "file servers are not supported")
+				continue
+			}
+			entries = append(entries, &entry{item, op, path, verb, where, rn, an, index})
+		}
+	}
+	// Process the additional routes of goagen actions last so they are added to the actions.
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].index < entries[b].index })
+	for _, e := range entries {
+		r, ok := byName[e.res]
+		if !ok {
+			r = &resource{Name: e.res, Description: descs[e.res]}
+			if len(e.op.Tags) > 0 && r.Description == "" {
+				r.Description = descs[e.op.Tags[0]]
+			}
+			byName[e.res] = r
+			res = append(res, r)
+		}
+		route := fmt.Sprintf("%s(%q)", e.verb, i.routePath(e.path, e.where))
+		an := e.act
+		if r.hasAction(an) {
+			if e.index > 0 {
+				for _, a := range r.Actions {
+					if a.Name == an {
+						a.Routes = append(a.Routes, route)
+					}
+				}
+				continue
+			}
+			for n := 2; r.hasAction(an); n++ {
+				an = fmt.Sprintf("%s%d", e.act, n)
+			}
+		}
+		r.Actions = append(r.Actions, &action{
+			Name:   an,
+			Op:     e.op,
+			Params: i.parameters(e.item.Parameters, e.op.Parameters, e.where),
+			Routes: []string{route},
+			where:  e.where,
+		})
+	}
+	return res
+}
+
+// resource writes a resource definition.
+func (i *swaggerImporter) resource(r *resource) {
+	i.p("var _ = Resource(%q, func() {", r.Name)
+	i.stringDSL("Description", r.Description)
+	for _, a := range r.Actions {
+		i.action(r, a)
+	}
+	i.p("})")
+	i.p("")
+}
+
+// action writes an action definition.
+func (i *swaggerImporter) action(r *resource, a *action) {
+	op := a.Op
+	i.p("Action(%q, func() {", a.Name)
+	desc := op.Description
+	if desc == "" && op.Summary != a.Name+" "+r.Name {
+		desc = op.Summary
+	}
+	i.stringDSL("Description", desc)
+	if op.Deprecated {
+		i.p("Deprecated(%q)", "")
+	}
+	i.p("Routing(%s)", strings.Join(a.Routes, ", "))
+
+	var params, headers, form []*parameter
+	var body *parameter
+	for _, p := range a.Params {
+		switch p.In {
+		case "path", "query":
+			params = append(params, p)
+		case "header":
+			headers = append(headers, p)
+		case "body":
+			body = p
+		case "formData":
+			form = append(form, p)
+		default:
+			i.warn(a.where+".parameters."+p.Name, "unsupported parameter location %#v", p.In)
+		}
+	}
+	i.parameterGroup("Params", "Param", params, a.where)
+	i.parameterGroup("Headers", "Header", headers, a.where)
+	switch {
+	case body != nil:
+		i.payload(body, a.where+".parameters."+body.Name)
+	case len(form) > 0:
+		i.p("Payload(func() {")
+		var required []string
+		for _, p := range form {
+			i.attribute("Member", p.Name, paramSchema(p), a.where+".parameters."+p.Name)
+			if p.Required {
+				required = append(required, p.Name)
+			}
+		}
+		if len(required) > 0 {
+			i.p("Required(%s)", quoteAll(required))
+		}
+		i.p("})")
+		i.p("MultipartForm()")
+	}
+	if op.Security != nil {
+		i.security(*op.Security, a.where+".security")
+	}
+	for _, code := range sortedKeys(op.Responses) {
+		i.response(code, op.Responses[code], a.where+".responses."+code)
+	}
+	i.p("})")
+}
+
+// parameterGroup writes the Params or Headers DSL.
+func (i *swaggerImporter) parameterGroup(fn, attFn string, params []*parameter, where string) {
+	if len(params) == 0 {
+		return
+	}
+	i.p("%s(func() {", fn)
+	var required []string
+	for _, p := range params {
+		i.attribute(attFn, p.Name, paramSchema(p), where+".parameters."+p.Name)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+		if p.CollectionFormat != "" && p.CollectionFormat != "multi" && p.CollectionFormat != "csv" {
+			i.warn(where+".parameters."+p.Name, "unsupported collection format %#v", p.CollectionFormat)
+		}
+	}
+	if len(required) > 0 {
+		i.p("Required(%s)", quoteAll(required))
+	}
+	i.p("})")
+}
+
+// payload writes the Payload DSL for the given body parameter.
+func (i *swaggerImporter) payload(p *parameter, where string) {
+	fn := "Payload"
+	if !p.Required {
+		fn = "OptionalPayload"
+	}
+	if p.Schema == nil {
+		i.warn(where, "missing body parameter schema")
+		return
+	}
+	s := i.flatten(i.resolve(p.Schema), where)
+	if isInlineObject(s) {
+		i.p("%s(func() {", fn)
+		i.stringDSL("Description", s.Description)
+		i.attributes("Member", s, where)
+		i.p("})")
+		return
+	}
+	i.p("%s(%s)", fn, i.dataType(s, where))
+}
+
+// response writes the Response DSL for the given response code.
+func (i *swaggerImporter) response(code string, r *response, where string) {
+	if r = i.resolveResponse(r); r == nil {
+		i.warn(where, "unknown response reference")
+		return
+	}
+	if code == "default" {
+		i.warn(where, "default responses are not supported")
+		return
+	}
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		i.warn(where, "invalid response status code %#v", code)
+		return
+	}
+	name, standard := i.statuses[status]
+	if standard {
+		i.usesDesign = true
+	} else {
+		name = codegen.Goify(http.StatusText(status), true)
+		if name == "" {
+			name = "Status" + code
+		}
+		name = strconv.Quote(name)
+	}
+	args := []string{name}
+	body := i.capture(func() {
+		if !standard {
+			i.p("Status(%d)", status)
+		}
+		if r.Description != http.StatusText(status) {
+			i.stringDSL("Description", r.Description)
+		}
+		if r.Schema != nil {
+			media, view := i.responseMedia(r.Schema, where+".schema")
+			switch {
+			case media == "":
+			case view != "":
+				i.p("Media(%s, %q)", media, view)
+			default:
+				args = append(args, media)
+			}
+		}
+		if len(r.Headers) > 0 {
+			i.p("Headers(func() {")
+			for _, h := range sortedKeys(r.Headers) {
+				i.attribute("Header", h, r.Headers[h], where+".headers."+h)
+			}
+			i.p("})")
+		}
+	})
+	if body != "" {
+		args = append(args, "func() {\n"+body+"}")
+	}
+	i.p("Response(%s)", strings.Join(args, ", "))
+}
+
+// responseMedia returns the media type and view used to render a response body.
+func (i *swaggerImporter) responseMedia(s *schema, where string) (string, string) {
+	if s.Type == "array" && s.Items != nil && s.Items.Ref != "" {
+		if n, ok := definitionName(s.Items.Ref); ok {
+			if d, ok := i.defs[n]; ok && d.node != nil && d.node.Identifier != "" && !d.collection {
+				return "CollectionOf(" + i.ref(s.Items.Ref, where) + ")", viewName(d.view)
+			}
+		}
+	}
+	if s.Ref != "" {
+		if n, ok := definitionName(s.Ref); ok {
+			if d, ok := i.defs[n]; ok && d.inline == nil {
+				return i.ref(s.Ref, where), viewName(d.view)
+			}
+		}
+	}
+	i.warn(where, "response bodies must be described by object definitions")
+	return "", ""
+}
+
+// mediaType writes a media type definition.
+func (i *swaggerImporter) mediaType(nd *node) {
+	i.current = nd
+	defer func() { i.current = nil }()
+	where := "definitions." + nd.TypeName
+	i.p("var %s = MediaType(%q, func() {", nd.Var, nd.Identifier)
+	i.stringDSL("Description", nd.Schema.Description)
+	i.p("TypeName(%q)", nd.TypeName)
+	i.p("Attributes(func() {")
+	i.attributes("Attribute", nd.Schema, where)
+	i.p("})")
+	if len(nd.Links) > 0 {
+		i.p("Links(func() {")
+		for _, l := range nd.Links {
+			if l.View == "link" {
+				i.p("Link(%q)", l.Name)
+			} else {
+				i.p("Link(%q, %q)", l.Name, l.View)
+			}
+		}
+		i.p("})")
+	}
+	for _, v := range nd.Views {
+		i.p("View(%q, func() {", v.Name)
+		for _, a := range v.Attributes {
+			if av, ok := v.Views[a]; ok {
+				i.p("Attribute(%q, func() {\nView(%q)\n})", a, av)
+			} else {
+				i.p("Attribute(%q)", a)
+			}
+		}
+		i.p("})")
+	}
+	i.p("})")
+	i.p("")
+}
+
+// userType writes a user type definition.
+func (i *swaggerImporter) userType(nd *node) {
+	i.current = nd
+	defer func() { i.current = nil }()
+	i.p("var %s = Type(%q, func() {", nd.Var, nd.TypeName)
+	i.stringDSL("Description", nd.Schema.Description)
+	i.attributes("Attribute", nd.Schema, "definitions."+nd.TypeName)
+	i.p("})")
+	i.p("")
+}
+
+// attributes writes the child attributes of an object schema.
+func (i *swaggerImporter) attributes(fn string, s *schema, where string) {
+	for _, n := range sortedKeys(s.Properties) {
+		i.attribute(fn, n, s.Properties[n], where+"."+n)
+	}
+	if len(s.Required) > 0 {
+		i.p("Required(%s)", quoteAll(s.Required))
+	}
+}
+
+// attribute writes the definition of an attribute using the given DSL function, one of
+// Attribute, Member, Param or Header.
+func (i *swaggerImporter) attribute(fn, name string, s *schema, where string) {
+	desc := s.Description
+	s = i.flatten(i.resolve(s), where)
+	if desc == "" {
+		desc = s.Description
+	}
+	if isInlineObject(s) {
+		i.p("%s(%q, func() {", fn, name)
+		i.stringDSL("Description", desc)
+		i.attributes("Attribute", s, where)
+		i.validations(s, where)
+		i.p("})")
+		return
+	}
+	args := []string{strconv.Quote(name), i.dataType(s, where)}
+	if desc != "" {
+		args = append(args, strconv.Quote(desc))
+	}
+	if body := i.capture(func() { i.validations(s, where) }); body != "" {
+		args = append(args, "func() {\n"+body+"}")
+	}
+	i.p("%s(%s)", fn, strings.Join(args, ", "))
+}
+
+// dataType returns the expression of the data type described by the schema.
+func (i *swaggerImporter) dataType(s *schema, where string) string {
+	if s.Ref != "" {
+		return i.ref(s.Ref, where)
+	}
+	switch s.Type {
+	case "string":
+		i.usesDesign = true
+		switch s.Format {
+		case "date-time":
+			return "DateTime"
+		case "uuid":
+			return "UUID"
+		}
+		return "String"
+	case "integer":
+		i.usesDesign = true
+		return "Integer"
+	case "number":
+		i.usesDesign = true
+		return "Number"
+	case "boolean":
+		i.usesDesign = true
+		return "Boolean"
+	case "file":
+		i.usesDesign = true
+		return "File"
+	case "array":
+		if s.Items == nil {
+			i.warn(where, "missing array items schema")
+			i.usesDesign = true
+			return "ArrayOf(Any)"
+		}
+		elem := i.flatten(i.resolve(s.Items), where+".items")
+		dt := i.elemType(elem, where+".items")
+		if body := i.capture(func() { i.validations(elem, where+".items") }); body != "" {
+			return "ArrayOf(" + dt + ", func() {\n" + body + "})"
+		}
+		return "ArrayOf(" + dt + ")"
+	case "object", "":
+		if len(s.Properties) > 0 {
+			return i.anonymous(s, where)
+		}
+		i.usesDesign = true
+		if s.AdditionalProperties != nil {
+			elem := i.flatten(i.resolve(s.AdditionalProperties), where+".additionalProperties")
+			if i.capture(func() { i.validations(elem, where) }) != "" {
+				i.warn(where, "validations of hash values are not supported")
+			}
+			i.inHash = true
+			defer func() { i.inHash = false }()
+			return "HashOf(String, " + i.elemType(elem, where+".additionalProperties") + ")"
+		}
+		return "Any"
+	}
+	i.warn(where, "unsupported type %#v", s.Type)
+	i.usesDesign = true
+	return "Any"
+}
+
+// elemType returns the expression of the type of array elements and hash values, inline
+// objects are mapped to anonymous user types.
+func (i *swaggerImporter) elemType(s *schema, where string) string {
+	if isInlineObject(s) {
+		return i.anonymous(s, where)
+	}
+	return i.dataType(s, where)
+}
+
+// anonymous creates a user type for an inline object schema that cannot be described inline
+// in the design language, e.g. array elements.
+func (i *swaggerImporter) anonymous(s *schema, where string) string {
+	segments := strings.Split(where, ".")
+	name := codegen.Goify(strings.Join(segments[1:], "_"), true)
+	nd := &node{TypeName: name, Schema: s, owner: i.current}
+	if i.current != nil && i.current.owner != nil {
+		nd.owner = i.current.owner
+	}
+	nd.Var = i.varName(name)
+	i.types = append(i.types, nd)
+	return nd.Var
+}
+
+// ref returns the expression of the type referenced by the given JSON reference.
+func (i *swaggerImporter) ref(ref, where string) string {
+	n, ok := definitionName(ref)
+	if !ok {
+		i.warn(where, "unsupported reference %#v", ref)
+		i.usesDesign = true
+		return "Any"
+	}
+	d, ok := i.defs[n]
+	if !ok {
+		i.warn(where, "unknown definition %#v", n)
+		i.usesDesign = true
+		return "Any"
+	}
+	if d.inline != nil {
+		if i.inlining[n] {
+			i.warn(where, "recursive definition %#v is not supported", n)
+			i.usesDesign = true
+			return "Any"
+		}
+		i.inlining[n] = true
+		defer delete(i.inlining, n)
+		return i.dataType(i.flatten(d.inline, "definitions."+n), "definitions."+n)
+	}
+	if d.node == nil {
+		i.usesDesign = true
+		return "ErrorMedia"
+	}
+	expr := d.node.Var
+	owner := i.current
+	if owner != nil && owner.owner != nil {
+		owner = owner.owner
+	}
+	if owner != nil && i.reaches(d.node, owner) {
+		// Break the Go initialization cycle by referring to the type by name.
+		if i.inHash {
+			i.warn(where, "recursive reference to %#v in hash values is not supported", d.node.TypeName)
+			i.usesDesign = true
+			return "Any"
+		}
+		if d.node.Identifier != "" {
+			// ArrayOf looks up media types by canonical identifier.
+			expr = strconv.Quote(design.CanonicalIdentifier(d.node.Identifier))
+		} else {
+			expr = strconv.Quote(d.node.TypeName)
+		}
+	}
+	if d.collection {
+		return "CollectionOf(" + expr + ")"
+	}
+	return expr
+}
+
+// validations writes the validation DSL for the schema.
+func (i *swaggerImporter) validations(s *schema, where string) {
+	if s.ReadOnly {
+		i.p("ReadOnly()")
+	}
+	if len(s.Enum) > 0 {
+		vals := make([]string, len(s.Enum))
+		for j, v := range s.Enum {
+			vals[j] = literal(v)
+		}
+		i.p("Enum(%s)", strings.Join(vals, ", "))
+	}
+	if s.Default != nil {
+		i.p("Default(%s)", literal(s.Default))
+	}
+	if s.Example != nil && s.Ref == "" && s.Type != "object" {
+		i.p("Example(%s)", literal(s.Example))
+	}
+	if s.Type == "string" {
+		switch {
+		case s.Format == "" || s.Format == "date-time" || s.Format == "uuid":
+		case contains(apidsl.SupportedValidationFormats, s.Format):
+			i.p("Format(%q)", s.Format)
+		default:
+			i.warn(where, "unsupported string format %#v", s.Format)
+		}
+	}
+	if s.Pattern != "" {
+		i.p("Pattern(%q)", s.Pattern)
+	}
+	if s.Minimum != nil {
+		if s.ExclusiveMinimum {
+			i.p("ExclusiveMinimum(%s)", number(*s.Minimum))
+		} else {
+			i.p("Minimum(%s)", number(*s.Minimum))
+		}
+	}
+	if s.Maximum != nil {
+		if s.ExclusiveMaximum {
+			i.p("ExclusiveMaximum(%s)", number(*s.Maximum))
+		} else {
+			i.p("Maximum(%s)", number(*s.Maximum))
+		}
+	}
+	if s.MultipleOf != nil {
+		i.p("MultipleOf(%s)", number(*s.MultipleOf))
+	}
+	for _, l := range []struct {
+		fn   string
+		vals []*int
+	}{{"MinLength", []*int{s.MinLength, s.MinItems}}, {"MaxLength", []*int{s.MaxLength, s.MaxItems}}} {
+		for _, v := range l.vals {
+			if v != nil {
+				i.p("%s(%d)", l.fn, *v)
+				break
+			}
+		}
+	}
+	if s.UniqueItems {
+		i.p("UniqueItems()")
+	}
+	if s.MinProperties != nil {
+		i.p("MinProperties(%d)", *s.MinProperties)
+	}
+	if s.MaxProperties != nil {
+		i.p("MaxProperties(%d)", *s.MaxProperties)
+	}
+}
+
+// flatten merges the schemas listed in allOf into a single schema.
+func (i *swaggerImporter) flatten(s *schema, where string) *schema {
+	if len(s.AllOf) == 0 {
+		return s
+	}
+	res := *s
+	res.AllOf = nil
+	res.Properties = make(map[string]*schema)
+	for n, p := range s.Properties {
+		res.Properties[n] = p
+	}
+	for _, part := range s.AllOf {
+		if n, ok := definitionName(part.Ref); ok {
+			if d, ok := i.spec.Definitions[n]; ok {
+				if i.inlining[n] {
+					continue
+				}
+				i.inlining[n] = true
+				part = i.flatten(d, "definitions."+n)
+				delete(i.inlining, n)
+			}
+		}
+		if res.Type == "" {
+			res.Type = part.Type
+		}
+		if res.Description == "" {
+			res.Description = part.Description
+		}
+		for n, p := range part.Properties {
+			if _, ok := res.Properties[n]; !ok {
+				res.Properties[n] = p
+			}
+		}
+		for _, r := range part.Required {
+			if !contains(res.Required, r) {
+				res.Required = append(res.Required, r)
+			}
+		}
+	}
+	i.warn(where, "allOf composition flattened into a single type")
+	return &res
+}
+
+// resolve returns the schema of the inlined definition referenced by s if any, s otherwise.
+func (i *swaggerImporter) resolve(s *schema) *schema {
+	if n, ok := definitionName(s.Ref); ok {
+		if d, ok := i.defs[n]; ok && d.inline != nil && !i.inlining[n] {
+			return d.inline
+		}
+	}
+	return s
+}
+
+// resolveResponse returns the response referenced by r if any, r otherwise.
+func (i *swaggerImporter) resolveResponse(r *response) *response {
+	if r == nil || r.Ref == "" {
+		return r
+	}
+	const prefix = "#/responses/"
+	if !strings.HasPrefix(r.Ref, prefix) {
+		return nil
+	}
+	return i.spec.Responses[strings.TrimPrefix(r.Ref, prefix)]
+}
+
+// parameters merges the path item and operation parameters resolving references.
+func (i *swaggerImporter) parameters(common, params []*parameter, where string) []*parameter {
+	var res []*parameter
+	for _, p := range append(append([]*parameter{}, common...), params...) {
+		if p.Ref != "" {
+			const prefix = "#/parameters/"
+			rp, ok := i.spec.Parameters[strings.TrimPrefix(p.Ref, prefix)]
+			if !strings.HasPrefix(p.Ref, prefix) || !ok {
+				i.warn(where, "unknown parameter reference %#v", p.Ref)
+				continue
+			}
+			p = rp
+		}
+		replaced := false
+		for j, e := range res {
+			if e.Name == p.Name && e.In == p.In {
+				res[j] = p
+				replaced = true
+			}
+		}
+		if !replaced {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// routePath converts a Swagger path to a goa route path.
+func (i *swaggerImporter) routePath(path, where string) string {
+	return pathParamRegex.ReplaceAllStringFunc(path, func(m string) string {
+		n := m[1 : len(m)-1]
+		if !validParamRegex.MatchString(n) {
+			i.warn(where, "invalid path parameter name %#v", n)
+		}
+		return ":" + n
+	})
+}
+
+// varName returns a unique variable name based on the given name.
+func (i *swaggerImporter) varName(name string) string {
+	v := name
+	for n := 2; i.names[v]; n++ {
+		v = fmt.Sprintf("%s%d", name, n)
+	}
+	i.names[v] = true
+	return v
+}
+
+// capture returns the code written by fn.
+func (i *swaggerImporter) capture(fn func()) string {
+	buf := i.buf
+	i.buf = new(bytes.Buffer)
+	defer func() { i.buf = buf }()
+	fn()
+	return i.buf.String()
+}
+
+// p writes a line of code.
+func (i *swaggerImporter) p(format string, args ...interface{}) {
+	fmt.Fprintf(i.buf, format+"\n", args...)
+}
+
+// stringDSL writes a call to a DSL function that accepts a single string argument if val is
+// not empty.
+func (i *swaggerImporter) stringDSL(fn, val string) {
+	if val != "" {
+		i.p("%s(%q)", fn, val)
+	}
+}
+
+// warn records a warning about the given location in the specification.
+func (i *swaggerImporter) warn(where, format string, args ...interface{}) {
+	w := where + ": " + fmt.Sprintf(format, args...)
+	if !contains(i.warnings, w) {
+		i.warnings = append(i.warnings, w)
+	}
+}
+
+// hasAction returns true if the resource has an action with the given name.
+func (r *resource) hasAction(name string) bool {
+	for _, a := range r.Actions {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// operationNames computes the resource and action names of an operation. index is the index of the
+// route for goagen operation IDs of actions with multiple routes.
+func operationNames(verb, path string, op *operation) (string, string, int) {
+	if parts := strings.Split(op.OperationID, "#"); len(parts) > 1 {
+		index := 0
+		if len(parts) > 2 {
+			index, _ = strconv.Atoi(parts[2])
+		}
+		return parts[0], parts[1], index
+	}
+	var res string
+	if len(op.Tags) > 0 {
+		res = op.Tags[0]
+	} else {
+		for _, seg := range strings.Split(path, "/") {
+			if seg != "" && !strings.HasPrefix(seg, "{") {
+				res = seg
+				break
+			}
+		}
+	}
+	if res == "" {
+		res = "root"
+	}
+	if !validParamRegex.MatchString(res) {
+		res = codegen.SnakeCase(codegen.Goify(res, true))
+	}
+	act := op.OperationID
+	if act == "" {
+		act = verb + " " + path
+	}
+	if !validParamRegex.MatchString(act) {
+		act = codegen.Goify(act, true)
+	}
+	return res, codegen.SnakeCase(act), 0
+}
+
+// operations returns the operations of a path item indexed by HTTP method.
+func operations(item *pathItem) map[string]*operation {
+	return map[string]*operation{
+		"GET":     item.Get,
+		"PUT":     item.Put,
+		"POST":    item.Post,
+		"DELETE":  item.Delete,
+		"OPTIONS": item.Options,
+		"HEAD":    item.Head,
+		"PATCH":   item.Patch,
+	}
+}
+
+// mediaTypeOf returns the media type identifier, view and collection flag of definitions
+// produced by goagen from media types.
+func mediaTypeOf(s *schema) (string, string, bool, bool) {
+	if !strings.HasPrefix(s.Title, mediaTypeTitlePrefix) {
+		return "", "", false, false
+	}
+	base, params, err := mime.ParseMediaType(strings.TrimPrefix(s.Title, mediaTypeTitlePrefix))
+	if err != nil {
+		return "", "", false, false
+	}
+	view := params["view"]
+	if view == "" {
+		view = "default"
+	}
+	delete(params, "view")
+	collection := params["type"] == "collection"
+	delete(params, "type")
+	return mime.FormatMediaType(base, params), view, collection, true
+}
+
+// paramSchema returns the schema describing a non-body parameter.
+func paramSchema(p *parameter) *schema {
+	s := schema(p.rawSchema)
+	s.Description = p.Description
+	return &s
+}
+
+// definitionName returns the name of the definition referenced by ref.
+func definitionName(ref string) (string, bool) {
+	const prefix = "#/definitions/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, prefix), true
+}
+
+// isObject returns true if the schema describes an object with named properties.
+func isObject(s *schema) bool {
+	return s.Ref == "" && (s.Type == "object" || s.Type == "" && len(s.Properties) > 0) &&
+		(len(s.Properties) > 0 || s.AdditionalProperties == nil)
+}
+
+// isInlineObject returns true if the schema describes an object with properties.
+func isInlineObject(s *schema) bool {
+	return s.Ref == "" && (s.Type == "object" || s.Type == "") && len(s.Properties) > 0
+}
+
+// viewName returns the name of the view to use in Media, empty for the default view.
+func viewName(v string) string {
+	if v == "default" {
+		return ""
+	}
+	return v
+}
+
+// literal returns the Go expression for a value decoded from JSON.
+func literal(v interface{}) string {
+	switch actual := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(actual)
+	case json.Number:
+		return actual.String()
+	case bool:
+		return strconv.FormatBool(actual)
+	case float64:
+		return number(actual)
+	case []interface{}:
+		elems := make([]string, len(actual))
+		for i, e := range actual {
+			elems[i] = literal(e)
+		}
+		return "[]interface{}{" + strings.Join(elems, ", ") + "}"
+	case map[string]interface{}:
+		elems := make([]string, 0, len(actual))
+		for _, k := range sortedKeys(actual) {
+			elems = append(elems, strconv.Quote(k)+": "+literal(actual[k]))
+		}
+		return "map[string]interface{}{" + strings.Join(elems, ", ") + "}"
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
+
+// number returns the Go literal for f.
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// quoteAll returns the comma separated list of quoted strings.
+func quoteAll(vals []string) string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = strconv.Quote(v)
+	}
+	return strings.Join(res, ", ")
+}
+
+// sortedKeys returns the sorted keys of a map with string keys.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch actual := m.(type) {
+	case map[string]*schema:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]*pathItem:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]*response:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]*securityDefinition:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]string:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string][]string:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]interface{}:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	default:
+		panic(fmt.Sprintf("unsupported map type %T", m)) // bug
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
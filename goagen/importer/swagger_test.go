@@ -0,0 +1,411 @@
+package importer_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	"github.com/goadesign/goa/goagen/importer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Swagger", func() {
+	var data []byte
+	var src string
+	var warnings []string
+	var importErr error
+
+	JustBeforeEach(func() {
+		var b []byte
+		b, warnings, importErr = importer.Swagger(data, "design")
+		src = string(b)
+	})
+
+	Context("with an invalid version", func() {
+		BeforeEach(func() {
+			data = []byte(`{"swagger": "1.2"}`)
+		})
+
+		It("returns an error", func() {
+			Ω(importErr).Should(HaveOccurred())
+			Ω(importErr.Error()).Should(ContainSubstring("unsupported Swagger version"))
+		})
+	})
+
+	Context("with a YAML specification", func() {
+		BeforeEach(func() {
+			data = []byte(petstore)
+		})
+
+		It("generates the API definition", func() {
+			Ω(importErr).ShouldNot(HaveOccurred())
+			Ω(src).Should(HavePrefix("// Package design contains the design of \"Petstore\"."))
+			Ω(src).Should(ContainSubstring(`var _ = API("petstore", func() {
+	Title("Petstore")
+	Version("1.0")
+	Host("petstore.swagger.io")
+	Scheme("https")
+	BasePath("/v1")
+	Security(APIKeyAuth)
+})`))
+		})
+
+		It("generates the security schemes", func() {
+			Ω(src).Should(ContainSubstring(`var APIKeyAuth = APIKeySecurity("api_key", func() {
+	Header("X-API-Key")
+})`))
+			Ω(src).Should(ContainSubstring(`var PetstoreAuth = OAuth2Security("petstore_auth", func() {
+	ImplicitFlow("http://petstore.swagger.io/oauth/dialog")
+	Scope("write:pets", "modify pets")
+})`))
+		})
+
+		It("generates the resources", func() {
+			Ω(src).Should(ContainSubstring(`var _ = Resource("pets", func() {
+	Description("Everything about your pets")
+	Action("list_pets", func() {
+		Description("List all pets")
+		Routing(GET("/pets"))
+		Params(func() {
+			Param("limit", Integer, func() {
+				Default(20)
+				Maximum(100)
+			})
+		})
+		Response(OK, CollectionOf(PetMedia))
+	})
+	Action("create_pets", func() {
+		Routing(POST("/pets"))
+		Payload(NewPet)
+		Security(PetstoreAuth, func() {
+			Scope("write:pets")
+		})
+		Response(Created)
+	})
+	Action("show_pet_by_id", func() {
+		Routing(GET("/pets/:petId"))
+		Params(func() {
+			Param("petId", UUID)
+			Required("petId")
+		})
+		NoSecurity()
+		Response(OK, PetMedia)
+		Response(NotFound, func() {
+			Description("Pet not found")
+		})
+	})
+})`))
+		})
+
+		It("generates media types for the definitions used in responses", func() {
+			Ω(src).Should(ContainSubstring(`var PetMedia = MediaType("application/vnd.pet+json", func() {
+	TypeName("Pet")
+	Attributes(func() {
+		Attribute("id", Integer)
+		Attribute("name", String, func() {
+			MinLength(1)
+		})
+		Attribute("owner", "Owner")
+		Required("id", "name")
+	})
+	View("default", func() {
+		Attribute("id")
+		Attribute("name")
+		Attribute("owner")
+	})
+})`))
+		})
+
+		It("generates types for the other definitions", func() {
+			Ω(src).Should(ContainSubstring(`var NewPet = Type("NewPet", func() {
+	Attribute("name", String)
+	Attribute("tags", HashOf(String, String))
+	Required("name")
+})`))
+			Ω(src).Should(ContainSubstring(`var Owner = Type("Owner", func() {
+	Attribute("email", String, func() {
+		Format("email")
+	})
+	Attribute("pets", ArrayOf("application/vnd.pet"))
+})`))
+		})
+
+		It("reports the constructs that cannot be mapped", func() {
+			Ω(warnings).Should(Equal([]string{
+				"paths./pets.get.responses.default: default responses are not supported",
+				"definitions.NewPet.name: unsupported string format \"binary\"",
+			}))
+		})
+	})
+
+	Context("with definitions named after DSL identifiers", func() {
+		var reserved map[string]bool
+
+		BeforeEach(func() {
+			reserved = exportedNames("../../design")
+			for n := range exportedNames("../../design/apidsl") {
+				reserved[n] = true
+			}
+			defs := map[string]interface{}{
+				"problem": map[string]interface{}{"type": "object"},
+			}
+			for n := range reserved {
+				defs[n] = map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"name": map[string]string{"type": "string"}},
+				}
+			}
+			spec := map[string]interface{}{
+				"swagger": "2.0",
+				"info":    map[string]string{"title": "test"},
+				"paths": map[string]interface{}{
+					"/": map[string]interface{}{
+						"get": map[string]interface{}{
+							"operationId": "show",
+							"responses": map[string]interface{}{
+								"200": map[string]interface{}{
+									"description": "OK",
+									"schema":      map[string]string{"$ref": "#/definitions/problem"},
+								},
+							},
+						},
+					},
+				},
+				"definitions": defs,
+			}
+			var err error
+			data, err = json.Marshal(spec)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("does not redeclare dot imported identifiers", func() {
+			Ω(importErr).ShouldNot(HaveOccurred())
+			Ω(reserved).Should(HaveKey("Cache"))
+			Ω(reserved).Should(HaveKey("ProblemMedia"))
+			f, err := parser.ParseFile(token.NewFileSet(), "design.go", src, 0)
+			Ω(err).ShouldNot(HaveOccurred())
+			var vars []string
+			for n, obj := range f.Scope.Objects {
+				if obj.Kind == ast.Var {
+					vars = append(vars, n)
+				}
+			}
+			Ω(vars).Should(ContainElement("CacheType"))
+			Ω(vars).Should(ContainElement("ProblemMedia2"))
+			for _, v := range vars {
+				Ω(reserved).ShouldNot(HaveKey(v))
+			}
+		})
+	})
+
+	Context("with a specification generated from a design", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			var winery = MediaType("application/vnd.winery+json", func() {
+				Attributes(func() {
+					Attribute("name", String)
+					Attribute("href", String)
+				})
+				View("default", func() {
+					Attribute("name")
+					Attribute("href")
+				})
+				View("link", func() {
+					Attribute("href")
+				})
+			})
+			var bottle = MediaType("application/vnd.bottle+json", func() {
+				Description("A bottle of wine")
+				Attributes(func() {
+					Attribute("id", Integer, "ID of bottle")
+					Attribute("winery", winery)
+					Required("id")
+				})
+				Links(func() {
+					Link("winery")
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("winery")
+					Attribute("links")
+				})
+				View("tiny", func() {
+					Attribute("id")
+				})
+			})
+			API("cellar", nil)
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/bottles/:id"), GET("/wines/:id"))
+					Params(func() {
+						Param("id", Integer)
+					})
+					Response(OK, bottle)
+				})
+				Action("list", func() {
+					Routing(GET("/bottles"))
+					Response(OK, func() {
+						Media(CollectionOf(bottle), "tiny")
+					})
+					Response(BadRequest, ErrorMedia)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			s, err := genswagger.New(Design)
+			Ω(err).ShouldNot(HaveOccurred())
+			data, err = json.Marshal(s)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("restores the actions", func() {
+			Ω(importErr).ShouldNot(HaveOccurred())
+			Ω(warnings).Should(BeEmpty())
+			Ω(src).Should(ContainSubstring(`	Action("show", func() {
+		Routing(GET("/bottles/:id"), GET("/wines/:id"))
+		Params(func() {
+			Param("id", Integer)
+			Required("id")
+		})
+		Response(OK, BottleMedia)
+	})`))
+			Ω(src).Should(ContainSubstring(`	Action("list", func() {
+		Routing(GET("/bottles"))
+		Response(OK, func() {
+			Media(CollectionOf(BottleMedia), "tiny")
+		})
+		Response(BadRequest, ErrorMedia)
+	})`))
+		})
+
+		It("restores the media type views and links", func() {
+			Ω(src).Should(MatchRegexp(`(?s)var BottleMedia = MediaType\("application/vnd.bottle\+json", func\(\) {
+	Description\("A bottle of wine"\)
+	TypeName\("Bottle"\)
+	Attributes\(func\(\) {
+		Attribute\("id", Integer, "ID of bottle", func\(\) {.*?}\)
+		Attribute\("winery", WineryMedia\)
+		Required\("id"\)
+	}\)
+	Links\(func\(\) {
+		Link\("winery"\)
+	}\)
+	View\("default", func\(\) {
+		Attribute\("id"\)
+		Attribute\("links"\)
+		Attribute\("winery"\)
+	}\)
+	View\("tiny", func\(\) {
+		Attribute\("id"\)
+	}\)
+}\)`))
+			Ω(src).Should(ContainSubstring(`	View("link", func() {
+		Attribute("href")
+	})`))
+		})
+	})
+})
+
+// exportedNames returns the exported identifiers declared by the package in the given directory.
+func exportedNames(dir string) map[string]bool {
+	notest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, notest, 0)
+	Ω(err).ShouldNot(HaveOccurred())
+	names := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for n := range f.Scope.Objects {
+				if ast.IsExported(n) {
+					names[n] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+const petstore = `swagger: "2.0"
+info:
+  title: Petstore
+  version: "1.0"
+host: petstore.swagger.io
+basePath: /v1
+schemes: [https]
+securityDefinitions:
+  api_key: {type: apiKey, name: X-API-Key, in: header}
+  petstore_auth:
+    type: oauth2
+    flow: implicit
+    authorizationUrl: http://petstore.swagger.io/oauth/dialog
+    scopes: {"write:pets": modify pets}
+security:
+  - api_key: []
+tags:
+  - {name: pets, description: Everything about your pets}
+paths:
+  /pets:
+    get:
+      summary: List all pets
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - {name: limit, in: query, type: integer, format: int32, maximum: 100, default: 20}
+      responses:
+        "200":
+          description: OK
+          schema:
+            type: array
+            items: {$ref: "#/definitions/Pet"}
+        default:
+          description: unexpected error
+    post:
+      operationId: createPets
+      tags: [pets]
+      security:
+        - petstore_auth: ["write:pets"]
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: "#/definitions/NewPet"}}
+      responses:
+        "201": {description: Created}
+  /pets/{petId}:
+    parameters:
+      - {name: petId, in: path, required: true, type: string, format: uuid}
+    get:
+      operationId: showPetById
+      tags: [pets]
+      security: []
+      responses:
+        "200":
+          description: OK
+          schema: {$ref: "#/definitions/Pet"}
+        "404": {description: Pet not found}
+definitions:
+  NewPet:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string, format: binary}
+      tags:
+        type: object
+        additionalProperties: {type: string}
+  Pet:
+    type: object
+    required: [id, name]
+    properties:
+      id: {type: integer, format: int64}
+      name: {type: string, minLength: 1}
+      owner: {$ref: "#/definitions/Owner"}
+  Owner:
+    type: object
+    properties:
+      email: {type: string, format: email}
+      pets: {type: array, items: {$ref: "#/definitions/Pet"}}
+`
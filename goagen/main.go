@@ -3,12 +3,16 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	"github.com/goadesign/goa/goagen/importer"
 	"github.com/goadesign/goa/goagen/meta"
 	"github.com/goadesign/goa/goagen/utils"
 	"github.com/goadesign/goa/version"
@@ -23,6 +27,7 @@ import (
 func main() {
 	var (
 		files            []string
+		report           []string
 		err              error
 		terminatedByUser bool
 	)
@@ -109,6 +114,14 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(swaggerCmd)
 
+	// openapiCmd implements the "openapi" command.
+	openapiCmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Generate OpenAPI 3 specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genopenapi", c) },
+	}
+	rootCmd.AddCommand(openapiCmd)
+
 	// jsCmd implements the "js" command.
 	var (
 		timeout      = time.Duration(20) * time.Second
@@ -134,6 +147,66 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(schemaCmd)
 
+	// designCmd implements the "design" command.
+	designCmd := &cobra.Command{
+		Use:   "design",
+		Short: "Generate JSON representation of design",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendesign", c) },
+	}
+	rootCmd.AddCommand(designCmd)
+
+	// lintCmd implements the "lint" command.
+	var (
+		format, severity string
+	)
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check design for common issues",
+		Run:   func(c *cobra.Command, _ []string) { report, err = run("genlint", c) },
+	}
+	lintCmd.Flags().StringVar(&format, "format", "text", "report `format`, one of text or json")
+	lintCmd.Flags().StringVar(&severity, "severity", "", "comma separated list of rule severity overrides, e.g. description=off,unused-type=error")
+	rootCmd.AddCommand(lintCmd)
+
+	// diffCmd implements the "diff" command.
+	var (
+		base     string
+		snapshot bool
+	)
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Report changes and breaking changes between two versions of a design",
+		Run: func(c *cobra.Command, _ []string) {
+			if snapshot {
+				files, err = runDiff(c)
+			} else {
+				report, err = runDiff(c)
+			}
+		},
+	}
+	diffCmd.Flags().StringVar(&base, "base", "", "base design package `import path` or path to JSON snapshot file")
+	diffCmd.Flags().StringVar(&format, "format", "text", "report `format`, one of text, json or markdown")
+	diffCmd.Flags().BoolVar(&snapshot, "snapshot", false, "write JSON snapshot of design to be used as base of later comparisons to snapshot.json in output directory")
+	rootCmd.AddCommand(diffCmd)
+
+	// importCmd implements the "import" command.
+	var (
+		importPkg string
+	)
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Generate design package from existing API specification",
+	}
+	importSwaggerCmd := &cobra.Command{
+		Use:   "swagger FILE",
+		Short: "Generate design package from Swagger 2.0 specification",
+		Run:   func(c *cobra.Command, args []string) { files, err = runImportSwagger(c, args) },
+	}
+	importSwaggerCmd.Flags().StringVar(&importPkg, "pkg", "design", "name of the generated design `package`")
+	importSwaggerCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
+	importCmd.AddCommand(importSwaggerCmd)
+	rootCmd.AddCommand(importCmd)
+
 	// genCmd implements the "gen" command.
 	var (
 		pkgPath string
@@ -224,11 +297,23 @@ package and tool and the Swagger specification for the API.
 		return
 	}
 
+	// Reports are printed as is, the failure of commands that produce a report is signaled by
+	// the exit status only.
+	if len(report) > 0 {
+		fmt.Println(strings.Join(report, "\n"))
+	}
+	if err == dslengine.ErrSilentFailure {
+		os.Exit(1)
+	}
+
 	if err != nil {
 		cleanup()
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
+	if len(files) == 0 && len(report) > 0 {
+		return
+	}
 
 	rels := make([]string, len(files))
 	cd, _ := os.Getwd()
@@ -256,6 +341,78 @@ func run(pkg string, c *cobra.Command) ([]string, error) {
 	return generate(pkgName, pkgPath, c, nil)
 }
 
+// runDiff runs the diff generator. If the base design is given as a package import path runDiff
+// first writes its JSON snapshot to a temporary directory.
+func runDiff(c *cobra.Command) ([]string, error) {
+	base := c.Flag("base").Value.String()
+	if base != "" && filepath.Ext(base) != ".json" {
+		dir, err := ioutil.TempDir("", "goagen-diff")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		flags := map[string]string{"out": dir, "design": base, "snapshot": "true"}
+		if d := c.Flag("debug"); d.Changed {
+			flags["debug"] = d.Value.String()
+		}
+		gen, err := meta.NewGenerator(
+			"gendiff.Generate",
+			[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_diff")},
+			flags,
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := gen.Generate(); err != nil {
+			return nil, err
+		}
+		if err := c.Flags().Set("base", filepath.Join(dir, gendiff.SnapshotFile)); err != nil {
+			return nil, err
+		}
+	}
+	return run("gendiff", c)
+}
+
+// runImportSwagger generates a design package from the Swagger specification whose path is given
+// as argument. Warnings about the parts of the specification that could not be imported are
+// printed to stderr.
+func runImportSwagger(c *cobra.Command, args []string) ([]string, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("usage: goagen import swagger FILE")
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return nil, err
+	}
+	pkg := c.Flag("pkg").Value.String()
+	src, warnings, err := importer.Swagger(data, pkg)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	out, err := filepath.Abs(c.Flag("out").Value.String())
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(out, pkg)
+	file := filepath.Join(dir, "design.go")
+	if c.Flag("force").Value.String() != "true" {
+		if _, err := os.Stat(file); err == nil {
+			return nil, fmt.Errorf("%s already exists, use --force to overwrite", file)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(file, src, 0644); err != nil {
+		return nil, err
+	}
+	return []string{file}, nil
+}
+
 func runGen(c *cobra.Command, args []string) ([]string, error) {
 	pkgPath := c.Flag("pkg-path").Value.String()
 	pkgSrcPath, err := codegen.PackageSourcePath(pkgPath)
@@ -1,6 +1,7 @@
 package meta
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -9,8 +10,10 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"text/template"
 
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/version"
 )
@@ -79,7 +82,9 @@ func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[strin
 	}, nil
 }
 
-// Generate compiles and runs the generator and returns the generated filenames.
+// Generate compiles and runs the generator and returns the generated filenames, that is the lines
+// printed by the generator on stdout. Generate returns these lines together with
+// dslengine.ErrSilentFailure if the generator failed with that error.
 func (m *Generator) Generate() ([]string, error) {
 	// Sanity checks
 	if os.Getenv("GOPATH") == "" {
@@ -187,18 +192,36 @@ func (m *Generator) spawn(genbin string) ([]string, error) {
 	sort.Strings(args)
 	args = append(args, "--version="+version.String())
 	args = append(args, m.CustomFlags...)
+	var stdout, stderr bytes.Buffer
 	cmd := exec.Command(genbin, args...)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("%s\n%s", err, string(out))
-	}
-	res := strings.Split(string(out), "\n")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	res := strings.Split(stdout.String(), "\n")
 	for (len(res) > 0) && (res[len(res)-1] == "") {
 		res = res[:len(res)-1]
 	}
+	if err != nil {
+		if exitStatus(err) == dslengine.SilentFailureExitCode {
+			return res, dslengine.ErrSilentFailure
+		}
+		return nil, fmt.Errorf("%s\n%s%s", err, stdout.String(), stderr.String())
+	}
+	os.Stderr.Write(stderr.Bytes())
 	return res, nil
 }
 
+// exitStatus returns the exit status of the process that failed with err, -1 if err does not
+// describe a process exit.
+func exitStatus(err error) int {
+	if ee, ok := err.(*exec.ExitError); ok {
+		if ws, ok := ee.Sys().(syscall.WaitStatus); ok {
+			return ws.ExitStatus()
+		}
+	}
+	return -1
+}
+
 const mainTmpl = `
 func main() {
 	// Check if there were errors while running the first DSL pass
@@ -0,0 +1,29 @@
+package goa
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	// IdempotencyKeyHeader is the name of the request header that contains the key clients
+	// use to identify the attempts of a given request to an idempotent action.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is the name of the response header set to "true" when the
+	// response is replayed from a previous request made with the same idempotency key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotentHandler wraps the handler of an action marked as idempotent in the design. The
+// returned handler runs h through the middleware given to the service UseIdempotency method if
+// any or calls h directly otherwise.
+func IdempotentHandler(service *Service, h Handler) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		if m := service.idempotency; m != nil {
+			return m(h)(ctx, rw, req)
+		}
+		return h(ctx, rw, req)
+	}
+}
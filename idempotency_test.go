@@ -0,0 +1,54 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IdempotentHandler", func() {
+	var service *goa.Service
+	var called bool
+	var rw *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		called = false
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return nil
+		}
+		req, _ := http.NewRequest("POST", "/orders", nil)
+		err := goa.IdempotentHandler(service, h)(context.Background(), rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("calls the handler", func() {
+		Ω(called).Should(BeTrue())
+	})
+
+	Context("with an idempotency middleware", func() {
+		BeforeEach(func() {
+			service.UseIdempotency(func(h goa.Handler) goa.Handler {
+				return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					rw.Header().Set("X-Idempotency", "true")
+					return h(ctx, rw, req)
+				}
+			})
+		})
+
+		It("runs the handler through the middleware", func() {
+			Ω(called).Should(BeTrue())
+			Ω(rw.Header().Get("X-Idempotency")).Should(Equal("true"))
+		})
+	})
+})
@@ -11,9 +11,13 @@ import (
 // ErrorHandler turns a Go error into an HTTP response. It should be placed in the middleware chain
 // below the logger middleware so the logger properly logs the HTTP response. ErrorHandler
 // understands instances of goa.ServiceError and returns the status and response body embodied in
-// them, it turns other Go error types into a 500 internal error response.
+// them, it turns other Go error types into a 500 internal error response. The responses for
+// goa.TypedError errors use the error media type and set the goa.ErrorCodeHeader header.
 // If verbose is false the details of internal errors is not included in HTTP responses.
 // If you use github.com/pkg/errors then wrapping the error will allow a trace to be printed to the logs
+// If the service ProblemDetails field is true the responses for all errors other than
+// goa.TypedError are RFC 7807 problem details, see goa.Service.SendProblem. Errors that are not
+// instances of goa.ServiceError are then described as goa.ErrInternal errors.
 func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
@@ -24,11 +28,22 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 			cause := cause(e)
 			status := http.StatusInternalServerError
 			var respBody interface{}
-			if err, ok := cause.(goa.ServiceError); ok {
+			if err, ok := cause.(*goa.TypedError); ok {
+				status = err.Status
+				respBody = err.Body
+				goa.ContextResponse(ctx).ErrorCode = err.Token()
+				rw.Header().Set("Content-Type", err.MediaType)
+				rw.Header().Set(goa.ErrorCodeHeader, err.Code)
+			} else if err, ok := cause.(goa.ServiceError); ok {
 				status = err.ResponseStatus()
 				respBody = err
 				goa.ContextResponse(ctx).ErrorCode = err.Token()
 				rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
+			} else if service.ProblemDetails {
+				// Problem details responses always describe a goa.ServiceError.
+				err := goa.ErrInternal(e)
+				respBody = err
+				goa.ContextResponse(ctx).ErrorCode = err.(goa.ServiceError).Token()
 			} else {
 				respBody = e.Error()
 				rw.Header().Set("Content-Type", "text/plain")
@@ -42,6 +57,7 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 				goa.LogError(ctx, "uncaught error", "err", fmt.Sprintf("%+v", e), "id", reqID, "msg", respBody)
 				if !verbose {
 					rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
+					rw.Header().Del(goa.ErrorCodeHeader)
 					msg := fmt.Sprintf("%s [%s]", http.StatusText(http.StatusInternalServerError), reqID)
 					respBody = goa.ErrInternal(msg)
 					// Preserve the ID of the original error as that's what gets logged, the client
@@ -51,6 +67,9 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 					}
 				}
 			}
+			if err, ok := respBody.(goa.ServiceError); ok && service.ProblemDetails {
+				return service.SendProblem(ctx, status, err)
+			}
 			return service.Send(ctx, status, respBody)
 		}
 	}
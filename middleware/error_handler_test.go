@@ -80,6 +80,38 @@ var _ = Describe("ErrorHandler", func() {
 			Ω(string(rw.Body)).Should(Equal(`"boom"` + "\n"))
 		})
 
+		Context("with problem details enabled", func() {
+			BeforeEach(func() {
+				service.ProblemDetails = true
+			})
+
+			It("renders Go errors as problem details", func() {
+				var decoded goa.ProblemDetails
+				Ω(rw.Status).Should(Equal(500))
+				Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ProblemMediaIdentifier}))
+				err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded.Status).Should(Equal(500))
+				Ω(decoded.Detail).Should(Equal("boom"))
+				Ω(decoded.Token()).ShouldNot(BeEmpty())
+			})
+
+			Context("not verbose", func() {
+				BeforeEach(func() {
+					verbose = false
+				})
+
+				It("hides the error details", func() {
+					var decoded goa.ProblemDetails
+					Ω(rw.Status).Should(Equal(500))
+					Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ProblemMediaIdentifier}))
+					err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(decoded.Detail).ShouldNot(ContainSubstring("boom"))
+				})
+			})
+		})
+
 		Context("not verbose", func() {
 			BeforeEach(func() {
 				verbose = false
@@ -161,6 +193,69 @@ var _ = Describe("ErrorHandler", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(decoded.Error()).Should(Equal(gerr.Error()))
 		})
+
+		Context("with problem details enabled", func() {
+			BeforeEach(func() {
+				service.ProblemDetails = true
+			})
+
+			It("renders goa errors as problem details", func() {
+				var decoded goa.ProblemDetails
+				Ω(rw.Status).Should(Equal(418))
+				Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ProblemMediaIdentifier}))
+				err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded.Type).Should(Equal("about:blank"))
+				Ω(decoded.Title).Should(Equal("I'm a teapot"))
+				Ω(decoded.Status).Should(Equal(418))
+				Ω(decoded.Detail).Should(Equal("teapot"))
+				Ω(decoded.Instance).Should(Equal("/foo"))
+				Ω(decoded.Extensions).Should(Equal(map[string]interface{}{
+					"id":     gerr.(goa.ServiceError).Token(),
+					"code":   "code",
+					"foobar": 42.0,
+				}))
+			})
+
+			Context("not verbose", func() {
+				BeforeEach(func() {
+					verbose = false
+					gerr = goa.ErrInternal("boom")
+				})
+
+				It("hides the error details and preserves the error ID", func() {
+					var decoded goa.ProblemDetails
+					Ω(rw.Status).Should(Equal(500))
+					Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ProblemMediaIdentifier}))
+					err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(decoded.Detail).ShouldNot(ContainSubstring("boom"))
+					Ω(decoded.Token()).Should(Equal(gerr.(goa.ServiceError).Token()))
+				})
+			})
+		})
+	})
+
+	Context("with a handler returning a typed error", func() {
+		var terr *goa.TypedError
+
+		BeforeEach(func() {
+			service = newService(nil)
+			terr = goa.NewTypedError("out_of_stock", 409, "application/vnd.out-of-stock", map[string]int{"available": 2})
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return terr
+			}
+		})
+
+		It("uses the error media type and sets the error code header", func() {
+			var decoded map[string]int
+			Ω(rw.Status).Should(Equal(409))
+			Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{"application/vnd.out-of-stock"}))
+			Ω(rw.ParentHeader[goa.ErrorCodeHeader]).Should(Equal([]string{"out_of_stock"}))
+			err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(decoded).Should(Equal(map[string]int{"available": 2}))
+		})
 	})
 
 	Context("with a handler returning a pkg errors wrapped error", func() {
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/goadesign/goa"
+
+	"golang.org/x/net/context"
+)
+
+type (
+	// ETagResolver returns the entity tag of the current representation of the resource
+	// targeted by req or the empty string if the resource does not exist.
+	ETagResolver func(ctx context.Context, req *http.Request) (string, error)
+
+	// etagResponseWriter wraps an http.ResponseWriter and buffers the response so that the
+	// ETag middleware may compute its entity tag. Flushing the writer stops the buffering so
+	// that streamed responses keep working.
+	etagResponseWriter struct {
+		http.ResponseWriter
+		status    int
+		buf       bytes.Buffer
+		streaming bool
+	}
+)
+
+// ETag returns a middleware that implements conditional requests for the actions whose
+// responses carry an entity tag.
+//
+// For GET and HEAD requests the middleware sets the ETag header of successful responses to a
+// weak entity tag computed from the encoded body unless the handler already set one, see the
+// SetETag method of the generated contexts. It responds with 304 Not Modified if the entity tag
+// matches the If-None-Match request header.
+//
+// For the other methods the middleware calls resolver if not nil to retrieve the current entity
+// tag of the resource and rejects the requests whose If-Match or If-None-Match headers do not
+// match with a 412 Precondition Failed response. Handlers may also call the CheckPreconditions
+// method of the generated contexts instead.
+//
+//	service.UseETag(middleware.ETag(nil))
+func ETag(resolver ETagResolver) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			if req.Method != "GET" && req.Method != "HEAD" {
+				if resolver != nil && (req.Header.Get("If-Match") != "" || req.Header.Get("If-None-Match") != "") {
+					current, err := resolver(ctx, req)
+					if err != nil {
+						return err
+					}
+					if err := goa.CheckPreconditions(req, current); err != nil {
+						return err
+					}
+				}
+				return h(ctx, rw, req)
+			}
+
+			// chain a new buffering writer to the current response writer.
+			resp := goa.ContextResponse(ctx)
+			erw := &etagResponseWriter{ResponseWriter: resp.SwitchWriter(nil)}
+			resp.SwitchWriter(erw)
+			err := h(ctx, rw, req)
+			resp.SwitchWriter(erw.ResponseWriter)
+			if erw.streaming || erw.status == 0 {
+				return err
+			}
+			header := erw.Header()
+			if erw.status == http.StatusOK && err == nil {
+				etag := header.Get("ETag")
+				if etag == "" && erw.buf.Len() > 0 {
+					etag = goa.WeakETag(erw.buf.Bytes())
+					header.Set("ETag", etag)
+				}
+				if inm := req.Header.Get("If-None-Match"); inm != "" && goa.ETagMatch(inm, etag, true) {
+					header.Del("Content-Type")
+					header.Del("Content-Length")
+					erw.ResponseWriter.WriteHeader(http.StatusNotModified)
+					resp.Status, resp.Length = http.StatusNotModified, 0
+					return nil
+				}
+			}
+			erw.ResponseWriter.WriteHeader(erw.status)
+			if _, werr := erw.ResponseWriter.Write(erw.buf.Bytes()); werr != nil && err == nil {
+				err = werr
+			}
+			return err
+		}
+	}
+}
+
+// WriteHeader records the status code, the header is written once the handler returns.
+func (erw *etagResponseWriter) WriteHeader(status int) {
+	if erw.streaming {
+		erw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if erw.status == 0 {
+		erw.status = status
+	}
+}
+
+// Write buffers buf until the handler returns.
+func (erw *etagResponseWriter) Write(buf []byte) (int, error) {
+	if erw.streaming {
+		return erw.ResponseWriter.Write(buf)
+	}
+	if erw.status == 0 {
+		erw.status = http.StatusOK
+	}
+	return erw.buf.Write(buf)
+}
+
+// Flush writes the buffered response to the underlying response writer, stops buffering and
+// flushes the underlying response writer if it supports flushing.
+func (erw *etagResponseWriter) Flush() {
+	if !erw.streaming {
+		erw.streaming = true
+		if erw.status == 0 {
+			erw.status = http.StatusOK
+		}
+		erw.ResponseWriter.WriteHeader(erw.status)
+		erw.ResponseWriter.Write(erw.buf.Bytes())
+		erw.buf.Reset()
+	}
+	if f, ok := erw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
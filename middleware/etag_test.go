@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ETag", func() {
+	var service *goa.Service
+	var resolver middleware.ETagResolver
+	var etag string
+	var calls int
+	var h goa.Handler
+
+	BeforeEach(func() {
+		service = newService(new(testLogger))
+		resolver = nil
+		etag = ""
+		calls = 0
+		h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			calls++
+			if etag != "" {
+				rw.Header().Set("ETag", etag)
+			}
+			return service.Send(ctx, 200, map[string]int{"id": 1})
+		}
+	})
+
+	send := func(method string, header http.Header) (*testResponseWriter, *goa.ResponseData, error) {
+		req, err := http.NewRequest(method, "/bottles/1", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rw := newTestResponseWriter()
+		ctx := newContext(service, rw, req, nil)
+		err = middleware.ETag(resolver)(h)(ctx, goa.ContextResponse(ctx), req)
+		return rw, goa.ContextResponse(ctx), err
+	}
+
+	It("computes a weak entity tag from the body", func() {
+		rw, _, err := send("GET", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Status).Should(Equal(200))
+		Ω(string(rw.Body)).Should(Equal(`{"id":1}` + "\n"))
+		Ω(rw.Header().Get("ETag")).Should(HavePrefix(`W/"`))
+	})
+
+	It("responds with 304 when the entity tag matches If-None-Match", func() {
+		rw, _, _ := send("GET", nil)
+		tag := rw.Header().Get("ETag")
+
+		rw, resp, err := send("GET", http.Header{"If-None-Match": {`"other", ` + tag}})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Status).Should(Equal(304))
+		Ω(rw.Body).Should(BeEmpty())
+		Ω(rw.Header().Get("ETag")).Should(Equal(tag))
+		Ω(rw.Header()).ShouldNot(HaveKey("Content-Type"))
+		Ω(resp.Status).Should(Equal(304))
+	})
+
+	Context("with a handler setting the entity tag", func() {
+		BeforeEach(func() {
+			etag = `"v1"`
+		})
+
+		It("uses the handler entity tag", func() {
+			rw, _, err := send("GET", http.Header{"If-None-Match": {`"v0"`}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Status).Should(Equal(200))
+			Ω(rw.Header().Get("ETag")).Should(Equal(`"v1"`))
+
+			rw, _, err = send("GET", http.Header{"If-None-Match": {`W/"v1"`}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Status).Should(Equal(304))
+		})
+	})
+
+	Context("with a resolver", func() {
+		BeforeEach(func() {
+			resolver = func(ctx context.Context, req *http.Request) (string, error) {
+				return `"v2"`, nil
+			}
+		})
+
+		It("enforces If-Match on unsafe methods", func() {
+			_, _, err := send("PUT", http.Header{"If-Match": {`"v1"`}})
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(412))
+			Ω(calls).Should(Equal(0))
+
+			_, _, err = send("PUT", http.Header{"If-Match": {`"v2"`}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(calls).Should(Equal(1))
+		})
+	})
+})
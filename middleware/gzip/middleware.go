@@ -41,6 +41,15 @@ func (grw gzipResponseWriter) Write(b []byte) (int, error) {
 	return grw.gzw.Write(b)
 }
 
+// Flush writes any pending compressed data to the underlying response writer and flushes it
+// if it supports flushing.
+func (grw gzipResponseWriter) Flush() {
+	grw.gzw.Flush()
+	if f, ok := grw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // handler struct contains the ServeHTTP method
 type handler struct {
 	pool sync.Pool
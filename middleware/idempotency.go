@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goadesign/goa"
+
+	"golang.org/x/net/context"
+)
+
+var (
+	// ErrIdempotencyConflict is the error returned by the Idempotency middleware when a request
+	// uses the same idempotency key as a request still being processed.
+	ErrIdempotencyConflict = goa.NewErrorClass("idempotency_conflict", 409)
+
+	// ErrIdempotencyKeyMismatch is the error returned by the Idempotency middleware when a
+	// request reuses the idempotency key of a request with a different method, path or body.
+	ErrIdempotencyKeyMismatch = goa.NewErrorClass("idempotency_key_mismatch", 422)
+
+	// ErrIdempotencyKeyInUse is the error returned by IdempotencyStore.Begin when another
+	// request with the same key is in progress.
+	ErrIdempotencyKeyInUse = errors.New("idempotency key in use")
+)
+
+type (
+	// IdempotencyStore records the responses of the requests made with an idempotency key.
+	// Implementations must be safe for concurrent use.
+	IdempotencyStore interface {
+		// Begin reserves key for a new request. It returns the response recorded for key if
+		// any, nil if the key was reserved or ErrIdempotencyKeyInUse if another request with
+		// the same key is in progress. Reservations expire after ttl.
+		Begin(key string, ttl time.Duration) (*IdempotentResponse, error)
+		// Commit records the response for key, the response expires after ttl.
+		Commit(key string, resp *IdempotentResponse, ttl time.Duration) error
+		// Cancel releases the reservation of key without recording a response so that the
+		// request may be retried.
+		Cancel(key string) error
+	}
+
+	// IdempotencyScopeFunc identifies the principal that made a request. Recorded responses are
+	// only replayed to requests made by the same principal with the same idempotency key.
+	IdempotencyScopeFunc func(ctx context.Context, req *http.Request) string
+
+	// IdempotentResponse is a response recorded by the Idempotency middleware.
+	IdempotentResponse struct {
+		// Status is the response HTTP status code.
+		Status int
+		// Header contains the response headers.
+		Header http.Header
+		// Body is the response body.
+		Body []byte
+		// Fingerprint identifies the request the response was recorded for. It is computed
+		// from the request method, path and body.
+		Fingerprint string
+	}
+
+	// memoryIdempotencyStore is the in-memory implementation of IdempotencyStore.
+	memoryIdempotencyStore struct {
+		sync.Mutex
+		entries   map[string]*idempotencyEntry
+		lastSweep time.Time
+	}
+
+	// idempotencyEntry is a reservation or a recorded response held by memoryIdempotencyStore.
+	idempotencyEntry struct {
+		resp      *IdempotentResponse
+		expiresAt time.Time
+	}
+
+	// recordingResponseWriter wraps an http.ResponseWriter and records the status, headers
+	// and body of the response.
+	recordingResponseWriter struct {
+		http.ResponseWriter
+		status int
+		header http.Header
+		body   []byte
+	}
+)
+
+// idempotencySweepInterval is the interval at which memoryIdempotencyStore removes the expired
+// entries.
+const idempotencySweepInterval = time.Minute
+
+// Idempotency returns a middleware that records the first response to requests made with an
+// Idempotency-Key header and replays it to requests made with the same key for the duration of
+// ttl. Requests made with a key still being processed are rejected with a 409 response and
+// requests that reuse a key with a different method, path or body with a 422 response. Requests
+// with no key are rejected with a 400 response if required is true and handled normally
+// otherwise. Responses with a 5xx status and errors returned by the handler are not recorded so
+// that clients may retry.
+//
+// Keys are scoped to the principal identified by scope, the request method and the path so
+// that a client may not obtain the response recorded for another client by reusing its key.
+// Requests for which scope is nil or returns an empty string are scoped to the client IP
+// address instead. The middleware applies to the actions marked as idempotent in the design:
+//
+//	store := middleware.NewMemoryIdempotencyStore()
+//	service.UseIdempotency(middleware.Idempotency(store, 24*time.Hour, false, ratelimit.JWTSubject))
+func Idempotency(store IdempotencyStore, ttl time.Duration, required bool, scope IdempotencyScopeFunc) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			key := req.Header.Get(goa.IdempotencyKeyHeader)
+			if key == "" {
+				if required {
+					return goa.MissingHeaderError(goa.IdempotencyKeyHeader)
+				}
+				return h(ctx, rw, req)
+			}
+			var principal string
+			if scope != nil {
+				principal = scope(ctx, req)
+			}
+			if principal == "" {
+				principal = clientIP(req)
+			}
+			key = principal + "|" + req.Method + " " + req.URL.Path + " " + key
+			fingerprint, err := requestFingerprint(req)
+			if err != nil {
+				return err
+			}
+			recorded, err := store.Begin(key, ttl)
+			if err == ErrIdempotencyKeyInUse {
+				return ErrIdempotencyConflict("a request with the same idempotency key is in progress")
+			}
+			if err != nil {
+				return err
+			}
+			if recorded != nil {
+				if recorded.Fingerprint != fingerprint {
+					return ErrIdempotencyKeyMismatch("the idempotency key was used with a different request")
+				}
+				for k, v := range recorded.Header {
+					rw.Header()[k] = v
+				}
+				rw.Header().Set(goa.IdempotentReplayedHeader, "true")
+				rw.WriteHeader(recorded.Status)
+				_, err = rw.Write(recorded.Body)
+				return err
+			}
+
+			// chain a new recording writer to the current response writer.
+			resp := goa.ContextResponse(ctx)
+			rec := &recordingResponseWriter{ResponseWriter: resp.SwitchWriter(nil)}
+			resp.SwitchWriter(rec)
+			err = h(ctx, rw, req)
+			resp.SwitchWriter(rec.ResponseWriter)
+			if err != nil || rec.status == 0 || rec.status >= 500 {
+				if cerr := store.Cancel(key); cerr != nil {
+					goa.LogError(ctx, "idempotency", "err", cerr)
+				}
+				return err
+			}
+			recorded = &IdempotentResponse{Status: rec.status, Header: rec.header, Body: rec.body, Fingerprint: fingerprint}
+			if cerr := store.Commit(key, recorded, ttl); cerr != nil {
+				goa.LogError(ctx, "idempotency", "err", cerr)
+			}
+			return nil
+		}
+	}
+}
+
+// requestFingerprint returns a hash of the request method, path and body. It reads the body and
+// replaces it with a reader of its content so that the handler may read it again.
+func requestFingerprint(req *http.Request) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.Path + "\n"))
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// clientIP returns the IP address the request was sent from.
+func clientIP(req *http.Request) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return ip
+}
+
+// NewMemoryIdempotencyStore returns an IdempotencyStore that keeps the responses in memory.
+// Expired entries are removed periodically.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{entries: make(map[string]*idempotencyEntry), lastSweep: time.Now()}
+}
+
+// Begin implements IdempotencyStore.
+func (s *memoryIdempotencyStore) Begin(key string, ttl time.Duration) (*IdempotentResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) > idempotencySweepInterval {
+		for k, e := range s.entries {
+			if now.After(e.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		if e.resp == nil {
+			return nil, ErrIdempotencyKeyInUse
+		}
+		return e.resp, nil
+	}
+	s.entries[key] = &idempotencyEntry{expiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+// Commit implements IdempotencyStore.
+func (s *memoryIdempotencyStore) Commit(key string, resp *IdempotentResponse, ttl time.Duration) error {
+	s.Lock()
+	defer s.Unlock()
+	s.entries[key] = &idempotencyEntry{resp: resp, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Cancel implements IdempotencyStore.
+func (s *memoryIdempotencyStore) Cancel(key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// WriteHeader records the status and a copy of the headers then writes the header to the
+// underlying response writer.
+func (rrw *recordingResponseWriter) WriteHeader(status int) {
+	if rrw.status == 0 {
+		rrw.status = status
+		rrw.header = make(http.Header, len(rrw.Header()))
+		for k, v := range rrw.Header() {
+			rrw.header[k] = append([]string(nil), v...)
+		}
+	}
+	rrw.ResponseWriter.WriteHeader(status)
+}
+
+// Write records buf then writes it to the underlying response writer.
+func (rrw *recordingResponseWriter) Write(buf []byte) (int, error) {
+	if rrw.status == 0 {
+		rrw.WriteHeader(http.StatusOK)
+	}
+	rrw.body = append(rrw.body, buf...)
+	return rrw.ResponseWriter.Write(buf)
+}
+
+// Flush flushes the underlying response writer if it supports flushing.
+func (rrw *recordingResponseWriter) Flush() {
+	if f, ok := rrw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
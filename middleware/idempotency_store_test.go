@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMemoryIdempotencyStoreSweep(t *testing.T) {
+	s := NewMemoryIdempotencyStore().(*memoryIdempotencyStore)
+	for i := 0; i < 10; i++ {
+		if _, err := s.Begin(strconv.Itoa(i), time.Millisecond); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	time.Sleep(2 * time.Millisecond)
+	if _, err := s.Begin("live", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(s.entries) != 11 {
+		t.Errorf("got %d entries before the sweep interval elapsed, expected 11", len(s.entries))
+	}
+
+	s.lastSweep = time.Now().Add(-2 * idempotencySweepInterval)
+	if _, err := s.Begin("new", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(s.entries) != 2 {
+		t.Errorf("got %d entries after the sweep, expected 2", len(s.entries))
+	}
+	if _, ok := s.entries["live"]; !ok {
+		t.Errorf("sweep removed an entry that has not expired")
+	}
+}
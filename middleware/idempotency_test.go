@@ -0,0 +1,206 @@
+package middleware_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Idempotency", func() {
+	var service *goa.Service
+	var store middleware.IdempotencyStore
+	var required bool
+	var scope middleware.IdempotencyScopeFunc
+	var body string
+	var calls int
+	var h goa.Handler
+
+	BeforeEach(func() {
+		service = newService(new(testLogger))
+		store = middleware.NewMemoryIdempotencyStore()
+		required = false
+		scope = func(ctx context.Context, req *http.Request) string {
+			return req.Header.Get("X-User")
+		}
+		body = `{"item":"bottle"}`
+		calls = 0
+		h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			calls++
+			rw.Header().Set("Location", "/orders/1")
+			rw.WriteHeader(201)
+			rw.Write([]byte(`{"id":1}`))
+			return nil
+		}
+	})
+
+	sendAs := func(user, key string) (*testResponseWriter, error) {
+		req, err := http.NewRequest("POST", "/orders", strings.NewReader(body))
+		Ω(err).ShouldNot(HaveOccurred())
+		req.RemoteAddr = "10.0.0.1:4242"
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rw := newTestResponseWriter()
+		ctx := newContext(service, rw, req, nil)
+		err = middleware.Idempotency(store, time.Hour, required, scope)(h)(ctx, goa.ContextResponse(ctx), req)
+		return rw, err
+	}
+
+	send := func(key string) (*testResponseWriter, error) {
+		return sendAs("alice", key)
+	}
+
+	It("replays the first response to requests with the same key", func() {
+		rw, err := send("abc")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Status).Should(Equal(201))
+		Ω(rw.Header()).ShouldNot(HaveKey("Idempotent-Replayed"))
+
+		rw, err = send("abc")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(calls).Should(Equal(1))
+		Ω(rw.Status).Should(Equal(201))
+		Ω(string(rw.Body)).Should(Equal(`{"id":1}`))
+		Ω(rw.Header().Get("Location")).Should(Equal("/orders/1"))
+		Ω(rw.Header().Get("Idempotent-Replayed")).Should(Equal("true"))
+	})
+
+	It("rejects requests reusing a key with a different body", func() {
+		_, err := send("abc")
+		Ω(err).ShouldNot(HaveOccurred())
+		body = `{"item":"glass"}`
+		_, err = send("abc")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(422))
+		Ω(calls).Should(Equal(1))
+	})
+
+	It("lets the handler read the request body", func() {
+		var read []byte
+		h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			read, _ = ioutil.ReadAll(req.Body)
+			rw.WriteHeader(201)
+			return nil
+		}
+		_, err := send("abc")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(read)).Should(Equal(body))
+	})
+
+	It("runs the handler for requests with different keys or no key", func() {
+		send("abc")
+		send("def")
+		send("")
+		Ω(calls).Should(Equal(3))
+	})
+
+	It("does not replay responses to other principals", func() {
+		_, err := sendAs("alice", "abc")
+		Ω(err).ShouldNot(HaveOccurred())
+		rw, err := sendAs("bob", "abc")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(calls).Should(Equal(2))
+		Ω(rw.Header()).ShouldNot(HaveKey("Idempotent-Replayed"))
+	})
+
+	Context("with no scope", func() {
+		BeforeEach(func() {
+			scope = nil
+		})
+
+		It("scopes keys to the client IP address", func() {
+			sendAs("alice", "abc")
+			rw, err := sendAs("bob", "abc")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(calls).Should(Equal(1))
+			Ω(rw.Header().Get("Idempotent-Replayed")).Should(Equal("true"))
+			recorded, err := store.Begin("10.0.0.1|POST /orders abc", time.Hour)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(recorded).ShouldNot(BeNil())
+		})
+	})
+
+	Context("with a request in progress", func() {
+		BeforeEach(func() {
+			_, err := store.Begin("alice|POST /orders abc", time.Hour)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("rejects the duplicate request with a conflict error", func() {
+			_, err := send("abc")
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(409))
+			Ω(calls).Should(Equal(0))
+		})
+	})
+
+	Context("with a handler returning an error", func() {
+		BeforeEach(func() {
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				calls++
+				return goa.ErrInternal("boom")
+			}
+		})
+
+		It("does not record the response", func() {
+			_, err := send("abc")
+			Ω(err).Should(HaveOccurred())
+			_, err = send("abc")
+			Ω(err).Should(HaveOccurred())
+			Ω(calls).Should(Equal(2))
+		})
+	})
+
+	Context("with a required key", func() {
+		BeforeEach(func() {
+			required = true
+		})
+
+		It("rejects requests with no key", func() {
+			_, err := send("")
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(400))
+			Ω(calls).Should(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("NewMemoryIdempotencyStore", func() {
+	var store middleware.IdempotencyStore
+
+	BeforeEach(func() {
+		store = middleware.NewMemoryIdempotencyStore()
+	})
+
+	It("expires reservations and responses", func() {
+		resp, err := store.Begin("key", time.Millisecond)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp).Should(BeNil())
+		_, err = store.Begin("key", time.Millisecond)
+		Ω(err).Should(Equal(middleware.ErrIdempotencyKeyInUse))
+
+		time.Sleep(2 * time.Millisecond)
+		_, err = store.Begin("key", time.Hour)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(store.Commit("key", &middleware.IdempotentResponse{Status: 200}, time.Millisecond)).Should(Succeed())
+		resp, err = store.Begin("key", time.Hour)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp.Status).Should(Equal(200))
+
+		time.Sleep(2 * time.Millisecond)
+		resp, err = store.Begin("key", time.Hour)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp).Should(BeNil())
+	})
+})
@@ -22,6 +22,14 @@ func (lrw *loggingResponseWriter) Write(buf []byte) (int, error) {
 	return lrw.ResponseWriter.Write(buf)
 }
 
+// Flush flushes the underlying response writer if it supports flushing so that streamed
+// responses keep working when the middleware is mounted.
+func (lrw *loggingResponseWriter) Flush() {
+	if f, ok := lrw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // LogResponse creates a response logger middleware.
 // Only Logs the raw response data without accumulating any statistics.
 func LogResponse() goa.Middleware {
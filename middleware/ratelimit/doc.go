@@ -0,0 +1,17 @@
+/*
+Package ratelimit provides the rate limiters used to enforce the limits defined with the RateLimit
+DSL. A rate limiter combines a Limiter that implements the rate limiting algorithm, a Store that
+keeps the limiter state and a KeyFunc that identifies the clients:
+
+	store := ratelimit.NewMemoryStore()
+	service.UseRateLimiter(ratelimit.New(ratelimit.NewTokenBucket(store), ratelimit.ClientIP))
+
+The generated controllers apply the rate limiter to the actions that define a rate limit. The
+responses include the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers.
+Requests that exceed the limit are rejected with a 429 response that includes the Retry-After
+header.
+
+NewMemoryStore keeps the state in memory so that each service instance enforces its own limits,
+services running multiple instances may implement Store on top of a shared database instead.
+*/
+package ratelimit
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+type (
+	// tokenBucket implements the token bucket algorithm.
+	tokenBucket struct {
+		store Store
+	}
+
+	// slidingWindow implements the sliding window counter algorithm.
+	slidingWindow struct {
+		store Store
+	}
+)
+
+// NewTokenBucket returns a Limiter that implements the token bucket algorithm: each client gets
+// a bucket of requests tokens refilled continuously at the rate of requests per period. Clients
+// may burst up to requests requests and then make requests at the refill rate.
+func NewTokenBucket(store Store) Limiter {
+	return &tokenBucket{store: store}
+}
+
+// NewSlidingWindow returns a Limiter that implements the sliding window counter algorithm: the
+// number of requests made in the last period is estimated from the counts of the current and
+// previous fixed windows weighted by their overlap with the last period.
+func NewSlidingWindow(store Store) Limiter {
+	return &slidingWindow{store: store}
+}
+
+// Allow implements Limiter.
+func (l *tokenBucket) Allow(key string, requests int, period time.Duration) (*Result, error) {
+	res := &Result{Limit: requests}
+	rate := float64(requests) / float64(period)
+	err := l.store.Update(key, period, func(s *State) {
+		now := time.Now()
+		if s.Start.IsZero() {
+			s.Count = float64(requests)
+		} else {
+			s.Count = math.Min(float64(requests), s.Count+float64(now.Sub(s.Start))*rate)
+		}
+		s.Start = now
+		if s.Count >= 1 {
+			s.Count--
+			res.Allowed = true
+		} else {
+			res.RetryAfter = time.Duration((1 - s.Count) / rate)
+		}
+		res.Remaining = int(s.Count)
+		res.Reset = now.Add(time.Duration((float64(requests) - s.Count) / rate))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Allow implements Limiter.
+func (l *slidingWindow) Allow(key string, requests int, period time.Duration) (*Result, error) {
+	res := &Result{Limit: requests}
+	err := l.store.Update(key, 2*period, func(s *State) {
+		now := time.Now()
+		start := now.Truncate(period)
+		if !s.Start.Equal(start) {
+			if s.Start.Add(period).Equal(start) {
+				s.Previous = s.Count
+			} else {
+				s.Previous = 0
+			}
+			s.Count = 0
+			s.Start = start
+		}
+		weight := 1 - float64(now.Sub(start))/float64(period)
+		estimate := s.Previous*weight + s.Count
+		if estimate+1 <= float64(requests) {
+			s.Count++
+			estimate++
+			res.Allowed = true
+		} else if s.Count+1 <= float64(requests) && s.Previous > 0 {
+			// Wait until enough of the previous window slides out.
+			w := (float64(requests) - s.Count - 1) / s.Previous
+			res.RetryAfter = start.Add(time.Duration((1 - w) * float64(period))).Sub(now)
+		} else {
+			res.RetryAfter = start.Add(period).Sub(now)
+		}
+		res.Remaining = int(math.Max(0, float64(requests)-math.Ceil(estimate)))
+		res.Reset = start.Add(period)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
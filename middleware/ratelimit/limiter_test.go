@@ -0,0 +1,77 @@
+package ratelimit_test
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/middleware/ratelimit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewTokenBucket", func() {
+	var limiter ratelimit.Limiter
+
+	BeforeEach(func() {
+		limiter = ratelimit.NewTokenBucket(ratelimit.NewMemoryStore())
+	})
+
+	It("allows bursts and refills the bucket over time", func() {
+		for i := 0; i < 3; i++ {
+			res, err := limiter.Allow("key", 3, 30*time.Millisecond)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Allowed).Should(BeTrue())
+			Ω(res.Remaining).Should(Equal(2 - i))
+		}
+		res, err := limiter.Allow("key", 3, 30*time.Millisecond)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeFalse())
+		Ω(res.RetryAfter).Should(BeNumerically(">", 0))
+		Ω(res.RetryAfter).Should(BeNumerically("<=", 10*time.Millisecond))
+
+		time.Sleep(res.RetryAfter)
+		res, err = limiter.Allow("key", 3, 30*time.Millisecond)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeTrue())
+	})
+})
+
+var _ = Describe("NewSlidingWindow", func() {
+	var limiter ratelimit.Limiter
+
+	BeforeEach(func() {
+		limiter = ratelimit.NewSlidingWindow(ratelimit.NewMemoryStore())
+	})
+
+	It("limits the number of requests in the window", func() {
+		for i := 0; i < 2; i++ {
+			res, err := limiter.Allow("key", 2, time.Hour)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Allowed).Should(BeTrue())
+			Ω(res.Remaining).Should(Equal(1 - i))
+		}
+		res, err := limiter.Allow("key", 2, time.Hour)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeFalse())
+		Ω(res.Remaining).Should(Equal(0))
+		Ω(res.RetryAfter).Should(BeNumerically(">", 0))
+		Ω(res.Reset).Should(Equal(time.Now().Truncate(time.Hour).Add(time.Hour)))
+
+		res, err = limiter.Allow("other", 2, time.Hour)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeTrue())
+	})
+
+	It("weighs the previous window", func() {
+		period := 50 * time.Millisecond
+		// Start at the beginning of a window.
+		time.Sleep(time.Now().Truncate(period).Add(period).Sub(time.Now()))
+		for i := 0; i < 4; i++ {
+			res, _ := limiter.Allow("key", 4, period)
+			Ω(res.Allowed).Should(BeTrue())
+		}
+		// Move to the beginning of the next window, the previous window still counts.
+		time.Sleep(time.Now().Truncate(period).Add(period).Sub(time.Now()))
+		res, _ := limiter.Allow("key", 4, period)
+		Ω(res.Allowed).Should(BeFalse())
+	})
+})
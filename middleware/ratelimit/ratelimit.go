@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/security/jwt"
+)
+
+const (
+	// LimitHeader is the name of the response header that contains the maximum number of
+	// requests allowed in the rate limit period.
+	LimitHeader = "X-RateLimit-Limit"
+
+	// RemainingHeader is the name of the response header that contains the number of requests
+	// left in the current period.
+	RemainingHeader = "X-RateLimit-Remaining"
+
+	// ResetHeader is the name of the response header that contains the time at which the
+	// limit resets expressed as the number of seconds since the Unix epoch.
+	ResetHeader = "X-RateLimit-Reset"
+)
+
+// ErrRateLimitExceeded is the error returned when a client exceeds the rate limit.
+var ErrRateLimitExceeded = goa.NewErrorClass("rate_limit_exceeded", 429)
+
+type (
+	// Limiter implements a rate limiting algorithm.
+	Limiter interface {
+		// Allow records a request made by the client identified by key and reports whether
+		// the request is within the limit of requests per period.
+		Allow(key string, requests int, period time.Duration) (*Result, error)
+	}
+
+	// Result describes the outcome of a call to Limiter.Allow.
+	Result struct {
+		// Allowed is true if the request is within the limit.
+		Allowed bool
+		// Limit is the maximum number of requests allowed in the period.
+		Limit int
+		// Remaining is the number of requests the client may still make.
+		Remaining int
+		// Reset is the time at which the limit fully resets.
+		Reset time.Time
+		// RetryAfter is the time the client must wait before making another request
+		// when the request is not allowed.
+		RetryAfter time.Duration
+	}
+
+	// KeyFunc identifies the client that made a request. Requests made by clients with the
+	// same key count against the same limit.
+	KeyFunc func(ctx context.Context, req *http.Request) string
+)
+
+// New returns a rate limiter that enforces limits using limiter and identifies clients with key.
+// Requests for which key returns an empty string are identified by the client IP instead. Use
+// the rate limiter with the service UseRateLimiter method so that it applies to the actions that
+// define a rate limit in the design, or create the middleware directly:
+//
+//	rl := ratelimit.New(ratelimit.NewSlidingWindow(ratelimit.NewMemoryStore()), ratelimit.APIKey("X-Api-Key"))
+//	service.Use(rl("api", 1000, time.Hour))
+func New(limiter Limiter, key KeyFunc) goa.RateLimiter {
+	return func(scope string, requests int, period time.Duration) goa.Middleware {
+		return func(h goa.Handler) goa.Handler {
+			return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				k := key(ctx, req)
+				if k == "" {
+					k = ClientIP(ctx, req)
+				}
+				res, err := limiter.Allow(scope+"|"+k, requests, period)
+				if err != nil {
+					return err
+				}
+				header := rw.Header()
+				header.Set(LimitHeader, strconv.Itoa(res.Limit))
+				header.Set(RemainingHeader, strconv.Itoa(res.Remaining))
+				header.Set(ResetHeader, strconv.FormatInt(res.Reset.Unix(), 10))
+				if !res.Allowed {
+					header.Set("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+					return ErrRateLimitExceeded(fmt.Sprintf("rate limit of %d requests per %s exceeded", requests, period))
+				}
+				return h(ctx, rw, req)
+			}
+		}
+	}
+}
+
+// ClientIP identifies clients by the IP address the request was sent from. Services behind a
+// proxy or a load balancer should use a KeyFunc that reads the address from the header set by the
+// proxy instead.
+func ClientIP(ctx context.Context, req *http.Request) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return ip
+}
+
+// APIKey identifies clients by the value of the given request header.
+func APIKey(header string) KeyFunc {
+	return func(ctx context.Context, req *http.Request) string {
+		return req.Header.Get(header)
+	}
+}
+
+// JWTSubject identifies clients by the "sub" claim of the JWT validated by the jwt security
+// middleware. The security middleware runs before the rate limiter on the actions that use JWT
+// security.
+func JWTSubject(ctx context.Context, req *http.Request) string {
+	token := jwt.ContextJWT(ctx)
+	if token == nil {
+		return ""
+	}
+	claims, ok := token.Claims.(jwtgo.MapClaims)
+	if !ok {
+		return ""
+	}
+	sub, _ := claims["sub"].(string)
+	return sub
+}
@@ -0,0 +1,99 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/ratelimit"
+	"github.com/goadesign/goa/middleware/security/jwt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var key ratelimit.KeyFunc
+	var calls int
+	var h goa.Handler
+
+	BeforeEach(func() {
+		key = ratelimit.ClientIP
+		calls = 0
+	})
+
+	JustBeforeEach(func() {
+		rl := ratelimit.New(ratelimit.NewTokenBucket(ratelimit.NewMemoryStore()), key)
+		h = rl("bottle#create", 2, time.Minute)(func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			calls++
+			return nil
+		})
+	})
+
+	send := func(remoteAddr, apiKey string) (*httptest.ResponseRecorder, error) {
+		req, _ := http.NewRequest("POST", "/bottles", nil)
+		req.RemoteAddr = remoteAddr
+		if apiKey != "" {
+			req.Header.Set("X-Api-Key", apiKey)
+		}
+		rw := httptest.NewRecorder()
+		return rw, h(context.Background(), rw, req)
+	}
+
+	It("limits the requests made by a client", func() {
+		rw, err := send("10.0.0.1:1234", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("X-RateLimit-Limit")).Should(Equal("2"))
+		Ω(rw.Header().Get("X-RateLimit-Remaining")).Should(Equal("1"))
+		Ω(rw.Header().Get("X-RateLimit-Reset")).ShouldNot(BeEmpty())
+
+		_, err = send("10.0.0.1:4321", "")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		rw, err = send("10.0.0.1:1234", "")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(429))
+		Ω(rw.Header().Get("X-RateLimit-Remaining")).Should(Equal("0"))
+		Ω(rw.Header().Get("Retry-After")).Should(Equal("30"))
+		Ω(calls).Should(Equal(2))
+
+		_, err = send("10.0.0.2:1234", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(calls).Should(Equal(3))
+	})
+
+	Context("using API keys", func() {
+		BeforeEach(func() {
+			key = ratelimit.APIKey("X-Api-Key")
+		})
+
+		It("identifies clients by API key and falls back to the client IP", func() {
+			send("10.0.0.1:1234", "a")
+			send("10.0.0.1:1234", "a")
+			_, err := send("10.0.0.1:1234", "b")
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = send("10.0.0.1:1234", "a")
+			Ω(err).Should(HaveOccurred())
+			send("10.0.0.1:1234", "")
+			_, err = send("10.0.0.1:1234", "")
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = send("10.0.0.1:1234", "")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("JWTSubject", func() {
+	It("returns the subject of the token", func() {
+		token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, jwtgo.MapClaims{"sub": "alice"})
+		ctx := jwt.WithJWT(context.Background(), token)
+		Ω(ratelimit.JWTSubject(ctx, nil)).Should(Equal("alice"))
+	})
+
+	It("returns an empty string when there is no token", func() {
+		Ω(ratelimit.JWTSubject(context.Background(), nil)).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Store keeps the state of the limiters.
+	Store interface {
+		// Update calls fn with the state stored under key, or a zero state if there is
+		// none or if it expired, then stores the state modified by fn. The stored state
+		// expires after ttl. Concurrent calls for the same key must not interleave.
+		Update(key string, ttl time.Duration, fn func(*State)) error
+	}
+
+	// State is the state of a limiter for a given client.
+	State struct {
+		// Count is the number of tokens left in the bucket for token bucket limiters
+		// or the number of requests made in the current window for sliding window
+		// limiters.
+		Count float64
+		// Previous is the number of requests made in the previous window for sliding
+		// window limiters.
+		Previous float64
+		// Start is the time of the last refill for token bucket limiters or the start
+		// of the current window for sliding window limiters.
+		Start time.Time
+	}
+
+	// memoryStore is the in-memory implementation of Store.
+	memoryStore struct {
+		sync.Mutex
+		entries   map[string]*memoryEntry
+		lastSweep time.Time
+	}
+
+	// memoryEntry is a state held by memoryStore.
+	memoryEntry struct {
+		state     State
+		expiresAt time.Time
+	}
+)
+
+// sweepInterval is the interval at which memoryStore removes the expired states.
+const sweepInterval = time.Minute
+
+// NewMemoryStore returns a Store that keeps the states in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: make(map[string]*memoryEntry), lastSweep: time.Now()}
+}
+
+// Update implements Store.
+func (s *memoryStore) Update(key string, ttl time.Duration, fn func(*State)) error {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) > sweepInterval {
+		for k, e := range s.entries {
+			if now.After(e.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	e, ok := s.entries[key]
+	if !ok || now.After(e.expiresAt) {
+		e = &memoryEntry{}
+		s.entries[key] = e
+	}
+	fn(&e.state)
+	e.expiresAt = now.Add(ttl)
+	return nil
+}
@@ -56,19 +56,28 @@ func New(resolver KeyResolver, validationFunc goa.Middleware, scheme *goa.JWTSec
 	return func(nextHandler goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 			// TODO: implement the QUERY string handler too
-			if scheme.In != goa.LocHeader {
-				return fmt.Errorf("whoops, security scheme with location (in) %q not supported", scheme.In)
-			}
-			val := req.Header.Get(scheme.Name)
-			if val == "" {
-				return ErrJWTError(fmt.Sprintf("missing header %q", scheme.Name))
-			}
+			var incomingToken string
+			switch scheme.In {
+			case goa.LocHeader:
+				val := req.Header.Get(scheme.Name)
+				if val == "" {
+					return ErrJWTError(fmt.Sprintf("missing header %q", scheme.Name))
+				}
 
-			if !strings.HasPrefix(strings.ToLower(val), "bearer ") {
-				return ErrJWTError(fmt.Sprintf("invalid or malformed %q header, expected 'Bearer JWT-token...'", val))
-			}
+				if !strings.HasPrefix(strings.ToLower(val), "bearer ") {
+					return ErrJWTError(fmt.Sprintf("invalid or malformed %q header, expected 'Bearer JWT-token...'", val))
+				}
 
-			incomingToken := strings.Split(val, " ")[1]
+				incomingToken = strings.Split(val, " ")[1]
+			case goa.LocCookie:
+				cookie, err := req.Cookie(scheme.Name)
+				if err != nil || cookie.Value == "" {
+					return ErrJWTError(fmt.Sprintf("missing cookie %q", scheme.Name))
+				}
+				incomingToken = cookie.Value
+			default:
+				return fmt.Errorf("whoops, security scheme with location (in) %q not supported", scheme.In)
+			}
 
 			rsaKeys, ecdsaKeys, hmacKeys := partitionKeys(resolver.SelectKeys(req))
 
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa"
+)
+
+// ErrInvalidWebhook is the error returned by VerifyWebhook when a webhook request is not properly
+// signed or is too old.
+var ErrInvalidWebhook = goa.NewErrorClass("invalid_webhook", 401)
+
+// VerifyWebhook returns a HTTP middleware that checks the signature of webhook requests sent by
+// client.WebhookSender. Requests with a missing or invalid signature are rejected with a 401
+// response. tolerance is the maximum age of the request timestamp, 0 disables the check.
+//
+// The signature is computed over the raw request body. goa decodes the request body before
+// running the service and controller middleware so VerifyWebhook must wrap the service mux
+// instead:
+//
+//	secret := []byte(os.Getenv("WEBHOOK_SECRET"))
+//	http.ListenAndServe(":8080", middleware.VerifyWebhook(secret, 5*time.Minute)(service.Mux))
+func VerifyWebhook(secret []byte, tolerance time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			ts, err := strconv.ParseInt(req.Header.Get(goa.WebhookTimestampHeader), 10, 64)
+			if err != nil {
+				rejectWebhook(rw, ErrInvalidWebhook("missing or invalid webhook timestamp"))
+				return
+			}
+			if tolerance > 0 {
+				if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+					rejectWebhook(rw, ErrInvalidWebhook("webhook timestamp outside of tolerance"))
+					return
+				}
+			}
+			var body []byte
+			if req.Body != nil {
+				if body, err = ioutil.ReadAll(req.Body); err != nil {
+					rejectWebhook(rw, goa.ErrBadRequest(err))
+					return
+				}
+				req.Body.Close()
+			}
+			sig := req.Header.Get(goa.WebhookSignatureHeader)
+			if !goa.VerifyWebhookSignature(secret, ts, body, sig) {
+				rejectWebhook(rw, ErrInvalidWebhook("invalid webhook signature"))
+				return
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			h.ServeHTTP(rw, req)
+		})
+	}
+}
+
+// rejectWebhook writes the error response.
+func rejectWebhook(rw http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if se, ok := err.(goa.ServiceError); ok {
+		status = se.ResponseStatus()
+	}
+	rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(err)
+}
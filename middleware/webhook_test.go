@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VerifyWebhook", func() {
+	var secret = []byte("secret")
+	var body string
+	var ts int64
+	var sig string
+	var called bool
+	var received string
+	var rw *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		body = `{"order_id":1}`
+		ts = time.Now().Unix()
+		sig = goa.SignWebhook(secret, ts, []byte(body))
+		called = false
+		received = ""
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		h := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			called = true
+			b, _ := ioutil.ReadAll(req.Body)
+			received = string(b)
+		})
+		req, _ := http.NewRequest("POST", "/hooks", strings.NewReader(body))
+		req.Header.Set(goa.WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+		req.Header.Set(goa.WebhookSignatureHeader, sig)
+		middleware.VerifyWebhook(secret, time.Minute)(h).ServeHTTP(rw, req)
+	})
+
+	It("accepts signed requests", func() {
+		Ω(called).Should(BeTrue())
+		Ω(received).Should(Equal(body))
+	})
+
+	Context("with an invalid signature", func() {
+		BeforeEach(func() {
+			sig = goa.SignWebhook([]byte("other"), ts, []byte(body))
+		})
+
+		It("rejects the request", func() {
+			Ω(called).Should(BeFalse())
+			Ω(rw.Code).Should(Equal(http.StatusUnauthorized))
+			Ω(rw.Header().Get("Content-Type")).Should(Equal(goa.ErrorMediaIdentifier))
+			Ω(rw.Body.String()).Should(ContainSubstring("invalid_webhook"))
+		})
+	})
+
+	Context("with a stale timestamp", func() {
+		BeforeEach(func() {
+			ts = time.Now().Add(-time.Hour).Unix()
+			sig = goa.SignWebhook(secret, ts, []byte(body))
+		})
+
+		It("rejects the request", func() {
+			Ω(called).Should(BeFalse())
+			Ω(rw.Code).Should(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package goa
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// acceptRange is a media range listed in an Accept header together with its quality value.
+type acceptRange struct {
+	typ, sub string
+	q        float64
+}
+
+// NegotiateContentType returns the offer that best matches the given Accept header value as
+// defined by RFC 7231 section 5.3.2. Media ranges may use wildcards ("*/*" or "type/*") and
+// quality values, the most specific range matching an offer determines its quality. Offers with
+// the same quality are ranked in the order given. NegotiateContentType returns the first offer if
+// accept is empty and the empty string if no offer is acceptable.
+func NegotiateContentType(accept string, offers ...string) string {
+	best, _ := negotiate(parseAccept(accept), offers)
+	return best
+}
+
+// NegotiatedHandler wraps the handler of an action that restricts the MIME types of its request
+// and response bodies. The returned handler rejects requests whose Content-Type header is not
+// listed in consumes with ErrUnsupportedMediaType and requests whose Accept header does not
+// accept any of the MIME types listed in produces with ErrNotAcceptable. It records the
+// negotiated response content type in the response data so that the response is encoded with
+// it. Either list may be empty in which case the corresponding check is skipped.
+func NegotiatedHandler(h Handler, produces, consumes []string) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		if len(consumes) > 0 && req.ContentLength != 0 {
+			if ct := req.Header.Get("Content-Type"); ct != "" && !matchContentType(ct, consumes) {
+				msg := fmt.Sprintf("unsupported content type %#v, must be one of %s", ct, strings.Join(consumes, ", "))
+				return ErrUnsupportedMediaType(msg, "content_type", ct)
+			}
+		}
+		if len(produces) > 0 {
+			accept := req.Header.Get("Accept")
+			ct := NegotiateContentType(accept, produces...)
+			if ct == "" {
+				msg := fmt.Sprintf("none of the accepted media types %#v is available, must accept one of %s", accept, strings.Join(produces, ", "))
+				return ErrNotAcceptable(msg, "accept", accept)
+			}
+			ContextResponse(ctx).ContentType = ct
+		}
+		return h(ctx, rw, req)
+	}
+}
+
+// negotiate returns the offer with the highest quality given the media ranges and the
+// specificity of the range that matched it: 0 for "*/*", 1 for "type/*" and 2 for exact matches.
+// It returns the first offer if there are no ranges and the empty string if no offer is
+// acceptable.
+func negotiate(ranges []acceptRange, offers []string) (string, int) {
+	if len(offers) == 0 {
+		return "", -1
+	}
+	if len(ranges) == 0 {
+		return offers[0], 0
+	}
+	var (
+		best     string
+		bestQ    float64
+		bestSpec = -1
+	)
+	for _, offer := range offers {
+		q, spec := quality(ranges, offer)
+		if q > bestQ {
+			best, bestQ, bestSpec = offer, q, spec
+		}
+	}
+	return best, bestSpec
+}
+
+// quality returns the quality of the given content type and the specificity of the most specific
+// media range that matches it. The quality is 0 if no range matches.
+func quality(ranges []acceptRange, contentType string) (float64, int) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt = strings.ToLower(contentType)
+	}
+	typ, sub := mt, ""
+	if idx := strings.Index(mt, "/"); idx > -1 {
+		typ, sub = mt[:idx], mt[idx+1:]
+	}
+	q, spec := 0.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.typ == "*" && r.sub == "*":
+			s = 0
+		case r.typ == typ && r.sub == "*":
+			s = 1
+		case r.typ == typ && r.sub == sub:
+			s = 2
+		}
+		if s > spec {
+			q, spec = r.q, s
+		}
+	}
+	return q, spec
+}
+
+// parseAccept parses the media ranges listed in an Accept header value. Invalid ranges are
+// ignored.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		if mt == "*" {
+			mt = "*/*"
+		}
+		idx := strings.Index(mt, "/")
+		if idx < 1 || idx == len(mt)-1 {
+			continue
+		}
+		r := acceptRange{typ: mt[:idx], sub: mt[idx+1:], q: 1}
+		if v, ok := params["q"]; ok {
+			q, err := strconv.ParseFloat(v, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+			r.q = q
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// matchContentType returns true if the media type of the Content-Type header value ct is one of
+// the given MIME types.
+func matchContentType(ct string, mimeTypes []string) bool {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+	for _, m := range mimeTypes {
+		if other, _, err := mime.ParseMediaType(m); err == nil && other == mt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package goa_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NegotiateContentType", func() {
+	offers := []string{"application/json", "application/xml", "text/plain"}
+
+	It("selects the offer with the highest quality", func() {
+		Ω(goa.NegotiateContentType("", offers...)).Should(Equal("application/json"))
+		Ω(goa.NegotiateContentType("application/xml", offers...)).Should(Equal("application/xml"))
+		Ω(goa.NegotiateContentType("application/json;q=0.5, application/xml", offers...)).Should(Equal("application/xml"))
+		Ω(goa.NegotiateContentType("text/*, application/json;q=0.2", offers...)).Should(Equal("text/plain"))
+		Ω(goa.NegotiateContentType("*/*;q=0.1, application/xml;q=0.5", offers...)).Should(Equal("application/xml"))
+		Ω(goa.NegotiateContentType("*/*", offers...)).Should(Equal("application/json"))
+		Ω(goa.NegotiateContentType("*", offers...)).Should(Equal("application/json"))
+	})
+
+	It("uses the most specific range matching an offer", func() {
+		Ω(goa.NegotiateContentType("application/*, application/json;q=0", offers...)).Should(Equal("application/xml"))
+	})
+
+	It("returns the empty string if no offer is acceptable", func() {
+		Ω(goa.NegotiateContentType("image/png", offers...)).Should(BeEmpty())
+		Ω(goa.NegotiateContentType("application/json;q=0", "application/json")).Should(BeEmpty())
+	})
+
+	It("ignores invalid ranges", func() {
+		Ω(goa.NegotiateContentType("application/json;q=2, application/xml", offers...)).Should(Equal("application/xml"))
+	})
+})
+
+var _ = Describe("NegotiatedHandler", func() {
+	var produces, consumes []string
+	var accept, contentType string
+	var called bool
+	var ctx context.Context
+	var err error
+
+	BeforeEach(func() {
+		produces = []string{"application/json", "application/xml"}
+		consumes = []string{"application/json"}
+		accept = ""
+		contentType = "application/json; charset=utf-8"
+		called = false
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return nil
+		}
+		req, _ := http.NewRequest("POST", "/bottles", strings.NewReader(`{"name":"red"}`))
+		req.Header.Set("Accept", accept)
+		req.Header.Set("Content-Type", contentType)
+		rw := httptest.NewRecorder()
+		ctx = goa.NewContext(context.Background(), rw, req, nil)
+		err = goa.NegotiatedHandler(h, produces, consumes)(ctx, rw, req)
+	})
+
+	It("records the negotiated content type", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(called).Should(BeTrue())
+		Ω(goa.ContextResponse(ctx).ContentType).Should(Equal("application/json"))
+	})
+
+	Context("with an Accept header", func() {
+		BeforeEach(func() {
+			accept = "application/json;q=0.5, application/xml"
+		})
+
+		It("records the negotiated content type", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(goa.ContextResponse(ctx).ContentType).Should(Equal("application/xml"))
+		})
+	})
+
+	Context("with an Accept header that accepts none of the produced types", func() {
+		BeforeEach(func() {
+			accept = "text/html"
+		})
+
+		It("returns a not acceptable error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(406))
+			Ω(called).Should(BeFalse())
+		})
+	})
+
+	Context("with an unsupported request content type", func() {
+		BeforeEach(func() {
+			contentType = "application/xml"
+		})
+
+		It("returns an unsupported media type error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(415))
+			Ω(called).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("HTTPEncoder", func() {
+	var encoder *goa.HTTPEncoder
+
+	BeforeEach(func() {
+		encoder = goa.NewHTTPEncoder()
+		encoder.Register(goa.NewJSONEncoder, "application/json")
+		encoder.Register(goa.NewXMLEncoder, "application/xml")
+		encoder.Register(goa.NewJSONEncoder, "*/*")
+	})
+
+	encode := func(accept string) string {
+		var buf bytes.Buffer
+		Ω(encoder.Encode("hello", &buf, accept)).Should(Succeed())
+		return strings.TrimSpace(buf.String())
+	}
+
+	It("negotiates the encoder using quality values", func() {
+		Ω(encode("application/json, application/xml;q=0.5")).Should(Equal(`"hello"`))
+		Ω(encode("application/json;q=0.5, application/xml")).Should(Equal(`<string>hello</string>`))
+		Ω(encode("application/*;q=0.8, application/json;q=0.1")).Should(Equal(`<string>hello</string>`))
+	})
+
+	It("uses the default encoder otherwise", func() {
+		Ω(encode("")).Should(Equal(`"hello"`))
+		Ω(encode("*/*")).Should(Equal(`"hello"`))
+		Ω(encode("text/html")).Should(Equal(`"hello"`))
+	})
+})
+
+var _ = Describe("HTTPDecoder", func() {
+	It("rejects unsupported content types", func() {
+		decoder := goa.NewHTTPDecoder()
+		decoder.Register(goa.NewJSONDecoder, "application/json")
+		var v interface{}
+		err := decoder.Decode(&v, strings.NewReader("<a/>"), "application/xml")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(415))
+	})
+})
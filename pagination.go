@@ -0,0 +1,71 @@
+package goa
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CursorPageLinks returns the value of the RFC 5988 Link header for a page of results of an
+// action using cursor based pagination. href is the href of the paginated collection, query the
+// query string of the request that produced the page and next the cursor of the following page.
+// The returned value is empty if next is empty.
+func CursorPageLinks(href string, query url.Values, next string) string {
+	if next == "" {
+		return ""
+	}
+	return formatLinks([]string{"next"}, []string{pageURL(href, query, "cursor", next)})
+}
+
+// OffsetPageLinks returns the value of the RFC 5988 Link header for a page of results of an
+// action using offset based pagination. href is the href of the paginated collection, query the
+// query string of the request that produced the page, page the page number starting at 1, limit
+// the maximum number of results per page and total the total number of results. The "first" and
+// "last" links are always present, the "prev" and "next" links only if there is a previous or
+// next page.
+func OffsetPageLinks(href string, query url.Values, page, limit, total int) string {
+	if limit < 1 {
+		return ""
+	}
+	last := (total + limit - 1) / limit
+	if last < 1 {
+		last = 1
+	}
+	rels := []string{"first"}
+	urls := []string{pageURL(href, query, "page", "1")}
+	if page > 1 {
+		prev := page - 1
+		if prev > last {
+			prev = last
+		}
+		rels = append(rels, "prev")
+		urls = append(urls, pageURL(href, query, "page", strconv.Itoa(prev)))
+	}
+	if page < last {
+		rels = append(rels, "next")
+		urls = append(urls, pageURL(href, query, "page", strconv.Itoa(page+1)))
+	}
+	rels = append(rels, "last")
+	urls = append(urls, pageURL(href, query, "page", strconv.Itoa(last)))
+	return formatLinks(rels, urls)
+}
+
+// pageURL returns the URL made of href and a copy of query with the param name set to value.
+func pageURL(href string, query url.Values, name, value string) string {
+	q := make(url.Values, len(query)+1)
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set(name, value)
+	return href + "?" + q.Encode()
+}
+
+// formatLinks builds a Link header value from the given relation types and URLs.
+func formatLinks(rels, urls []string) string {
+	links := make([]string, len(rels))
+	for i, rel := range rels {
+		links[i] = fmt.Sprintf("<%s>; rel=%q", urls[i], rel)
+	}
+	return strings.Join(links, ", ")
+}
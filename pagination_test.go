@@ -0,0 +1,52 @@
+package goa_test
+
+import (
+	"net/url"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CursorPageLinks", func() {
+	var query url.Values
+
+	BeforeEach(func() {
+		query = url.Values{"cursor": {"abc"}, "limit": {"10"}}
+	})
+
+	It("links to the next page", func() {
+		links := goa.CursorPageLinks("/bottles", query, "def")
+		Ω(links).Should(Equal(`</bottles?cursor=def&limit=10>; rel="next"`))
+	})
+
+	It("returns nothing on the last page", func() {
+		Ω(goa.CursorPageLinks("/bottles", query, "")).Should(BeEmpty())
+	})
+
+	It("does not modify the request query", func() {
+		goa.CursorPageLinks("/bottles", query, "def")
+		Ω(query.Get("cursor")).Should(Equal("abc"))
+	})
+})
+
+var _ = Describe("OffsetPageLinks", func() {
+	var query url.Values
+
+	BeforeEach(func() {
+		query = url.Values{"limit": {"10"}, "page": {"2"}}
+	})
+
+	It("links to the first, previous, next and last pages", func() {
+		links := goa.OffsetPageLinks("/bottles", query, 2, 10, 35)
+		Ω(links).Should(Equal(`</bottles?limit=10&page=1>; rel="first", ` +
+			`</bottles?limit=10&page=1>; rel="prev", ` +
+			`</bottles?limit=10&page=3>; rel="next", ` +
+			`</bottles?limit=10&page=4>; rel="last"`))
+	})
+
+	It("omits the previous link on the first page", func() {
+		links := goa.OffsetPageLinks("/bottles", query, 1, 10, 5)
+		Ω(links).Should(Equal(`</bottles?limit=10&page=1>; rel="first", </bottles?limit=10&page=1>; rel="last"`))
+	})
+})
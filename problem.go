@@ -0,0 +1,208 @@
+package goa
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/net/context"
+)
+
+// ProblemMediaIdentifier is the media type identifier of RFC 7807 problem details.
+const ProblemMediaIdentifier = "application/problem+json"
+
+// ProblemTypeBaseURI is the URI prefix used by NewProblemDetails to compute the type member of
+// problem details from the error code, e.g. "https://errors.example.com/" produces types such as
+// "https://errors.example.com/invalid_request". The type member is "about:blank" if empty.
+var ProblemTypeBaseURI string
+
+// ProblemDetails is an error rendered as RFC 7807 problem details. It implements ServiceError.
+// The Extensions members are encoded alongside the standard members, see
+// https://tools.ietf.org/html/rfc7807.
+type ProblemDetails struct {
+	// Type is a URI reference that identifies the problem type.
+	Type string
+	// Title is a short summary of the problem type.
+	Title string
+	// Status is the HTTP status code of the response.
+	Status int
+	// Detail is an explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is a URI reference that identifies this occurrence of the problem.
+	Instance string
+	// Extensions contains the additional members of the problem details. The "id" and
+	// "code" members hold the error ID and code of the errors created via an ErrorClass.
+	Extensions map[string]interface{}
+}
+
+// NewProblemDetails creates the problem details describing err. The status, detail, ID and code
+// of err are used if err is a ServiceError, an internal error is described otherwise. The Meta
+// entries of errors created via an ErrorClass become extension members. instance identifies the
+// occurrence of the problem, typically the request path.
+func NewProblemDetails(err error, instance string) *ProblemDetails {
+	var p *ProblemDetails
+	var code string
+	switch e := err.(type) {
+	case *ProblemDetails:
+		dup := *e
+		p = &dup
+	case *ErrorResponse:
+		p = &ProblemDetails{
+			Status:     e.Status,
+			Detail:     e.Detail,
+			Extensions: make(map[string]interface{}, len(e.Meta)+2),
+		}
+		for k, v := range e.Meta {
+			if !isProblemMember(k) {
+				p.Extensions[k] = v
+			}
+		}
+		p.Extensions["id"] = e.ID
+		p.Extensions["code"] = e.Code
+		code = e.Code
+	case ServiceError:
+		p = &ProblemDetails{
+			Status:     e.ResponseStatus(),
+			Detail:     e.Error(),
+			Extensions: map[string]interface{}{"id": e.Token()},
+		}
+	default:
+		p = &ProblemDetails{Status: http.StatusInternalServerError, Detail: err.Error()}
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+		if ProblemTypeBaseURI != "" && code != "" {
+			p.Type = ProblemTypeBaseURI + code
+		}
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	if p.Instance == "" {
+		p.Instance = instance
+	}
+	return p
+}
+
+// SendProblem writes err as RFC 7807 problem details with the given status code. The response
+// Content-Type header is set to ProblemMediaIdentifier and the body is encoded with the service
+// JSON encoder.
+func (service *Service) SendProblem(ctx context.Context, code int, err error) error {
+	r := ContextResponse(ctx)
+	if r == nil {
+		return fmt.Errorf("no response data in context")
+	}
+	var instance string
+	if req := ContextRequest(ctx); req != nil {
+		instance = req.URL.Path
+	}
+	p := NewProblemDetails(err, instance)
+	p.Status = code
+	r.Header().Set("Content-Type", ProblemMediaIdentifier)
+	r.WriteHeader(code)
+	return service.Encoder.Encode(p, r, "application/json")
+}
+
+// ErrorResponse converts the problem details into an error response. The "id" and "code"
+// extension members become the ID and Code fields and the other extension members the Meta
+// field. Generated clients use it to decode problem details error responses.
+func (p *ProblemDetails) ErrorResponse() *ErrorResponse {
+	e := &ErrorResponse{Status: p.Status, Detail: p.Detail}
+	for k, v := range p.Extensions {
+		switch k {
+		case "id":
+			e.ID, _ = v.(string)
+		case "code":
+			e.Code, _ = v.(string)
+		default:
+			if e.Meta == nil {
+				e.Meta = make(map[string]interface{})
+			}
+			e.Meta[k] = v
+		}
+	}
+	return e
+}
+
+// Error returns the problem details.
+func (p *ProblemDetails) Error() string {
+	return fmt.Sprintf("[%s] %d %s: %s", p.Token(), p.Status, p.Title, p.Detail)
+}
+
+// ResponseStatus is the status used to build responses.
+func (p *ProblemDetails) ResponseStatus() int { return p.Status }
+
+// Token is the unique error occurrence identifier, that is the value of the "id" extension
+// member.
+func (p *ProblemDetails) Token() string {
+	id, _ := p.Extensions["id"].(string)
+	return id
+}
+
+// MarshalJSON encodes the standard members and the extension members in the same JSON object.
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	if p.Type != "" {
+		m["type"] = p.Type
+	}
+	if p.Title != "" {
+		m["title"] = p.Title
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes the standard members and stores the other members in Extensions.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*p = ProblemDetails{}
+	for k, v := range m {
+		switch k {
+		case "type":
+			p.Type, _ = v.(string)
+		case "title":
+			p.Title, _ = v.(string)
+		case "detail":
+			p.Detail, _ = v.(string)
+		case "instance":
+			p.Instance, _ = v.(string)
+		case "status":
+			switch s := v.(type) {
+			case float64:
+				p.Status = int(s)
+			case string:
+				p.Status, _ = strconv.Atoi(s)
+			}
+		default:
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]interface{})
+			}
+			p.Extensions[k] = v
+		}
+	}
+	return nil
+}
+
+// isProblemMember returns true if name is the name of a standard problem details member.
+func isProblemMember(name string) bool {
+	switch name {
+	case "type", "title", "status", "detail", "instance":
+		return true
+	}
+	return false
+}
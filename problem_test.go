@@ -0,0 +1,111 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewProblemDetails", func() {
+	var err error
+	var p *goa.ProblemDetails
+
+	JustBeforeEach(func() {
+		p = goa.NewProblemDetails(err, "/orders/1")
+	})
+
+	Context("with an error created via an error class", func() {
+		BeforeEach(func() {
+			err = &goa.ErrorResponse{
+				ID:     "abc",
+				Code:   "out_of_stock",
+				Status: 409,
+				Detail: "no more bottles",
+				Meta:   map[string]interface{}{"available": 0, "title": "ignored"},
+			}
+		})
+
+		It("uses the error status, detail, ID, code and meta", func() {
+			Ω(p.Type).Should(Equal("about:blank"))
+			Ω(p.Title).Should(Equal("Conflict"))
+			Ω(p.Status).Should(Equal(409))
+			Ω(p.Detail).Should(Equal("no more bottles"))
+			Ω(p.Instance).Should(Equal("/orders/1"))
+			Ω(p.Extensions).Should(Equal(map[string]interface{}{"id": "abc", "code": "out_of_stock", "available": 0}))
+			Ω(p.Token()).Should(Equal("abc"))
+		})
+
+		Context("and a problem type base URI", func() {
+			BeforeEach(func() {
+				goa.ProblemTypeBaseURI = "https://errors.example.com/"
+			})
+
+			AfterEach(func() {
+				goa.ProblemTypeBaseURI = ""
+			})
+
+			It("computes the type from the error code", func() {
+				Ω(p.Type).Should(Equal("https://errors.example.com/out_of_stock"))
+			})
+		})
+	})
+
+	Context("with a Go error", func() {
+		BeforeEach(func() {
+			err = errors.New("boom")
+		})
+
+		It("describes an internal error", func() {
+			Ω(p.Status).Should(Equal(500))
+			Ω(p.Title).Should(Equal("Internal Server Error"))
+			Ω(p.Detail).Should(Equal("boom"))
+		})
+	})
+})
+
+var _ = Describe("ProblemDetails", func() {
+	var p *goa.ProblemDetails
+
+	BeforeEach(func() {
+		p = &goa.ProblemDetails{
+			Type:       "about:blank",
+			Title:      "Bad Request",
+			Status:     400,
+			Detail:     "invalid value",
+			Extensions: map[string]interface{}{"id": "abc", "code": "invalid_request", "attribute": "name"},
+		}
+	})
+
+	It("serializes the extension members alongside the standard members", func() {
+		b, err := json.Marshal(p)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"attribute":"name","code":"invalid_request","detail":"invalid value","id":"abc","status":400,"title":"Bad Request","type":"about:blank"}`))
+
+		var decoded goa.ProblemDetails
+		Ω(json.Unmarshal(b, &decoded)).Should(Succeed())
+		Ω(&decoded).Should(Equal(p))
+	})
+
+	It("converts to an error response", func() {
+		Ω(p.ErrorResponse()).Should(Equal(&goa.ErrorResponse{
+			ID:     "abc",
+			Code:   "invalid_request",
+			Status: 400,
+			Detail: "invalid value",
+			Meta:   map[string]interface{}{"attribute": "name"},
+		}))
+	})
+
+	It("is decoded by the JSON decoder", func() {
+		decoder := goa.NewHTTPDecoder()
+		decoder.Register(goa.NewJSONDecoder, "application/json")
+		var decoded goa.ProblemDetails
+		body := strings.NewReader(`{"status":404,"title":"Not Found"}`)
+		Ω(decoder.Decode(&decoded, body, goa.ProblemMediaIdentifier)).Should(Succeed())
+		Ω(decoded.Status).Should(Equal(404))
+	})
+})
@@ -0,0 +1,25 @@
+package goa
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RateLimiter creates the middleware that limits the number of requests a client may make in the
+// given period. scope identifies the requests counted against the limit: the handlers wrapped with
+// middleware created with the same scope share the same limit.
+type RateLimiter func(scope string, requests int, period time.Duration) Middleware
+
+// RateLimitedHandler wraps the handler of an action that defines a rate limit in the design. The
+// returned handler runs h through the middleware created by the rate limiter given to the service
+// UseRateLimiter method if any or calls h directly otherwise.
+func RateLimitedHandler(service *Service, h Handler, scope string, requests int, period time.Duration) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		if l := service.rateLimiter; l != nil {
+			return l(scope, requests, period)(h)(ctx, rw, req)
+		}
+		return h(ctx, rw, req)
+	}
+}
@@ -0,0 +1,65 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimitedHandler", func() {
+	var service *goa.Service
+	var called bool
+	var rw *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		called = false
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return nil
+		}
+		req, _ := http.NewRequest("POST", "/bottles", nil)
+		err := goa.RateLimitedHandler(service, h, "bottle#create", 10, time.Minute)(context.Background(), rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("calls the handler", func() {
+		Ω(called).Should(BeTrue())
+	})
+
+	Context("with a rate limiter", func() {
+		var scope string
+		var requests int
+		var period time.Duration
+
+		BeforeEach(func() {
+			service.UseRateLimiter(func(s string, r int, p time.Duration) goa.Middleware {
+				scope, requests, period = s, r, p
+				return func(h goa.Handler) goa.Handler {
+					return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+						rw.Header().Set("X-RateLimit-Limit", "10")
+						return h(ctx, rw, req)
+					}
+				}
+			})
+		})
+
+		It("runs the handler through the rate limiter middleware", func() {
+			Ω(called).Should(BeTrue())
+			Ω(scope).Should(Equal("bottle#create"))
+			Ω(requests).Should(Equal(10))
+			Ω(period).Should(Equal(time.Minute))
+			Ω(rw.Header().Get("X-RateLimit-Limit")).Should(Equal("10"))
+		})
+	})
+})
@@ -3,7 +3,7 @@ package goa
 import "golang.org/x/net/context"
 
 // Location is the enum defining where the value of key based security schemes should be read:
-// either a HTTP request header or a URL querystring value
+// a HTTP request header, a URL querystring value or a cookie.
 type Location string
 
 // LocHeader indicates the secret value should be loaded from the request headers.
@@ -12,6 +12,9 @@ const LocHeader Location = "header"
 // LocQuery indicates the secret value should be loaded from the request URL querystring.
 const LocQuery Location = "query"
 
+// LocCookie indicates the secret value should be loaded from the request cookies.
+const LocCookie Location = "cookie"
+
 // ContextRequiredScopes extracts the security scopes from the given context.
 // This should be used in auth handlers to validate that the required scopes are present in the
 // JWT or OAuth2 token.
@@ -51,14 +54,15 @@ type BasicAuthSecurity struct {
 }
 
 // APIKeySecurity represents the `apiKey` security scheme. It handles a key that can be in the
-// headers or in the query parameters, and does authentication based on that.  The Name field
-// represents the key of either the query string parameter or the header, depending on the In field.
+// headers, in the query parameters or in a cookie, and does authentication based on that.  The Name
+// field represents the key of the query string parameter, the header or the cookie, depending on
+// the In field.
 type APIKeySecurity struct {
 	// Description of the security scheme
 	Description string
-	// In represents where to check for some data, `query` or `header`
+	// In represents where to check for some data, `query`, `header` or `cookie`
 	In Location
-	// Name is the name of the `header` or `query` parameter to check for data.
+	// Name is the name of the `header`, `query` parameter or `cookie` to check for data.
 	Name string
 }
 
@@ -66,9 +70,9 @@ type APIKeySecurity struct {
 type JWTSecurity struct {
 	// Description of the security scheme
 	Description string
-	// In represents where to check for the JWT, `query` or `header`
+	// In represents where to check for the JWT, `query`, `header` or `cookie`
 	In Location
-	// Name is the name of the `header` or `query` parameter to check for data.
+	// Name is the name of the `header`, `query` parameter or `cookie` to check for data.
 	Name string
 	// TokenURL defines the URL where you'd get the JWT tokens.
 	TokenURL string
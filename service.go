@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"net/url"
@@ -15,6 +16,10 @@ import (
 	"golang.org/x/net/context"
 )
 
+// MaxMultipartMemory is the maximum number of bytes of multipart/form-data request bodies kept in
+// memory by DecodeMultipartRequest.
+var MaxMultipartMemory int64 = 32 << 20 // 32 MB
+
 type (
 	// Service is the data structure supporting goa services.
 	// It provides methods for configuring a service and running it.
@@ -35,9 +40,16 @@ type (
 		Decoder *HTTPDecoder
 		// Response body encoder
 		Encoder *HTTPEncoder
-
-		middleware []Middleware       // Middleware chain
-		cancel     context.CancelFunc // Service context cancel signal trigger
+		// ProblemDetails causes errors to be rendered as RFC 7807 problem details with the
+		// application/problem+json content type instead of the goa error media type, see
+		// SendProblem.
+		ProblemDetails bool
+
+		middleware  []Middleware       // Middleware chain
+		idempotency Middleware         // Middleware applied to idempotent actions
+		rateLimiter RateLimiter        // Rate limiter applied to rate limited actions
+		etag        Middleware         // Middleware applied to actions with entity tags
+		cancel      context.CancelFunc // Service context cancel signal trigger
 	}
 
 	// Controller defines the common fields and behavior of generated controllers.
@@ -57,8 +69,9 @@ type (
 
 	// FileServer is the interface implemented by controllers that can serve static files.
 	FileServer interface {
-		// FileHandler returns a handler that serves files under the given request path.
-		FileHandler(path, filename string) Handler
+		// FileHandler returns a handler that serves files under the given request path using
+		// the optional cache policy.
+		FileHandler(path, filename string, cache ...*CachePolicy) Handler
 	}
 
 	// Handler defines the request handler signatures.
@@ -69,6 +82,15 @@ type (
 
 	// DecodeFunc is the function that initialize the unmarshaled payload from the request body.
 	DecodeFunc func(context.Context, io.ReadCloser, interface{}) error
+
+	// limitedBody wraps the reader returned by http.MaxBytesReader and records whether reading
+	// failed because the request body is longer than the limit.
+	limitedBody struct {
+		io.ReadCloser
+		limit    int64
+		read     int64
+		exceeded bool
+	}
 )
 
 // New instantiates a service with the given name.
@@ -110,6 +132,10 @@ func New(name string) *Service {
 		ctx := NewContext(service.Context, rw, req, params)
 		err := notFoundHandler(ctx, ContextResponse(ctx), req)
 		if !ContextResponse(ctx).Written() {
+			if service.ProblemDetails {
+				service.SendProblem(ctx, 404, err)
+				return
+			}
 			service.Send(ctx, 404, err)
 		}
 	})
@@ -130,6 +156,24 @@ func (service *Service) Use(m Middleware) {
 	service.middleware = append(service.middleware, m)
 }
 
+// UseIdempotency sets the middleware applied to the actions marked as idempotent in the design,
+// see middleware.Idempotency.
+func (service *Service) UseIdempotency(m Middleware) {
+	service.idempotency = m
+}
+
+// UseRateLimiter sets the rate limiter applied to the actions that define a rate limit in the
+// design, see the middleware/ratelimit package.
+func (service *Service) UseRateLimiter(l RateLimiter) {
+	service.rateLimiter = l
+}
+
+// UseETag sets the middleware applied to the actions whose responses carry an entity tag, see
+// middleware.ETag.
+func (service *Service) UseETag(m Middleware) {
+	service.etag = m
+}
+
 // WithLogger sets the logger used internally by the service and by Log.
 func (service *Service) WithLogger(logger LogAdapter) {
 	service.Context = WithLogger(service.Context, logger)
@@ -177,12 +221,22 @@ func (service *Service) NewController(name string) *Controller {
 }
 
 // Send serializes the given body matching the request Accept header against the service
-// encoders. It uses the default service encoder if no match is found.
+// encoders. It uses the default service encoder if no match is found. Send sets the response
+// Content-Type header to the negotiated content type unless already set.
 func (service *Service) Send(ctx context.Context, code int, body interface{}) error {
 	r := ContextResponse(ctx)
 	if r == nil {
 		return fmt.Errorf("no response data in context")
 	}
+	if r.Header().Get("Content-Type") == "" {
+		ct := r.ContentType
+		if ct == "" {
+			ct = service.Encoder.contentType(ContextRequest(ctx).Header.Get("Accept"))
+		}
+		if ct != "" && ct != "*/*" {
+			r.Header().Set("Content-Type", ct)
+		}
+	}
 	r.WriteHeader(code)
 	return service.EncodeResponse(ctx, body)
 }
@@ -200,29 +254,52 @@ func (service *Service) DecodeRequest(req *http.Request, v interface{}) error {
 	defer body.Close()
 
 	if err := service.Decoder.Decode(v, body, contentType); err != nil {
+		if _, ok := err.(ServiceError); ok {
+			return err
+		}
 		return fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
 	}
 
 	return nil
 }
 
+// DecodeMultipartRequest reads the entire multipart/form-data request body and returns the
+// resulting form. The form is buffered before the action runs: up to MaxMultipartMemory bytes are
+// kept in memory and the files that do not fit are stored in temporary files which are removed once
+// the request has been handled.
+func (service *Service) DecodeMultipartRequest(req *http.Request) (*multipart.Form, error) {
+	mr, err := req.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	form, err := mr.ReadForm(MaxMultipartMemory)
+	if err != nil {
+		return nil, err
+	}
+	req.MultipartForm = form
+	return form, nil
+}
+
 // EncodeResponse uses the HTTP encoder to marshal and write the response body based on the request
-// Accept header.
+// Accept header or on the negotiated content type if any.
 func (service *Service) EncodeResponse(ctx context.Context, v interface{}) error {
 	accept := ContextRequest(ctx).Header.Get("Accept")
+	if ct := ContextResponse(ctx).ContentType; ct != "" {
+		accept = ct
+	}
 	return service.Encoder.Encode(v, ContextResponse(ctx), accept)
 }
 
 // ServeFiles replies to the request with the contents of the named file or directory. See
 // FileHandler for details.
-func (ctrl *Controller) ServeFiles(path, filename string) error {
+func (ctrl *Controller) ServeFiles(path, filename string, cache ...*CachePolicy) error {
 	if strings.Contains(path, ":") {
 		return fmt.Errorf("path may only include wildcards that match the entire end of the URL (e.g. *filepath)")
 	}
 	LogInfo(ctrl.Context, "mount file", "name", filename, "route", fmt.Sprintf("GET %s", path))
 	handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		if !ContextResponse(ctx).Written() {
-			return ctrl.FileHandler(path, filename)(ctx, rw, req)
+			return ctrl.FileHandler(path, filename, cache...)(ctx, rw, req)
 		}
 		return nil
 	}
@@ -266,17 +343,22 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 		ctx := NewContext(WithAction(ctrl.Context, name), rw, req, params)
 
 		// Protect against request bodies with unreasonable length
+		var body *limitedBody
 		if ctrl.MaxRequestBodyLength > 0 {
-			req.Body = http.MaxBytesReader(rw, req.Body, ctrl.MaxRequestBodyLength)
+			body = &limitedBody{
+				ReadCloser: http.MaxBytesReader(rw, req.Body, ctrl.MaxRequestBodyLength),
+				limit:      ctrl.MaxRequestBodyLength,
+			}
+			req.Body = body
 		}
 
 		// Load body if any
 		if req.ContentLength > 0 && unm != nil {
 			if err := unm(ctx, ctrl.Service, req); err != nil {
-				if err.Error() == "http: request body too large" {
+				if body != nil && body.exceeded {
 					msg := fmt.Sprintf("request body length exceeds %d bytes", ctrl.MaxRequestBodyLength)
 					err = ErrRequestBodyTooLarge(msg)
-				} else {
+				} else if se, ok := err.(ServiceError); !ok || se.ResponseStatus() != http.StatusUnsupportedMediaType {
 					err = ErrBadRequest(err)
 				}
 				ctx = WithError(ctx, err)
@@ -306,8 +388,11 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 //	c.FileHandler("/assets/*filepath", "/www/data/assets")
 //
 // returns the content of the file "/www/data/assets/x/y/z" when requests are sent to
-// "/assets/x/y/z".
-func (ctrl *Controller) FileHandler(path, filename string) Handler {
+// "/assets/x/y/z". The optional cache policy sets the Cache-Control and Vary headers of the
+// responses:
+//
+//	c.FileHandler("/assets/*filepath", "/www/data/assets", &goa.CachePolicy{MaxAge: 24 * time.Hour})
+func (ctrl *Controller) FileHandler(path, filename string, cache ...*CachePolicy) Handler {
 	var wc string
 	if idx := strings.LastIndex(path, "/*"); idx > -1 && idx < len(path)-1 {
 		wc = path[idx+2:]
@@ -349,6 +434,10 @@ func (ctrl *Controller) FileHandler(path, filename string) Handler {
 			}
 		}
 
+		if len(cache) > 0 && cache[0] != nil {
+			cache[0].Apply(rw.Header())
+		}
+
 		// serveContent will check modification time
 		// Still a directory? (we didn't find an index.html file)
 		if d.IsDir() {
@@ -393,6 +482,16 @@ func dirList(w http.ResponseWriter, f http.File) error {
 	return nil
 }
 
+// Read reads from the underlying reader and records whether the limit was exceeded.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil && err != io.EOF && b.read >= b.limit {
+		b.exceeded = true
+	}
+	return n, err
+}
+
 type byName []os.FileInfo
 
 func (s byName) Len() int           { return len(s) }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 
@@ -52,6 +53,18 @@ var _ = Describe("Service", func() {
 			Ω(string(rw.Body)).Should(MatchRegexp(`{"id":".*","code":"not_found","status":404,"detail":"/foo"}` + "\n"))
 		})
 
+		Context("with problem details enabled", func() {
+			BeforeEach(func() {
+				s.ProblemDetails = true
+			})
+
+			It("responds with problem details", func() {
+				Ω(rw.Status).Should(Equal(404))
+				Ω(rw.ParentHeader.Get("Content-Type")).Should(Equal(goa.ProblemMediaIdentifier))
+				Ω(string(rw.Body)).Should(MatchRegexp(`{"code":"not_found","detail":"/foo","id":".*","instance":"/foo","status":404,"title":"Not Found","type":"about:blank"}` + "\n"))
+			})
+		})
+
 		Context("with middleware", func() {
 			middlewareCalled := false
 
@@ -113,6 +126,72 @@ var _ = Describe("Service", func() {
 		It("prevents reading more bytes", func() {
 			Ω(string(rw.Body)).Should(MatchRegexp(`\[.*\] 413 request_too_large: request body length exceeds 4 bytes`))
 		})
+
+		Context("with a multipart request body", func() {
+			BeforeEach(func() {
+				var body bytes.Buffer
+				w := multipart.NewWriter(&body)
+				fw, _ := w.CreateFormFile("file", "goa.txt")
+				fw.Write([]byte("content"))
+				w.Close()
+				req, _ = http.NewRequest("POST", "/foo", &body)
+				req.Header.Set("Content-Type", w.FormDataContentType())
+				ctrl := s.NewController("test")
+				ctrl.MaxRequestBodyLength = 64
+				unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+					_, err := service.DecodeMultipartRequest(req)
+					return err
+				}
+				handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					rw.WriteHeader(400)
+					rw.Write([]byte(goa.ContextError(ctx).Error()))
+					return nil
+				}
+				muxHandler = ctrl.MuxHandler("testMaxMultipart", handler, unmarshaler)
+			})
+
+			It("reports the request body as too large", func() {
+				Ω(string(rw.Body)).Should(MatchRegexp(`\[.*\] 413 request_too_large: request body length exceeds 64 bytes`))
+			})
+		})
+	})
+
+	Describe("DecodeMultipartRequest", func() {
+		var req *http.Request
+		var form *multipart.Form
+		var err error
+
+		BeforeEach(func() {
+			var body bytes.Buffer
+			w := multipart.NewWriter(&body)
+			w.WriteField("title", "goa")
+			fw, _ := w.CreateFormFile("file", "goa.txt")
+			fw.Write([]byte("content"))
+			w.Close()
+			req, _ = http.NewRequest("POST", "/foo", &body)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+		})
+
+		JustBeforeEach(func() {
+			form, err = s.DecodeMultipartRequest(req)
+		})
+
+		It("decodes the form values and files", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(form.Value["title"]).Should(Equal([]string{"goa"}))
+			Ω(form.File["file"]).Should(HaveLen(1))
+			Ω(form.File["file"][0].Filename).Should(Equal("goa.txt"))
+		})
+
+		Context("with a request body that is not multipart", func() {
+			BeforeEach(func() {
+				req.Header.Set("Content-Type", "application/json")
+			})
+
+			It("returns an error", func() {
+				Ω(err).Should(HaveOccurred())
+			})
+		})
 	})
 
 	Describe("MuxHandler", func() {
@@ -0,0 +1,92 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	// StreamSSE is the content type of streamed responses that send each message as a
+	// Server-Sent Event.
+	StreamSSE = "text/event-stream"
+
+	// StreamNDJSON is the content type of streamed responses that send each message as a
+	// JSON document terminated by a newline.
+	StreamNDJSON = "application/x-ndjson"
+)
+
+// ResponseStream writes a sequence of messages to the response body of a streaming action.
+// Each message is encoded as JSON and written either as a Server-Sent Event data field or as a
+// single line depending on the stream content type. The response is flushed after each message
+// so that clients receive them as they are sent.
+type ResponseStream struct {
+	ctx         context.Context
+	contentType string
+	resp        *ResponseData
+}
+
+// NewResponseStream writes the response header for a stream with the given status code and
+// content type and returns the stream used to send messages. contentType must be StreamSSE or
+// StreamNDJSON.
+func NewResponseStream(ctx context.Context, code int, contentType string) (*ResponseStream, error) {
+	resp := ContextResponse(ctx)
+	if resp == nil {
+		return nil, fmt.Errorf("no response data in context")
+	}
+	if contentType != StreamSSE && contentType != StreamNDJSON {
+		return nil, fmt.Errorf("unsupported stream content type %#v", contentType)
+	}
+	if _, ok := resp.ResponseWriter.(http.Flusher); !ok {
+		return nil, fmt.Errorf("response writer does not support flushing")
+	}
+	resp.Header().Set("Content-Type", contentType)
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.WriteHeader(code)
+	resp.ResponseWriter.(http.Flusher).Flush()
+	return &ResponseStream{ctx: ctx, contentType: contentType, resp: resp}, nil
+}
+
+// Send encodes and writes a message to the stream and flushes the response. It returns the
+// context error without writing anything if the request context is done, for example because
+// the client went away.
+func (s *ResponseStream) Send(v interface{}) error {
+	return s.write("", v)
+}
+
+// SendEvent is similar to Send but sets the name of the event for Server-Sent Events streams.
+// The event name is ignored for newline delimited streams.
+func (s *ResponseStream) SendEvent(event string, v interface{}) error {
+	return s.write(event, v)
+}
+
+// write encodes v and writes it to the response using the stream framing.
+func (s *ResponseStream) write(event string, v interface{}) error {
+	select {
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	default:
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if s.contentType == StreamSSE {
+		if event != "" {
+			fmt.Fprintf(&buf, "event: %s\n", event)
+		}
+		fmt.Fprintf(&buf, "data: %s\n\n", b)
+	} else {
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	if _, err := s.resp.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	s.resp.ResponseWriter.(http.Flusher).Flush()
+	return nil
+}
@@ -0,0 +1,80 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResponseStream", func() {
+	var rw *httptest.ResponseRecorder
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var contentType string
+
+	var stream *goa.ResponseStream
+	var err error
+
+	BeforeEach(func() {
+		req, _ := http.NewRequest("GET", "/events", nil)
+		rw = httptest.NewRecorder()
+		ctx, cancel = context.WithCancel(context.Background())
+		ctx = goa.NewContext(ctx, rw, req, nil)
+		contentType = goa.StreamSSE
+	})
+
+	JustBeforeEach(func() {
+		stream, err = goa.NewResponseStream(ctx, 200, contentType)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("writes the response header", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Header().Get("Content-Type")).Should(Equal(goa.StreamSSE))
+		Ω(rw.Flushed).Should(BeTrue())
+	})
+
+	It("sends server-sent events", func() {
+		Ω(stream.Send(map[string]int{"id": 1})).ShouldNot(HaveOccurred())
+		Ω(stream.SendEvent("update", map[string]int{"id": 2})).ShouldNot(HaveOccurred())
+		Ω(rw.Body.String()).Should(Equal("data: {\"id\":1}\n\nevent: update\ndata: {\"id\":2}\n\n"))
+	})
+
+	It("stops sending once the context is done", func() {
+		cancel()
+		Ω(stream.Send(map[string]int{"id": 1})).Should(Equal(context.Canceled))
+		Ω(rw.Body.Len()).Should(Equal(0))
+	})
+
+	Context("with newline delimited JSON", func() {
+		BeforeEach(func() {
+			contentType = goa.StreamNDJSON
+		})
+
+		It("sends one message per line", func() {
+			Ω(stream.Send(map[string]int{"id": 1})).ShouldNot(HaveOccurred())
+			Ω(stream.SendEvent("ignored", map[string]int{"id": 2})).ShouldNot(HaveOccurred())
+			Ω(rw.Body.String()).Should(Equal("{\"id\":1}\n{\"id\":2}\n"))
+		})
+	})
+
+	Context("with an unsupported content type", func() {
+		BeforeEach(func() {
+			contentType = "application/json"
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(rw.Body.Len()).Should(Equal(0))
+		})
+	})
+})
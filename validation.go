@@ -2,9 +2,11 @@ package goa
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/mail"
 	"net/url"
+	"reflect"
 	"regexp"
 	"sync"
 	"time"
@@ -141,3 +143,40 @@ func ValidatePattern(p string, val string) bool {
 	}
 	return r.MatchString(val)
 }
+
+// ValidateMultipleOf returns true if val is a multiple of m.
+func ValidateMultipleOf(val, m float64) bool {
+	q := val / m
+	return math.Abs(q-math.Floor(q+0.5)) < 1e-9
+}
+
+// ValidateUniqueItems returns true if the elements of the slice val are all different.
+func ValidateUniqueItems(val interface{}) bool {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return true
+	}
+	switch v.Type().Elem().Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		// primitive elements are compared with ==, index them to avoid a quadratic lookup
+		seen := make(map[interface{}]struct{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			e := v.Index(i).Interface()
+			if _, ok := seen[e]; ok {
+				return false
+			}
+			seen[e] = struct{}{}
+		}
+		return true
+	}
+	for i := 0; i < v.Len(); i++ {
+		for j := i + 1; j < v.Len(); j++ {
+			if reflect.DeepEqual(v.Index(i).Interface(), v.Index(j).Interface()) {
+				return false
+			}
+		}
+	}
+	return true
+}
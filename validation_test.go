@@ -343,3 +343,37 @@ var _ = Describe("ValidateFormat", func() {
 
 	})
 })
+
+var _ = Describe("ValidateMultipleOf", func() {
+	It("validates multiples", func() {
+		Ω(goa.ValidateMultipleOf(42, 3)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(0.6, 0.2)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(-4.5, 1.5)).Should(BeTrue())
+	})
+
+	It("rejects non multiples", func() {
+		Ω(goa.ValidateMultipleOf(43, 3)).Should(BeFalse())
+		Ω(goa.ValidateMultipleOf(0.7, 0.2)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("ValidateUniqueItems", func() {
+	It("validates slices with unique elements", func() {
+		Ω(goa.ValidateUniqueItems([]int{1, 2, 3})).Should(BeTrue())
+		Ω(goa.ValidateUniqueItems([]string{})).Should(BeTrue())
+		Ω(goa.ValidateUniqueItems([]string{"a", "b"})).Should(BeTrue())
+		Ω(goa.ValidateUniqueItems([]map[string]int{{"a": 1}, {"a": 2}})).Should(BeTrue())
+	})
+
+	It("rejects slices with duplicate elements", func() {
+		Ω(goa.ValidateUniqueItems([]int{1, 2, 1})).Should(BeFalse())
+		Ω(goa.ValidateUniqueItems([]float64{0.5, 0.5})).Should(BeFalse())
+		Ω(goa.ValidateUniqueItems([]string{"a", "b", "a"})).Should(BeFalse())
+		Ω(goa.ValidateUniqueItems([]map[string]int{{"a": 1}, {"a": 1}})).Should(BeFalse())
+	})
+
+	It("compares the values pointed to by pointer elements", func() {
+		a, b := "a", "a"
+		Ω(goa.ValidateUniqueItems([]*string{&a, &b})).Should(BeFalse())
+	})
+})
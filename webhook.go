@@ -0,0 +1,51 @@
+package goa
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	// WebhookEventHeader is the name of the header that contains the name of the callback
+	// being delivered, e.g. "order_shipped".
+	WebhookEventHeader = "X-Webhook-Event"
+
+	// WebhookDeliveryHeader is the name of the header that contains the unique delivery
+	// identifier. The identifier is the same for all the attempts of a given delivery so that
+	// receivers may discard duplicates.
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+
+	// WebhookTimestampHeader is the name of the header that contains the time the request was
+	// sent expressed as the number of seconds since the Unix epoch.
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+
+	// WebhookSignatureHeader is the name of the header that contains the request signature
+	// computed with SignWebhook.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// webhookSignaturePrefix identifies the algorithm used to compute webhook signatures.
+const webhookSignaturePrefix = "sha256="
+
+// SignWebhook computes the signature of a webhook request. The signature consists of the
+// "sha256=" prefix followed by the hex encoded HMAC-SHA256 of the timestamp, a dot and the
+// request body. Including the timestamp lets receivers reject replayed requests.
+func SignWebhook(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature returns true if signature is the signature of the webhook request with
+// the given timestamp and body computed with secret. The comparison is done in constant time.
+func VerifyWebhookSignature(secret []byte, timestamp int64, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(SignWebhook(secret, timestamp, body)))
+}
@@ -0,0 +1,27 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SignWebhook", func() {
+	secret := []byte("secret")
+	body := []byte(`{"order_id":1}`)
+
+	It("computes a HMAC-SHA256 signature", func() {
+		sig := goa.SignWebhook(secret, 1500000000, body)
+		Ω(sig).Should(Equal("sha256=cb51d39b8d9b91bca3a207274234e2badeea204970110538c66d8e6f1a53176c"))
+		Ω(goa.SignWebhook(secret, 1500000001, body)).ShouldNot(Equal(sig))
+	})
+
+	It("verifies signatures", func() {
+		sig := goa.SignWebhook(secret, 1500000000, body)
+		Ω(goa.VerifyWebhookSignature(secret, 1500000000, body, sig)).Should(BeTrue())
+		Ω(goa.VerifyWebhookSignature([]byte("other"), 1500000000, body, sig)).Should(BeFalse())
+		Ω(goa.VerifyWebhookSignature(secret, 1500000001, body, sig)).Should(BeFalse())
+		Ω(goa.VerifyWebhookSignature(secret, 1500000000, []byte("{}"), sig)).Should(BeFalse())
+		Ω(goa.VerifyWebhookSignature(secret, 1500000000, body, sig[7:])).Should(BeFalse())
+	})
+})